@@ -2,16 +2,35 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"fmt"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/rs/zerolog/log"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
+	"job-portal-api/internal/alerts"
+	"job-portal-api/internal/analytics"
 	"job-portal-api/internal/auth"
 	"job-portal-api/internal/database"
+	"job-portal-api/internal/events"
+	"job-portal-api/internal/feed"
 	"job-portal-api/internal/handlers"
+	"job-portal-api/internal/interviews"
+	"job-portal-api/internal/invites"
+	"job-portal-api/internal/lifecycle"
+	"job-portal-api/internal/mail"
+	"job-portal-api/internal/matching"
+	"job-portal-api/internal/messaging"
 	"job-portal-api/internal/models"
+	"job-portal-api/internal/offers"
+	"job-portal-api/internal/queue"
+	"job-portal-api/internal/resume"
+	"job-portal-api/internal/salary"
+	"job-portal-api/internal/storage"
+	"job-portal-api/internal/webhooks"
 	"time"
 )
 
@@ -81,14 +100,145 @@ func startApp() error {
 		return err
 	}
 
+	// =========================================================================
+	// Initialize blob storage for uploads
+	log.Info().Msg("main : Started : Initializing blob storage")
+	bs, err := openBlobStore()
+	if err != nil {
+		return fmt.Errorf("opening blob store %w", err)
+	}
+
+	// =========================================================================
+	// Background work runs on a queue kept in Postgres; workers drain when the app shuts down
+	workers, err := strconv.Atoi(os.Getenv("QUEUE_WORKERS"))
+	if err != nil {
+		workers = 4
+	}
+	q, err := queue.New(ms, queue.Options{Workers: workers})
+	if err != nil {
+		return fmt.Errorf("constructing queue %w", err)
+	}
+	rp, err := resume.NewPipeline(ms, bs, q)
+	if err != nil {
+		return fmt.Errorf("constructing resume pipeline %w", err)
+	}
+	// Match scores are computed in the background whenever jobs or profiles change
+	weights, err := matching.ParseWeights(os.Getenv("MATCH_WEIGHTS"))
+	if err != nil {
+		return fmt.Errorf("parsing match weights %w", err)
+	}
+	me, err := matching.NewEngine(ms, weights, q)
+	if err != nil {
+		return fmt.Errorf("constructing matching engine %w", err)
+	}
+	// Emails are delivered by the queue, so a mail server outage only delays them
+	smtpMailer, err := openMailer()
+	if err != nil {
+		return fmt.Errorf("opening mailer %w", err)
+	}
+	mailer, err := mail.NewQueuedMailer(q, smtpMailer)
+	if err != nil {
+		return fmt.Errorf("constructing queued mailer %w", err)
+	}
+	iv, err := invites.NewInviter(ms, mailer, publicURL())
+	if err != nil {
+		return fmt.Errorf("constructing inviter %w", err)
+	}
+	ic, err := interviews.NewCoordinator(ms, mailer, q, publicURL())
+	if err != nil {
+		return fmt.Errorf("constructing interview coordinator %w", err)
+	}
+	of, err := offers.NewIssuer(ms, bs)
+	if err != nil {
+		return fmt.Errorf("constructing offer issuer %w", err)
+	}
+	ds, err := alerts.NewScheduler(ms, mailer, publicURL())
+	if err != nil {
+		return fmt.Errorf("constructing digest scheduler %w", err)
+	}
+	err = ds.Register(q)
+	if err != nil {
+		return fmt.Errorf("scheduling digests %w", err)
+	}
+	// Followers of a company hear about each job it posts
+	fn, err := alerts.NewFollowNotifier(ms, mailer, q, publicURL())
+	if err != nil {
+		return fmt.Errorf("constructing follow notifier %w", err)
+	}
+	// Scheduled jobs are published and expired ones taken down every minute
+	err = lifecycle.NewSweeper(ms).Register(q)
+	if err != nil {
+		return fmt.Errorf("scheduling job sweeps %w", err)
+	}
+	// Salary insights convert currencies with a configured table of rates to the base currency
+	rates, err := salary.ParseRates(os.Getenv("SALARY_RATES"), os.Getenv("SALARY_BASE_CURRENCY"))
+	if err != nil {
+		return fmt.Errorf("parsing salary exchange rates %w", err)
+	}
+	minSamples, err := strconv.Atoi(os.Getenv("SALARY_MIN_SAMPLES"))
+	if err != nil {
+		minSamples = salary.DefaultMinSamples
+	}
+	sr := salary.NewReporter(ms, rates, minSamples)
+	// Job activity is rolled up into the daily stats analytics are built from
+	err = analytics.NewRoller(ms).Register(q)
+	if err != nil {
+		return fmt.Errorf("scheduling analytics rollups %w", err)
+	}
+	// Domain events leave the outbox through the relay; in-process subscribers react to them
+	bus := events.NewBus()
+	bus.Subscribe(models.EventJobPosted, func(ctx context.Context, e models.OutboxEvent) error {
+		return me.JobChanged(ctx, e.AggregateID)
+	})
+	bus.Subscribe(models.EventJobPosted, func(ctx context.Context, e models.OutboxEvent) error {
+		return fn.JobPosted(ctx, e.AggregateID)
+	})
+	bus.Subscribe(models.EventJobClosed, func(ctx context.Context, e models.OutboxEvent) error {
+		return me.JobChanged(ctx, e.AggregateID)
+	})
+	wh, err := webhooks.NewDispatcher(ms, q, nil)
+	if err != nil {
+		return fmt.Errorf("constructing webhook dispatcher %w", err)
+	}
+	relay, err := events.NewRelay(ms, events.LogSink{}, bus, wh)
+	if err != nil {
+		return fmt.Errorf("constructing event relay %w", err)
+	}
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer func() {
+		stopWorkers()
+		relay.Wait()
+		q.Wait()
+	}()
+	q.Run(workerCtx)
+	relay.Run(workerCtx, time.Second)
+	err = me.ScheduleRescore(workerCtx)
+	if err != nil {
+		return fmt.Errorf("scheduling match rescoring %w", err)
+	}
+
 	// Initialize http service
+	hub := messaging.NewHub()
+	vs, err := openVisitors()
+	if err != nil {
+		return fmt.Errorf("constructing visitor hashing %w", err)
+	}
+	router := handlers.API(a, ms, bs, storage.NopScanner{}, rp, me, wh,
+		feed.NewRenderer(publicURL(), "Job Portal"), iv, ic, hub, of, sr, vs)
+	// TRUSTED_PROXIES lists the addresses or CIDRs of the reverse proxies whose X-Forwarded-For is believed
+	err = router.SetTrustedProxies(trustedProxies())
+	if err != nil {
+		return fmt.Errorf("setting trusted proxies %w", err)
+	}
 	api := http.Server{
 		Addr:         ":8080",
 		ReadTimeout:  8000 * time.Second,
 		WriteTimeout: 800 * time.Second,
 		IdleTimeout:  800 * time.Second,
-		Handler:      handlers.API(a, ms),
+		Handler:      router,
 	}
+	// Shutdown does not cancel the requests in flight, so open message streams are ended by the hub
+	api.RegisterOnShutdown(hub.Close)
 
 	// channel to store any errors while setting up the service
 	serverErrors := make(chan error, 1)
@@ -118,3 +268,74 @@ func startApp() error {
 	}
 	return nil
 }
+
+// openBlobStore picks the blob store from the environment.
+// BLOB_STORE=s3 talks to an S3 compatible service, anything else keeps files on local disk.
+func openBlobStore() (storage.BlobStore, error) {
+	if os.Getenv("BLOB_STORE") == "s3" {
+		return storage.NewS3Store(storage.S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+			PathStyle: os.Getenv("S3_PATH_STYLE") == "true",
+		}, &http.Client{Timeout: time.Minute})
+	}
+
+	secret := []byte(os.Getenv("BLOB_SIGNING_SECRET"))
+	if len(secret) == 0 {
+		// Links signed with a random secret stop working when the process restarts
+		log.Warn().Msg("main : BLOB_SIGNING_SECRET not set, using a random secret")
+		secret = make([]byte, 32)
+		_, err := rand.Read(secret)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return storage.NewLocalStore("uploads", publicURL()+"/files", secret)
+}
+
+// openVisitors hashes anonymous visitors with VISITOR_SECRET, or with a random secret when it is not set.
+func openVisitors() (*analytics.Visitors, error) {
+	secret := []byte(os.Getenv("VISITOR_SECRET"))
+	if len(secret) == 0 {
+		// A random secret counts a visitor again after the process restarts
+		log.Warn().Msg("main : VISITOR_SECRET not set, using a random secret")
+		secret = make([]byte, 32)
+		_, err := rand.Read(secret)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return analytics.NewVisitors(secret)
+}
+
+// openMailer sends mail through SMTP_ADDR when it is set, and only logs it otherwise.
+func openMailer() (mail.Mailer, error) {
+	addr := os.Getenv("SMTP_ADDR")
+	if addr == "" {
+		log.Warn().Msg("main : SMTP_ADDR not set, emails are only logged")
+		return mail.LogMailer{}, nil
+	}
+	return mail.NewSMTPMailer(addr, os.Getenv("SMTP_FROM"), os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"))
+}
+
+// trustedProxies reads the comma separated TRUSTED_PROXIES, none by default.
+func trustedProxies() []string {
+	var proxies []string
+	for _, p := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			proxies = append(proxies, p)
+		}
+	}
+	return proxies
+}
+
+// publicURL is where users reach the API, used for links in emails and signed downloads.
+func publicURL() string {
+	if u := os.Getenv("PUBLIC_URL"); u != "" {
+		return strings.TrimRight(u, "/")
+	}
+	return "http://localhost:8080"
+}
@@ -0,0 +1,187 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"job-portal-api/internal/mail"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/queue"
+)
+
+// batchSize is how many due searches are loaded at once.
+const batchSize = 500
+
+// Store is the part of the data layer the scheduler needs.
+type Store interface {
+	DueSavedSearches(ctx context.Context, now time.Time, limit int) ([]models.SavedSearch, error)
+	JobsPublishedBetween(ctx context.Context, from, to time.Time) ([]models.Job, error)
+	SentJobIDs(ctx context.Context, userId uint, jobIds []uint) ([]uint, error)
+	RecordDigest(ctx context.Context, userId uint, jobIds []uint, searches []models.SavedSearch, runAt time.Time) error
+	ViewUser(ctx context.Context, userId uint) (models.User, error)
+}
+
+// Scheduler mails the digests of saved searches.
+// Each user gets one email per run covering all their due searches, and never hears about a job twice.
+// A digest that fails to send is retried on the next run.
+type Scheduler struct {
+	store   Store
+	mailer  mail.Mailer
+	baseURL string
+	now     func() time.Time
+}
+
+// NewScheduler returns a scheduler linking to the API at baseURL. Call Register to run it.
+func NewScheduler(store Store, mailer mail.Mailer, baseURL string) (*Scheduler, error) {
+	if store == nil || mailer == nil {
+		return nil, errors.New("store and mailer cannot be nil")
+	}
+	return &Scheduler{store: store, mailer: mailer, baseURL: strings.TrimRight(baseURL, "/"), now: time.Now}, nil
+}
+
+// KindDigest is the queue task sending the due digests.
+const KindDigest = "alerts.digest"
+
+// Register runs the digests on q, checking for due ones every 15 minutes.
+func (s *Scheduler) Register(q *queue.Queue) error {
+	q.Handle(KindDigest, func(ctx context.Context, _ json.RawMessage) error {
+		return s.RunOnce(ctx)
+	})
+	return q.Schedule("digests", "*/15 * * * *", KindDigest, nil)
+}
+
+// RunOnce sends every digest that is due.
+func (s *Scheduler) RunOnce(ctx context.Context) error {
+	runAt := s.now().UTC()
+	for {
+		due, err := s.store.DueSavedSearches(ctx, runAt, batchSize)
+		if err != nil {
+			return err
+		}
+		sent := 0
+		for start := 0; start < len(due); {
+			end := start + 1
+			for end < len(due) && due[end].UserID == due[start].UserID {
+				end++
+			}
+			err := s.sendDigest(ctx, due[start].UserID, due[start:end], runAt)
+			if err != nil {
+				log.Error().Err(err).Uint("user", due[start].UserID).Msg("sending search digest")
+			} else {
+				sent++
+			}
+			start = end
+		}
+		// Stop when everything due was handled, or when only failing digests are left
+		if len(due) < batchSize || sent == 0 {
+			return nil
+		}
+	}
+}
+
+type section struct {
+	search models.SavedSearch
+	jobs   []models.Job
+}
+
+func (s *Scheduler) sendDigest(ctx context.Context, userId uint, searches []models.SavedSearch, runAt time.Time) error {
+	from := searches[0].LastRunAt
+	for _, ss := range searches {
+		if ss.LastRunAt.Before(from) {
+			from = ss.LastRunAt
+		}
+	}
+	jobs, err := s.store.JobsPublishedBetween(ctx, from, runAt)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]uint, 0, len(jobs))
+	for _, j := range jobs {
+		ids = append(ids, j.ID)
+	}
+	already, err := s.store.SentJobIDs(ctx, userId, ids)
+	if err != nil {
+		return err
+	}
+	seen := make(map[uint]bool, len(already))
+	for _, id := range already {
+		seen[id] = true
+	}
+
+	var sections []section
+	var newIds []uint
+	for _, ss := range searches {
+		sec := section{search: ss}
+		for _, j := range jobs {
+			if seen[j.ID] || j.PublishedAt == nil || !j.PublishedAt.After(ss.LastRunAt) || !Matches(ss, j) {
+				continue
+			}
+			// A job matching several searches is listed under the first one only
+			seen[j.ID] = true
+			sec.jobs = append(sec.jobs, j)
+			newIds = append(newIds, j.ID)
+		}
+		if len(sec.jobs) > 0 {
+			sections = append(sections, sec)
+		}
+	}
+
+	if len(sections) > 0 {
+		u, err := s.store.ViewUser(ctx, userId)
+		if err != nil {
+			return err
+		}
+		err = s.mailer.Send(ctx, s.compose(u, sections))
+		if err != nil {
+			return err
+		}
+	}
+	return s.store.RecordDigest(ctx, userId, newIds, searches, runAt)
+}
+
+func (s *Scheduler) compose(u models.User, sections []section) mail.Message {
+	total := 0
+	for _, sec := range sections {
+		total += len(sec.jobs)
+	}
+	subject := fmt.Sprintf("%d new jobs for your saved searches", total)
+	if len(sections) == 1 {
+		subject = fmt.Sprintf("%d new jobs for %q", total, sections[0].search.Name)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Hi %s,\n\nThese jobs were posted since your last digest.\n", u.Name)
+	for _, sec := range sections {
+		fmt.Fprintf(&b, "\n%s\n", sec.search.Name)
+		for _, j := range sec.jobs {
+			fmt.Fprintf(&b, "  - %s", j.Title)
+			if j.Location != "" {
+				fmt.Fprintf(&b, " (%s)", j.Location)
+			}
+			fmt.Fprintf(&b, "\n    %s/viewjobbyid/%d/jobs\n", s.baseURL, j.ID)
+		}
+		fmt.Fprintf(&b, "  Stop these alerts: %s\n", s.unsubscribeURL(sec.search))
+	}
+
+	m := mail.Message{To: u.Email, Subject: subject, Body: b.String()}
+	if len(sections) == 1 {
+		// One-click unsubscribe (RFC 8058) has mail clients POST to the link
+		m.Headers = map[string]string{
+			"List-Unsubscribe":      "<" + s.unsubscribeURL(sections[0].search) + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		}
+	}
+	return m
+}
+
+func (s *Scheduler) unsubscribeURL(ss models.SavedSearch) string {
+	return s.baseURL + "/saved-searches/unsubscribe?token=" + url.QueryEscape(ss.UnsubscribeToken)
+}
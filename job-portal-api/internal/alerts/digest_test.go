@@ -0,0 +1,163 @@
+package alerts
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"job-portal-api/internal/mail"
+	"job-portal-api/internal/models"
+)
+
+type fakeStore struct {
+	searches []models.SavedSearch
+	jobs     []models.Job
+	sent     map[uint]bool
+	recorded []uint
+}
+
+func (f *fakeStore) DueSavedSearches(ctx context.Context, now time.Time, limit int) ([]models.SavedSearch, error) {
+	var due []models.SavedSearch
+	for _, ss := range f.searches {
+		if ss.Active && !ss.NextRunAt.After(now) {
+			due = append(due, ss)
+		}
+	}
+	return due, nil
+}
+
+func (f *fakeStore) JobsPublishedBetween(ctx context.Context, from, to time.Time) ([]models.Job, error) {
+	var jobs []models.Job
+	for _, j := range f.jobs {
+		if j.PublishedAt.After(from) && !j.PublishedAt.After(to) {
+			jobs = append(jobs, j)
+		}
+	}
+	return jobs, nil
+}
+
+func (f *fakeStore) SentJobIDs(ctx context.Context, userId uint, jobIds []uint) ([]uint, error) {
+	var ids []uint
+	for _, id := range jobIds {
+		if f.sent[id] {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (f *fakeStore) RecordDigest(ctx context.Context, userId uint, jobIds []uint, searches []models.SavedSearch, runAt time.Time) error {
+	for _, id := range jobIds {
+		f.sent[id] = true
+	}
+	f.recorded = append(f.recorded, jobIds...)
+	for _, done := range searches {
+		for i := range f.searches {
+			if f.searches[i].ID == done.ID {
+				f.searches[i].LastRunAt = runAt
+				f.searches[i].NextRunAt = runAt.Add(models.Period(done.Frequency))
+			}
+		}
+	}
+	return nil
+}
+
+func (f *fakeStore) ViewUser(ctx context.Context, userId uint) (models.User, error) {
+	return models.User{Model: gorm.Model{ID: userId}, Name: "Asha", Email: "asha@example.com"}, nil
+}
+
+type recordingMailer struct {
+	sent []mail.Message
+}
+
+func (r *recordingMailer) Send(ctx context.Context, m mail.Message) error {
+	r.sent = append(r.sent, m)
+	return nil
+}
+
+func TestScheduler_RunOnce(t *testing.T) {
+	start := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	job := func(id uint, title string, skills []string, published time.Time) models.Job {
+		return models.Job{Model: gorm.Model{ID: id}, Title: title, Skills: skills, Location: "Pune",
+			Status: models.JobPublished, PublishedAt: &published}
+	}
+	store := &fakeStore{
+		searches: []models.SavedSearch{
+			{Model: gorm.Model{ID: 1}, UserID: 7, Name: "Go jobs", Keywords: "engineer", Skills: []string{"go"},
+				Frequency: models.FrequencyDaily, Active: true, LastRunAt: start, NextRunAt: start.Add(24 * time.Hour), UnsubscribeToken: "tok1"},
+			{Model: gorm.Model{ID: 2}, UserID: 7, Name: "Pune", Location: "pune",
+				Frequency: models.FrequencyDaily, Active: true, LastRunAt: start, NextRunAt: start.Add(24 * time.Hour), UnsubscribeToken: "tok2"},
+		},
+		jobs: []models.Job{
+			job(10, "Backend Engineer", []string{"Go"}, start.Add(-time.Hour)), // before the search was saved
+			job(11, "Backend Engineer", []string{"Go"}, start.Add(time.Hour)),
+			job(12, "Data Engineer", []string{"Python"}, start.Add(2*time.Hour)),
+			job(13, "Platform Engineer", []string{"Go"}, start.Add(3*time.Hour)),
+		},
+		sent: map[uint]bool{13: true},
+	}
+	mailer := &recordingMailer{}
+	s, err := NewScheduler(store, mailer, "https://jobs.example.com/")
+	require.NoError(t, err)
+
+	// Nothing is due yet
+	s.now = func() time.Time { return start.Add(12 * time.Hour) }
+	require.NoError(t, s.RunOnce(context.Background()))
+	require.Empty(t, mailer.sent)
+
+	s.now = func() time.Time { return start.Add(25 * time.Hour) }
+	require.NoError(t, s.RunOnce(context.Background()))
+	require.Len(t, mailer.sent, 1)
+	m := mailer.sent[0]
+	require.Equal(t, "asha@example.com", m.To)
+	require.Equal(t, "2 new jobs for your saved searches", m.Subject)
+	// Job 11 matches both searches but is listed once, job 13 was sent before
+	require.Equal(t, 1, strings.Count(m.Body, "/viewjobbyid/11/jobs"))
+	require.Contains(t, m.Body, "/viewjobbyid/12/jobs")
+	require.NotContains(t, m.Body, "/viewjobbyid/13/jobs")
+	require.NotContains(t, m.Body, "/viewjobbyid/10/jobs")
+	require.Contains(t, m.Body, "https://jobs.example.com/saved-searches/unsubscribe?token=tok1")
+	require.Contains(t, m.Body, "https://jobs.example.com/saved-searches/unsubscribe?token=tok2")
+	require.ElementsMatch(t, []uint{11, 12}, store.recorded)
+
+	// The next day brings nothing new, so no email
+	s.now = func() time.Time { return start.Add(49 * time.Hour) }
+	require.NoError(t, s.RunOnce(context.Background()))
+	require.Len(t, mailer.sent, 1)
+}
+
+func TestMatches(t *testing.T) {
+	job := models.Job{
+		Title:           "Senior Go Developer",
+		Location:        "Bengaluru, India",
+		ExperienceLevel: "Senior",
+		Skills:          []string{"go", "kubernetes"},
+		SalaryMin:       100,
+		SalaryMax:       200,
+	}
+	tt := []struct {
+		name string
+		ss   models.SavedSearch
+		want bool
+	}{
+		{name: "NoFilters", ss: models.SavedSearch{}, want: true},
+		{name: "Keywords", ss: models.SavedSearch{Keywords: "go kubernetes"}, want: true},
+		{name: "MissingKeyword", ss: models.SavedSearch{Keywords: "go rust"}, want: false},
+		{name: "Location", ss: models.SavedSearch{Location: "bengaluru"}, want: true},
+		{name: "OtherLocation", ss: models.SavedSearch{Location: "Pune"}, want: false},
+		{name: "Skills", ss: models.SavedSearch{Skills: []string{"Kubernetes"}}, want: true},
+		{name: "MissingSkill", ss: models.SavedSearch{Skills: []string{"go", "aws"}}, want: false},
+		{name: "Salary", ss: models.SavedSearch{SalaryMin: 150}, want: true},
+		{name: "SalaryTooLow", ss: models.SavedSearch{SalaryMin: 250}, want: false},
+		{name: "Level", ss: models.SavedSearch{ExperienceLevel: "junior"}, want: false},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, Matches(tc.ss, job))
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"job-portal-api/internal/mail"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/queue"
+)
+
+// KindFollowers is the queue task telling the followers of a company about a job it posted.
+const KindFollowers = "alerts.followers"
+
+// FollowStore is the part of the data layer the follow notifier needs.
+type FollowStore interface {
+	ViewJobByJobId(ctx context.Context, jobId uint, userId string) ([]models.Job, error)
+	ViewCompany(ctx context.Context, companyID uint, userId string) (models.Company, error)
+	CompanyFollowers(ctx context.Context, companyId uint) ([]models.User, error)
+}
+
+type followersTask struct {
+	JobID uint `json:"job_id"`
+}
+
+// FollowNotifier mails the followers of a company when it posts a job.
+type FollowNotifier struct {
+	store   FollowStore
+	mailer  mail.Mailer
+	q       *queue.Queue
+	baseURL string
+}
+
+// NewFollowNotifier returns a notifier linking to the API at baseURL and running its work on q.
+func NewFollowNotifier(store FollowStore, mailer mail.Mailer, q *queue.Queue, baseURL string) (*FollowNotifier, error) {
+	if store == nil || mailer == nil || q == nil {
+		return nil, errors.New("store, mailer and queue cannot be nil")
+	}
+	n := &FollowNotifier{store: store, mailer: mailer, q: q, baseURL: strings.TrimRight(baseURL, "/")}
+	q.Handle(KindFollowers, func(ctx context.Context, payload json.RawMessage) error {
+		var t followersTask
+		err := json.Unmarshal(payload, &t)
+		if err != nil {
+			return queue.Permanent(err)
+		}
+		return n.Notify(ctx, t.JobID)
+	})
+	return n, nil
+}
+
+// JobPosted schedules telling the followers about a posted job. Followers hear about a job once,
+// however often the event is delivered.
+func (n *FollowNotifier) JobPosted(ctx context.Context, jobId uint) error {
+	return n.q.Enqueue(ctx, KindFollowers, followersTask{JobID: jobId}, queue.Unique(fmt.Sprintf("company-followers:%d", jobId)))
+}
+
+// Notify mails every follower of the company that posted the job, unless the job is no longer listed.
+// A follower whose email cannot be handed to the mailer misses this job.
+func (n *FollowNotifier) Notify(ctx context.Context, jobId uint) error {
+	jobs, err := n.store.ViewJobByJobId(ctx, jobId, "")
+	if err != nil {
+		return err
+	}
+	if len(jobs) == 0 || jobs[0].Status != models.JobPublished {
+		return nil
+	}
+	job := jobs[0]
+	company, err := n.store.ViewCompany(ctx, job.CompanyID, "")
+	if err != nil {
+		return err
+	}
+	followers, err := n.store.CompanyFollowers(ctx, job.CompanyID)
+	if err != nil {
+		return err
+	}
+	for _, u := range followers {
+		err := n.mailer.Send(ctx, n.compose(job, company, u))
+		if err != nil {
+			log.Error().Err(err).Uint("user", u.ID).Uint("job", job.ID).Msg("notifying company follower")
+		}
+	}
+	return nil
+}
+
+func (n *FollowNotifier) compose(job models.Job, company models.Company, u models.User) mail.Message {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Hi %s,\n\n%s, which you follow, just posted a job:\n\n", u.Name, company.CompanyName)
+	fmt.Fprintf(&b, "  %s", job.Title)
+	if job.Location != "" {
+		fmt.Fprintf(&b, " (%s)", job.Location)
+	}
+	fmt.Fprintf(&b, "\n  %s/jobs/%d\n\n", n.baseURL, job.ID)
+	fmt.Fprintf(&b, "To stop hearing about %s's jobs, unfollow it:\n  DELETE %s/me/follows/%d\n",
+		company.CompanyName, n.baseURL, company.ID)
+	return mail.Message{
+		To:      u.Email,
+		Subject: fmt.Sprintf("%s posted a new job: %s", company.CompanyName, job.Title),
+		Body:    b.String(),
+	}
+}
@@ -0,0 +1,106 @@
+package alerts
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/queue"
+)
+
+// followStore holds two jobs of company 2, one of them closed, and the tasks queued for them.
+type followStore struct {
+	tasks []models.Task
+}
+
+func (f *followStore) ViewJobByJobId(ctx context.Context, jobId uint, userId string) ([]models.Job, error) {
+	jobs := map[uint]models.Job{
+		5: {Model: gorm.Model{ID: 5}, Title: "Go developer", Location: "Berlin", CompanyID: 2, Status: models.JobPublished},
+		6: {Model: gorm.Model{ID: 6}, Title: "Intern", CompanyID: 2, Status: models.JobClosed},
+	}
+	j, ok := jobs[jobId]
+	if !ok {
+		return nil, nil
+	}
+	return []models.Job{j}, nil
+}
+
+func (f *followStore) ViewCompany(ctx context.Context, companyID uint, userId string) (models.Company, error) {
+	return models.Company{Model: gorm.Model{ID: companyID}, CompanyName: "Acme"}, nil
+}
+
+func (f *followStore) CompanyFollowers(ctx context.Context, companyId uint) ([]models.User, error) {
+	return []models.User{
+		{Model: gorm.Model{ID: 8}, Name: "Ben", Email: "ben@example.com"},
+		{Model: gorm.Model{ID: 9}, Name: "Asha", Email: "asha@example.com"},
+	}, nil
+}
+
+func (f *followStore) EnqueueTask(ctx context.Context, t models.Task) (models.Task, error) {
+	for _, old := range f.tasks {
+		if t.UniqueKey != nil && old.UniqueKey != nil && *old.UniqueKey == *t.UniqueKey {
+			return t, nil
+		}
+	}
+	f.tasks = append(f.tasks, t)
+	return t, nil
+}
+
+func (f *followStore) ClaimTasks(ctx context.Context, kinds []string, worker string, limit int, now time.Time) ([]models.Task, error) {
+	return nil, nil
+}
+func (f *followStore) FinishTask(ctx context.Context, taskId uint) error { return nil }
+func (f *followStore) RetryTask(ctx context.Context, taskId uint, runAt time.Time, lastErr string) error {
+	return nil
+}
+func (f *followStore) BuryTask(ctx context.Context, taskId uint, lastErr string) error { return nil }
+func (f *followStore) RequeueStaleTasks(ctx context.Context, lockedBefore time.Time) (int64, error) {
+	return 0, nil
+}
+func (f *followStore) PurgeFinishedTasks(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+func newFollowNotifier(t *testing.T) (*FollowNotifier, *followStore, *recordingMailer) {
+	store := &followStore{}
+	q, err := queue.New(store, queue.Options{})
+	require.NoError(t, err)
+	mailer := &recordingMailer{}
+	n, err := NewFollowNotifier(store, mailer, q, "https://jobs.example.com/")
+	require.NoError(t, err)
+	return n, store, mailer
+}
+
+func TestFollowNotifier_JobPosted(t *testing.T) {
+	n, store, _ := newFollowNotifier(t)
+
+	// The event may be delivered more than once
+	require.NoError(t, n.JobPosted(context.Background(), 5))
+	require.NoError(t, n.JobPosted(context.Background(), 5))
+	require.Len(t, store.tasks, 1)
+	require.Equal(t, KindFollowers, store.tasks[0].Kind)
+	require.JSONEq(t, `{"job_id":5}`, string(store.tasks[0].Payload))
+}
+
+func TestFollowNotifier_Notify(t *testing.T) {
+	n, _, mailer := newFollowNotifier(t)
+
+	require.NoError(t, n.Notify(context.Background(), 5))
+	require.Len(t, mailer.sent, 2)
+	m := mailer.sent[1]
+	require.Equal(t, "asha@example.com", m.To)
+	require.Equal(t, "Acme posted a new job: Go developer", m.Subject)
+	require.Contains(t, m.Body, "Hi Asha,\n\nAcme, which you follow, just posted a job:")
+	require.Contains(t, m.Body, "Go developer (Berlin)\n  https://jobs.example.com/jobs/5\n")
+	require.Contains(t, m.Body, "DELETE https://jobs.example.com/me/follows/2")
+
+	// Jobs taken down before the task ran, or gone, are not announced
+	for _, id := range []uint{6, 42} {
+		require.NoError(t, n.Notify(context.Background(), id))
+	}
+	require.Len(t, mailer.sent, 2)
+}
@@ -0,0 +1,51 @@
+package alerts
+
+import (
+	"strings"
+
+	"job-portal-api/internal/models"
+)
+
+// Matches reports whether a job satisfies every filter of a saved search.
+// Keywords must all appear in the title, description or skills of the job.
+func Matches(ss models.SavedSearch, job models.Job) bool {
+	if kw := strings.Fields(strings.ToLower(ss.Keywords)); len(kw) > 0 {
+		text := strings.ToLower(job.Title + " " + job.Description + " " + strings.Join(job.Skills, " "))
+		for _, w := range kw {
+			if !strings.Contains(text, w) {
+				return false
+			}
+		}
+	}
+	if ss.Location != "" && !containsFold(job.Location, ss.Location) {
+		return false
+	}
+	if ss.ExperienceLevel != "" && !containsFold(job.ExperienceLevel, ss.ExperienceLevel) {
+		return false
+	}
+	if len(ss.Skills) > 0 {
+		has := map[string]bool{}
+		for _, s := range models.NormalizeSkills(job.Skills) {
+			has[s] = true
+		}
+		for _, s := range models.NormalizeSkills(ss.Skills) {
+			if !has[s] {
+				return false
+			}
+		}
+	}
+	if ss.SalaryMin > 0 {
+		top := job.SalaryMax
+		if top == 0 {
+			top = job.SalaryMin
+		}
+		if job.AnnualSalary(top) < ss.SalaryMin {
+			return false
+		}
+	}
+	return true
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(strings.TrimSpace(substr)))
+}
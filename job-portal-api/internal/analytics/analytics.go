@@ -0,0 +1,198 @@
+// Package analytics reports how the jobs of a company perform: views, application starts and submissions
+// over time, how visitors convert through the funnel, and how long hiring takes. Reports are built from
+// the daily rollups of job activity, which the Roller keeps up to date.
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/queue"
+)
+
+// MaxPeriod is the longest period a report covers.
+const MaxPeriod = 366 * 24 * time.Hour
+
+// defaultDays is how many days, up to today, a report covers when no period is asked for.
+const defaultDays = 30
+
+// ErrInvalidPeriod is returned for periods that cannot be reported on.
+var ErrInvalidPeriod = errors.New("from and to must be days (YYYY-MM-DD), from not after to, at most 366 days apart, and interval day or week")
+
+// Store is the part of the data layer analytics needs.
+type Store interface {
+	JobStatSeries(ctx context.Context, scope models.StatScope, from, to time.Time, interval string) ([]models.StatPoint, error)
+	MedianTimeToHire(ctx context.Context, scope models.StatScope, from, to time.Time) (*float64, error)
+	MedianTimeInStage(ctx context.Context, scope models.StatScope, from, to time.Time) ([]models.StageTime, error)
+	RollupJobStats(ctx context.Context, since time.Time) (int64, error)
+}
+
+// Period is the days a report covers, from From up to but not including End, bucketed by Interval.
+type Period struct {
+	From     time.Time
+	End      time.Time
+	Interval string
+}
+
+// ParsePeriod reads the period asked for as from and to, both days included, and the interval.
+// Without from and to, the period is the last 30 days up to today; the interval defaults to day.
+func ParsePeriod(from, to, interval string, now time.Time) (Period, error) {
+	p := Period{Interval: interval}
+	if p.Interval == "" {
+		p.Interval = models.StatIntervalDay
+	}
+	if p.Interval != models.StatIntervalDay && p.Interval != models.StatIntervalWeek {
+		return Period{}, ErrInvalidPeriod
+	}
+	last := now.UTC().Truncate(24 * time.Hour)
+	if to != "" {
+		d, err := time.Parse(time.DateOnly, to)
+		if err != nil {
+			return Period{}, ErrInvalidPeriod
+		}
+		last = d
+	}
+	p.End = last.AddDate(0, 0, 1)
+	p.From = last.AddDate(0, 0, 1-defaultDays)
+	if from != "" {
+		d, err := time.Parse(time.DateOnly, from)
+		if err != nil {
+			return Period{}, ErrInvalidPeriod
+		}
+		p.From = d
+	}
+	if !p.From.Before(p.End) || p.End.Sub(p.From) > MaxPeriod {
+		return Period{}, ErrInvalidPeriod
+	}
+	return p, nil
+}
+
+// Funnel is how visitors move from seeing a job to being hired, as fractions of the previous step.
+// A rate is empty when nobody reached the previous step. Applications are counted whether or not
+// the client reported starting them, so StartToApply can exceed 1.
+type Funnel struct {
+	ViewToStart  *float64 `json:"view_to_start"`
+	StartToApply *float64 `json:"start_to_apply"`
+	ViewToApply  *float64 `json:"view_to_apply"`
+	ApplyToHire  *float64 `json:"apply_to_hire"`
+}
+
+// Report is the performance of a job, or of every job of a company, over a period.
+type Report struct {
+	From time.Time `json:"from"`
+	// To is the last day covered
+	To       time.Time          `json:"to"`
+	Interval string             `json:"interval"`
+	Totals   models.StatCounts  `json:"totals"`
+	Series   []models.StatPoint `json:"series"`
+	Funnel   Funnel             `json:"funnel"`
+	// MedianTimeToHireHours is taken over the hires of the period, and empty without any
+	MedianTimeToHireHours *float64 `json:"median_time_to_hire_hours"`
+	// TimeInStage is the median time applications spent in each stage they left during the period
+	TimeInStage []models.StageTime `json:"time_in_stage"`
+}
+
+// Build reports on the scope over the period. Every bucket of the period is in the series,
+// with zeros when nothing happened; weekly buckets start on Monday.
+func Build(ctx context.Context, store Store, scope models.StatScope, p Period) (Report, error) {
+	points, err := store.JobStatSeries(ctx, scope, p.From, p.End, p.Interval)
+	if err != nil {
+		return Report{}, err
+	}
+	r := Report{From: p.From, To: p.End.AddDate(0, 0, -1), Interval: p.Interval}
+	r.Series = fill(points, p)
+	for _, pt := range r.Series {
+		r.Totals.Views += pt.Views
+		r.Totals.ApplyStarts += pt.ApplyStarts
+		r.Totals.Applications += pt.Applications
+		r.Totals.StageMoves += pt.StageMoves
+		r.Totals.Hires += pt.Hires
+	}
+	r.Funnel = Funnel{
+		ViewToStart:  rate(r.Totals.ApplyStarts, r.Totals.Views),
+		StartToApply: rate(r.Totals.Applications, r.Totals.ApplyStarts),
+		ViewToApply:  rate(r.Totals.Applications, r.Totals.Views),
+		ApplyToHire:  rate(r.Totals.Hires, r.Totals.Applications),
+	}
+	r.MedianTimeToHireHours, err = store.MedianTimeToHire(ctx, scope, p.From, p.End)
+	if err != nil {
+		return Report{}, err
+	}
+	r.TimeInStage, err = store.MedianTimeInStage(ctx, scope, p.From, p.End)
+	if err != nil {
+		return Report{}, err
+	}
+	return r, nil
+}
+
+// bucket returns the start of the bucket day falls in.
+func bucket(day time.Time, interval string) time.Time {
+	day = day.UTC().Truncate(24 * time.Hour)
+	if interval == models.StatIntervalWeek {
+		// Monday is the first day of the week
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	}
+	return day
+}
+
+// fill returns a point for every bucket of the period, taking the counts of the matching point.
+func fill(points []models.StatPoint, p Period) []models.StatPoint {
+	byPeriod := make(map[time.Time]models.StatCounts, len(points))
+	for _, pt := range points {
+		byPeriod[bucket(pt.Period, p.Interval)] = pt.StatCounts
+	}
+	step := 1
+	if p.Interval == models.StatIntervalWeek {
+		step = 7
+	}
+	var series []models.StatPoint
+	for at := bucket(p.From, p.Interval); at.Before(p.End); at = at.AddDate(0, 0, step) {
+		series = append(series, models.StatPoint{Period: at, StatCounts: byPeriod[at]})
+	}
+	return series
+}
+
+func rate(n, of int64) *float64 {
+	if of == 0 {
+		return nil
+	}
+	r := float64(n) / float64(of)
+	return &r
+}
+
+// KindRollup is the queue task rolling job activity up into daily stats.
+const KindRollup = "analytics.rollup"
+
+// Roller keeps the daily stats reports are built from up to date.
+type Roller struct {
+	store Store
+	now   func() time.Time
+}
+
+// NewRoller returns a roller over store. Call Register to run it.
+func NewRoller(store Store) *Roller {
+	return &Roller{store: store, now: time.Now}
+}
+
+// Register runs the roller on q every 15 minutes, so reports lag activity by at most that long.
+func (r *Roller) Register(q *queue.Queue) error {
+	q.Handle(KindRollup, func(ctx context.Context, _ json.RawMessage) error {
+		return r.RunOnce(ctx)
+	})
+	return q.Schedule("analytics-rollup", "*/15 * * * *", KindRollup, nil)
+}
+
+// RunOnce rolls up today and yesterday, so activity recorded just before midnight is not missed.
+func (r *Roller) RunOnce(ctx context.Context) error {
+	n, err := r.store.RollupJobStats(ctx, r.now().UTC().AddDate(0, 0, -1))
+	if err != nil {
+		return err
+	}
+	log.Debug().Int64("days", n).Msg("rolled up job stats")
+	return nil
+}
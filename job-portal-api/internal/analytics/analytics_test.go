@@ -0,0 +1,159 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"job-portal-api/internal/models"
+)
+
+type fakeStore struct {
+	points []models.StatPoint
+	median *float64
+	stages []models.StageTime
+	err    error
+
+	scope    models.StatScope
+	from, to time.Time
+	interval string
+	since    []time.Time
+}
+
+func (f *fakeStore) JobStatSeries(ctx context.Context, scope models.StatScope, from, to time.Time, interval string) ([]models.StatPoint, error) {
+	f.scope, f.from, f.to, f.interval = scope, from, to, interval
+	return f.points, f.err
+}
+
+func (f *fakeStore) MedianTimeToHire(ctx context.Context, scope models.StatScope, from, to time.Time) (*float64, error) {
+	return f.median, nil
+}
+
+func (f *fakeStore) MedianTimeInStage(ctx context.Context, scope models.StatScope, from, to time.Time) ([]models.StageTime, error) {
+	return f.stages, nil
+}
+
+func (f *fakeStore) RollupJobStats(ctx context.Context, since time.Time) (int64, error) {
+	f.since = append(f.since, since)
+	return 3, f.err
+}
+
+func day(s string) time.Time {
+	d, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestParsePeriod(t *testing.T) {
+	now := time.Date(2024, 3, 20, 15, 4, 0, 0, time.UTC)
+	tt := []struct {
+		name               string
+		from, to, interval string
+		wantFrom, wantEnd  string
+		wantInterval       string
+		wantErr            bool
+	}{
+		{name: "Default", wantFrom: "2024-02-20", wantEnd: "2024-03-21", wantInterval: "day"},
+		{name: "Range", from: "2024-01-01", to: "2024-01-31", interval: "week",
+			wantFrom: "2024-01-01", wantEnd: "2024-02-01", wantInterval: "week"},
+		{name: "OneDay", from: "2024-01-01", to: "2024-01-01", wantFrom: "2024-01-01", wantEnd: "2024-01-02", wantInterval: "day"},
+		{name: "Fail_Reversed", from: "2024-02-01", to: "2024-01-01", wantErr: true},
+		{name: "Fail_TooLong", from: "2023-01-01", to: "2024-01-02", wantErr: true},
+		{name: "Fail_BadDay", from: "yesterday", wantErr: true},
+		{name: "Fail_BadInterval", interval: "month", wantErr: true},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := ParsePeriod(tc.from, tc.to, tc.interval, now)
+			if tc.wantErr {
+				require.ErrorIs(t, err, ErrInvalidPeriod)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, day(tc.wantFrom), p.From)
+			require.Equal(t, day(tc.wantEnd), p.End)
+			require.Equal(t, tc.wantInterval, p.Interval)
+		})
+	}
+}
+
+func TestBuild(t *testing.T) {
+	median := 36.5
+	store := &fakeStore{
+		points: []models.StatPoint{
+			{Period: day("2024-03-02"), StatCounts: models.StatCounts{Views: 40, ApplyStarts: 10, Applications: 4}},
+			{Period: day("2024-03-04"), StatCounts: models.StatCounts{Views: 60, ApplyStarts: 10, Applications: 6, Hires: 1}},
+		},
+		median: &median,
+		stages: []models.StageTime{{StageID: 3, Name: "Screening", MedianHours: 20, Samples: 4}},
+	}
+	scope := models.StatScope{CompanyID: 2, JobID: 5}
+	p := Period{From: day("2024-03-01"), End: day("2024-03-05"), Interval: models.StatIntervalDay}
+
+	r, err := Build(context.Background(), store, scope, p)
+	require.NoError(t, err)
+	require.Equal(t, scope, store.scope)
+	require.Equal(t, day("2024-03-04"), r.To)
+
+	// Days without activity are in the series too
+	require.Len(t, r.Series, 4)
+	require.Equal(t, day("2024-03-01"), r.Series[0].Period)
+	require.Zero(t, r.Series[0].Views)
+	require.Equal(t, int64(40), r.Series[1].Views)
+	require.Zero(t, r.Series[2].Views)
+	require.Equal(t, int64(60), r.Series[3].Views)
+
+	require.Equal(t, models.StatCounts{Views: 100, ApplyStarts: 20, Applications: 10, Hires: 1}, r.Totals)
+	require.InDelta(t, 0.2, *r.Funnel.ViewToStart, 1e-9)
+	require.InDelta(t, 0.5, *r.Funnel.StartToApply, 1e-9)
+	require.InDelta(t, 0.1, *r.Funnel.ViewToApply, 1e-9)
+	require.InDelta(t, 0.1, *r.Funnel.ApplyToHire, 1e-9)
+	require.Equal(t, &median, r.MedianTimeToHireHours)
+	require.Equal(t, store.stages, r.TimeInStage)
+}
+
+func TestBuildWeekly(t *testing.T) {
+	// 2024-03-06 is a Wednesday, so the first week starts on Monday 2024-03-04
+	store := &fakeStore{points: []models.StatPoint{
+		{Period: day("2024-03-11"), StatCounts: models.StatCounts{Views: 7}},
+	}}
+	p := Period{From: day("2024-03-06"), End: day("2024-03-20"), Interval: models.StatIntervalWeek}
+
+	r, err := Build(context.Background(), store, models.StatScope{CompanyID: 2}, p)
+	require.NoError(t, err)
+	require.Equal(t, models.StatIntervalWeek, store.interval)
+	require.Len(t, r.Series, 3)
+	require.Equal(t, day("2024-03-04"), r.Series[0].Period)
+	require.Equal(t, day("2024-03-11"), r.Series[1].Period)
+	require.Equal(t, int64(7), r.Series[1].Views)
+	require.Equal(t, day("2024-03-18"), r.Series[2].Period)
+
+	// Nothing to convert from leaves the rates empty
+	require.Nil(t, r.Funnel.ApplyToHire)
+	require.Nil(t, r.MedianTimeToHireHours)
+}
+
+func TestBuildFails(t *testing.T) {
+	store := &fakeStore{err: errors.New("db down")}
+	_, err := Build(context.Background(), store, models.StatScope{CompanyID: 2}, Period{
+		From: day("2024-03-01"), End: day("2024-03-02"), Interval: models.StatIntervalDay,
+	})
+	require.Error(t, err)
+}
+
+func TestRollerRunOnce(t *testing.T) {
+	store := &fakeStore{}
+	r := NewRoller(store)
+	r.now = func() time.Time { return time.Date(2024, 3, 20, 0, 5, 0, 0, time.UTC) }
+
+	require.NoError(t, r.RunOnce(context.Background()))
+	require.Equal(t, []time.Time{time.Date(2024, 3, 19, 0, 5, 0, 0, time.UTC)}, store.since)
+
+	store.err = errors.New("db down")
+	require.Error(t, r.RunOnce(context.Background()))
+}
@@ -0,0 +1,33 @@
+package analytics
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// Visitors tells anonymous visitors apart by their address and browser without keeping either.
+// Both are hashed with a key derived from a server secret and the day: without the secret the hashes
+// cannot be brute-forced, and a visitor cannot be followed from one day to the next.
+type Visitors struct {
+	secret []byte
+}
+
+// NewVisitors returns visitors hashed with keys derived from secret.
+func NewVisitors(secret []byte) (*Visitors, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("visitor secret cannot be empty")
+	}
+	return &Visitors{secret: secret}, nil
+}
+
+// Anonymous returns the id of the visitor on the day of now.
+func (v *Visitors) Anonymous(ip, userAgent string, now time.Time) string {
+	day := hmac.New(sha256.New, v.secret)
+	day.Write([]byte(now.UTC().Format(time.DateOnly)))
+	mac := hmac.New(sha256.New, day.Sum(nil))
+	mac.Write([]byte(ip + "|" + userAgent))
+	return "anon:" + hex.EncodeToString(mac.Sum(nil))
+}
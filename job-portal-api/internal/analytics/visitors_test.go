@@ -0,0 +1,28 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVisitors(t *testing.T) {
+	_, err := NewVisitors(nil)
+	require.Error(t, err)
+
+	v, err := NewVisitors([]byte("secret"))
+	require.NoError(t, err)
+	other, err := NewVisitors([]byte("another secret"))
+	require.NoError(t, err)
+	morning := time.Date(2024, 3, 20, 8, 0, 0, 0, time.UTC)
+
+	id := v.Anonymous("203.0.113.7", "Firefox", morning)
+	require.Equal(t, id, v.Anonymous("203.0.113.7", "Firefox", morning.Add(10*time.Hour)))
+	require.NotEqual(t, id, v.Anonymous("203.0.113.8", "Firefox", morning))
+	require.NotEqual(t, id, v.Anonymous("203.0.113.7", "Chrome", morning))
+	// Ids change every day and with the secret
+	require.NotEqual(t, id, v.Anonymous("203.0.113.7", "Firefox", morning.Add(24*time.Hour)))
+	require.NotEqual(t, id, other.Anonymous("203.0.113.7", "Firefox", morning))
+	require.NotContains(t, id, "203.0.113.7")
+}
@@ -0,0 +1,140 @@
+// Package calendar writes iCalendar files (RFC 5545), so interviews land in the calendar apps
+// of candidates and recruiters, either as email invitations or through a subscribed feed.
+package calendar
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Methods of an iCalendar object (RFC 5546). Invitations sent by email use Request and Cancel,
+// feeds leave the method out.
+const (
+	MethodRequest = "REQUEST"
+	MethodCancel  = "CANCEL"
+)
+
+// ContentType is the media type of iCalendar files.
+const ContentType = "text/calendar; charset=utf-8"
+
+// Person is the organizer or an attendee of an event.
+type Person struct {
+	Name  string
+	Email string
+}
+
+// Event is a single meeting. Times are written in UTC, calendar apps show them in their own zone.
+type Event struct {
+	// UID identifies the event across updates; Sequence counts the updates.
+	UID       string
+	Sequence  int
+	Start     time.Time
+	End       time.Time
+	Summary   string
+	Location  string
+	Details   string
+	Organizer Person
+	Attendees []Person
+	Cancelled bool
+	// Stamp is when the event was last changed.
+	Stamp time.Time
+}
+
+// Calendar is a set of events, with a name shown by apps subscribing to it.
+type Calendar struct {
+	Name   string
+	Method string
+	Events []Event
+}
+
+// Bytes renders the calendar.
+func (c Calendar) Bytes() []byte {
+	var w writer
+	w.line("BEGIN:VCALENDAR")
+	w.line("VERSION:2.0")
+	w.line("PRODID:-//Job Portal//Interviews//EN")
+	w.line("CALSCALE:GREGORIAN")
+	if c.Method != "" {
+		w.line("METHOD:" + c.Method)
+	}
+	if c.Name != "" {
+		w.line("X-WR-CALNAME:" + escape(c.Name))
+	}
+	for _, e := range c.Events {
+		w.event(e)
+	}
+	w.line("END:VCALENDAR")
+	return w.Bytes()
+}
+
+type writer struct {
+	bytes.Buffer
+}
+
+func (w *writer) event(e Event) {
+	w.line("BEGIN:VEVENT")
+	w.line("UID:" + escape(e.UID))
+	w.line(fmt.Sprintf("SEQUENCE:%d", e.Sequence))
+	w.line("DTSTAMP:" + stamp(e.Stamp))
+	w.line("DTSTART:" + stamp(e.Start))
+	w.line("DTEND:" + stamp(e.End))
+	w.line("SUMMARY:" + escape(e.Summary))
+	if e.Location != "" {
+		w.line("LOCATION:" + escape(e.Location))
+	}
+	if e.Details != "" {
+		w.line("DESCRIPTION:" + escape(e.Details))
+	}
+	if e.Organizer.Email != "" {
+		w.line("ORGANIZER" + commonName(e.Organizer) + ":mailto:" + e.Organizer.Email)
+	}
+	for _, a := range e.Attendees {
+		w.line("ATTENDEE" + commonName(a) + ";ROLE=REQ-PARTICIPANT:mailto:" + a.Email)
+	}
+	if e.Cancelled {
+		w.line("STATUS:CANCELLED")
+	} else {
+		w.line("STATUS:CONFIRMED")
+	}
+	w.line("END:VEVENT")
+}
+
+// line writes a content line, folded so no line is longer than 75 octets.
+// Continuation lines start with a space and UTF-8 sequences are never split.
+func (w *writer) line(s string) {
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		w.WriteString(s[:cut])
+		w.WriteString("\r\n ")
+		s = s[cut:]
+		// The leading space counts towards the next line
+		limit = 74
+	}
+	w.WriteString(s)
+	w.WriteString("\r\n")
+}
+
+func stamp(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// escape escapes a TEXT value.
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", "").Replace(s)
+}
+
+// commonName is the CN parameter naming a person. Parameter values cannot contain quotes.
+func commonName(p Person) string {
+	name := strings.NewReplacer(`"`, "", "\r", "", "\n", " ").Replace(p.Name)
+	if name == "" {
+		return ""
+	}
+	return `;CN="` + name + `"`
+}
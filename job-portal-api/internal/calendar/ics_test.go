@@ -0,0 +1,62 @@
+package calendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalendar_Bytes(t *testing.T) {
+	start := time.Date(2026, 11, 2, 10, 0, 0, 0, time.FixedZone("CET", 3600))
+	c := Calendar{
+		Method: MethodRequest,
+		Events: []Event{{
+			UID:       "interview-7@jobs.example.com",
+			Sequence:  2,
+			Start:     start,
+			End:       start.Add(45 * time.Minute),
+			Summary:   "Interview: Go developer, Acme",
+			Location:  "Room 4; second floor",
+			Details:   "Bring your portfolio.\nAsk for Zoë at the front desk — she will take you up to the fourth floor meeting rooms.",
+			Organizer: Person{Name: `Ravi "R" Kumar`, Email: "ravi@acme.example"},
+			Attendees: []Person{{Name: "Asha", Email: "asha@example.com"}},
+			Stamp:     start.Add(-24 * time.Hour),
+		}},
+	}
+	out := string(c.Bytes())
+
+	require.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	require.True(t, strings.HasSuffix(out, "END:VCALENDAR\r\n"))
+	require.Contains(t, out, "METHOD:REQUEST\r\n")
+	require.Contains(t, out, "DTSTART:20261102T090000Z\r\n")
+	require.Contains(t, out, "DTEND:20261102T094500Z\r\n")
+	require.Contains(t, out, "SEQUENCE:2\r\n")
+	require.Contains(t, out, `SUMMARY:Interview: Go developer\, Acme`)
+	require.Contains(t, out, `LOCATION:Room 4\; second floor`)
+	require.Contains(t, out, `ORGANIZER;CN="Ravi R Kumar":mailto:ravi@acme.example`)
+	require.Contains(t, out, "STATUS:CONFIRMED")
+
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		require.LessOrEqual(t, len(line), 75)
+		require.True(t, utf8.ValidString(line), line)
+	}
+	// Unfolding gives back the escaped description
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+	require.Contains(t, unfolded, `DESCRIPTION:Bring your portfolio.\nAsk for Zoë at the front desk — she will take you up to the fourth floor meeting rooms.`)
+}
+
+func TestCalendar_Cancelled(t *testing.T) {
+	start := time.Date(2026, 11, 2, 9, 0, 0, 0, time.UTC)
+	out := string(Calendar{
+		Name:   "Interviews",
+		Events: []Event{{UID: "interview-7@jobs.example.com", Start: start, End: start.Add(time.Hour), Cancelled: true}},
+	}.Bytes())
+
+	require.NotContains(t, out, "METHOD:")
+	require.Contains(t, out, "X-WR-CALNAME:Interviews\r\n")
+	require.Contains(t, out, "STATUS:CANCELLED\r\n")
+	require.NotContains(t, out, "ORGANIZER")
+}
@@ -0,0 +1,106 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/queue"
+)
+
+// batchSize is how many events one relay round publishes at most.
+const batchSize = 100
+
+// retention is how long published events are kept around.
+const retention = 7 * 24 * time.Hour
+
+// retry gives up on an event after 10 attempts, waiting from 5s up to an hour between them.
+var retry = models.EventRetry{
+	MaxAttempts: 10,
+	Backoff: func(attempt int) time.Duration {
+		return queue.Backoff(attempt, 5*time.Second, time.Hour)
+	},
+}
+
+// Store is the part of the data layer the relay needs.
+type Store interface {
+	RelayEvents(ctx context.Context, limit int, retry models.EventRetry, publish func(e models.OutboxEvent) error) (int, error)
+	PurgePublishedEvents(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Relay publishes the events of the outbox to the sinks.
+// An event counts as published once every sink accepted it; until then it is retried with a backoff,
+// and later events of the same aggregate are held back, until it is given up on as dead.
+type Relay struct {
+	store Store
+	sinks []Sink
+	wg    sync.WaitGroup
+}
+
+// NewRelay returns a relay publishing to the given sinks. Call Run to start it.
+func NewRelay(store Store, sinks ...Sink) (*Relay, error) {
+	if store == nil {
+		return nil, errors.New("store cannot be nil")
+	}
+	return &Relay{store: store, sinks: sinks}, nil
+}
+
+// Run relays events every interval until ctx is cancelled; Wait blocks until it stopped.
+func (r *Relay) Run(ctx context.Context, interval time.Duration) {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		var purged time.Time
+		for {
+			n, err := r.RelayOnce(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Error().Err(err).Msg("relaying events")
+			}
+			if time.Since(purged) > time.Hour {
+				purged = time.Now()
+				_, err = r.store.PurgePublishedEvents(ctx, purged.Add(-retention))
+				if err != nil && ctx.Err() == nil {
+					log.Error().Err(err).Msg("purging published events")
+				}
+			}
+			// A full batch means there is probably more waiting
+			if n == batchSize {
+				continue
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+		}
+	}()
+}
+
+// Wait blocks until Run has returned.
+func (r *Relay) Wait() {
+	r.wg.Wait()
+}
+
+// RelayOnce publishes one batch of events and returns how many went out.
+func (r *Relay) RelayOnce(ctx context.Context) (int, error) {
+	return r.store.RelayEvents(ctx, batchSize, retry, func(e models.OutboxEvent) error {
+		var errs []error
+		for _, s := range r.sinks {
+			err := s.Publish(ctx, e)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%T: %w", s, err))
+			}
+		}
+		if len(errs) > 0 && e.Attempts+1 >= retry.MaxAttempts {
+			log.Error().Err(errors.Join(errs...)).Uint("event", e.ID).Str("type", e.Type).Msg("publishing event failed, giving up")
+		} else if len(errs) > 0 {
+			log.Warn().Err(errors.Join(errs...)).Uint("event", e.ID).Str("type", e.Type).Msg("publishing event failed")
+		}
+		return errors.Join(errs...)
+	})
+}
@@ -0,0 +1,149 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"job-portal-api/internal/models"
+)
+
+// fakeStore hands out its unpublished events like the Postgres store does, without waiting between attempts.
+type fakeStore struct {
+	events    []models.OutboxEvent
+	published map[uint]bool
+	dead      map[uint]bool
+}
+
+func (f *fakeStore) RelayEvents(ctx context.Context, limit int, retry models.EventRetry, publish func(e models.OutboxEvent) error) (int, error) {
+	n := 0
+	blocked := map[string]bool{}
+	for i, e := range f.events {
+		key := fmt.Sprintf("%s:%d", e.AggregateType, e.AggregateID)
+		if f.published[e.ID] || f.dead[e.ID] || blocked[key] {
+			continue
+		}
+		if publish(e) != nil {
+			blocked[key] = true
+			f.events[i].Attempts++
+			if f.events[i].Attempts >= retry.MaxAttempts {
+				f.dead[e.ID] = true
+			}
+			continue
+		}
+		f.published[e.ID] = true
+		n++
+	}
+	return n, nil
+}
+
+func (f *fakeStore) PurgePublishedEvents(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+// flakySink fails the first time it sees each listed event.
+type flakySink struct {
+	failOnce map[uint]bool
+	got      []uint
+}
+
+func (s *flakySink) Publish(ctx context.Context, e models.OutboxEvent) error {
+	if s.failOnce[e.ID] {
+		delete(s.failOnce, e.ID)
+		return errors.New("sink unavailable")
+	}
+	s.got = append(s.got, e.ID)
+	return nil
+}
+
+func TestRelay(t *testing.T) {
+	store := &fakeStore{
+		events: []models.OutboxEvent{
+			{ID: 1, Type: models.EventCompanyCreated, AggregateType: models.AggregateCompany, AggregateID: 1},
+			{ID: 2, Type: models.EventJobPosted, AggregateType: models.AggregateJob, AggregateID: 5},
+			{ID: 3, Type: models.EventApplicationSubmitted, AggregateType: models.AggregateApplication, AggregateID: 9},
+			{ID: 4, Type: "job.closed", AggregateType: models.AggregateJob, AggregateID: 5},
+		},
+		published: map[uint]bool{},
+		dead:      map[uint]bool{},
+	}
+	sink := &flakySink{failOnce: map[uint]bool{2: true}}
+
+	bus := NewBus()
+	var posted, all []uint
+	bus.Subscribe(models.EventJobPosted, func(ctx context.Context, e models.OutboxEvent) error {
+		posted = append(posted, e.AggregateID)
+		return nil
+	})
+	bus.Subscribe(All, func(ctx context.Context, e models.OutboxEvent) error {
+		all = append(all, e.ID)
+		return nil
+	})
+
+	r, err := NewRelay(store, bus, sink)
+	require.NoError(t, err)
+
+	// Event 2 fails, so event 4 of the same job has to wait for it
+	n, err := r.RelayOnce(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+	require.Equal(t, []uint{1, 3}, sink.got)
+
+	n, err = r.RelayOnce(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+	require.Equal(t, []uint{1, 3, 2, 4}, sink.got)
+
+	// The bus saw event 2 twice: delivery is at least once
+	require.Equal(t, []uint{5, 5}, posted)
+	require.Equal(t, []uint{1, 2, 3, 2, 4}, all)
+}
+
+// brokenSink fails every event of the listed ones.
+type brokenSink map[uint]bool
+
+func (s brokenSink) Publish(ctx context.Context, e models.OutboxEvent) error {
+	if s[e.ID] {
+		return errors.New("rejected")
+	}
+	return nil
+}
+
+func TestRelayGivesUp(t *testing.T) {
+	store := &fakeStore{
+		events: []models.OutboxEvent{
+			{ID: 1, Type: models.EventJobPosted, AggregateType: models.AggregateJob, AggregateID: 5},
+			{ID: 2, Type: models.EventJobClosed, AggregateType: models.AggregateJob, AggregateID: 5},
+		},
+		published: map[uint]bool{},
+		dead:      map[uint]bool{},
+	}
+	r, err := NewRelay(store, brokenSink{1: true})
+	require.NoError(t, err)
+
+	for i := 1; i < retry.MaxAttempts; i++ {
+		n, err := r.RelayOnce(context.Background())
+		require.NoError(t, err)
+		require.Zero(t, n, "attempt %d", i)
+	}
+	// Event 1 dies on its last attempt and stops holding event 2 back
+	n, err := r.RelayOnce(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, n)
+	require.True(t, store.dead[1])
+
+	n, err = r.RelayOnce(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.True(t, store.published[2])
+}
+
+func TestRetryBackoff(t *testing.T) {
+	require.GreaterOrEqual(t, retry.Backoff(1), 5*time.Second)
+	require.GreaterOrEqual(t, retry.Backoff(3), 20*time.Second)
+	require.LessOrEqual(t, retry.Backoff(retry.MaxAttempts), time.Hour+12*time.Minute)
+}
@@ -0,0 +1,65 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+
+	"job-portal-api/internal/models"
+)
+
+// Sink receives the events published by the relay.
+// Delivery is at least once, so a sink may see the same event again after a failure.
+type Sink interface {
+	Publish(ctx context.Context, e models.OutboxEvent) error
+}
+
+// LogSink writes every event to the log.
+type LogSink struct{}
+
+func (LogSink) Publish(ctx context.Context, e models.OutboxEvent) error {
+	log.Info().Uint("event", e.ID).Str("type", e.Type).Str("aggregate", e.AggregateType).
+		Uint("aggregate_id", e.AggregateID).Msg("domain event")
+	return nil
+}
+
+// Subscriber handles events delivered by a Bus. It must tolerate seeing an event twice.
+type Subscriber func(ctx context.Context, e models.OutboxEvent) error
+
+// All subscribes to every event type.
+const All = "*"
+
+// Bus is an in-process sink dispatching events to subscribers by type.
+type Bus struct {
+	mu   sync.RWMutex
+	subs map[string][]Subscriber
+}
+
+// NewBus returns a bus without subscribers.
+func NewBus() *Bus {
+	return &Bus{subs: map[string][]Subscriber{}}
+}
+
+// Subscribe registers fn for events of the given type, or for every event with All.
+func (b *Bus) Subscribe(eventType string, fn Subscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs[eventType] = append(b.subs[eventType], fn)
+}
+
+// Publish calls every matching subscriber. If any of them fails the event is retried for all.
+func (b *Bus) Publish(ctx context.Context, e models.OutboxEvent) error {
+	b.mu.RLock()
+	subs := append(append([]Subscriber(nil), b.subs[e.Type]...), b.subs[All]...)
+	b.mu.RUnlock()
+	var errs []error
+	for _, fn := range subs {
+		err := fn(ctx, e)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
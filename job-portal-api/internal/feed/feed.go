@@ -0,0 +1,218 @@
+// Package feed renders jobs for search engines and job aggregators:
+// schema.org JobPosting JSON-LD, a public HTML page embedding it, and XML feeds.
+package feed
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"job-portal-api/internal/models"
+)
+
+// Media types served on top of plain JSON.
+const (
+	MediaJSONLD = "application/ld+json"
+	MediaHTML   = "text/html"
+)
+
+// Renderer turns jobs into the documents search engines and aggregators read.
+// Links in them point at baseURL, and publisher is the name feeds go by.
+type Renderer struct {
+	baseURL   string
+	publisher string
+}
+
+// NewRenderer returns a renderer linking to baseURL.
+func NewRenderer(baseURL, publisher string) *Renderer {
+	return &Renderer{baseURL: strings.TrimRight(baseURL, "/"), publisher: publisher}
+}
+
+// JobURL is the public page of a job.
+func (r *Renderer) JobURL(jobId uint) string {
+	return fmt.Sprintf("%s/jobs/%d", r.baseURL, jobId)
+}
+
+// FeedURL is where the XML feed is served.
+func (r *Renderer) FeedURL() string {
+	return r.baseURL + "/feeds/jobs.xml"
+}
+
+// JobPosting is a schema.org JobPosting, see https://schema.org/JobPosting.
+type JobPosting struct {
+	Context                string                 `json:"@context"`
+	Type                   string                 `json:"@type"`
+	Title                  string                 `json:"title"`
+	Description            string                 `json:"description"`
+	DatePosted             string                 `json:"datePosted"`
+	Identifier             *PropertyValue         `json:"identifier,omitempty"`
+	URL                    string                 `json:"url"`
+	HiringOrganization     Organization           `json:"hiringOrganization"`
+	JobLocation            *Place                 `json:"jobLocation,omitempty"`
+	BaseSalary             *MonetaryAmount        `json:"baseSalary,omitempty"`
+	Skills                 string                 `json:"skills,omitempty"`
+	ExperienceRequirements *ExperienceRequirement `json:"experienceRequirements,omitempty"`
+	DirectApply            bool                   `json:"directApply"`
+}
+
+type PropertyValue struct {
+	Type  string `json:"@type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type Organization struct {
+	Type string `json:"@type"`
+	Name string `json:"name"`
+	Logo string `json:"logo,omitempty"`
+}
+
+type Place struct {
+	Type    string        `json:"@type"`
+	Address PostalAddress `json:"address"`
+}
+
+type PostalAddress struct {
+	Type            string `json:"@type"`
+	AddressLocality string `json:"addressLocality"`
+	AddressRegion   string `json:"addressRegion,omitempty"`
+	AddressCountry  string `json:"addressCountry,omitempty"`
+}
+
+type MonetaryAmount struct {
+	Type     string            `json:"@type"`
+	Currency string            `json:"currency"`
+	Value    QuantitativeValue `json:"value"`
+}
+
+type QuantitativeValue struct {
+	Type     string `json:"@type"`
+	MinValue int    `json:"minValue,omitempty"`
+	MaxValue int    `json:"maxValue,omitempty"`
+	UnitText string `json:"unitText"`
+}
+
+type ExperienceRequirement struct {
+	Type               string `json:"@type"`
+	MonthsOfExperience int    `json:"monthsOfExperience"`
+}
+
+// JobPosting describes the job of company for search engines.
+func (r *Renderer) JobPosting(job models.Job, company models.Company) JobPosting {
+	p := JobPosting{
+		Context:     "https://schema.org/",
+		Type:        "JobPosting",
+		Title:       job.Title,
+		Description: description(job),
+		DatePosted:  job.CreatedAt.UTC().Format(time.RFC3339),
+		Identifier: &PropertyValue{
+			Type:  "PropertyValue",
+			Name:  company.CompanyName,
+			Value: strconv.FormatUint(uint64(job.ID), 10),
+		},
+		URL: r.JobURL(job.ID),
+		HiringOrganization: Organization{
+			Type: "Organization",
+			Name: company.CompanyName,
+		},
+		Skills:      strings.Join(job.Skills, ", "),
+		DirectApply: true,
+	}
+	if company.LogoDocumentID != nil {
+		p.HiringOrganization.Logo = fmt.Sprintf("%s/companies/%d/logo", r.baseURL, company.ID)
+	}
+	if location := firstNonEmpty(job.Location, company.Location); location != "" {
+		p.JobLocation = &Place{
+			Type:    "Place",
+			Address: PostalAddress{Type: "PostalAddress", AddressLocality: location},
+		}
+		// Where the gazetteer placed the job, search engines are told its region and country too
+		if job.Country != "" {
+			p.JobLocation.Address.AddressLocality = job.City
+			p.JobLocation.Address.AddressRegion = job.Region
+			p.JobLocation.Address.AddressCountry = job.Country
+		}
+	}
+	if job.SalaryCurrency != "" && (job.SalaryMin > 0 || job.SalaryMax > 0) {
+		p.BaseSalary = &MonetaryAmount{
+			Type:     "MonetaryAmount",
+			Currency: job.SalaryCurrency,
+			Value: QuantitativeValue{
+				Type:     "QuantitativeValue",
+				MinValue: job.SalaryMin,
+				MaxValue: job.SalaryMax,
+				UnitText: unitText[job.SalaryPeriod],
+			},
+		}
+	}
+	if job.MinExperienceYears > 0 {
+		p.ExperienceRequirements = &ExperienceRequirement{
+			Type:               "OccupationalExperienceRequirements",
+			MonthsOfExperience: job.MinExperienceYears * 12,
+		}
+	}
+	return p
+}
+
+var pageTemplate = template.Must(template.New("job").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Job.Title}} at {{.Company.CompanyName}}</title>
+<link rel="canonical" href="{{.URL}}">
+<script type="application/ld+json">{{.JSONLD}}</script>
+</head>
+<body>
+<h1>{{.Job.Title}}</h1>
+<p>{{.Company.CompanyName}}{{with .Location}} · {{.}}{{end}}</p>
+{{with .Job.ExperienceLevel}}<p>Experience: {{.}}</p>{{end}}
+{{with .Job.Skills}}<ul>{{range .}}<li>{{.}}</li>{{end}}</ul>{{end}}
+<div>{{.Description}}</div>
+</body>
+</html>
+`))
+
+// WriteHTML writes a public page of the job with its JSON-LD embedded, which is what crawlers index.
+func (r *Renderer) WriteHTML(w io.Writer, job models.Job, company models.Company) error {
+	ld, err := json.Marshal(r.JobPosting(job, company))
+	if err != nil {
+		return err
+	}
+	return pageTemplate.Execute(w, map[string]any{
+		"Job":         job,
+		"Company":     company,
+		"URL":         r.JobURL(job.ID),
+		"Location":    firstNonEmpty(job.Location, company.Location),
+		"Description": description(job),
+		// json.Marshal escapes <, > and &, so the JSON cannot end the script element
+		"JSONLD": template.JS(ld),
+	})
+}
+
+// description falls back on a summary for jobs posted without a description.
+func description(job models.Job) string {
+	if job.Description != "" {
+		return job.Description
+	}
+	parts := []string{job.Title}
+	if job.ExperienceLevel != "" {
+		parts = append(parts, "Experience: "+job.ExperienceLevel)
+	}
+	if len(job.Skills) > 0 {
+		parts = append(parts, "Skills: "+strings.Join(job.Skills, ", "))
+	}
+	return strings.Join(parts, ". ")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,144 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"job-portal-api/internal/models"
+)
+
+var (
+	posted = time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	logoID = uint(4)
+	acme   = models.Company{Model: gorm.Model{ID: 2}, CompanyName: "Acme", Location: "Pune", LogoDocumentID: &logoID}
+	goDev  = models.Job{
+		Model:              gorm.Model{ID: 7, CreatedAt: posted},
+		Title:              "Go developer",
+		ExperienceLevel:    "senior",
+		CompanyID:          2,
+		Description:        "Build APIs",
+		Skills:             []string{"go", "postgresql"},
+		MinExperienceYears: 3,
+		SalaryMin:          100,
+		SalaryMax:          200,
+		SalaryCurrency:     "USD",
+	}
+)
+
+func TestJobPosting(t *testing.T) {
+	r := NewRenderer("https://jobs.example.com/", "Job Portal")
+
+	b, err := json.Marshal(r.JobPosting(goDev, acme))
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"@context": "https://schema.org/",
+		"@type": "JobPosting",
+		"title": "Go developer",
+		"description": "Build APIs",
+		"datePosted": "2024-03-01T09:30:00Z",
+		"identifier": {"@type": "PropertyValue", "name": "Acme", "value": "7"},
+		"url": "https://jobs.example.com/jobs/7",
+		"hiringOrganization": {"@type": "Organization", "name": "Acme", "logo": "https://jobs.example.com/companies/2/logo"},
+		"jobLocation": {"@type": "Place", "address": {"@type": "PostalAddress", "addressLocality": "Pune"}},
+		"baseSalary": {"@type": "MonetaryAmount", "currency": "USD",
+			"value": {"@type": "QuantitativeValue", "minValue": 100, "maxValue": 200, "unitText": "YEAR"}},
+		"skills": "go, postgresql",
+		"experienceRequirements": {"@type": "OccupationalExperienceRequirements", "monthsOfExperience": 36},
+		"directApply": true
+	}`, string(b))
+}
+
+func TestJobPostingLocated(t *testing.T) {
+	r := NewRenderer("https://jobs.example.com", "Job Portal")
+	lat, lng := 18.5204, 73.8567
+	job := goDev
+	job.Location = "Pune (Hybrid)"
+	job.GeoLocation = models.GeoLocation{City: "Pune", Region: "Maharashtra", Country: "IN", Latitude: &lat, Longitude: &lng}
+	job.SalaryPeriod = models.PayHourly
+	p := r.JobPosting(job, acme)
+
+	require.Equal(t, PostalAddress{Type: "PostalAddress", AddressLocality: "Pune", AddressRegion: "Maharashtra", AddressCountry: "IN"},
+		p.JobLocation.Address)
+	require.Equal(t, "HOUR", p.BaseSalary.Value.UnitText)
+}
+
+func TestJobPostingWithoutOptionalFields(t *testing.T) {
+	r := NewRenderer("https://jobs.example.com", "Job Portal")
+	p := r.JobPosting(models.Job{Model: gorm.Model{ID: 1}, Title: "SRE", ExperienceLevel: "mid"}, models.Company{CompanyName: "Acme"})
+
+	require.Nil(t, p.JobLocation)
+	require.Nil(t, p.BaseSalary)
+	require.Nil(t, p.ExperienceRequirements)
+	require.Empty(t, p.HiringOrganization.Logo)
+	require.Equal(t, "SRE. Experience: mid", p.Description)
+}
+
+func TestWriteHTMLEscapes(t *testing.T) {
+	r := NewRenderer("https://jobs.example.com", "Job Portal")
+	job := goDev
+	job.Title = `</script><script>alert(1)</script>`
+
+	var buf bytes.Buffer
+	require.NoError(t, r.WriteHTML(&buf, job, acme))
+	page := buf.String()
+
+	require.Equal(t, 1, strings.Count(page, "</script>"))
+	require.Contains(t, page, `<script type="application/ld+json">`)
+	require.Contains(t, page, `<link rel="canonical" href="https://jobs.example.com/jobs/7">`)
+	start := strings.Index(page, `<script type="application/ld+json">`) + len(`<script type="application/ld+json">`)
+	end := strings.Index(page, "</script>")
+	var p JobPosting
+	require.NoError(t, json.Unmarshal([]byte(page[start:end]), &p))
+	require.Equal(t, job.Title, p.Title)
+}
+
+func TestWriteXMLIndeed(t *testing.T) {
+	r := NewRenderer("https://jobs.example.com", "Job Portal")
+	built := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	err := r.WriteXML(&buf, FormatIndeed, []models.Job{goDev}, map[uint]models.Company{2: acme}, built)
+	require.NoError(t, err)
+
+	out := buf.String()
+	require.True(t, strings.HasPrefix(out, xml.Header))
+	require.Contains(t, out, "<publisher>Job Portal</publisher>")
+	require.Contains(t, out, "<lastBuildDate>Sat, 02 Mar 2024 00:00:00 UTC</lastBuildDate>")
+	require.Contains(t, out, "<title><![CDATA[Go developer]]></title>")
+	require.Contains(t, out, "<referencenumber><![CDATA[7]]></referencenumber>")
+	require.Contains(t, out, "<company><![CDATA[Acme]]></company>")
+	require.Contains(t, out, "<city><![CDATA[Pune]]></city>")
+	require.Contains(t, out, "<salary><![CDATA[100 - 200 USD per year]]></salary>")
+
+	var src indeedSource
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &src))
+	require.Len(t, src.Jobs, 1)
+	require.Equal(t, "https://jobs.example.com/jobs/7", src.Jobs[0].URL.Text)
+}
+
+func TestWriteXMLRSS(t *testing.T) {
+	r := NewRenderer("https://jobs.example.com", "Job Portal")
+
+	var buf bytes.Buffer
+	err := r.WriteXML(&buf, FormatRSS, []models.Job{goDev}, map[uint]models.Company{2: acme}, posted)
+	require.NoError(t, err)
+
+	var doc rss
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	require.Equal(t, "2.0", doc.Version)
+	require.Len(t, doc.Channel.Items, 1)
+	item := doc.Channel.Items[0]
+	require.Equal(t, "Go developer at Acme", item.Title)
+	require.Equal(t, "https://jobs.example.com/jobs/7", item.GUID.Value)
+	require.Equal(t, "Fri, 01 Mar 2024 09:30:00 +0000", item.PubDate)
+	require.Equal(t, []string{"go", "postgresql"}, item.Categories)
+
+	require.Error(t, r.WriteXML(&buf, "atom", nil, nil, posted))
+}
@@ -0,0 +1,169 @@
+package feed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"job-portal-api/internal/models"
+)
+
+// Feed formats.
+const (
+	// FormatIndeed is the XML job feed most aggregators read, in the layout Indeed defined.
+	FormatIndeed = "indeed"
+	FormatRSS    = "rss"
+)
+
+// cdata is text written as a CDATA section, which aggregators expect for free text.
+type cdata struct {
+	Text string `xml:",cdata"`
+}
+
+type indeedSource struct {
+	XMLName       xml.Name    `xml:"source"`
+	Publisher     string      `xml:"publisher"`
+	PublisherURL  string      `xml:"publisherurl"`
+	LastBuildDate string      `xml:"lastBuildDate"`
+	Jobs          []indeedJob `xml:"job"`
+}
+
+type indeedJob struct {
+	Title           cdata  `xml:"title"`
+	Date            cdata  `xml:"date"`
+	ReferenceNumber cdata  `xml:"referencenumber"`
+	URL             cdata  `xml:"url"`
+	Company         cdata  `xml:"company"`
+	City            *cdata `xml:"city,omitempty"`
+	Description     cdata  `xml:"description"`
+	Salary          *cdata `xml:"salary,omitempty"`
+	Experience      *cdata `xml:"experience,omitempty"`
+}
+
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate"`
+	Description cdata    `xml:"description"`
+	Categories  []string `xml:"category"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// WriteXML writes the jobs as a feed in the format; companies holds the company of every job by id.
+func (r *Renderer) WriteXML(w io.Writer, format string, jobs []models.Job, companies map[uint]models.Company, built time.Time) error {
+	var doc any
+	switch format {
+	case FormatIndeed:
+		src := indeedSource{
+			Publisher:     r.publisher,
+			PublisherURL:  r.baseURL,
+			LastBuildDate: built.UTC().Format(time.RFC1123),
+			Jobs:          make([]indeedJob, 0, len(jobs)),
+		}
+		for _, job := range jobs {
+			company := companies[job.CompanyID]
+			j := indeedJob{
+				Title:           cdata{job.Title},
+				Date:            cdata{job.CreatedAt.UTC().Format(time.RFC1123)},
+				ReferenceNumber: cdata{strconv.FormatUint(uint64(job.ID), 10)},
+				URL:             cdata{r.JobURL(job.ID)},
+				Company:         cdata{company.CompanyName},
+				Description:     cdata{description(job)},
+			}
+			if city := firstNonEmpty(job.Location, company.Location); city != "" {
+				j.City = &cdata{city}
+			}
+			if s := salary(job); s != "" {
+				j.Salary = &cdata{s}
+			}
+			if job.ExperienceLevel != "" {
+				j.Experience = &cdata{job.ExperienceLevel}
+			}
+			src.Jobs = append(src.Jobs, j)
+		}
+		doc = src
+	case FormatRSS:
+		ch := rssChannel{
+			Title:         r.publisher + " jobs",
+			Link:          r.baseURL,
+			Description:   "Latest jobs posted on " + r.publisher,
+			LastBuildDate: built.UTC().Format(time.RFC1123Z),
+			Items:         make([]rssItem, 0, len(jobs)),
+		}
+		for _, job := range jobs {
+			title := job.Title
+			if name := companies[job.CompanyID].CompanyName; name != "" {
+				title += " at " + name
+			}
+			ch.Items = append(ch.Items, rssItem{
+				Title:       title,
+				Link:        r.JobURL(job.ID),
+				GUID:        rssGUID{IsPermaLink: true, Value: r.JobURL(job.ID)},
+				PubDate:     job.CreatedAt.UTC().Format(time.RFC1123Z),
+				Description: cdata{description(job)},
+				Categories:  job.Skills,
+			})
+		}
+		doc = rss{Version: "2.0", Channel: ch}
+	default:
+		return fmt.Errorf("unknown feed format %q", format)
+	}
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	err = enc.Encode(doc)
+	if err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// unitText is the schema.org unit of each salary period; annual is the default.
+var unitText = map[string]string{
+	"":                "YEAR",
+	models.PayAnnual:  "YEAR",
+	models.PayMonthly: "MONTH",
+	models.PayHourly:  "HOUR",
+}
+
+// salary reads like "50000 - 70000 USD per year".
+func salary(job models.Job) string {
+	var amount string
+	switch {
+	case job.SalaryMin > 0 && job.SalaryMax > 0:
+		amount = fmt.Sprintf("%d - %d", job.SalaryMin, job.SalaryMax)
+	case job.SalaryMin > 0:
+		amount = fmt.Sprintf("from %d", job.SalaryMin)
+	case job.SalaryMax > 0:
+		amount = fmt.Sprintf("up to %d", job.SalaryMax)
+	default:
+		return ""
+	}
+	return strings.TrimSpace(amount + " " + job.SalaryCurrency + " per " + strings.ToLower(unitText[job.SalaryPeriod]))
+}
@@ -0,0 +1,381 @@
+// Package geo places free-form locations such as "Pune, India" on the map, offline, with a gazetteer of
+// cities embedded in the binary, and measures distances between places for radius searches.
+package geo
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// EarthRadiusKm is the mean radius of the earth distances are measured on.
+const EarthRadiusKm = 6371.0
+
+//go:embed cities.csv
+var citiesCSV string
+
+//go:embed countries.csv
+var countriesCSV string
+
+// ErrInvalidBox is returned for bounding boxes that cannot be searched in.
+var ErrInvalidBox = errors.New("bbox must be min_longitude,min_latitude,max_longitude,max_latitude")
+
+// ErrInvalidPoint is returned for coordinates off the map.
+var ErrInvalidPoint = errors.New("latitude must be within -90 and 90, and longitude within -180 and 180")
+
+// Point is a position on the map in degrees.
+type Point struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// Place is a city of the gazetteer. Country is the ISO 3166-1 alpha-2 code.
+type Place struct {
+	City    string `json:"city"`
+	Region  string `json:"region"`
+	Country string `json:"country"`
+	Point
+}
+
+// city is a place with what it can be told apart by.
+type city struct {
+	Place
+	regionKeys []string
+	population int
+}
+
+// Gazetteer resolves free-form locations to the cities it lists.
+type Gazetteer struct {
+	cities []city
+	// byName indexes cities by their normalized names and aliases
+	byName map[string][]int
+	// countries maps normalized country names and aliases to their codes
+	countries map[string]string
+}
+
+var (
+	defaultOnce sync.Once
+	defaultGaz  *Gazetteer
+)
+
+// Default returns the gazetteer embedded in the binary, loaded on first use.
+func Default() *Gazetteer {
+	defaultOnce.Do(func() {
+		g, err := Load(strings.NewReader(citiesCSV), strings.NewReader(countriesCSV))
+		if err != nil {
+			panic(fmt.Sprintf("loading embedded gazetteer: %v", err))
+		}
+		defaultGaz = g
+	})
+	return defaultGaz
+}
+
+// Load reads a gazetteer from CSV lists of cities (name, aliases, region, region_code, country, latitude,
+// longitude, population) and countries (code, name, aliases). Aliases are separated by semicolons.
+func Load(cities, countries io.Reader) (*Gazetteer, error) {
+	g := &Gazetteer{byName: map[string][]int{}, countries: map[string]string{}}
+	err := readCSV(countries, 3, func(rec []string) error {
+		code := strings.ToUpper(strings.TrimSpace(rec[0]))
+		if len(code) != 2 {
+			return fmt.Errorf("invalid country code %q", rec[0])
+		}
+		for _, name := range append([]string{rec[1]}, splitAliases(rec[2])...) {
+			if key := Normalize(name); key != "" {
+				g.countries[key] = code
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading countries: %w", err)
+	}
+	err = readCSV(cities, 8, func(rec []string) error {
+		lat, err := strconv.ParseFloat(rec[5], 64)
+		if err != nil {
+			return fmt.Errorf("invalid latitude of %s: %w", rec[0], err)
+		}
+		lng, err := strconv.ParseFloat(rec[6], 64)
+		if err != nil {
+			return fmt.Errorf("invalid longitude of %s: %w", rec[0], err)
+		}
+		p := Point{Latitude: lat, Longitude: lng}
+		if !p.Valid() {
+			return fmt.Errorf("%s: %w", rec[0], ErrInvalidPoint)
+		}
+		pop, err := strconv.Atoi(rec[7])
+		if err != nil {
+			return fmt.Errorf("invalid population of %s: %w", rec[0], err)
+		}
+		c := city{
+			Place:      Place{City: rec[0], Region: rec[2], Country: strings.ToUpper(rec[4]), Point: p},
+			population: pop,
+		}
+		for _, r := range []string{rec[2], rec[3]} {
+			if key := Normalize(r); key != "" {
+				c.regionKeys = append(c.regionKeys, key)
+			}
+		}
+		i := len(g.cities)
+		g.cities = append(g.cities, c)
+		for _, name := range append([]string{rec[0]}, splitAliases(rec[1])...) {
+			if key := Normalize(name); key != "" {
+				g.byName[key] = append(g.byName[key], i)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading cities: %w", err)
+	}
+	return g, nil
+}
+
+// readCSV calls fn with each record after the header, checking it has the number of fields.
+func readCSV(r io.Reader, fields int, fn func([]string) error) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = fields
+	_, err := cr.Read()
+	if err != nil {
+		return err
+	}
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		err = fn(rec)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func splitAliases(s string) []string {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	return strings.Split(s, ";")
+}
+
+// folds spells letters with diacritics the way they are often typed without them.
+var folds = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ä", "a", "ã", "a", "å", "a", "ā", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e", "ē", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i", "ī", "i",
+	"ó", "o", "ò", "o", "ô", "o", "ö", "o", "õ", "o", "ø", "o", "ō", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u", "ū", "u",
+	"ç", "c", "ć", "c", "č", "c", "ñ", "n", "ń", "n", "ł", "l", "ś", "s", "š", "s",
+	"ș", "s", "ş", "s", "ț", "t", "ţ", "t", "ź", "z", "ż", "z", "ž", "z", "ř", "r", "ý", "y", "ß", "ss",
+)
+
+// Normalize is how names are compared: lower case, without diacritics, digits or punctuation, words
+// separated by single spaces.
+func Normalize(s string) string {
+	s = folds.Replace(strings.ToLower(s))
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) {
+			return r
+		}
+		return ' '
+	}, s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// splitLocation cuts a location into the parts it lists, e.g. "Pune (Hybrid), India" into Pune, Hybrid and India.
+func splitLocation(location string) []string {
+	location = strings.ReplaceAll(location, " - ", ",")
+	fields := strings.FieldsFunc(location, func(r rune) bool {
+		return strings.ContainsRune(",;/|()", r)
+	})
+	parts := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if p := Normalize(f); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return parts
+}
+
+// Resolve finds the city a free-form location is in. The first part of the location naming a city is
+// taken, the other parts telling apart cities of the same name by their region or country; otherwise the
+// most populous is taken. Locations naming a country none of the cities is in, and locations naming no
+// city at all, like "Remote", are not resolved.
+func (g *Gazetteer) Resolve(location string) (Place, bool) {
+	parts := splitLocation(location)
+	for i, part := range parts {
+		if ids, ok := g.byName[part]; ok {
+			return g.pick(ids, parts, i)
+		}
+	}
+	// Then names within the parts, e.g. "Greater London" or "Bangalore Urban", the longest first
+	for i, part := range parts {
+		words := strings.Fields(part)
+		for n := len(words) - 1; n > 0; n-- {
+			for start := 0; start+n <= len(words); start++ {
+				if ids, ok := g.byName[strings.Join(words[start:start+n], " ")]; ok {
+					return g.pick(ids, parts, i)
+				}
+			}
+		}
+	}
+	return Place{}, false
+}
+
+// pick chooses among the cities named by parts[named] with the other parts as hints.
+func (g *Gazetteer) pick(ids []int, parts []string, named int) (Place, bool) {
+	best, bestScore := -1, -1
+	for _, id := range ids {
+		c := g.cities[id]
+		score := 0
+		for i, hint := range parts {
+			if i == named {
+				continue
+			}
+			switch {
+			case contains(c.regionKeys, hint):
+				score += 2
+			case strings.EqualFold(hint, c.Country):
+				score++
+			case g.countries[hint] != "":
+				if g.countries[hint] != c.Country {
+					// The location is in another country
+					score = -1
+				} else {
+					score++
+				}
+			}
+			if score < 0 {
+				break
+			}
+		}
+		if score < 0 {
+			continue
+		}
+		if score > bestScore || score == bestScore && c.population > g.cities[best].population {
+			best, bestScore = id, score
+		}
+	}
+	if best < 0 {
+		return Place{}, false
+	}
+	return g.cities[best].Place, true
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Valid reports whether the point is on the map.
+func (p Point) Valid() bool {
+	return p.Latitude >= -90 && p.Latitude <= 90 && p.Longitude >= -180 && p.Longitude <= 180
+}
+
+// Distance is the great-circle distance between two points in kilometres.
+func Distance(a, b Point) float64 {
+	lat1, lat2 := radians(a.Latitude), radians(b.Latitude)
+	dLat, dLng := lat2-lat1, radians(b.Longitude-a.Longitude)
+	h := math.Pow(math.Sin(dLat/2), 2) + math.Cos(lat1)*math.Cos(lat2)*math.Pow(math.Sin(dLng/2), 2)
+	return 2 * EarthRadiusKm * math.Asin(math.Sqrt(math.Min(1, h)))
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func degrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
+
+// Box is an area between two latitudes and two longitudes. A box crossing the antimeridian has a
+// MinLongitude greater than its MaxLongitude.
+type Box struct {
+	MinLatitude  float64 `json:"min_latitude"`
+	MaxLatitude  float64 `json:"max_latitude"`
+	MinLongitude float64 `json:"min_longitude"`
+	MaxLongitude float64 `json:"max_longitude"`
+}
+
+// ParseBox reads a box in the "min_longitude,min_latitude,max_longitude,max_latitude" order of GeoJSON.
+func ParseBox(s string) (Box, error) {
+	fields := strings.Split(s, ",")
+	if len(fields) != 4 {
+		return Box{}, ErrInvalidBox
+	}
+	var v [4]float64
+	for i, f := range fields {
+		n, err := strconv.ParseFloat(strings.TrimSpace(f), 64)
+		if err != nil {
+			return Box{}, ErrInvalidBox
+		}
+		v[i] = n
+	}
+	b := Box{MinLongitude: v[0], MinLatitude: v[1], MaxLongitude: v[2], MaxLatitude: v[3]}
+	lo, hi := Point{b.MinLatitude, b.MinLongitude}, Point{b.MaxLatitude, b.MaxLongitude}
+	if !lo.Valid() || !hi.Valid() || b.MinLatitude > b.MaxLatitude {
+		return Box{}, ErrInvalidBox
+	}
+	return b, nil
+}
+
+// BoundingBox is the smallest box holding every point within radiusKm of the center, to narrow down
+// radius searches before distances are measured.
+func BoundingBox(center Point, radiusKm float64) Box {
+	d := radiusKm / EarthRadiusKm
+	lat := radians(center.Latitude)
+	b := Box{
+		MinLatitude:  degrees(lat - d),
+		MaxLatitude:  degrees(lat + d),
+		MinLongitude: -180,
+		MaxLongitude: 180,
+	}
+	// Near a pole the circle covers every longitude
+	if b.MinLatitude <= -90 || b.MaxLatitude >= 90 {
+		b.MinLatitude, b.MaxLatitude = math.Max(b.MinLatitude, -90), math.Min(b.MaxLatitude, 90)
+		return b
+	}
+	dLng := degrees(math.Asin(math.Sin(d) / math.Cos(lat)))
+	b.MinLongitude, b.MaxLongitude = wrap(center.Longitude-dLng), wrap(center.Longitude+dLng)
+	return b
+}
+
+// wrap brings a longitude back within -180 and 180.
+func wrap(lng float64) float64 {
+	switch {
+	case lng < -180:
+		return lng + 360
+	case lng > 180:
+		return lng - 360
+	}
+	return lng
+}
+
+// CrossesAntimeridian reports whether the box spans the 180th meridian.
+func (b Box) CrossesAntimeridian() bool {
+	return b.MinLongitude > b.MaxLongitude
+}
+
+// Contains reports whether the point is within the box.
+func (b Box) Contains(p Point) bool {
+	if p.Latitude < b.MinLatitude || p.Latitude > b.MaxLatitude {
+		return false
+	}
+	if b.CrossesAntimeridian() {
+		return p.Longitude >= b.MinLongitude || p.Longitude <= b.MaxLongitude
+	}
+	return p.Longitude >= b.MinLongitude && p.Longitude <= b.MaxLongitude
+}
@@ -0,0 +1,104 @@
+package geo
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolve(t *testing.T) {
+	g := Default()
+	tt := []struct {
+		location string
+		city     string
+		country  string
+		ok       bool
+	}{
+		{location: "Pune", city: "Pune", country: "IN", ok: true},
+		{location: "pune, maharashtra, India", city: "Pune", country: "IN", ok: true},
+		{location: "Bangalore (Hybrid)", city: "Bengaluru", country: "IN", ok: true},
+		{location: "Remote / Greater London", city: "London", country: "GB", ok: true},
+		{location: "München, Deutschland", city: "Munich", country: "DE", ok: true},
+		{location: "Hyderabad", city: "Hyderabad", country: "IN", ok: true},
+		{location: "Hyderabad, Pakistan", city: "Hyderabad", country: "PK", ok: true},
+		{location: "Cambridge", city: "Cambridge", country: "GB", ok: true},
+		{location: "Cambridge, MA", city: "Cambridge", country: "US", ok: true},
+		{location: "Portland, ME 04101", city: "Portland", country: "US", ok: true},
+		{location: "San Francisco, CA", city: "San Francisco", country: "US", ok: true},
+		{location: "Cambridge, Canada"},
+		{location: "Remote, India"},
+		{location: ""},
+	}
+	for _, tc := range tt {
+		t.Run(tc.location, func(t *testing.T) {
+			p, ok := g.Resolve(tc.location)
+			require.Equal(t, tc.ok, ok)
+			require.Equal(t, tc.city, p.City)
+			require.Equal(t, tc.country, p.Country)
+		})
+	}
+
+	p, _ := g.Resolve("Portland, Maine")
+	require.Equal(t, "Maine", p.Region)
+	p, _ = g.Resolve("Portland")
+	require.Equal(t, "Oregon", p.Region)
+}
+
+func TestLoad(t *testing.T) {
+	countries := "code,name,aliases\nIN,India,\n"
+	_, err := Load(strings.NewReader("name,aliases,region,region_code,country,latitude,longitude,population\nPune,,Maharashtra,MH,IN,98,73.8,1\n"),
+		strings.NewReader(countries))
+	require.ErrorIs(t, err, ErrInvalidPoint)
+
+	_, err = Load(strings.NewReader("name,aliases,region,region_code,country,latitude,longitude,population\nPune,,MH,IN,18.5,73.8,1\n"),
+		strings.NewReader(countries))
+	require.Error(t, err)
+}
+
+func TestDistance(t *testing.T) {
+	pune, _ := Default().Resolve("Pune")
+	mumbai, _ := Default().Resolve("Mumbai")
+	require.InDelta(t, 120, Distance(pune.Point, mumbai.Point), 5)
+	require.Zero(t, Distance(pune.Point, pune.Point))
+	// Across the antimeridian
+	require.InDelta(t, 111.2, Distance(Point{0, 179.5}, Point{0, -179.5}), 0.1)
+}
+
+func TestBoundingBox(t *testing.T) {
+	pune := Point{Latitude: 18.5204, Longitude: 73.8567}
+	b := BoundingBox(pune, 25)
+	require.InDelta(t, 18.2956, b.MinLatitude, 0.001)
+	require.InDelta(t, 18.7452, b.MaxLatitude, 0.001)
+	require.True(t, b.Contains(pune))
+	require.False(t, b.Contains(Point{Latitude: 19.0760, Longitude: 72.8777}))
+	// Every point within the radius is in the box
+	for _, p := range []Point{{18.52, 74.09}, {18.52, 73.62}, {18.74, 73.86}, {18.30, 73.86}} {
+		if Distance(pune, p) <= 25 {
+			require.True(t, b.Contains(p), p)
+		}
+	}
+
+	b = BoundingBox(Point{Latitude: -41.2865, Longitude: 179.9}, 50)
+	require.True(t, b.CrossesAntimeridian())
+	require.True(t, b.Contains(Point{Latitude: -41.2, Longitude: -179.8}))
+
+	b = BoundingBox(Point{Latitude: 89.9, Longitude: 10}, 50)
+	require.Equal(t, 90.0, b.MaxLatitude)
+	require.Equal(t, -180.0, b.MinLongitude)
+}
+
+func TestParseBox(t *testing.T) {
+	b, err := ParseBox("72.5, 18, 74.5,19.5")
+	require.NoError(t, err)
+	require.Equal(t, Box{MinLongitude: 72.5, MinLatitude: 18, MaxLongitude: 74.5, MaxLatitude: 19.5}, b)
+
+	b, err = ParseBox("170,-50,-170,-30")
+	require.NoError(t, err)
+	require.True(t, b.CrossesAntimeridian())
+
+	for _, in := range []string{"", "1,2,3", "a,1,2,3", "0,10,1,5", "0,-91,1,5", "181,0,182,1"} {
+		_, err := ParseBox(in)
+		require.ErrorIs(t, err, ErrInvalidBox, in)
+	}
+}
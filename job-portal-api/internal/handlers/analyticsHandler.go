@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"errors"
+	"job-portal-api/internal/analytics"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog/log"
+)
+
+// visitor tells apart who is looking at a job: signed in users by their ID, anyone else by their address
+// and browser, hashed with a daily key
+func (h *handler) visitor(c *gin.Context) string {
+	if claims, ok := c.Request.Context().Value(auth.Key).(jwt.RegisteredClaims); ok && claims.Subject != "" {
+		return "user:" + claims.Subject
+	}
+	return h.vs.Anonymous(c.ClientIP(), c.Request.UserAgent(), time.Now())
+}
+
+// recordJobView counts the request as a view of the job. Failing to count it does not fail the request.
+func (h *handler) recordJobView(c *gin.Context, traceId string, job models.Job) {
+	err := h.s.RecordJobView(c.Request.Context(), job.ID, job.CompanyID, h.visitor(c))
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Uint("job", job.ID).Msg("recording job view")
+	}
+}
+
+// StartApplication records the candidate opening the application form of a listed job, so recruiters
+// see how many who start applying go on to submit
+func (h *handler) StartApplication(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	jobID, err := strconv.ParseUint(c.Param("jobID"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid job ID"})
+		return
+	}
+	jobs, err := h.s.ViewJobByJobId(ctx, uint(jobID), claims.Subject)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching job details"})
+		return
+	}
+	if len(jobs) == 0 || jobs[0].Status != models.JobPublished {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "job not found"})
+		return
+	}
+	err = h.s.RecordApplyStart(ctx, jobs[0].ID, jobs[0].CompanyID, "user:"+claims.Subject)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in recording application start"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// report writes the analytics of the scope over the period asked for in the query: from and to (days, both
+// included, the last 30 days by default) and interval (day or week)
+func (h *handler) report(c *gin.Context, traceId string, scope models.StatScope) {
+	p, err := analytics.ParsePeriod(c.Query("from"), c.Query("to"), c.Query("interval"), time.Now())
+	if errors.Is(err, analytics.ErrInvalidPeriod) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	r, err := analytics.Build(c.Request.Context(), h.s, scope, p)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in building analytics"})
+		return
+	}
+	c.JSON(http.StatusOK, r)
+}
+
+// JobAnalytics reports the views, applications and hiring times of a job to members of its company
+func (h *handler) JobAnalytics(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	job, ok := h.requireJobRole(c, traceId, claims, models.RoleViewer)
+	if !ok {
+		return
+	}
+	h.report(c, traceId, models.StatScope{CompanyID: job.CompanyID, JobID: job.ID})
+}
+
+// CompanyAnalytics reports the views, applications and hiring times of every job of a company to its members
+func (h *handler) CompanyAnalytics(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	companyID, ok := companyParam(c)
+	if !ok {
+		return
+	}
+	if _, ok := h.requireCompanyRole(c, traceId, claims, companyID, models.RoleViewer); !ok {
+		return
+	}
+	h.report(c, traceId, models.StatScope{CompanyID: companyID})
+}
@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+	"job-portal-api/internal/analytics"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/models/mockmodels"
+	"job-portal-api/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTestVisitors(t *testing.T) *analytics.Visitors {
+	vs, err := analytics.NewVisitors([]byte("test-secret"))
+	require.NoError(t, err)
+	return vs
+}
+
+func TestHandler_StartApplication(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{
+		Subject: "7",
+	}
+	job := models.Job{Model: gorm.Model{ID: 5}, CompanyID: 2, Status: models.JobPublished}
+
+	tt := []struct {
+		name           string
+		expectedStatus int
+		mockService    func(m *mockmodels.MockService)
+	}{
+		{
+			name:           "OK",
+			expectedStatus: http.StatusNoContent,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewJobByJobId(gomock.Any(), gomock.Eq(uint(5)), gomock.Any()).Times(1).Return([]models.Job{job}, nil)
+				m.EXPECT().RecordApplyStart(gomock.Any(), gomock.Eq(uint(5)), gomock.Eq(uint(2)), gomock.Eq("user:7")).Times(1).Return(nil)
+			},
+		},
+		{
+			name:           "Fail_Closed",
+			expectedStatus: http.StatusNotFound,
+			mockService: func(m *mockmodels.MockService) {
+				closed := job
+				closed.Status = models.JobClosed
+				m.EXPECT().ViewJobByJobId(gomock.Any(), gomock.Eq(uint(5)), gomock.Any()).Times(1).Return([]models.Job{closed}, nil)
+				m.EXPECT().RecordApplyStart(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService)}
+			router.POST("/jobs/:jobID/apply/start", h.StartApplication)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/jobs/5/apply/start", nil)
+			require.NoError(t, err)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandler_PublicJobViewNotRecorded(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	mockService := mockmodels.NewMockService(ctrl)
+	mockService.EXPECT().ViewJobByJobId(gomock.Any(), gomock.Eq(uint(3)), gomock.Any()).Times(1).
+		Return([]models.Job{{Model: gorm.Model{ID: 3}, CompanyID: 2, Status: models.JobPublished}}, nil)
+	mockService.EXPECT().RecordJobView(gomock.Any(), gomock.Eq(uint(3)), gomock.Eq(uint(2)), gomock.Any()).Times(1).
+		Return(errors.New("db down"))
+
+	router := gin.New()
+	h := handler{s: services.NewStore(mockService), vs: newTestVisitors(t)}
+	router.GET("/jobs/:jobID", h.PublicJob)
+
+	ctx := context.WithValue(context.Background(), middleware.TraceIdKey, "fake-trace-id")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/jobs/3", nil)
+	require.NoError(t, err)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	// A view that could not be counted still shows the job
+	require.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestHandler_JobAnalytics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{
+		Subject: "1",
+	}
+	job := models.Job{Model: gorm.Model{ID: 5}, CompanyID: 2, Status: models.JobPublished}
+	jobScope := models.StatScope{CompanyID: 2, JobID: 5}
+	from, end := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC)
+
+	tt := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		mockService    func(m *mockmodels.MockService)
+	}{
+		{
+			name:           "OK",
+			query:          "?from=2024-03-01&to=2024-03-02&interval=day",
+			expectedStatus: http.StatusOK,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().JobStatSeries(gomock.Any(), gomock.Eq(jobScope), gomock.Eq(from), gomock.Eq(end), gomock.Eq("day")).Times(1).
+					Return([]models.StatPoint{{Period: from, StatCounts: models.StatCounts{Views: 10, Applications: 2}}}, nil)
+				m.EXPECT().MedianTimeToHire(gomock.Any(), gomock.Eq(jobScope), gomock.Eq(from), gomock.Eq(end)).Times(1).Return(nil, nil)
+				m.EXPECT().MedianTimeInStage(gomock.Any(), gomock.Eq(jobScope), gomock.Eq(from), gomock.Eq(end)).Times(1).
+					Return([]models.StageTime{{StageID: 3, Name: "Screening", MedianHours: 12, Samples: 2}}, nil)
+			},
+		},
+		{
+			name:           "Fail_Period",
+			query:          "?from=2024-03-02&to=2024-03-01",
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().JobStatSeries(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:           "Fail_NotMember",
+			expectedStatus: http.StatusForbidden,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).Times(1).
+					Return(models.Membership{}, gorm.ErrRecordNotFound)
+				m.EXPECT().ViewUser(gomock.Any(), gomock.Eq(uint(1))).Times(1).Return(models.User{}, nil)
+				m.EXPECT().JobStatSeries(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+			mockService.EXPECT().ViewJobByJobId(gomock.Any(), gomock.Eq(uint(5)), gomock.Any()).AnyTimes().Return([]models.Job{job}, nil)
+			mockService.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).AnyTimes().
+				Return(models.Membership{Role: models.RoleViewer}, nil)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService)}
+			router.GET("/jobs/:jobID/analytics", h.JobAnalytics)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/jobs/5/analytics"+tc.query, nil)
+			require.NoError(t, err)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+			if tc.expectedStatus == http.StatusOK {
+				var r analytics.Report
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &r))
+				require.Len(t, r.Series, 2)
+				require.Equal(t, int64(10), r.Totals.Views)
+				require.InDelta(t, 0.2, *r.Funnel.ViewToApply, 1e-9)
+				require.Nil(t, r.Funnel.StartToApply)
+				require.Nil(t, r.MedianTimeToHireHours)
+				require.Equal(t, "Screening", r.TimeInStage[0].Name)
+			}
+		})
+	}
+}
+
+func TestHandler_CompanyAnalytics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	mockService := mockmodels.NewMockService(ctrl)
+	scope := models.StatScope{CompanyID: 2}
+	mockService.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).Times(1).
+		Return(models.Membership{Role: models.RoleViewer}, nil)
+	mockService.EXPECT().JobStatSeries(gomock.Any(), gomock.Eq(scope), gomock.Any(), gomock.Any(), gomock.Eq("week")).Times(1).
+		Return(nil, nil)
+	mockService.EXPECT().MedianTimeToHire(gomock.Any(), gomock.Eq(scope), gomock.Any(), gomock.Any()).Times(1).Return(nil, nil)
+	mockService.EXPECT().MedianTimeInStage(gomock.Any(), gomock.Eq(scope), gomock.Any(), gomock.Any()).Times(1).
+		Return([]models.StageTime{}, nil)
+
+	router := gin.New()
+	h := handler{s: services.NewStore(mockService)}
+	router.GET("/companies/:companyID/analytics", h.CompanyAnalytics)
+
+	ctx := context.WithValue(context.Background(), auth.Key, jwt.RegisteredClaims{Subject: "1"})
+	ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/companies/2/analytics?interval=week", nil)
+	require.NoError(t, err)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `"interval":"week"`)
+}
@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// Apply submits an application of the logged-in candidate to a job
+func (h *handler) Apply(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	jobID, err := strconv.ParseUint(c.Param("jobID"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid job ID"})
+		return
+	}
+
+	var na models.NewApplication
+	err = json.NewDecoder(c.Request.Body).Decode(&na)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	validate := validator.New()
+	err = validate.Struct(na)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": "invalid application details", "error": err.Error()})
+		return
+	}
+
+	uid, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	app, err := h.s.CreateApplication(ctx, na, uint(jobID), uint(uid))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "job not found"})
+		return
+	}
+	if errors.Is(err, models.ErrInvalidResume) || errors.Is(err, models.ErrInvalidAnswers) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, models.ErrJobNotOpen) {
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"msg": err.Error()})
+		return
+	}
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"msg": "already applied to this job"})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "application failed"})
+		return
+	}
+	c.JSON(http.StatusCreated, app)
+}
+
+// ViewMyApplications lists the applications of the logged-in candidate
+func (h *handler) ViewMyApplications(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+	uid, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	apps, err := h.s.ViewApplicationsByUser(ctx, uint(uid))
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching applications"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"applications": apps})
+}
+
+// applicationFilter reads the filter of the applicant list from the query, responding itself when it is invalid:
+// status=, answer[<question id>]= for exact answers, answer_min[<question id>]= and answer_max[<question id>]= for numbers
+func applicationFilter(c *gin.Context) (models.ApplicationFilter, bool) {
+	f := models.ApplicationFilter{
+		Status:     c.Query("status"),
+		Answers:    map[uint]string{},
+		AnswersMin: map[uint]float64{},
+		AnswersMax: map[uint]float64{},
+	}
+	for key, v := range c.QueryMap("answer") {
+		id, err := strconv.ParseUint(key, 10, 64)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "answer filters need a question ID"})
+			return models.ApplicationFilter{}, false
+		}
+		f.Answers[uint(id)] = v
+	}
+	for param, bounds := range map[string]map[uint]float64{"answer_min": f.AnswersMin, "answer_max": f.AnswersMax} {
+		for key, v := range c.QueryMap(param) {
+			id, err := strconv.ParseUint(key, 10, 64)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": param + " filters need a question ID"})
+				return models.ApplicationFilter{}, false
+			}
+			n, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": param + " filters need a number"})
+				return models.ApplicationFilter{}, false
+			}
+			bounds[uint(id)] = n
+		}
+	}
+	return f, true
+}
+
+// ViewJobApplications lists the applications received for a job, filtered by status and screening answers
+func (h *handler) ViewJobApplications(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	f, ok := applicationFilter(c)
+	if !ok {
+		return
+	}
+	job, ok := h.requireJobRole(c, traceId, claims, models.RoleViewer)
+	if !ok {
+		return
+	}
+
+	apps, err := h.s.ViewApplicationsByJob(ctx, job.ID, f)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching applications"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"applications": apps})
+}
+
+// ApplicationResume hands out a short-lived download link for the resume attached to an application
+func (h *handler) ApplicationResume(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	applicationID, err := strconv.ParseUint(c.Param("applicationID"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid application ID"})
+		return
+	}
+	app, err := h.s.ViewApplication(ctx, uint(applicationID))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "application not found"})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching application"})
+		return
+	}
+	// Applicants see their own resume, the company's members the resumes sent to its jobs
+	if strconv.FormatUint(uint64(app.UserID), 10) != claims.Subject {
+		jobs, err := h.s.ViewJobByJobId(ctx, app.JobID, claims.Subject)
+		if err != nil {
+			log.Error().Err(err).Str("Trace Id", traceId).Send()
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching job details"})
+			return
+		}
+		if len(jobs) == 0 {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "application not found"})
+			return
+		}
+		if _, ok := h.requireCompanyRole(c, traceId, claims, jobs[0].CompanyID, models.RoleViewer); !ok {
+			return
+		}
+	}
+	if app.ResumeDocumentID == nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "no resume attached"})
+		return
+	}
+	doc, err := h.s.ViewDocument(ctx, *app.ResumeDocumentID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "resume was deleted"})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching resume"})
+		return
+	}
+	h.signedURL(c, traceId, doc)
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"errors"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// ViewAuditLog lets admins search the audit log, newest first.
+// Filters: actor, action, entity_type, entity_id, trace_id, since and until (RFC 3339);
+// pass next_before of a page as before to get the next one.
+func (h *handler) ViewAuditLog(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	if !h.requireAdmin(c, traceId, claims) {
+		return
+	}
+
+	f := models.AuditFilter{
+		ActorID:    c.Query("actor"),
+		Action:     c.Query("action"),
+		EntityType: c.Query("entity_type"),
+		EntityID:   c.Query("entity_id"),
+		TraceID:    c.Query("trace_id"),
+		Limit:      limitParam(c),
+	}
+	var err error
+	if v := c.Query("since"); v != "" {
+		f.Since, err = time.Parse(time.RFC3339, v)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "since must be an RFC 3339 time"})
+			return
+		}
+	}
+	if v := c.Query("until"); v != "" {
+		f.Until, err = time.Parse(time.RFC3339, v)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "until must be an RFC 3339 time"})
+			return
+		}
+	}
+	if v := c.Query("before"); v != "" {
+		before, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid before ID"})
+			return
+		}
+		f.BeforeID = uint(before)
+	}
+
+	entries, err := h.s.ViewAuditLog(ctx, f)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching the audit log"})
+		return
+	}
+	resp := gin.H{"entries": entries}
+	if len(entries) == f.Limit {
+		resp["next_before"] = entries[len(entries)-1].ID
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// requireAdmin stops the request unless the logged-in user is an admin
+func (h *handler) requireAdmin(c *gin.Context, traceId string, claims jwt.RegisteredClaims) bool {
+	uid, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return false
+	}
+	u, err := h.s.ViewUser(c.Request.Context(), uint(uid))
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return false
+	}
+	if err != nil || !u.Admin {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": http.StatusText(http.StatusForbidden)})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/models/mockmodels"
+	"job-portal-api/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHandler_ViewAuditLog(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{
+		Subject: "1",
+	}
+
+	tt := []struct {
+		name             string
+		query            string
+		expectedStatus   int
+		expectedResponse string
+		mockService      func(m *mockmodels.MockService)
+	}{
+		{
+			name:             "OK",
+			query:            "?entity_type=jobs&entity_id=4&since=2024-01-01T00:00:00Z&limit=1",
+			expectedStatus:   http.StatusOK,
+			expectedResponse: `{"entries":[{"id":9,"actor_id":"7","action":"create","entity_type":"jobs","entity_id":"4","changes":{"title":{"to":"Go developer"}},"trace_id":"abc","ip":"10.0.0.1","user_agent":"curl","created_at":"2024-01-02T00:00:00Z"}],"next_before":9}`,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewUser(gomock.Any(), gomock.Eq(uint(1))).Times(1).Return(models.User{Admin: true}, nil)
+				m.EXPECT().ViewAuditLog(gomock.Any(), gomock.Eq(models.AuditFilter{
+					EntityType: "jobs",
+					EntityID:   "4",
+					Since:      time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+					Limit:      1,
+				})).Times(1).Return([]models.AuditEntry{{
+					ID: 9, ActorID: "7", Action: models.AuditCreate, EntityType: "jobs", EntityID: "4",
+					Changes:   map[string]models.AuditChange{"title": {To: "Go developer"}},
+					TraceID:   "abc",
+					IP:        "10.0.0.1",
+					UserAgent: "curl",
+					CreatedAt: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+				}}, nil)
+			},
+		},
+		{
+			name:             "Fail_NotAdmin",
+			expectedStatus:   http.StatusForbidden,
+			expectedResponse: `{"error":"Forbidden"}`,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewUser(gomock.Any(), gomock.Eq(uint(1))).Times(1).Return(models.User{}, nil)
+				m.EXPECT().ViewAuditLog(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:           "Fail_BadSince",
+			query:          "?since=yesterday",
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewUser(gomock.Any(), gomock.Eq(uint(1))).Times(1).Return(models.User{Admin: true}, nil)
+				m.EXPECT().ViewAuditLog(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService)}
+			router.GET("/admin/audit-log", h.ViewAuditLog)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/admin/audit-log"+tc.query, nil)
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+			if tc.expectedResponse != "" {
+				require.JSONEq(t, tc.expectedResponse, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/models/mockmodels"
+	"job-portal-api/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler_BookmarkJob(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{
+		Subject: "7",
+	}
+
+	tt := []struct {
+		name           string
+		method         string
+		jobID          string
+		expectedStatus int
+		mockService    func(m *mockmodels.MockService)
+	}{
+		{
+			name:           "OK",
+			method:         http.MethodPut,
+			jobID:          "5",
+			expectedStatus: http.StatusOK,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().BookmarkJob(gomock.Any(), gomock.Eq(uint(7)), gomock.Eq(uint(5))).Times(1).
+					Return(models.Bookmark{ID: 1, UserID: 7, JobID: 5}, nil)
+			},
+		},
+		{
+			name:           "Fail_NotListed",
+			method:         http.MethodPut,
+			jobID:          "6",
+			expectedStatus: http.StatusNotFound,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().BookmarkJob(gomock.Any(), gomock.Eq(uint(7)), gomock.Eq(uint(6))).Times(1).
+					Return(models.Bookmark{}, gorm.ErrRecordNotFound)
+			},
+		},
+		{
+			name:           "Fail_InvalidID",
+			method:         http.MethodPut,
+			jobID:          "abc",
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().BookmarkJob(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:           "Delete_OK",
+			method:         http.MethodDelete,
+			jobID:          "5",
+			expectedStatus: http.StatusNoContent,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().DeleteBookmark(gomock.Any(), gomock.Eq(uint(7)), gomock.Eq(uint(5))).Times(1).Return(nil)
+			},
+		},
+		{
+			name:           "Delete_NotBookmarked",
+			method:         http.MethodDelete,
+			jobID:          "5",
+			expectedStatus: http.StatusNotFound,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().DeleteBookmark(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(gorm.ErrRecordNotFound)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService)}
+			router.PUT("/me/bookmarks/:jobID", h.BookmarkJob)
+			router.DELETE("/me/bookmarks/:jobID", h.DeleteBookmark)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			req, err := http.NewRequestWithContext(ctx, tc.method, "/me/bookmarks/"+tc.jobID, nil)
+			require.NoError(t, err)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandler_FollowCompany(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{
+		Subject: "7",
+	}
+
+	tt := []struct {
+		name           string
+		method         string
+		expectedStatus int
+		mockService    func(m *mockmodels.MockService)
+	}{
+		{
+			name:           "OK",
+			method:         http.MethodPut,
+			expectedStatus: http.StatusOK,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().FollowCompany(gomock.Any(), gomock.Eq(uint(7)), gomock.Eq(uint(2))).Times(1).
+					Return(models.CompanyFollow{ID: 1, UserID: 7, CompanyID: 2}, nil)
+			},
+		},
+		{
+			name:           "Fail_NoCompany",
+			method:         http.MethodPut,
+			expectedStatus: http.StatusNotFound,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().FollowCompany(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					Return(models.CompanyFollow{}, gorm.ErrRecordNotFound)
+			},
+		},
+		{
+			name:           "Unfollow_OK",
+			method:         http.MethodDelete,
+			expectedStatus: http.StatusNoContent,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().UnfollowCompany(gomock.Any(), gomock.Eq(uint(7)), gomock.Eq(uint(2))).Times(1).Return(nil)
+			},
+		},
+		{
+			name:           "Unfollow_NotFollowing",
+			method:         http.MethodDelete,
+			expectedStatus: http.StatusNotFound,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().UnfollowCompany(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(gorm.ErrRecordNotFound)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService)}
+			router.PUT("/me/follows/:companyID", h.FollowCompany)
+			router.DELETE("/me/follows/:companyID", h.UnfollowCompany)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			req, err := http.NewRequestWithContext(ctx, tc.method, "/me/follows/2", nil)
+			require.NoError(t, err)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandler_ViewMyBookmarks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	mockService := mockmodels.NewMockService(ctrl)
+	bookmarked := true
+	mockService.EXPECT().ViewBookmarkedJobs(gomock.Any(), gomock.Eq(uint(7))).Times(1).
+		Return([]models.Job{{Model: gorm.Model{ID: 5}, Title: "Go developer", Status: models.JobClosed, Bookmarked: &bookmarked}}, nil)
+
+	router := gin.New()
+	h := handler{s: services.NewStore(mockService)}
+	router.GET("/me/bookmarks", h.ViewMyBookmarks)
+
+	ctx := context.WithValue(context.Background(), auth.Key, jwt.RegisteredClaims{Subject: "7"})
+	ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/me/bookmarks", nil)
+	require.NoError(t, err)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `"status":"closed","bookmarked":true`)
+}
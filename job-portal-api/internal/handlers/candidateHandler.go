@@ -0,0 +1,508 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/matching"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// candidateScanLimit is how many matching profiles a search filtering on experience looks at,
+// since experience is worked out from the entries of each profile
+const candidateScanLimit = 500
+
+// decodeTalentPool reads and validates a talent pool from the request body.
+// On failure the response has already been written and ok is false.
+func decodeTalentPool(c *gin.Context, traceId string) (models.NewTalentPool, bool) {
+	var np models.NewTalentPool
+	err := json.NewDecoder(c.Request.Body).Decode(&np)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return models.NewTalentPool{}, false
+	}
+	validate := validator.New()
+	err = validate.Struct(np)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": "invalid talent pool", "error": err.Error()})
+		return models.NewTalentPool{}, false
+	}
+	return np, true
+}
+
+// companyTalentPool loads the talent pool named in the URL and makes sure it belongs to the company in the URL,
+// in which the logged-in user has at least the role min
+func (h *handler) companyTalentPool(c *gin.Context, traceId string, claims jwt.RegisteredClaims, min string) (models.TalentPool, models.Membership, bool) {
+	companyID, ok := companyParam(c)
+	if !ok {
+		return models.TalentPool{}, models.Membership{}, false
+	}
+	me, ok := h.requireCompanyRole(c, traceId, claims, companyID, min)
+	if !ok {
+		return models.TalentPool{}, models.Membership{}, false
+	}
+	poolID, err := strconv.ParseUint(c.Param("poolID"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid pool ID"})
+		return models.TalentPool{}, models.Membership{}, false
+	}
+
+	pool, err := h.s.ViewTalentPool(c.Request.Context(), uint(poolID))
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && pool.CompanyID != companyID) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "talent pool not found"})
+		return models.TalentPool{}, models.Membership{}, false
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching talent pool"})
+		return models.TalentPool{}, models.Membership{}, false
+	}
+	return pool, me, true
+}
+
+// SearchCandidates finds the candidates who made their profile discoverable, for the recruiters of a company.
+// Filters: q (keywords of the headline and summary), skills (comma separated, all required),
+// location and role (what candidates wish for) and min_experience in years.
+func (h *handler) SearchCandidates(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	companyID, ok := companyParam(c)
+	if !ok {
+		return
+	}
+	q := models.CandidateSearch{
+		Keywords: c.Query("q"),
+		Location: c.Query("location"),
+		Role:     c.Query("role"),
+	}
+	if v := c.Query("skills"); v != "" {
+		q.Skills = strings.Split(v, ",")
+	}
+	var minYears float64
+	if v := c.Query("min_experience"); v != "" {
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil || n < 0 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid min_experience"})
+			return
+		}
+		minYears = n
+	}
+	if _, ok := h.requireCompanyRole(c, traceId, claims, companyID, models.RoleRecruiter); !ok {
+		return
+	}
+
+	limit := limitParam(c)
+	scan := limit
+	if minYears > 0 {
+		scan = candidateScanLimit
+	}
+	found, err := h.s.SearchCandidates(ctx, q, scan)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "searching candidates failed"})
+		return
+	}
+	now := time.Now()
+	candidates := make([]models.Candidate, 0, limit)
+	for _, cand := range found {
+		cand.ExperienceYears = matching.ExperienceYears(cand.Experiences, now)
+		if cand.ExperienceYears < minYears {
+			continue
+		}
+		candidates = append(candidates, cand)
+		if len(candidates) == limit {
+			break
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"candidates": candidates})
+}
+
+// CreateTalentPool adds a talent pool to a company
+func (h *handler) CreateTalentPool(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	companyID, ok := companyParam(c)
+	if !ok {
+		return
+	}
+	np, ok := decodeTalentPool(c, traceId)
+	if !ok {
+		return
+	}
+	me, ok := h.requireCompanyRole(c, traceId, claims, companyID, models.RoleRecruiter)
+	if !ok {
+		return
+	}
+	pool, err := h.s.CreateTalentPool(ctx, companyID, me.UserID, np)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "a talent pool with this name already exists"})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "saving talent pool failed"})
+		return
+	}
+	c.JSON(http.StatusCreated, pool)
+}
+
+// ViewTalentPools lists the talent pools of a company with the number of candidates in each
+func (h *handler) ViewTalentPools(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	companyID, ok := companyParam(c)
+	if !ok {
+		return
+	}
+	if _, ok := h.requireCompanyRole(c, traceId, claims, companyID, models.RoleViewer); !ok {
+		return
+	}
+	pools, err := h.s.ViewTalentPools(ctx, companyID)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching talent pools"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"talent_pools": pools})
+}
+
+// UpdateTalentPool renames a talent pool of a company
+func (h *handler) UpdateTalentPool(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	np, ok := decodeTalentPool(c, traceId)
+	if !ok {
+		return
+	}
+	pool, _, ok := h.companyTalentPool(c, traceId, claims, models.RoleRecruiter)
+	if !ok {
+		return
+	}
+	pool, err := h.s.UpdateTalentPool(ctx, pool.ID, np)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "a talent pool with this name already exists"})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "saving talent pool failed"})
+		return
+	}
+	c.JSON(http.StatusOK, pool)
+}
+
+// DeleteTalentPool removes a talent pool of a company and the candidates saved in it
+func (h *handler) DeleteTalentPool(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	pool, _, ok := h.companyTalentPool(c, traceId, claims, models.RoleRecruiter)
+	if !ok {
+		return
+	}
+	err := h.s.DeleteTalentPool(ctx, pool.ID)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "deleting talent pool failed"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ViewPoolCandidates lists the candidates saved in a talent pool, only those with the tag given in the "tag" parameter if any
+func (h *handler) ViewPoolCandidates(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	pool, _, ok := h.companyTalentPool(c, traceId, claims, models.RoleViewer)
+	if !ok {
+		return
+	}
+	candidates, err := h.s.ViewPoolCandidates(ctx, pool.ID, c.Query("tag"))
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching candidates"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"candidates": candidates})
+}
+
+// SavePoolCandidate saves a candidate into a talent pool with tags and notes, or replaces them.
+// Candidates must be discoverable or have applied to the company.
+func (h *handler) SavePoolCandidate(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	userID, err := strconv.ParseUint(c.Param("userID"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+	var nm models.NewPoolMember
+	err = json.NewDecoder(c.Request.Body).Decode(&nm)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	validate := validator.New()
+	err = validate.Struct(nm)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": "invalid candidate", "error": err.Error()})
+		return
+	}
+	pool, me, ok := h.companyTalentPool(c, traceId, claims, models.RoleRecruiter)
+	if !ok {
+		return
+	}
+
+	m, err := h.s.SavePoolMember(ctx, pool.ID, uint(userID), me.UserID, nm)
+	if errors.Is(err, models.ErrCandidateNotFound) || errors.Is(err, gorm.ErrRecordNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "candidate not found"})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "saving candidate failed"})
+		return
+	}
+	c.JSON(http.StatusOK, m)
+}
+
+// DeletePoolCandidate takes a candidate out of a talent pool
+func (h *handler) DeletePoolCandidate(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	userID, err := strconv.ParseUint(c.Param("userID"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+	pool, _, ok := h.companyTalentPool(c, traceId, claims, models.RoleRecruiter)
+	if !ok {
+		return
+	}
+	err = h.s.DeletePoolMember(ctx, pool.ID, uint(userID))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "candidate not found"})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "removing candidate failed"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// InviteCandidate mails a candidate an invitation to apply to one of the company's published jobs
+func (h *handler) InviteCandidate(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	companyID, ok := companyParam(c)
+	if !ok {
+		return
+	}
+	userID, err := strconv.ParseUint(c.Param("userID"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+	var ni models.NewJobInvitation
+	err = json.NewDecoder(c.Request.Body).Decode(&ni)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	validate := validator.New()
+	err = validate.Struct(ni)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": "invalid invitation", "error": err.Error()})
+		return
+	}
+	me, ok := h.requireCompanyRole(c, traceId, claims, companyID, models.RoleRecruiter)
+	if !ok {
+		return
+	}
+
+	inv, err := h.iv.InviteToApply(ctx, companyID, uint(userID), me.UserID, ni)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "job not found"})
+	case errors.Is(err, models.ErrCandidateNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "candidate not found"})
+	case errors.Is(err, models.ErrJobNotOpen):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrCandidateApplied):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "candidate was already invited to the job"})
+	case err != nil:
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "inviting failed"})
+	default:
+		c.JSON(http.StatusCreated, inv)
+	}
+}
+
+// ViewMyJobInvitations lists the jobs the logged-in candidate was invited to apply to
+func (h *handler) ViewMyJobInvitations(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+	uid, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	invs, err := h.s.ViewJobInvitationsByUser(ctx, uint(uid))
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching invitations"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"invitations": invs})
+}
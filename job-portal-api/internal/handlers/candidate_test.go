@@ -0,0 +1,406 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/invites"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/models/mockmodels"
+	"job-portal-api/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler_SearchCandidates(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{
+		Subject: "1",
+	}
+	junior := models.Candidate{Name: "Ben", Profile: models.Profile{UserID: 8,
+		Experiences: []models.Experience{{StartDate: "2023-01", EndDate: "2024-01"}}}}
+	senior := models.Candidate{Name: "Asha", Profile: models.Profile{UserID: 9,
+		Experiences: []models.Experience{{StartDate: "2012-01", EndDate: "2020-01"}}}}
+
+	tt := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedNames  []string
+		mockService    func(m *mockmodels.MockService)
+	}{
+		{
+			name:           "OK",
+			query:          "?q=backend&skills=Go,%20SQL&location=Berlin&role=engineer&limit=5",
+			expectedStatus: http.StatusOK,
+			expectedNames:  []string{"Ben", "Asha"},
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().SearchCandidates(gomock.Any(), gomock.Eq(models.CandidateSearch{
+					Keywords: "backend", Skills: []string{"Go", " SQL"}, Location: "Berlin", Role: "engineer",
+				}), gomock.Eq(5)).Times(1).Return([]models.Candidate{junior, senior}, nil)
+			},
+		},
+		{
+			name:           "OK_MinExperience",
+			query:          "?min_experience=5",
+			expectedStatus: http.StatusOK,
+			expectedNames:  []string{"Asha"},
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().SearchCandidates(gomock.Any(), gomock.Any(), gomock.Eq(candidateScanLimit)).Times(1).
+					Return([]models.Candidate{junior, senior}, nil)
+			},
+		},
+		{
+			name:           "Fail_MinExperience",
+			query:          "?min_experience=lots",
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().SearchCandidates(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:           "Fail_Viewer",
+			expectedStatus: http.StatusForbidden,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).Times(1).
+					Return(models.Membership{Role: models.RoleViewer}, nil)
+				m.EXPECT().ViewUser(gomock.Any(), gomock.Eq(uint(1))).Times(1).Return(models.User{}, nil)
+				m.EXPECT().SearchCandidates(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+			mockService.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).AnyTimes().
+				Return(models.Membership{Role: models.RoleRecruiter}, nil)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService)}
+			router.GET("/companies/:companyID/candidates", h.SearchCandidates)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/companies/2/candidates"+tc.query, nil)
+			require.NoError(t, err)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+			if tc.expectedStatus == http.StatusOK {
+				var res struct {
+					Candidates []models.Candidate `json:"candidates"`
+				}
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+				var names []string
+				for _, cand := range res.Candidates {
+					names = append(names, cand.Name)
+					require.Greater(t, cand.ExperienceYears, 0.0)
+				}
+				require.Equal(t, tc.expectedNames, names)
+			}
+		})
+	}
+}
+
+func TestHandler_SavePoolCandidate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{
+		Subject: "1",
+	}
+	valid := `{"tags":["Go","shortlist"],"notes":"Met at GopherCon"}`
+
+	tt := []struct {
+		name           string
+		url            string
+		body           string
+		expectedStatus int
+		mockService    func(m *mockmodels.MockService)
+	}{
+		{
+			name:           "OK",
+			url:            "/companies/2/talent-pools/4/candidates/9",
+			body:           valid,
+			expectedStatus: http.StatusOK,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().SavePoolMember(gomock.Any(), gomock.Eq(uint(4)), gomock.Eq(uint(9)), gomock.Eq(uint(1)),
+					gomock.Eq(models.NewPoolMember{Tags: []string{"Go", "shortlist"}, Notes: "Met at GopherCon"})).Times(1).
+					Return(models.PoolMember{PoolID: 4, UserID: 9, Tags: []string{"go", "shortlist"}}, nil)
+			},
+		},
+		{
+			name:           "Fail_NotVisible",
+			url:            "/companies/2/talent-pools/4/candidates/9",
+			body:           valid,
+			expectedStatus: http.StatusNotFound,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().SavePoolMember(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					Return(models.PoolMember{}, models.ErrCandidateNotFound)
+			},
+		},
+		{
+			name:           "Fail_OtherCompanyPool",
+			url:            "/companies/2/talent-pools/5/candidates/9",
+			body:           valid,
+			expectedStatus: http.StatusNotFound,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().SavePoolMember(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:           "Fail_TooManyTags",
+			url:            "/companies/2/talent-pools/4/candidates/9",
+			body:           `{"tags":["a","b","c","d","e","f","g","h","i","j","k","l","m","n","o","p","q","r","s","t","u"]}`,
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().SavePoolMember(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:           "Fail_Viewer",
+			url:            "/companies/2/talent-pools/4/candidates/9",
+			body:           valid,
+			expectedStatus: http.StatusForbidden,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).Times(1).
+					Return(models.Membership{Role: models.RoleViewer}, nil)
+				m.EXPECT().ViewUser(gomock.Any(), gomock.Eq(uint(1))).Times(1).Return(models.User{}, nil)
+				m.EXPECT().SavePoolMember(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+			mockService.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).AnyTimes().
+				Return(models.Membership{UserID: 1, Role: models.RoleRecruiter}, nil)
+			mockService.EXPECT().ViewTalentPool(gomock.Any(), gomock.Eq(uint(4))).AnyTimes().
+				Return(models.TalentPool{Model: gorm.Model{ID: 4}, CompanyID: 2}, nil)
+			mockService.EXPECT().ViewTalentPool(gomock.Any(), gomock.Eq(uint(5))).AnyTimes().
+				Return(models.TalentPool{Model: gorm.Model{ID: 5}, CompanyID: 8}, nil)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService)}
+			router.PUT("/companies/:companyID/talent-pools/:poolID/candidates/:userID", h.SavePoolCandidate)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodPut, tc.url, strings.NewReader(tc.body))
+			require.NoError(t, err)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandler_ViewPoolCandidates(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	mockService := mockmodels.NewMockService(ctrl)
+	mockService.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).AnyTimes().
+		Return(models.Membership{UserID: 1, Role: models.RoleViewer}, nil)
+	mockService.EXPECT().ViewTalentPool(gomock.Any(), gomock.Eq(uint(4))).AnyTimes().
+		Return(models.TalentPool{Model: gorm.Model{ID: 4}, CompanyID: 2}, nil)
+	mockService.EXPECT().ViewPoolCandidates(gomock.Any(), gomock.Eq(uint(4)), gomock.Eq("shortlist")).Times(1).
+		Return([]models.PoolCandidate{{PoolMember: models.PoolMember{UserID: 9, Tags: []string{"shortlist"}}, Name: "Asha"}}, nil)
+
+	router := gin.New()
+	h := handler{s: services.NewStore(mockService)}
+	router.GET("/companies/:companyID/talent-pools/:poolID/candidates", h.ViewPoolCandidates)
+
+	ctx := context.WithValue(context.Background(), auth.Key, jwt.RegisteredClaims{Subject: "1"})
+	ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/companies/2/talent-pools/4/candidates?tag=shortlist", nil)
+	require.NoError(t, err)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `"name":"Asha"`)
+	require.Contains(t, rec.Body.String(), `"tags":["shortlist"]`)
+}
+
+func TestHandler_CreateTalentPool(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{
+		Subject: "1",
+	}
+
+	tt := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		mockService    func(m *mockmodels.MockService)
+	}{
+		{
+			name:           "OK",
+			body:           `{"name":"Backend","description":"Strong Go engineers"}`,
+			expectedStatus: http.StatusCreated,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateTalentPool(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1)),
+					gomock.Eq(models.NewTalentPool{Name: "Backend", Description: "Strong Go engineers"})).Times(1).
+					Return(models.TalentPool{CompanyID: 2, Name: "Backend"}, nil)
+			},
+		},
+		{
+			name:           "Fail_NoName",
+			body:           `{"description":"Strong Go engineers"}`,
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateTalentPool(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:           "Fail_Duplicate",
+			body:           `{"name":"Backend"}`,
+			expectedStatus: http.StatusConflict,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateTalentPool(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					Return(models.TalentPool{}, gorm.ErrDuplicatedKey)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+			mockService.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).AnyTimes().
+				Return(models.Membership{UserID: 1, Role: models.RoleRecruiter}, nil)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService)}
+			router.POST("/companies/:companyID/talent-pools", h.CreateTalentPool)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/companies/2/talent-pools", strings.NewReader(tc.body))
+			require.NoError(t, err)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandler_InviteCandidate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{
+		Subject: "1",
+	}
+	valid := `{"job_id":5,"message":"We loved your talk."}`
+
+	tt := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedMails  int
+		mockService    func(m *mockmodels.MockService)
+	}{
+		{
+			name:           "OK",
+			body:           valid,
+			expectedStatus: http.StatusCreated,
+			expectedMails:  1,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateJobInvitation(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(9)), gomock.Eq(uint(1)),
+					gomock.Eq(models.NewJobInvitation{JobID: 5, Message: "We loved your talk."})).Times(1).
+					Return(models.JobInvitation{ID: 3, JobID: 5, UserID: 9}, nil)
+				m.EXPECT().ViewCompany(gomock.Any(), gomock.Eq(uint(2)), gomock.Any()).Times(1).
+					Return(models.Company{CompanyName: "Acme"}, nil)
+				m.EXPECT().ViewJobByJobId(gomock.Any(), gomock.Eq(uint(5)), gomock.Any()).Times(1).
+					Return([]models.Job{{Model: gorm.Model{ID: 5}, Title: "Go developer"}}, nil)
+				m.EXPECT().ViewUser(gomock.Any(), gomock.Eq(uint(9))).Times(1).
+					Return(models.User{Name: "Asha", Email: "asha@example.com"}, nil)
+			},
+		},
+		{
+			name:           "Fail_NoJob",
+			body:           `{"message":"Hi"}`,
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateJobInvitation(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:           "Fail_NotVisible",
+			body:           valid,
+			expectedStatus: http.StatusNotFound,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateJobInvitation(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					Return(models.JobInvitation{}, models.ErrCandidateNotFound)
+			},
+		},
+		{
+			name:           "Fail_JobNotOpen",
+			body:           valid,
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateJobInvitation(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					Return(models.JobInvitation{}, models.ErrJobNotOpen)
+			},
+		},
+		{
+			name:           "Fail_AlreadyInvited",
+			body:           valid,
+			expectedStatus: http.StatusConflict,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateJobInvitation(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					Return(models.JobInvitation{}, gorm.ErrDuplicatedKey)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+			mockService.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).AnyTimes().
+				Return(models.Membership{UserID: 1, Role: models.RoleRecruiter}, nil)
+
+			mailer := &recordingMailer{}
+			iv, err := invites.NewInviter(mockService, mailer, "https://jobs.example.com")
+			require.NoError(t, err)
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService), iv: iv}
+			router.POST("/companies/:companyID/candidates/:userID/invitations", h.InviteCandidate)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/companies/2/candidates/9/invitations", strings.NewReader(tc.body))
+			require.NoError(t, err)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+			require.Len(t, mailer.sent, tc.expectedMails)
+		})
+	}
+}
@@ -0,0 +1,410 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/storage"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// signedURLTTL is how long download links handed out by the API stay valid
+const signedURLTTL = 15 * time.Minute
+
+// extensions maps the accepted content types to the extension used in storage keys
+var extensions = map[string]string{
+	storage.MimePDF:  ".pdf",
+	storage.MimeDOCX: ".docx",
+	storage.MimeText: ".txt",
+	storage.MimePNG:  ".png",
+	storage.MimeJPEG: ".jpg",
+	storage.MimeWEBP: ".webp",
+}
+
+// storeUpload reads the "file" form field, checks it against the policy and the virus scanner,
+// writes it to the blob store and records its metadata.
+// On failure the response has already been written and ok is false.
+func (h *handler) storeUpload(c *gin.Context, traceId string, p storage.Policy, kind string, ownerId uint) (models.Document, bool) {
+	ctx := c.Request.Context()
+
+	// Leave some room for the multipart framing on top of the file itself
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, p.MaxSize+64<<10)
+	fh, err := c.FormFile("file")
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": storage.ErrTooLarge.Error()})
+		return models.Document{}, false
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "please attach a file in the 'file' field"})
+		return models.Document{}, false
+	}
+	if fh.Size > p.MaxSize {
+		c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": storage.ErrTooLarge.Error()})
+		return models.Document{}, false
+	}
+
+	f, err := fh.Open()
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return models.Document{}, false
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, p.MaxSize+1))
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return models.Document{}, false
+	}
+
+	// The content type comes from the bytes, not from what the client claims
+	contentType, err := p.Inspect(data, fh.Filename)
+	if errors.Is(err, storage.ErrTooLarge) {
+		c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+		return models.Document{}, false
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+		return models.Document{}, false
+	}
+
+	err = h.sc.Scan(ctx, fh.Filename, bytes.NewReader(data))
+	if errors.Is(err, storage.ErrInfected) {
+		log.Warn().Err(err).Str("Trace Id", traceId).Str("file", fh.Filename).Msg("upload rejected")
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": storage.ErrInfected.Error()})
+		return models.Document{}, false
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Msg("virus scan failed")
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"msg": "file could not be scanned, try again later"})
+		return models.Document{}, false
+	}
+
+	key := fmt.Sprintf("%ss/%d/%s%s", kind, ownerId, uuid.NewString(), extensions[contentType])
+	err = h.bs.Put(ctx, key, bytes.NewReader(data), int64(len(data)), contentType)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Msg("storing upload")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "upload failed"})
+		return models.Document{}, false
+	}
+
+	sum := sha256.Sum256(data)
+	doc, err := h.s.CreateDocument(ctx, models.Document{
+		OwnerID:     ownerId,
+		Kind:        kind,
+		FileName:    fh.Filename,
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		Checksum:    hex.EncodeToString(sum[:]),
+		StorageKey:  key,
+	})
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Msg("recording upload")
+		// Do not leave an orphan blob behind
+		_ = h.bs.Delete(ctx, key)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "upload failed"})
+		return models.Document{}, false
+	}
+	return doc, true
+}
+
+// UploadDocument stores a resume uploaded by the logged-in candidate
+func (h *handler) UploadDocument(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+	uid, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	doc, ok := h.storeUpload(c, traceId, storage.ResumePolicy, models.DocumentKindResume, uint(uid))
+	if !ok {
+		return
+	}
+
+	// Start pre-filling the profile right away; the candidate can retry if this fails
+	if h.rp != nil {
+		_, err = h.queueResumeParse(ctx, doc)
+		if err != nil {
+			log.Error().Err(err).Str("Trace Id", traceId).Msg("queueing resume parse")
+		}
+	}
+	c.JSON(http.StatusCreated, doc)
+}
+
+// ViewMyDocuments lists the files uploaded by the logged-in user
+func (h *handler) ViewMyDocuments(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+	uid, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	docs, err := h.s.ViewDocuments(ctx, uint(uid))
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching documents"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"documents": docs})
+}
+
+// myDocument loads the document named in the URL and makes sure the logged-in user owns it
+func (h *handler) myDocument(c *gin.Context, traceId string, claims jwt.RegisteredClaims) (models.Document, bool) {
+	documentID, err := strconv.ParseUint(c.Param("documentID"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid document ID"})
+		return models.Document{}, false
+	}
+	doc, err := h.s.ViewDocument(c.Request.Context(), uint(documentID))
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && strconv.FormatUint(uint64(doc.OwnerID), 10) != claims.Subject) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "document not found"})
+		return models.Document{}, false
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching document"})
+		return models.Document{}, false
+	}
+	return doc, true
+}
+
+// DocumentURL hands out a short-lived download link for one of the user's documents
+func (h *handler) DocumentURL(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	doc, ok := h.myDocument(c, traceId, claims)
+	if !ok {
+		return
+	}
+	h.signedURL(c, traceId, doc)
+}
+
+// signedURL answers with a download link for doc
+func (h *handler) signedURL(c *gin.Context, traceId string, doc models.Document) {
+	link, err := h.bs.SignedURL(c.Request.Context(), doc.StorageKey, signedURLTTL)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"url": link, "expires_at": time.Now().Add(signedURLTTL).UTC()})
+}
+
+// DeleteDocument removes one of the user's documents from the blob store and the database
+func (h *handler) DeleteDocument(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	doc, ok := h.myDocument(c, traceId, claims)
+	if !ok {
+		return
+	}
+	err := h.s.DeleteDocument(ctx, doc.ID)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "document deletion failed"})
+		return
+	}
+	err = h.bs.Delete(ctx, doc.StorageKey)
+	if err != nil {
+		// The row is gone already, an orphan blob is harmless
+		log.Error().Err(err).Str("Trace Id", traceId).Str("key", doc.StorageKey).Msg("deleting blob")
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// UploadCompanyLogo stores a logo image and attaches it to the company
+func (h *handler) UploadCompanyLogo(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+	uid, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	companyID, err := strconv.ParseUint(c.Param("companyID"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid company ID"})
+		return
+	}
+	if _, ok := h.requireCompanyRole(c, traceId, claims, uint(companyID), models.RoleAdmin); !ok {
+		return
+	}
+	// Nothing is stored for a company that does not exist
+	company, err := h.s.ViewCompany(ctx, uint(companyID), "")
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching company details"})
+		return
+	}
+	if company.ID == 0 {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "company not found"})
+		return
+	}
+
+	doc, ok := h.storeUpload(c, traceId, storage.LogoPolicy, models.DocumentKindLogo, uint(uid))
+	if !ok {
+		return
+	}
+	company, old, err := h.s.SetCompanyLogo(ctx, uint(companyID), doc.ID)
+	if err != nil {
+		// Do not leave an orphan logo behind
+		h.discardDocument(ctx, traceId, doc)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "company not found"})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "logo upload failed"})
+		return
+	}
+	if old.ID != 0 {
+		// The replaced logo's row is gone already, an orphan blob is harmless
+		err = h.bs.Delete(ctx, old.StorageKey)
+		if err != nil {
+			log.Error().Err(err).Str("Trace Id", traceId).Str("key", old.StorageKey).Msg("deleting blob")
+		}
+	}
+	c.JSON(http.StatusOK, company)
+}
+
+// discardDocument deletes an upload that could not be used, both its row and its blob.
+func (h *handler) discardDocument(ctx context.Context, traceId string, doc models.Document) {
+	err := h.s.DeleteDocument(ctx, doc.ID)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Uint("document", doc.ID).Msg("deleting document")
+	}
+	err = h.bs.Delete(ctx, doc.StorageKey)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Str("key", doc.StorageKey).Msg("deleting blob")
+	}
+}
+
+// CompanyLogo redirects to a signed download link of the company logo. Logos are public.
+func (h *handler) CompanyLogo(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	companyID, err := strconv.ParseUint(c.Param("companyID"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid company ID"})
+		return
+	}
+	company, err := h.s.ViewCompany(ctx, uint(companyID), "")
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching company details"})
+		return
+	}
+	if company.ID == 0 || company.LogoDocumentID == nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "company has no logo"})
+		return
+	}
+	doc, err := h.s.ViewDocument(ctx, *company.LogoDocumentID)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching logo"})
+		return
+	}
+	link, err := h.bs.SignedURL(ctx, doc.StorageKey, signedURLTTL)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	c.Redirect(http.StatusFound, link)
+}
@@ -0,0 +1,283 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+	"io"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/models/mockmodels"
+	"job-portal-api/internal/services"
+	"job-portal-api/internal/storage"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fakeScanner rejects every file whose content contains the EICAR marker
+type fakeScanner struct{}
+
+func (fakeScanner) Scan(ctx context.Context, filename string, r io.Reader) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if bytes.Contains(b, []byte("EICAR")) {
+		return storage.ErrInfected
+	}
+	return nil
+}
+
+func TestHandler_UploadDocument(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{
+		Subject: "7",
+	}
+
+	tt := []struct {
+		name           string
+		fileName       string
+		content        []byte
+		expectedStatus int
+		mockService    func(m *mockmodels.MockService)
+	}{
+		{
+			name:           "OK",
+			fileName:       "cv.pdf",
+			content:        []byte("%PDF-1.4\n1 0 obj << >> endobj\n"),
+			expectedStatus: http.StatusCreated,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateDocument(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(ctx context.Context, doc models.Document) (models.Document, error) {
+						if doc.ContentType != storage.MimePDF || doc.OwnerID != 7 || !strings.HasPrefix(doc.StorageKey, "resumes/7/") {
+							return models.Document{}, errors.New("unexpected document")
+						}
+						doc.ID = 1
+						return doc, nil
+					})
+			},
+		},
+		{
+			name:           "Fail_ExecutableRenamedToPDF",
+			fileName:       "cv.pdf",
+			content:        []byte("MZ\x90\x00\x03\x00\x00\x00\x04\x00\x00\x00\xff\xff"),
+			expectedStatus: http.StatusUnsupportedMediaType,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateDocument(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:           "Fail_Infected",
+			fileName:       "cv.txt",
+			content:        []byte("EICAR test file"),
+			expectedStatus: http.StatusUnprocessableEntity,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateDocument(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:           "Fail_TooLarge",
+			fileName:       "cv.txt",
+			content:        bytes.Repeat([]byte("a"), int(storage.ResumePolicy.MaxSize)+1),
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateDocument(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+
+			bs, err := storage.NewLocalStore(t.TempDir(), "http://localhost:8080/files", []byte("secret"))
+			require.NoError(t, err)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService), bs: bs, sc: fakeScanner{}}
+			router.POST("/me/documents", h.UploadDocument)
+
+			// Build the multipart body the way a browser would
+			var body bytes.Buffer
+			mw := multipart.NewWriter(&body)
+			fw, err := mw.CreateFormFile("file", tc.fileName)
+			require.NoError(t, err)
+			_, err = fw.Write(tc.content)
+			require.NoError(t, err)
+			require.NoError(t, mw.Close())
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/me/documents", &body)
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", mw.FormDataContentType())
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+func TestHandler_DocumentURL(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tt := []struct {
+		name           string
+		subject        string
+		expectedStatus int
+	}{
+		{name: "OK", subject: "7", expectedStatus: http.StatusOK},
+		{name: "Fail_NotOwner", subject: "8", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			mockService.EXPECT().ViewDocument(gomock.Any(), gomock.Eq(uint(3))).Times(1).
+				Return(models.Document{Model: gorm.Model{ID: 3}, OwnerID: 7, StorageKey: "resumes/7/cv.pdf"}, nil)
+
+			bs, err := storage.NewLocalStore(t.TempDir(), "http://localhost:8080/files", []byte("secret"))
+			require.NoError(t, err)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService), bs: bs}
+			router.GET("/me/documents/:documentID/url", h.DocumentURL)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{Subject: tc.subject})
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/me/documents/3/url", nil)
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+			if tc.expectedStatus == http.StatusOK {
+				require.Contains(t, rec.Body.String(), `"url":"http://localhost:8080/files/resumes/7/cv.pdf?expires=`)
+			}
+		})
+	}
+}
+
+func TestHandler_UploadCompanyLogo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{Subject: "1"}
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x00\x00\x01\x00\x00\x00\x01\x08\x02\x00\x00\x00")
+	admin := func(m *mockmodels.MockService) {
+		m.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).Times(1).
+			Return(models.Membership{UserID: 1, Role: models.RoleAdmin}, nil)
+	}
+	created := func(m *mockmodels.MockService, key *string) {
+		m.EXPECT().CreateDocument(gomock.Any(), gomock.Any()).Times(1).
+			DoAndReturn(func(ctx context.Context, doc models.Document) (models.Document, error) {
+				*key = doc.StorageKey
+				doc.ID = 5
+				return doc, nil
+			})
+	}
+
+	tt := [...]struct {
+		name           string
+		expectedStatus int
+		keepsUpload    bool
+		keepsOldLogo   bool
+		mockService    func(m *mockmodels.MockService, key *string)
+	}{
+		{
+			name:           "OK",
+			expectedStatus: http.StatusOK,
+			keepsUpload:    true,
+			mockService: func(m *mockmodels.MockService, key *string) {
+				admin(m)
+				m.EXPECT().ViewCompany(gomock.Any(), gomock.Eq(uint(2)), gomock.Any()).Times(1).
+					Return(models.Company{Model: gorm.Model{ID: 2}}, nil)
+				created(m, key)
+				m.EXPECT().SetCompanyLogo(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(5))).Times(1).
+					Return(models.Company{Model: gorm.Model{ID: 2}}, models.Document{Model: gorm.Model{ID: 4}, StorageKey: "logos/1/old.png"}, nil)
+			},
+		},
+		{
+			name:           "Fail_CompanyNotFound",
+			expectedStatus: http.StatusNotFound,
+			keepsOldLogo:   true,
+			mockService: func(m *mockmodels.MockService, key *string) {
+				admin(m)
+				m.EXPECT().ViewCompany(gomock.Any(), gomock.Eq(uint(2)), gomock.Any()).Times(1).Return(models.Company{}, nil)
+				m.EXPECT().CreateDocument(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:           "Fail_SetLogo",
+			expectedStatus: http.StatusInternalServerError,
+			keepsOldLogo:   true,
+			mockService: func(m *mockmodels.MockService, key *string) {
+				admin(m)
+				m.EXPECT().ViewCompany(gomock.Any(), gomock.Eq(uint(2)), gomock.Any()).Times(1).
+					Return(models.Company{Model: gorm.Model{ID: 2}}, nil)
+				created(m, key)
+				m.EXPECT().SetCompanyLogo(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(5))).Times(1).
+					Return(models.Company{}, models.Document{}, errors.New("db down"))
+				m.EXPECT().DeleteDocument(gomock.Any(), gomock.Eq(uint(5))).Times(1).Return(nil)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			var key string
+			tc.mockService(mockService, &key)
+
+			bs, err := storage.NewLocalStore(t.TempDir(), "http://localhost:8080/files", []byte("secret"))
+			require.NoError(t, err)
+			require.NoError(t, bs.Put(context.Background(), "logos/1/old.png", bytes.NewReader(png), int64(len(png)), storage.MimePNG))
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService), bs: bs, sc: fakeScanner{}}
+			router.POST("/companies/:companyID/logo", h.UploadCompanyLogo)
+
+			var body bytes.Buffer
+			mw := multipart.NewWriter(&body)
+			fw, err := mw.CreateFormFile("file", "logo.png")
+			require.NoError(t, err)
+			_, err = fw.Write(png)
+			require.NoError(t, err)
+			require.NoError(t, mw.Close())
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/companies/2/logo", &body)
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", mw.FormDataContentType())
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code, rec.Body.String())
+			if key != "" {
+				_, err = bs.Get(context.Background(), key)
+				require.Equal(t, tc.keepsUpload, err == nil, "upload kept")
+			}
+			_, err = bs.Get(context.Background(), "logos/1/old.png")
+			require.Equal(t, tc.keepsOldLogo, err == nil, "old logo kept")
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"job-portal-api/internal/feed"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/rs/zerolog/log"
+)
+
+// feedPageSize is how many jobs a feed holds unless asked for fewer.
+const feedPageSize = 500
+
+// PublicJob shows a job to anyone, including crawlers.
+// The Accept header picks plain JSON, schema.org JSON-LD or an HTML page embedding the JSON-LD.
+func (h *handler) PublicJob(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	jobID, err := strconv.ParseUint(c.Param("jobID"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid job ID"})
+		return
+	}
+	jobs, err := h.s.ViewJobByJobId(ctx, uint(jobID), "")
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching job details"})
+		return
+	}
+	if len(jobs) == 0 || jobs[0].Status != models.JobPublished {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "job not found"})
+		return
+	}
+	h.recordJobView(c, traceId, jobs[0])
+
+	c.Header("Vary", "Accept")
+	switch c.NegotiateFormat(binding.MIMEJSON, feed.MediaJSONLD, feed.MediaHTML) {
+	case feed.MediaJSONLD:
+		h.renderJobPosting(c, traceId, jobs[0])
+	case feed.MediaHTML:
+		company, ok := h.jobCompany(c, traceId, jobs[0])
+		if !ok {
+			return
+		}
+		c.Header("Content-Type", "text/html; charset=utf-8")
+		c.Status(http.StatusOK)
+		err = h.fr.WriteHTML(c.Writer, jobs[0], company)
+		if err != nil {
+			log.Error().Err(err).Str("Trace Id", traceId).Msg("rendering job page")
+		}
+	default:
+		c.JSON(http.StatusOK, jobs[0])
+	}
+}
+
+// JobFeed is the public XML feed of jobs for aggregators, in Indeed's layout or as RSS (format=rss).
+// Aggregators polling for changes pass since (RFC 3339) and follow the Link header to the next page.
+func (h *handler) JobFeed(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	format := c.DefaultQuery("format", feed.FormatIndeed)
+	if format != feed.FormatIndeed && format != feed.FormatRSS {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "format must be indeed or rss"})
+		return
+	}
+	var since time.Time
+	var err error
+	if v := c.Query("since"); v != "" {
+		since, err = time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "since must be an RFC 3339 time"})
+			return
+		}
+	}
+	var after uint64
+	if v := c.Query("after"); v != "" {
+		after, err = strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid after ID"})
+			return
+		}
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(feedPageSize)))
+	if err != nil || limit <= 0 || limit > feedPageSize {
+		limit = feedPageSize
+	}
+
+	jobs, err := h.s.FeedJobs(ctx, since, uint(after), limit)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching jobs"})
+		return
+	}
+	ids := make([]uint, 0, len(jobs))
+	for _, job := range jobs {
+		ids = append(ids, job.CompanyID)
+	}
+	list, err := h.s.ViewCompanies(ctx, ids)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching companies"})
+		return
+	}
+	companies := make(map[uint]models.Company, len(list))
+	for _, company := range list {
+		companies[company.ID] = company
+	}
+
+	built := time.Now()
+	if len(jobs) > 0 {
+		last := jobs[len(jobs)-1]
+		built = last.UpdatedAt
+		c.Header("Last-Modified", last.UpdatedAt.UTC().Format(http.TimeFormat))
+		if len(jobs) == limit {
+			q := url.Values{}
+			q.Set("format", format)
+			q.Set("since", last.UpdatedAt.UTC().Format(time.RFC3339Nano))
+			q.Set("after", strconv.FormatUint(uint64(last.ID), 10))
+			q.Set("limit", strconv.Itoa(limit))
+			c.Header("Link", fmt.Sprintf(`<%s?%s>; rel="next"`, h.fr.FeedURL(), q.Encode()))
+		}
+	}
+	contentType := "application/xml; charset=utf-8"
+	if format == feed.FormatRSS {
+		contentType = "application/rss+xml; charset=utf-8"
+	}
+	c.Header("Content-Type", contentType)
+	c.Status(http.StatusOK)
+	err = h.fr.WriteXML(c.Writer, format, jobs, companies, built)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Msg("writing job feed")
+	}
+}
+
+// renderJobPosting responds with the job as schema.org JSON-LD
+func (h *handler) renderJobPosting(c *gin.Context, traceId string, job models.Job) {
+	company, ok := h.jobCompany(c, traceId, job)
+	if !ok {
+		return
+	}
+	body, err := json.Marshal(h.fr.JobPosting(job, company))
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	c.Data(http.StatusOK, feed.MediaJSONLD+"; charset=utf-8", body)
+}
+
+// jobCompany loads the company that posted the job
+func (h *handler) jobCompany(c *gin.Context, traceId string, job models.Job) (models.Company, bool) {
+	company, err := h.s.ViewCompany(c.Request.Context(), job.CompanyID, "")
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching company details"})
+		return models.Company{}, false
+	}
+	return company, true
+}
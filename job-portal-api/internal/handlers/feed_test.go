@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+	"job-portal-api/internal/feed"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/models/mockmodels"
+	"job-portal-api/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHandler_PublicJob(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	job := models.Job{Model: gorm.Model{ID: 3}, Title: "Go developer", ExperienceLevel: "senior", CompanyID: 2, Status: models.JobPublished}
+
+	tt := []struct {
+		name                string
+		accept              string
+		expectedStatus      int
+		expectedContentType string
+		expectedBody        string
+		mockService         func(m *mockmodels.MockService)
+	}{
+		{
+			name:                "OK_JSON",
+			accept:              "application/json",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/json; charset=utf-8",
+			expectedBody:        `"title":"Go developer"`,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewJobByJobId(gomock.Any(), gomock.Eq(uint(3)), gomock.Any()).Times(1).Return([]models.Job{job}, nil)
+				m.EXPECT().RecordJobView(gomock.Any(), gomock.Eq(uint(3)), gomock.Eq(uint(2)), gomock.Any()).Times(1).Return(nil)
+			},
+		},
+		{
+			name:                "OK_JSONLD",
+			accept:              "application/ld+json",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/ld+json; charset=utf-8",
+			expectedBody:        `"hiringOrganization":{"@type":"Organization","name":"Acme"}`,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewJobByJobId(gomock.Any(), gomock.Eq(uint(3)), gomock.Any()).Times(1).Return([]models.Job{job}, nil)
+				m.EXPECT().RecordJobView(gomock.Any(), gomock.Eq(uint(3)), gomock.Eq(uint(2)), gomock.Any()).Times(1).Return(nil)
+				m.EXPECT().ViewCompany(gomock.Any(), gomock.Eq(uint(2)), gomock.Any()).Times(1).
+					Return(models.Company{Model: gorm.Model{ID: 2}, CompanyName: "Acme"}, nil)
+			},
+		},
+		{
+			name:                "OK_HTML",
+			accept:              "text/html,application/xhtml+xml,*/*;q=0.8",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/html; charset=utf-8",
+			expectedBody:        `<script type="application/ld+json">`,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewJobByJobId(gomock.Any(), gomock.Eq(uint(3)), gomock.Any()).Times(1).Return([]models.Job{job}, nil)
+				m.EXPECT().RecordJobView(gomock.Any(), gomock.Eq(uint(3)), gomock.Eq(uint(2)), gomock.Any()).Times(1).Return(nil)
+				m.EXPECT().ViewCompany(gomock.Any(), gomock.Eq(uint(2)), gomock.Any()).Times(1).
+					Return(models.Company{Model: gorm.Model{ID: 2}, CompanyName: "Acme"}, nil)
+			},
+		},
+		{
+			name:           "Fail_Draft",
+			accept:         "application/json",
+			expectedStatus: http.StatusNotFound,
+			mockService: func(m *mockmodels.MockService) {
+				draft := job
+				draft.Status = models.JobDraft
+				m.EXPECT().ViewJobByJobId(gomock.Any(), gomock.Eq(uint(3)), gomock.Any()).Times(1).Return([]models.Job{draft}, nil)
+			},
+		},
+		{
+			name:           "Fail_NotFound",
+			accept:         "application/ld+json",
+			expectedStatus: http.StatusNotFound,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewJobByJobId(gomock.Any(), gomock.Eq(uint(3)), gomock.Any()).Times(1).Return(nil, nil)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService), fr: feed.NewRenderer("https://jobs.example.com", "Job Portal"),
+				vs: newTestVisitors(t)}
+			router.GET("/jobs/:jobID", h.PublicJob)
+
+			ctx := context.WithValue(context.Background(), middleware.TraceIdKey, "fake-trace-id")
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/jobs/3", nil)
+			require.NoError(t, err)
+			req.Header.Set("Accept", tc.accept)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+			if tc.expectedContentType != "" {
+				require.Equal(t, tc.expectedContentType, rec.Header().Get("Content-Type"))
+				require.Equal(t, "Accept", rec.Header().Get("Vary"))
+			}
+			require.Contains(t, rec.Body.String(), tc.expectedBody)
+		})
+	}
+}
+
+func TestHandler_JobFeed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	since := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	updated := time.Date(2024, 3, 2, 10, 0, 0, 500, time.UTC)
+
+	ctrl := gomock.NewController(t)
+	mockService := mockmodels.NewMockService(ctrl)
+	mockService.EXPECT().FeedJobs(gomock.Any(), gomock.Eq(since), gomock.Eq(uint(0)), gomock.Eq(1)).Times(1).
+		Return([]models.Job{{Model: gorm.Model{ID: 9, UpdatedAt: updated}, Title: "SRE", CompanyID: 2}}, nil)
+	mockService.EXPECT().ViewCompanies(gomock.Any(), gomock.Eq([]uint{2})).Times(1).
+		Return([]models.Company{{Model: gorm.Model{ID: 2}, CompanyName: "Acme"}}, nil)
+
+	router := gin.New()
+	h := handler{s: services.NewStore(mockService), fr: feed.NewRenderer("https://jobs.example.com", "Job Portal"),
+		vs: newTestVisitors(t)}
+	router.GET("/feeds/jobs.xml", h.JobFeed)
+
+	ctx := context.WithValue(context.Background(), middleware.TraceIdKey, "fake-trace-id")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/feeds/jobs.xml?since=2024-03-01T00:00:00Z&limit=1", nil)
+	require.NoError(t, err)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "application/xml; charset=utf-8", rec.Header().Get("Content-Type"))
+	require.Equal(t, "Sat, 02 Mar 2024 10:00:00 GMT", rec.Header().Get("Last-Modified"))
+	require.Equal(t, `<https://jobs.example.com/feeds/jobs.xml?after=9&format=indeed&limit=1&since=2024-03-02T10%3A00%3A00.0000005Z>; rel="next"`,
+		rec.Header().Get("Link"))
+	require.Contains(t, rec.Body.String(), "<company><![CDATA[Acme]]></company>")
+}
@@ -2,8 +2,19 @@ package handlers
 
 import (
 	"fmt"
+	"job-portal-api/internal/analytics"
+	"job-portal-api/internal/feed"
+	"job-portal-api/internal/interviews"
+	"job-portal-api/internal/invites"
+	"job-portal-api/internal/matching"
+	"job-portal-api/internal/messaging"
 	"job-portal-api/internal/models"
+	"job-portal-api/internal/offers"
+	"job-portal-api/internal/resume"
+	"job-portal-api/internal/salary"
 	"job-portal-api/internal/services"
+	"job-portal-api/internal/storage"
+	"job-portal-api/internal/webhooks"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -16,19 +27,44 @@ import (
 
 // Define a function called API that takes an argument a of type *auth.Auth
 // and returns a pointer to a gin.Engine
+// bs is where uploaded files are kept, sc checks every upload before it is stored
+// rp parses uploaded resumes in the background and me keeps job/candidate match scores fresh
+// wh delivers domain events to the webhooks companies registered and fr renders jobs for crawlers and aggregators
+// iv mails the invitations to join a company or apply to its jobs, and ic the news about interviews
+// mh pushes new messages to the participants of a conversation while they are connected
+// of writes the letters of the offers sent to candidates
+// vs tells anonymous visitors apart when counting job views
 
-func API(a *auth.Auth, c *models.Conn) *gin.Engine {
+func API(a *auth.Auth, c *models.Conn, bs storage.BlobStore, sc storage.Scanner, rp *resume.Pipeline,
+	me *matching.Engine, wh *webhooks.Dispatcher, fr *feed.Renderer, iv *invites.Inviter,
+	ic *interviews.Coordinator, mh *messaging.Hub, of *offers.Issuer, sr *salary.Reporter,
+	vs *analytics.Visitors) *gin.Engine {
 
 	// Create a new Gin engine; Gin is a HTTP web framework written in Go
 	r := gin.New()
+	// The client IP, kept in the audit log, comes from X-Forwarded-For only when set by a trusted proxy.
+	// None is trusted unless the caller configures them on the returned engine.
+	r.SetTrustedProxies(nil)
 
 	// Attempt to create new middleware with authentication
 	// Here, *auth.Auth passed as a parameter will be used to set up the middleware
 	m, err := middleware.NewMid(a)
 	ms := services.NewStore(c)
 	h := handler{
-		s: ms,
-		a: a,
+		s:  ms,
+		a:  a,
+		bs: bs,
+		sc: sc,
+		rp: rp,
+		me: me,
+		wh: wh,
+		fr: fr,
+		iv: iv,
+		ic: ic,
+		mh: mh,
+		of: of,
+		sr: sr,
+		vs: vs,
 	}
 
 	// If there is an error in setting up the middleware, panic and stop the application
@@ -53,6 +89,130 @@ func API(a *auth.Auth, c *models.Conn) *gin.Engine {
 	r.GET("/viewjob/:companyID/jobs", m.Authenticate(h.ViewJobByCompId))
 	r.GET("/viewjobbyid/:jobID/jobs", m.Authenticate(h.ViewJobByJobId))
 	r.GET("/viewjoball", m.Authenticate(h.ViewJobAll))
+	r.POST("/companies/:companyID/jobs/import", m.Authenticate(h.ImportJobs))
+	r.GET("/companies/:companyID/jobs/export", m.Authenticate(h.ExportJobs))
+	r.GET("/companies/:companyID/jobs", m.Authenticate(h.ViewCompanyJobs))
+	r.POST("/jobs/:jobID/status", m.Authenticate(h.TransitionJob))
+	r.GET("/me/profile", m.Authenticate(h.ViewMyProfile))
+	r.PUT("/me/profile", m.Authenticate(h.UpdateMyProfile))
+	r.DELETE("/me/profile", m.Authenticate(h.DeleteMyProfile))
+	r.GET("/candidates/:userID/profile", m.Authenticate(h.ViewCandidateProfile))
+	r.POST("/me/documents", m.Authenticate(h.UploadDocument))
+	r.GET("/me/documents", m.Authenticate(h.ViewMyDocuments))
+	r.GET("/me/documents/:documentID/url", m.Authenticate(h.DocumentURL))
+	r.DELETE("/me/documents/:documentID", m.Authenticate(h.DeleteDocument))
+	r.POST("/me/documents/:documentID/parse", m.Authenticate(h.ParseResume))
+	r.GET("/me/documents/:documentID/parse", m.Authenticate(h.ViewResumeParse))
+	r.POST("/me/documents/:documentID/parse/confirm", m.Authenticate(h.ConfirmResumeParse))
+	r.POST("/companies/:companyID/logo", m.Authenticate(h.UploadCompanyLogo))
+	r.GET("/companies/:companyID/logo", h.CompanyLogo)
+	r.GET("/companies/:companyID", h.CompanyPage)
+	r.PUT("/companies/:companyID", m.Authenticate(h.UpdateCompany))
+	r.GET("/companies/:companyID/reviews", h.ViewCompanyReviews)
+	r.POST("/companies/:companyID/reviews", m.Authenticate(h.CreateCompanyReview))
+	r.PUT("/reviews/:reviewID", m.Authenticate(h.UpdateCompanyReview))
+	r.DELETE("/reviews/:reviewID", m.Authenticate(h.DeleteCompanyReview))
+	r.GET("/me/reviews", m.Authenticate(h.ViewMyReviews))
+	r.GET("/admin/reviews", m.Authenticate(h.ViewPendingReviews))
+	r.POST("/admin/reviews/:reviewID/moderation", m.Authenticate(h.ModerateReview))
+	r.GET("/jobs/search", m.Authenticate(h.SearchJobs))
+	r.GET("/jobs/:jobID", h.PublicJob)
+	r.GET("/feeds/jobs.xml", h.JobFeed)
+	r.POST("/jobs/:jobID/apply/start", m.Authenticate(h.StartApplication))
+	r.POST("/jobs/:jobID/apply", m.Authenticate(h.Apply))
+	r.GET("/jobs/:jobID/applications", m.Authenticate(h.ViewJobApplications))
+	r.GET("/jobs/:jobID/analytics", m.Authenticate(h.JobAnalytics))
+	r.GET("/companies/:companyID/analytics", m.Authenticate(h.CompanyAnalytics))
+	r.GET("/insights/salaries", m.Authenticate(h.SalaryInsights))
+	r.GET("/me/applications", m.Authenticate(h.ViewMyApplications))
+	r.GET("/applications/:applicationID/resume", m.Authenticate(h.ApplicationResume))
+	r.GET("/me/recommended-jobs", m.Authenticate(h.RecommendedJobs))
+	r.GET("/jobs/:jobID/recommended-candidates", m.Authenticate(h.RecommendedCandidates))
+	r.POST("/me/saved-searches", m.Authenticate(h.CreateSavedSearch))
+	r.GET("/me/saved-searches", m.Authenticate(h.ViewMySavedSearches))
+	r.PUT("/me/saved-searches/:searchID", m.Authenticate(h.UpdateSavedSearch))
+	r.DELETE("/me/saved-searches/:searchID", m.Authenticate(h.DeleteSavedSearch))
+	r.GET("/saved-searches/unsubscribe", h.ConfirmUnsubscribeSavedSearch)
+	r.POST("/saved-searches/unsubscribe", h.UnsubscribeSavedSearch)
+	r.POST("/companies/:companyID/webhooks", m.Authenticate(h.CreateWebhook))
+	r.GET("/companies/:companyID/webhooks", m.Authenticate(h.ViewWebhooks))
+	r.DELETE("/companies/:companyID/webhooks/:webhookID", m.Authenticate(h.DeleteWebhook))
+	r.POST("/companies/:companyID/webhooks/:webhookID/enable", m.Authenticate(h.EnableWebhook))
+	r.GET("/companies/:companyID/webhooks/:webhookID/deliveries", m.Authenticate(h.ViewWebhookDeliveries))
+	r.POST("/companies/:companyID/webhooks/:webhookID/test", m.Authenticate(h.SendTestWebhook))
+	r.GET("/admin/audit-log", m.Authenticate(h.ViewAuditLog))
+	r.GET("/admin/tasks/dead", m.Authenticate(h.ViewDeadTasks))
+	r.POST("/admin/tasks/:taskID/requeue", m.Authenticate(h.RequeueDeadTask))
+	r.GET("/companies/:companyID/members", m.Authenticate(h.ViewMembers))
+	r.PUT("/companies/:companyID/members/:userID", m.Authenticate(h.UpdateMember))
+	r.DELETE("/companies/:companyID/members/:userID", m.Authenticate(h.DeleteMember))
+	r.POST("/companies/:companyID/invitations", m.Authenticate(h.CreateInvitation))
+	r.GET("/companies/:companyID/invitations", m.Authenticate(h.ViewInvitations))
+	r.DELETE("/companies/:companyID/invitations/:invitationID", m.Authenticate(h.DeleteInvitation))
+	r.POST("/invitations/accept", m.Authenticate(h.AcceptInvitation))
+	r.GET("/me/companies", m.Authenticate(h.ViewMyCompanies))
+	r.POST("/applications/:applicationID/interviews", m.Authenticate(h.ProposeInterview))
+	r.GET("/applications/:applicationID/interviews", m.Authenticate(h.ViewApplicationInterviews))
+	r.POST("/interviews/:interviewID/select", m.Authenticate(h.SelectInterviewSlot))
+	r.POST("/interviews/:interviewID/cancel", m.Authenticate(h.CancelInterview))
+	r.GET("/interviews/:interviewID/calendar.ics", m.Authenticate(h.InterviewCalendar))
+	r.GET("/me/interviews", m.Authenticate(h.ViewMyInterviews))
+	r.GET("/me/calendar-feed", m.Authenticate(h.MyCalendarFeed))
+	r.POST("/me/calendar-feed", m.Authenticate(h.MyCalendarFeed))
+	r.GET("/calendar/:token", h.CalendarFeed)
+	r.GET("/applications/:applicationID/messages", m.Authenticate(h.ViewMessages))
+	r.POST("/applications/:applicationID/messages", m.Authenticate(h.SendMessage))
+	r.POST("/applications/:applicationID/messages/read", m.Authenticate(h.MarkMessagesRead))
+	r.POST("/applications/:applicationID/attachments", m.Authenticate(h.UploadMessageAttachment))
+	r.GET("/applications/:applicationID/messages/:messageID/attachment", m.Authenticate(h.MessageAttachment))
+	r.GET("/me/conversations", m.Authenticate(h.ViewMyConversations))
+	r.GET("/me/messages/stream", m.Authenticate(h.MessageStream))
+	r.GET("/companies/:companyID/pipeline", m.Authenticate(h.ViewCompanyPipeline))
+	r.PUT("/companies/:companyID/pipeline", m.Authenticate(h.UpdateCompanyPipeline))
+	r.GET("/jobs/:jobID/pipeline", m.Authenticate(h.ViewJobPipeline))
+	r.PUT("/jobs/:jobID/pipeline", m.Authenticate(h.UpdateJobPipeline))
+	r.GET("/jobs/:jobID/board", m.Authenticate(h.ViewJobBoard))
+	r.POST("/applications/:applicationID/stage", m.Authenticate(h.MoveApplication))
+	r.GET("/applications/:applicationID/stages", m.Authenticate(h.ViewApplicationStages))
+	r.PUT("/jobs/:jobID/scorecard-template", m.Authenticate(h.SetScorecardTemplate))
+	r.GET("/jobs/:jobID/scorecard-template", m.Authenticate(h.ViewScorecardTemplate))
+	r.POST("/applications/:applicationID/scorecards", m.Authenticate(h.SubmitScorecard))
+	r.GET("/applications/:applicationID/scorecards", m.Authenticate(h.ViewApplicationScorecards))
+	r.GET("/jobs/:jobID/scorecards/summary", m.Authenticate(h.ViewJobScorecardSummary))
+	r.PUT("/jobs/:jobID/screening-questions", m.Authenticate(h.SetScreeningQuestions))
+	r.GET("/jobs/:jobID/screening-questions", m.Authenticate(h.ViewScreeningQuestions))
+	r.GET("/jobs/:jobID/questions", h.JobQuestions)
+	r.POST("/companies/:companyID/offer-templates", m.Authenticate(h.CreateOfferTemplate))
+	r.GET("/companies/:companyID/offer-templates", m.Authenticate(h.ViewOfferTemplates))
+	r.PUT("/companies/:companyID/offer-templates/:templateID", m.Authenticate(h.UpdateOfferTemplate))
+	r.DELETE("/companies/:companyID/offer-templates/:templateID", m.Authenticate(h.DeleteOfferTemplate))
+	r.POST("/applications/:applicationID/offers", m.Authenticate(h.SendOffer))
+	r.GET("/applications/:applicationID/offers", m.Authenticate(h.ViewApplicationOffers))
+	r.GET("/me/offers", m.Authenticate(h.ViewMyOffers))
+	r.GET("/offers/:offerID/letter", m.Authenticate(h.OfferLetter))
+	r.POST("/offers/:offerID/accept", m.Authenticate(h.AcceptOffer))
+	r.POST("/offers/:offerID/decline", m.Authenticate(h.DeclineOffer))
+	r.GET("/companies/:companyID/candidates", m.Authenticate(h.SearchCandidates))
+	r.POST("/companies/:companyID/candidates/:userID/invitations", m.Authenticate(h.InviteCandidate))
+	r.POST("/companies/:companyID/talent-pools", m.Authenticate(h.CreateTalentPool))
+	r.GET("/companies/:companyID/talent-pools", m.Authenticate(h.ViewTalentPools))
+	r.PUT("/companies/:companyID/talent-pools/:poolID", m.Authenticate(h.UpdateTalentPool))
+	r.DELETE("/companies/:companyID/talent-pools/:poolID", m.Authenticate(h.DeleteTalentPool))
+	r.GET("/companies/:companyID/talent-pools/:poolID/candidates", m.Authenticate(h.ViewPoolCandidates))
+	r.PUT("/companies/:companyID/talent-pools/:poolID/candidates/:userID", m.Authenticate(h.SavePoolCandidate))
+	r.DELETE("/companies/:companyID/talent-pools/:poolID/candidates/:userID", m.Authenticate(h.DeletePoolCandidate))
+	r.GET("/me/job-invitations", m.Authenticate(h.ViewMyJobInvitations))
+	r.GET("/me/bookmarks", m.Authenticate(h.ViewMyBookmarks))
+	r.PUT("/me/bookmarks/:jobID", m.Authenticate(h.BookmarkJob))
+	r.DELETE("/me/bookmarks/:jobID", m.Authenticate(h.DeleteBookmark))
+	r.GET("/me/follows", m.Authenticate(h.ViewMyFollows))
+	r.PUT("/me/follows/:companyID", m.Authenticate(h.FollowCompany))
+	r.DELETE("/me/follows/:companyID", m.Authenticate(h.UnfollowCompany))
+
+	// The local blob store serves its own signed download links
+	if ls, ok := bs.(*storage.LocalStore); ok {
+		r.GET("/files/*key", gin.WrapH(http.StripPrefix("/files", ls)))
+	}
 
 	// Return the prepared Gin engine
 	return r
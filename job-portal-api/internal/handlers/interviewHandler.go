@@ -0,0 +1,429 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/calendar"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// feedHistory is how far back calendar feeds reach.
+const feedHistory = 30 * 24 * time.Hour
+
+// requireApplicationRole loads the application named in the URL and makes sure the logged-in user
+// is its applicant, when applicant is set, or has at least the role min in the company that posted the job.
+func (h *handler) requireApplicationRole(c *gin.Context, traceId string, claims jwt.RegisteredClaims, min string, applicant bool) (models.Application, bool) {
+	app, _, ok := h.requireApplicationMember(c, traceId, claims, min, applicant)
+	return app, ok
+}
+
+// requireApplicationMember is requireApplicationRole also returning the membership of the logged-in user,
+// which is empty when they passed as the applicant.
+func (h *handler) requireApplicationMember(c *gin.Context, traceId string, claims jwt.RegisteredClaims, min string, applicant bool) (models.Application, models.Membership, bool) {
+	ctx := c.Request.Context()
+	applicationID, err := strconv.ParseUint(c.Param("applicationID"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid application ID"})
+		return models.Application{}, models.Membership{}, false
+	}
+	app, err := h.s.ViewApplication(ctx, uint(applicationID))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "application not found"})
+		return models.Application{}, models.Membership{}, false
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching application"})
+		return models.Application{}, models.Membership{}, false
+	}
+	if applicant && strconv.FormatUint(uint64(app.UserID), 10) == claims.Subject {
+		return app, models.Membership{}, true
+	}
+	jobs, err := h.s.ViewJobByJobId(ctx, app.JobID, claims.Subject)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching job details"})
+		return models.Application{}, models.Membership{}, false
+	}
+	if len(jobs) == 0 {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "application not found"})
+		return models.Application{}, models.Membership{}, false
+	}
+	m, ok := h.requireCompanyRole(c, traceId, claims, jobs[0].CompanyID, min)
+	return app, m, ok
+}
+
+// requireInterviewRole loads the interview named in the URL and makes sure the logged-in user
+// is its candidate or has at least the role min in the company holding it.
+func (h *handler) requireInterviewRole(c *gin.Context, traceId string, claims jwt.RegisteredClaims, min string) (models.Interview, bool) {
+	interviewID, err := strconv.ParseUint(c.Param("interviewID"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid interview ID"})
+		return models.Interview{}, false
+	}
+	in, err := h.s.ViewInterview(c.Request.Context(), uint(interviewID))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "interview not found"})
+		return models.Interview{}, false
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching interview"})
+		return models.Interview{}, false
+	}
+	if strconv.FormatUint(uint64(in.CandidateID), 10) == claims.Subject {
+		return in, true
+	}
+	_, ok := h.requireCompanyRole(c, traceId, claims, in.CompanyID, min)
+	return in, ok
+}
+
+// interviewError responds to the errors of planning an interview, reporting whether there was one
+func interviewError(c *gin.Context, traceId string, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "interview not found"})
+	case errors.Is(err, models.ErrInterviewSlot):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrInterviewConflict), errors.Is(err, models.ErrInterviewState),
+		errors.Is(err, models.ErrApplicationWithdrawn):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "planning the interview failed"})
+	}
+	return true
+}
+
+// ProposeInterview offers the candidate of an application slots for an interview with the logged-in recruiter
+func (h *handler) ProposeInterview(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	app, ok := h.requireApplicationRole(c, traceId, claims, models.RoleRecruiter, false)
+	if !ok {
+		return
+	}
+
+	var ni models.NewInterview
+	err := json.NewDecoder(c.Request.Body).Decode(&ni)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	validate := validator.New()
+	err = validate.Struct(ni)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": "invalid interview details", "error": err.Error()})
+		return
+	}
+
+	uid, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	in, err := h.ic.Propose(ctx, app.ID, uint(uid), ni)
+	if interviewError(c, traceId, err) {
+		return
+	}
+	c.JSON(http.StatusCreated, in)
+}
+
+// ViewApplicationInterviews lists the interviews planned for an application, for its applicant and the company
+func (h *handler) ViewApplicationInterviews(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	app, ok := h.requireApplicationRole(c, traceId, claims, models.RoleViewer, true)
+	if !ok {
+		return
+	}
+	ins, err := h.s.ViewApplicationInterviews(ctx, app.ID)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching interviews"})
+		return
+	}
+	c.JSON(http.StatusOK, ins)
+}
+
+// SelectInterviewSlot schedules an interview in the slot its candidate picked
+func (h *handler) SelectInterviewSlot(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	interviewID, err := strconv.ParseUint(c.Param("interviewID"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid interview ID"})
+		return
+	}
+	var body struct {
+		SlotID uint `json:"slot_id" validate:"required"`
+	}
+	err = json.NewDecoder(c.Request.Body).Decode(&body)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	validate := validator.New()
+	err = validate.Struct(body)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": "slot_id is required", "error": err.Error()})
+		return
+	}
+
+	uid, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	// Only the candidate picks; anyone else gets a not found from the store
+	in, err := h.ic.Select(ctx, uint(interviewID), body.SlotID, uint(uid))
+	if interviewError(c, traceId, err) {
+		return
+	}
+	c.JSON(http.StatusOK, in)
+}
+
+// CancelInterview calls off an interview, either by its candidate or by a recruiter of the company
+func (h *handler) CancelInterview(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	in, ok := h.requireInterviewRole(c, traceId, claims, models.RoleRecruiter)
+	if !ok {
+		return
+	}
+	// The reason is optional, so is the body
+	var body struct {
+		Reason string `json:"reason" validate:"max=1000"`
+	}
+	err := json.NewDecoder(c.Request.Body).Decode(&body)
+	if err != nil && !errors.Is(err, io.EOF) {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	validate := validator.New()
+	err = validate.Struct(body)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": "reason is too long", "error": err.Error()})
+		return
+	}
+
+	in, err = h.ic.Cancel(ctx, in.ID, body.Reason)
+	if interviewError(c, traceId, err) {
+		return
+	}
+	c.JSON(http.StatusOK, in)
+}
+
+// InterviewCalendar downloads a scheduled interview as an iCalendar file
+func (h *handler) InterviewCalendar(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	in, ok := h.requireInterviewRole(c, traceId, claims, models.RoleViewer)
+	if !ok {
+		return
+	}
+	if in.StartsAt == nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "interview is not scheduled yet"})
+		return
+	}
+	body, err := h.ic.Calendar(ctx, "", []models.Interview{in})
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in rendering the interview"})
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="interview-`+strconv.FormatUint(uint64(in.ID), 10)+`.ics"`)
+	c.Data(http.StatusOK, calendar.ContentType, body)
+}
+
+// ViewMyInterviews lists the upcoming interviews of the logged-in user, as candidate or recruiter,
+// and the proposals still waiting for a slot to be picked
+func (h *handler) ViewMyInterviews(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	uid, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	ins, err := h.s.ViewUserInterviews(ctx, uint(uid), time.Now().UTC())
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching interviews"})
+		return
+	}
+	c.JSON(http.StatusOK, ins)
+}
+
+// MyCalendarFeed returns the address of the logged-in user's interview calendar for calendar apps.
+// POSTing replaces the address, for when it was shared by mistake.
+func (h *handler) MyCalendarFeed(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	uid, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	token, err := h.s.CalendarFeedToken(ctx, uint(uid), c.Request.Method == http.MethodPost)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching the calendar feed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"url": h.ic.FeedURL(token)})
+}
+
+// CalendarFeed serves the interviews of the user owning the token in the URL as an iCalendar feed.
+// Calendar apps cannot log in, the token is the credential.
+func (h *handler) CalendarFeed(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+	uid, err := h.s.ViewCalendarFeedUser(ctx, token)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "calendar not found"})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching the calendar"})
+		return
+	}
+	ins, err := h.s.ViewUserInterviews(ctx, uid, time.Now().UTC().Add(-feedHistory))
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching interviews"})
+		return
+	}
+	body, err := h.ic.Calendar(ctx, "Interviews", ins)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in rendering the calendar"})
+		return
+	}
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(http.StatusOK, calendar.ContentType, body)
+}
@@ -0,0 +1,283 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/interviews"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/models/mockmodels"
+	"job-portal-api/internal/queue"
+	"job-portal-api/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestCoordinator returns an interview coordinator over the mock, which also stands in for the queue
+// and answers the lookups made for emails.
+func newTestCoordinator(t *testing.T, m *mockmodels.MockService) *interviews.Coordinator {
+	m.EXPECT().ViewUser(gomock.Any(), gomock.Any()).AnyTimes().Return(models.User{Name: "Asha", Email: "asha@example.com"}, nil)
+	m.EXPECT().ViewCompany(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(models.Company{CompanyName: "Acme"}, nil)
+	m.EXPECT().EnqueueTask(gomock.Any(), gomock.Any()).AnyTimes().Return(models.Task{}, nil)
+	q, err := queue.New(m, queue.Options{})
+	require.NoError(t, err)
+	co, err := interviews.NewCoordinator(m, &recordingMailer{}, q, "https://jobs.example.com")
+	require.NoError(t, err)
+	return co
+}
+
+func TestHandler_ProposeInterview(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{
+		Subject: "1",
+	}
+	valid := `{"slots":["2099-11-02T10:00","2099-11-02T14:00"],"duration_minutes":45,"timezone":"Europe/Berlin"}`
+
+	tt := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		mockService    func(m *mockmodels.MockService)
+	}{
+		{
+			name:           "OK",
+			body:           valid,
+			expectedStatus: http.StatusCreated,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateInterview(gomock.Any(), gomock.Eq(uint(3)), gomock.Eq(uint(1)), gomock.Any()).Times(1).
+					Return(models.Interview{Model: gorm.Model{ID: 7}, JobID: 4, CompanyID: 2, CandidateID: 9, RecruiterID: 1,
+						Status: models.InterviewProposed, Timezone: "Europe/Berlin"}, nil)
+			},
+		},
+		{
+			name:           "Fail_Viewer",
+			body:           valid,
+			expectedStatus: http.StatusForbidden,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).Times(1).
+					Return(models.Membership{Role: models.RoleViewer}, nil)
+				m.EXPECT().CreateInterview(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:           "Fail_Conflict",
+			body:           valid,
+			expectedStatus: http.StatusConflict,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateInterview(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					Return(models.Interview{}, fmt.Errorf("%w: interview 5", models.ErrInterviewConflict))
+			},
+		},
+		{
+			name:           "Fail_SlotInPast",
+			body:           valid,
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateInterview(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					Return(models.Interview{}, fmt.Errorf("%w: 2020-01-01T10:00 is in the past", models.ErrInterviewSlot))
+			},
+		},
+		{
+			name:           "Fail_UnknownTimezone",
+			body:           `{"slots":["2099-11-02T10:00"],"duration_minutes":45,"timezone":"Mars/Olympus"}`,
+			expectedStatus: http.StatusBadRequest,
+			mockService:    func(m *mockmodels.MockService) {},
+		},
+		{
+			name:           "Fail_NoSlots",
+			body:           `{"slots":[],"duration_minutes":45,"timezone":"UTC"}`,
+			expectedStatus: http.StatusBadRequest,
+			mockService:    func(m *mockmodels.MockService) {},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+			mockService.EXPECT().ViewApplication(gomock.Any(), gomock.Eq(uint(3))).AnyTimes().
+				Return(models.Application{Model: gorm.Model{ID: 3}, JobID: 4, UserID: 9}, nil)
+			mockService.EXPECT().ViewJobByJobId(gomock.Any(), gomock.Eq(uint(4)), gomock.Any()).AnyTimes().
+				Return([]models.Job{{Model: gorm.Model{ID: 4}, CompanyID: 2, Title: "Go developer"}}, nil)
+			mockService.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).AnyTimes().
+				Return(models.Membership{Role: models.RoleRecruiter}, nil)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService), ic: newTestCoordinator(t, mockService)}
+			router.POST("/applications/:applicationID/interviews", h.ProposeInterview)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/applications/3/interviews", strings.NewReader(tc.body))
+			require.NoError(t, err)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandler_SelectInterviewSlot(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{
+		Subject: "9",
+	}
+	start := time.Now().Add(72 * time.Hour).UTC().Truncate(time.Minute)
+	end := start.Add(45 * time.Minute)
+
+	tt := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		mockService    func(m *mockmodels.MockService)
+	}{
+		{
+			name:           "OK",
+			body:           `{"slot_id":2}`,
+			expectedStatus: http.StatusOK,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().SelectInterviewSlot(gomock.Any(), gomock.Eq(uint(7)), gomock.Eq(uint(2)), gomock.Eq(uint(9))).Times(1).
+					Return(models.Interview{Model: gorm.Model{ID: 7}, JobID: 4, CompanyID: 2, CandidateID: 9, RecruiterID: 1,
+						Status: models.InterviewScheduled, StartsAt: &start, EndsAt: &end, Sequence: 1}, nil)
+			},
+		},
+		{
+			name:           "Fail_NotCandidate",
+			body:           `{"slot_id":2}`,
+			expectedStatus: http.StatusNotFound,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().SelectInterviewSlot(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					Return(models.Interview{}, gorm.ErrRecordNotFound)
+			},
+		},
+		{
+			name:           "Fail_Taken",
+			body:           `{"slot_id":2}`,
+			expectedStatus: http.StatusConflict,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().SelectInterviewSlot(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					Return(models.Interview{}, fmt.Errorf("%w: interview 5", models.ErrInterviewConflict))
+			},
+		},
+		{
+			name:           "Fail_AlreadyScheduled",
+			body:           `{"slot_id":2}`,
+			expectedStatus: http.StatusConflict,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().SelectInterviewSlot(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					Return(models.Interview{}, fmt.Errorf("%w: interview is scheduled", models.ErrInterviewState))
+			},
+		},
+		{
+			name:           "Fail_NoSlot",
+			body:           `{}`,
+			expectedStatus: http.StatusBadRequest,
+			mockService:    func(m *mockmodels.MockService) {},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+			mockService.EXPECT().ViewJobByJobId(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().
+				Return([]models.Job{{Model: gorm.Model{ID: 4}, CompanyID: 2, Title: "Go developer"}}, nil)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService), ic: newTestCoordinator(t, mockService)}
+			router.POST("/interviews/:interviewID/select", h.SelectInterviewSlot)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/interviews/7/select", strings.NewReader(tc.body))
+			require.NoError(t, err)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandler_CalendarFeed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	start := time.Now().Add(72 * time.Hour).UTC().Truncate(time.Minute)
+	end := start.Add(45 * time.Minute)
+
+	tt := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectedBody   string
+		mockService    func(m *mockmodels.MockService)
+	}{
+		{
+			name:           "OK",
+			path:           "/calendar/secret.ics",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "DTSTART:" + start.Format("20060102T150405Z"),
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewCalendarFeedUser(gomock.Any(), gomock.Eq("secret")).Times(1).Return(uint(9), nil)
+				m.EXPECT().ViewUserInterviews(gomock.Any(), gomock.Eq(uint(9)), gomock.Any()).Times(1).
+					Return([]models.Interview{
+						{Model: gorm.Model{ID: 7}, JobID: 4, CompanyID: 2, CandidateID: 9, RecruiterID: 1,
+							Status: models.InterviewScheduled, StartsAt: &start, EndsAt: &end},
+						// Proposals have no time yet and stay out of the calendar
+						{Model: gorm.Model{ID: 8}, JobID: 4, CompanyID: 2, CandidateID: 9, RecruiterID: 1,
+							Status: models.InterviewProposed},
+					}, nil)
+			},
+		},
+		{
+			name:           "Fail_UnknownToken",
+			path:           "/calendar/guess.ics",
+			expectedStatus: http.StatusNotFound,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewCalendarFeedUser(gomock.Any(), gomock.Eq("guess")).Times(1).Return(uint(0), gorm.ErrRecordNotFound)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+			mockService.EXPECT().ViewJobByJobId(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().
+				Return([]models.Job{{Model: gorm.Model{ID: 4}, CompanyID: 2, Title: "Go developer"}}, nil)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService), ic: newTestCoordinator(t, mockService)}
+			router.GET("/calendar/:token", h.CalendarFeed)
+
+			ctx := context.WithValue(context.Background(), middleware.TraceIdKey, "fake-trace-id")
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, tc.path, nil)
+			require.NoError(t, err)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+			require.Contains(t, rec.Body.String(), tc.expectedBody)
+			if tc.expectedStatus == http.StatusOK {
+				require.Equal(t, "text/calendar; charset=utf-8", rec.Header().Get("Content-Type"))
+				require.Equal(t, 1, strings.Count(rec.Body.String(), "BEGIN:VEVENT"))
+			}
+		})
+	}
+}
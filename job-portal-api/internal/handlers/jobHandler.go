@@ -2,14 +2,18 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"job-portal-api/internal/auth"
+	"job-portal-api/internal/feed"
 	middlewares "job-portal-api/internal/middleware"
 	"job-portal-api/internal/models"
 	"net/http"
+	"slices"
 
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/rs/zerolog/log"
@@ -87,6 +91,10 @@ func (h *handler) CreateJob(c *gin.Context) {
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
 		return
 	}
+	if _, ok := models.PeriodsPerYear[newJob.SalaryPeriod]; newJob.SalaryPeriod != "" && !ok {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "salary_period must be hourly, monthly or annual"})
+		return
+	}
 
 	// Set the CompanyID from the URL parameter
 	companyIDStr := c.Param("companyID")
@@ -96,10 +104,17 @@ func (h *handler) CreateJob(c *gin.Context) {
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid company ID"})
 		return
 	}
+	if _, ok := h.requireCompanyRole(c, traceId, claims, uint(companyID), models.RoleRecruiter); !ok {
+		return
+	}
 	newJob.CompanyID = uint(companyID)
 
 	// Create the job
 	createdJob, err := h.s.CreateJob(ctx, newJob, claims.Subject)
+	if errors.Is(err, models.ErrJobTransition) || errors.Is(err, models.ErrJobSchedule) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		log.Error().Err(err).Str("Trace Id", traceId)
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "failed to create job"})
@@ -199,6 +214,26 @@ func (h *handler) ViewJobByJobId(c *gin.Context) {
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": "problem in fetching job details"})
 		return
 	}
+	// Jobs that are not published are only shown to recruiters, through their company's listing
+	job = slices.DeleteFunc(job, func(j models.Job) bool { return j.Status != models.JobPublished })
+	if len(job) > 0 {
+		h.recordJobView(c, traceId, job[0])
+	}
+	c.Header("Vary", "Accept")
+	if c.NegotiateFormat(binding.MIMEJSON, feed.MediaJSONLD) == feed.MediaJSONLD {
+		if len(job) == 0 {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "job not found"})
+			return
+		}
+		h.renderJobPosting(c, traceId, job[0])
+		return
+	}
+	if !h.markBookmarked(c, traceId, claims, job) {
+		return
+	}
+	if !h.markRatings(c, traceId, job) {
+		return
+	}
 	c.JSON(http.StatusOK, job)
 }
 
@@ -259,7 +294,12 @@ func (h *handler) ViewJobByCompId(c *gin.Context) {
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": "problem in fetching company details"})
 		return
 	}
-
+	if !h.markBookmarked(c, traceId, claims, job) {
+		return
+	}
+	if !h.markRatings(c, traceId, job) {
+		return
+	}
 	c.JSON(http.StatusOK, job)
 }
 func (h *handler) ViewJobAll(c *gin.Context) {
@@ -284,6 +324,12 @@ func (h *handler) ViewJobAll(c *gin.Context) {
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": "problem in viewing job"})
 		return
 	}
+	if !h.markBookmarked(c, traceId, claims, jobList) {
+		return
+	}
+	if !h.markRatings(c, traceId, jobList) {
+		return
+	}
 	m := gin.H{"job list": jobList}
 	c.JSON(http.StatusOK, m)
 }
@@ -133,6 +133,7 @@ func TestHandler_ViewJobAll(t *testing.T) {
 			Title:           "Software Engineer",
 			ExperienceLevel: "Senior",
 			CompanyID:       1,
+			Status:          models.JobPublished,
 		},
 		// Add more mock jobs as needed.
 	}
@@ -147,13 +148,17 @@ func TestHandler_ViewJobAll(t *testing.T) {
 		{
 			name:             "OK",
 			expectedStatus:   200,
-			expectedResponse: `{"job list":[{"ID":1,"CreatedAt":"2006-01-01T01:01:01.000000001Z","UpdatedAt":"2006-01-01T01:01:01.000000001Z","DeletedAt":null,"title":"Software Engineer","experience_required":"Senior","company_id":1}]}`,
+			expectedResponse: `{"job list":[{"ID":1,"CreatedAt":"2006-01-01T01:01:01.000000001Z","UpdatedAt":"2006-01-01T01:01:01.000000001Z","DeletedAt":null,"title":"Software Engineer","experience_required":"Senior","company_id":1,"status":"published","bookmarked":true,"company_rating":{"average":4.5,"count":2}}]}`,
 			// Function for mocking service.
 			// This simulates ViewJobAll service and its return value.
 			mockService: func(m *mockmodels.MockService) {
 
 				m.EXPECT().ViewJobAll(gomock.Any(), gomock.Any()).Times(1).
 					Return(mockJobs, nil)
+				m.EXPECT().BookmarkedJobIDs(gomock.Any(), gomock.Eq(uint(1)), gomock.Eq([]uint{1})).Times(1).
+					Return([]uint{1}, nil)
+				m.EXPECT().CompanyRatings(gomock.Any(), gomock.Eq([]uint{1})).Times(1).
+					Return([]models.RatingSummary{{CompanyID: 1, Average: 4.5, Count: 2}}, nil)
 			},
 		},
 	}
@@ -231,6 +236,7 @@ func TestHandler_ViewJobByCompId(t *testing.T) {
 			Title:           "Software Engineer",
 			ExperienceLevel: "Senior",
 			CompanyID:       1,
+			Status:          models.JobPublished,
 		},
 		// Add more mock jobs as needed.
 	}
@@ -245,13 +251,16 @@ func TestHandler_ViewJobByCompId(t *testing.T) {
 		{
 			name:             "OK",
 			expectedStatus:   200,
-			expectedResponse: `[{"ID":1,"CreatedAt":"2006-01-01T01:01:01.000000001Z","UpdatedAt":"2006-01-01T01:01:01.000000001Z","DeletedAt":null,"title":"Software Engineer","experience_required":"Senior","company_id":1}]`,
+			expectedResponse: `[{"ID":1,"CreatedAt":"2006-01-01T01:01:01.000000001Z","UpdatedAt":"2006-01-01T01:01:01.000000001Z","DeletedAt":null,"title":"Software Engineer","experience_required":"Senior","company_id":1,"status":"published","bookmarked":false}]`,
 			// Function for mocking service.
 			// This simulates ViewJobByCompId service and its return value.
 			mockService: func(m *mockmodels.MockService) {
 
 				m.EXPECT().ViewJobByCompId(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
 					Return(mockJobs, nil)
+				m.EXPECT().BookmarkedJobIDs(gomock.Any(), gomock.Eq(uint(1)), gomock.Eq([]uint{1})).Times(1).
+					Return(nil, nil)
+				m.EXPECT().CompanyRatings(gomock.Any(), gomock.Eq([]uint{1})).Times(1).Return(nil, nil)
 			},
 		},
 	}
@@ -329,6 +338,7 @@ func TestHandler_ViewJobByJobId(t *testing.T) {
 			Title:           "Software Engineer",
 			ExperienceLevel: "Senior",
 			CompanyID:       1,
+			Status:          models.JobPublished,
 		},
 		// Add more mock jobs as needed.
 	}
@@ -343,13 +353,18 @@ func TestHandler_ViewJobByJobId(t *testing.T) {
 		{
 			name:             "OK",
 			expectedStatus:   200,
-			expectedResponse: `[{"ID":1,"CreatedAt":"2006-01-01T01:01:01.000000001Z","UpdatedAt":"2006-01-01T01:01:01.000000001Z","DeletedAt":null,"title":"Software Engineer","experience_required":"Senior","company_id":1}]`,
+			expectedResponse: `[{"ID":1,"CreatedAt":"2006-01-01T01:01:01.000000001Z","UpdatedAt":"2006-01-01T01:01:01.000000001Z","DeletedAt":null,"title":"Software Engineer","experience_required":"Senior","company_id":1,"status":"published","bookmarked":false}]`,
 			// Function for mocking service.
 			// This simulates ViewJobByJobId service and its return value.
 			mockService: func(m *mockmodels.MockService) {
 
 				m.EXPECT().ViewJobByJobId(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
 					Return(mockJobs, nil)
+				m.EXPECT().RecordJobView(gomock.Any(), gomock.Eq(uint(1)), gomock.Eq(uint(1)), gomock.Eq("user:1")).Times(1).
+					Return(nil)
+				m.EXPECT().BookmarkedJobIDs(gomock.Any(), gomock.Eq(uint(1)), gomock.Eq([]uint{1})).Times(1).
+					Return([]uint{}, nil)
+				m.EXPECT().CompanyRatings(gomock.Any(), gomock.Eq([]uint{1})).Times(1).Return(nil, nil)
 			},
 		},
 	}
@@ -608,6 +623,7 @@ func TestHandler_CreateJob(t *testing.T) {
 		Title:           "Software Engineer",
 		ExperienceLevel: "Senior",
 		CompanyID:       1,
+		Status:          models.JobPublished,
 	}
 
 	// Define the list of test cases
@@ -621,14 +637,26 @@ func TestHandler_CreateJob(t *testing.T) {
 			name:           "OK",
 			expectedStatus: 201,
 			// You can adjust the expected response based on your application's actual response format.
-			expectedResponse: `{"ID":0,"CreatedAt":"0001-01-01T00:00:00Z","UpdatedAt":"0001-01-01T00:00:00Z","DeletedAt":null,"title":"Software Engineer","experience_required":"Senior","company_id":1}`,
+			expectedResponse: `{"ID":0,"CreatedAt":"0001-01-01T00:00:00Z","UpdatedAt":"0001-01-01T00:00:00Z","DeletedAt":null,"title":"Software Engineer","experience_required":"Senior","company_id":1,"status":"published"}`,
 			// Function for mocking service.
 			// This simulates CreateJob service and its return value.
 			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(1)), gomock.Eq(uint(1))).Times(1).
+					Return(models.Membership{Role: models.RoleRecruiter}, nil)
 				m.EXPECT().CreateJob(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
 					Return(jobData, nil)
 			},
 		},
+		{
+			name:             "Fail_NotMember",
+			expectedStatus:   http.StatusForbidden,
+			expectedResponse: `{"error":"requires the recruiter role in this company"}`,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewMembership(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(models.Membership{}, gorm.ErrRecordNotFound)
+				m.EXPECT().ViewUser(gomock.Any(), gomock.Any()).Times(1).Return(models.User{}, nil)
+				m.EXPECT().CreateJob(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
 	}
 
 	// Start a loop over `testCases` array where each element is represented by `tc`.
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/jobio"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// maxImportSize bounds the body of a bulk import.
+const maxImportSize = 10 << 20
+
+// Import modes: atomic imports nothing when a row is invalid, partial imports the valid rows.
+const (
+	importAtomic  = "atomic"
+	importPartial = "partial"
+)
+
+// ImportJobs posts many jobs of a company from a CSV or JSON Lines body.
+// Every row is validated and the invalid ones are reported by line.
+func (h *handler) ImportJobs(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	companyID, err := strconv.ParseUint(c.Param("companyID"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid company ID"})
+		return
+	}
+	if _, ok := h.requireCompanyRole(c, traceId, claims, uint(companyID), models.RoleRecruiter); !ok {
+		return
+	}
+	mode := c.DefaultQuery("mode", importAtomic)
+	if mode != importAtomic && mode != importPartial {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "mode must be atomic or partial"})
+		return
+	}
+	format, err := jobio.Format(c.Query("format"), c.ContentType())
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+		return
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+	rows, rowErrs, err := jobio.Read(format, body)
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("an import may be at most %d bytes", maxImportSize)})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if rowErrs == nil {
+		rowErrs = []jobio.RowError{}
+	}
+	if len(rows) == 0 || (mode == importAtomic && len(rowErrs) > 0) {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"imported": 0, "errors": rowErrs})
+		return
+	}
+
+	njs := make([]models.NewJob, 0, len(rows))
+	for _, r := range rows {
+		njs = append(njs, r.Job)
+	}
+	jobs, err := h.s.CreateJobs(ctx, uint(companyID), njs)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "company not found"})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "importing jobs failed"})
+		return
+	}
+
+	created := make([]gin.H, 0, len(jobs))
+	for i, job := range jobs {
+		created = append(created, gin.H{"line": rows[i].Line, "id": job.ID})
+	}
+	c.JSON(http.StatusCreated, gin.H{"imported": len(jobs), "jobs": created, "errors": rowErrs})
+}
+
+// ExportJobs streams every job of a company as CSV or JSON Lines, in the format imports accept.
+func (h *handler) ExportJobs(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	companyID, err := strconv.ParseUint(c.Param("companyID"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid company ID"})
+		return
+	}
+	if _, ok := h.requireCompanyRole(c, traceId, claims, uint(companyID), models.RoleViewer); !ok {
+		return
+	}
+	format, err := jobio.Format(c.DefaultQuery("format", jobio.FormatCSV), "")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	company, err := h.s.ViewCompany(ctx, uint(companyID), claims.Subject)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching company"})
+		return
+	}
+	if company.ID == 0 {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "company not found"})
+		return
+	}
+
+	c.Header("Content-Type", jobio.ContentType(format))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="company-%d-jobs-%s.%s"`,
+		companyID, time.Now().UTC().Format("20060102"), format))
+	c.Status(http.StatusOK)
+	w, err := jobio.NewWriter(format, c.Writer)
+	if err == nil {
+		err = h.s.EachCompanyJob(ctx, uint(companyID), func(job models.Job) error {
+			return w.Write(job)
+		})
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if err != nil {
+		// Part of the file may already be out, all that is left is cutting it short
+		log.Error().Err(err).Str("Trace Id", traceId).Msg("exporting jobs")
+		c.Abort()
+	}
+}
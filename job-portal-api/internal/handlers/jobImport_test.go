@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/models/mockmodels"
+	"job-portal-api/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandler_ImportJobs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{
+		Subject: "7",
+	}
+	csvBody := "title,experience_required,skills\n" +
+		"Go developer,senior,Go;Postgres\n" +
+		",junior,\n"
+
+	tt := []struct {
+		name             string
+		query            string
+		contentType      string
+		body             string
+		expectedStatus   int
+		expectedResponse string
+		mockService      func(m *mockmodels.MockService)
+	}{
+		{
+			name:             "Fail_AtomicWithInvalidRow",
+			contentType:      "text/csv",
+			body:             csvBody,
+			expectedStatus:   http.StatusUnprocessableEntity,
+			expectedResponse: `{"imported":0,"errors":[{"line":3,"field":"title","error":"is required"}]}`,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateJobs(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:             "OK_Partial",
+			query:            "?mode=partial",
+			contentType:      "text/csv",
+			body:             csvBody,
+			expectedStatus:   http.StatusCreated,
+			expectedResponse: `{"imported":1,"jobs":[{"line":2,"id":11}],"errors":[{"line":3,"field":"title","error":"is required"}]}`,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateJobs(gomock.Any(), gomock.Eq(uint(5)), gomock.Eq([]models.NewJob{{
+					Title: "Go developer", ExperienceLevel: "senior", Skills: []string{"Go", "Postgres"},
+				}})).Times(1).Return([]models.Job{{Model: gorm.Model{ID: 11}}}, nil)
+			},
+		},
+		{
+			name:             "OK_JSONL",
+			query:            "?format=jsonl",
+			body:             `{"title":"SRE","experience_required":"mid"}`,
+			expectedStatus:   http.StatusCreated,
+			expectedResponse: `{"imported":1,"jobs":[{"line":1,"id":12}],"errors":[]}`,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateJobs(gomock.Any(), gomock.Eq(uint(5)), gomock.Any()).
+					Times(1).Return([]models.Job{{Model: gorm.Model{ID: 12}}}, nil)
+			},
+		},
+		{
+			name:           "Fail_UnknownCompany",
+			query:          "?format=jsonl",
+			body:           `{"title":"SRE","experience_required":"mid"}`,
+			expectedStatus: http.StatusNotFound,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateJobs(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(nil, gorm.ErrRecordNotFound)
+			},
+		},
+		{
+			name:           "Fail_UnknownFormat",
+			contentType:    "application/json",
+			body:           `[]`,
+			expectedStatus: http.StatusUnsupportedMediaType,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateJobs(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:           "Fail_Viewer",
+			contentType:    "text/csv",
+			body:           csvBody,
+			expectedStatus: http.StatusForbidden,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(5)), gomock.Eq(uint(7))).Times(1).
+					Return(models.Membership{Role: models.RoleViewer}, nil)
+				m.EXPECT().ViewUser(gomock.Any(), gomock.Eq(uint(7))).Times(1).Return(models.User{}, nil)
+			},
+		},
+		{
+			name:           "Fail_BadHeader",
+			contentType:    "text/csv",
+			body:           "name\nGo\n",
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateJobs(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+			mockService.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(5)), gomock.Eq(uint(7))).AnyTimes().
+				Return(models.Membership{Role: models.RoleRecruiter}, nil)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService)}
+			router.POST("/companies/:companyID/jobs/import", h.ImportJobs)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/companies/5/jobs/import"+tc.query, strings.NewReader(tc.body))
+			require.NoError(t, err)
+			if tc.contentType != "" {
+				req.Header.Set("Content-Type", tc.contentType)
+			}
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+			if tc.expectedResponse != "" {
+				require.JSONEq(t, tc.expectedResponse, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_ExportJobs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{
+		Subject: "7",
+	}
+
+	ctrl := gomock.NewController(t)
+	mockService := mockmodels.NewMockService(ctrl)
+	mockService.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(5)), gomock.Eq(uint(7))).Times(1).
+		Return(models.Membership{Role: models.RoleViewer}, nil)
+	mockService.EXPECT().ViewCompany(gomock.Any(), gomock.Eq(uint(5)), gomock.Any()).
+		Times(1).Return(models.Company{Model: gorm.Model{ID: 5}}, nil)
+	mockService.EXPECT().EachCompanyJob(gomock.Any(), gomock.Eq(uint(5)), gomock.Any()).Times(1).
+		DoAndReturn(func(ctx context.Context, companyId uint, fn func(job models.Job) error) error {
+			return fn(models.Job{
+				Model: gorm.Model{ID: 3, CreatedAt: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+				Title: "Go developer", ExperienceLevel: "senior", Skills: []string{"go", "sql"},
+				Status: models.JobPaused,
+			})
+		})
+
+	router := gin.New()
+	h := handler{s: services.NewStore(mockService)}
+	router.GET("/companies/:companyID/jobs/export", h.ExportJobs)
+
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+	ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/companies/5/jobs/export?format=csv", nil)
+	require.NoError(t, err)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "text/csv; charset=utf-8", rec.Header().Get("Content-Type"))
+	require.Equal(t, "id,created_at,title,experience_required,description,location,skills,min_experience_years,salary_min,salary_max,salary_currency,salary_period,status\n"+
+		"3,2024-03-01T00:00:00Z,Go developer,senior,,,go;sql,0,0,0,,,paused\n", rec.Body.String())
+}
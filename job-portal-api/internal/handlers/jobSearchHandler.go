@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/geo"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog/log"
+)
+
+// Radius searches cover defaultRadiusKm around the point unless asked otherwise, and at most maxRadiusKm.
+const (
+	defaultRadiusKm = 25
+	maxRadiusKm     = 500
+)
+
+// SearchJobs finds listed jobs by keywords (q) and by where they are: within radius_km of a place named in
+// near, like "Pune, India", or of the point at lat and lng, the nearest first and with their distance; and
+// within a bounding box given as bbox=min_longitude,min_latitude,max_longitude,max_latitude
+func (h *handler) SearchJobs(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	q := models.JobSearch{Keywords: c.Query("q")}
+	resp := gin.H{}
+	if v := c.Query("bbox"); v != "" {
+		box, err := geo.ParseBox(v)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		q.Box = &box
+	}
+	switch {
+	case c.Query("near") != "":
+		place, ok := geo.Default().Resolve(c.Query("near"))
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "unknown place " + strconv.Quote(c.Query("near"))})
+			return
+		}
+		q.Near = &place.Point
+		resp["near"] = place
+	case c.Query("lat") != "" || c.Query("lng") != "":
+		lat, errLat := strconv.ParseFloat(c.Query("lat"), 64)
+		lng, errLng := strconv.ParseFloat(c.Query("lng"), 64)
+		p := geo.Point{Latitude: lat, Longitude: lng}
+		if errLat != nil || errLng != nil || !p.Valid() {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": geo.ErrInvalidPoint.Error()})
+			return
+		}
+		q.Near = &p
+		resp["near"] = p
+	}
+	if q.Near != nil {
+		q.RadiusKm = defaultRadiusKm
+		if v := c.Query("radius_km"); v != "" {
+			r, err := strconv.ParseFloat(v, 64)
+			if err != nil || r <= 0 || r > maxRadiusKm {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "radius_km must be more than 0 and at most 500"})
+				return
+			}
+			q.RadiusKm = r
+		}
+		resp["radius_km"] = q.RadiusKm
+	}
+
+	jobs, err := h.s.SearchJobs(ctx, q, limitParam(c))
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "searching jobs failed"})
+		return
+	}
+	if !h.markBookmarked(c, traceId, claims, jobs) {
+		return
+	}
+	if !h.markRatings(c, traceId, jobs) {
+		return
+	}
+	resp["jobs"] = jobs
+	c.JSON(http.StatusOK, resp)
+}
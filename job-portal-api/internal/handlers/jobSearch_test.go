@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/models/mockmodels"
+	"job-portal-api/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler_SearchJobs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{
+		Subject: "1",
+	}
+	distance := 3.2
+	found := []models.Job{{Model: gorm.Model{ID: 1}, Title: "Go developer", CompanyID: 1, DistanceKm: &distance}}
+
+	tt := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedBody   string
+		mockService    func(m *mockmodels.MockService)
+	}{
+		{
+			name:           "OK_Near",
+			query:          "?q=go&near=Pune,+India",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"distance_km":3.2`,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().SearchJobs(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, q models.JobSearch, _ int) ([]models.Job, error) {
+						require.Equal(t, "go", q.Keywords)
+						require.NotNil(t, q.Near)
+						require.InDelta(t, 18.52, q.Near.Latitude, 0.1)
+						require.Equal(t, float64(defaultRadiusKm), q.RadiusKm)
+						require.Nil(t, q.Box)
+						return found, nil
+					})
+				m.EXPECT().BookmarkedJobIDs(gomock.Any(), gomock.Eq(uint(1)), gomock.Eq([]uint{1})).Times(1).Return(nil, nil)
+				m.EXPECT().CompanyRatings(gomock.Any(), gomock.Eq([]uint{1})).Times(1).Return(nil, nil)
+			},
+		},
+		{
+			name:           "OK_Point",
+			query:          "?lat=52.52&lng=13.4&radius_km=10&bbox=13,52,14,53",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"radius_km":10`,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().SearchJobs(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, q models.JobSearch, _ int) ([]models.Job, error) {
+						require.Equal(t, 52.52, q.Near.Latitude)
+						require.Equal(t, 10.0, q.RadiusKm)
+						require.NotNil(t, q.Box)
+						require.Equal(t, 13.0, q.Box.MinLongitude)
+						return nil, nil
+					})
+			},
+		},
+		{
+			name:           "Fail_UnknownPlace",
+			query:          "?near=Atlantis",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `unknown place \"Atlantis\"`,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().SearchJobs(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:           "Fail_Radius",
+			query:          "?near=Pune&radius_km=1000",
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().SearchJobs(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:           "Fail_Point",
+			query:          "?lat=95&lng=10",
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().SearchJobs(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:           "Fail_Box",
+			query:          "?bbox=1,2,3",
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().SearchJobs(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService)}
+			router.GET("/jobs/search", h.SearchJobs)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/jobs/search"+tc.query, nil)
+			require.NoError(t, err)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+			require.Contains(t, rec.Body.String(), tc.expectedBody)
+		})
+	}
+}
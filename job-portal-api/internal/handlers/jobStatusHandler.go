@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// jobStatuses are the values the status filter of a company's job listing accepts.
+var jobStatuses = []string{models.JobDraft, models.JobPublished, models.JobPaused, models.JobClosed, models.JobExpired}
+
+// TransitionJob moves a job to another status, e.g. publishing a draft or closing it,
+// and sets when it is published and when it expires.
+func (h *handler) TransitionJob(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	job, ok := h.requireJobRole(c, traceId, claims, models.RoleRecruiter)
+	if !ok {
+		return
+	}
+
+	var t models.JobTransition
+	err := json.NewDecoder(c.Request.Body).Decode(&t)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	validate := validator.New()
+	err = validate.Struct(t)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": "invalid status change", "error": err.Error()})
+		return
+	}
+
+	job, err = h.s.TransitionJob(ctx, job.ID, t)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "job not found"})
+		return
+	}
+	if errors.Is(err, models.ErrJobTransition) {
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, models.ErrJobSchedule) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "changing job status failed"})
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// ViewCompanyJobs lists the jobs of a company in every status for its recruiters,
+// or only those in the status given as a query parameter.
+func (h *handler) ViewCompanyJobs(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	companyID, err := strconv.ParseUint(c.Param("companyID"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid company ID"})
+		return
+	}
+	if _, ok := h.requireCompanyRole(c, traceId, claims, uint(companyID), models.RoleViewer); !ok {
+		return
+	}
+	status := c.Query("status")
+	if status != "" && !slices.Contains(jobStatuses, status) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": "unknown status " + status, "statuses": jobStatuses})
+		return
+	}
+
+	jobs, err := h.s.ViewCompanyJobs(ctx, uint(companyID), status)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching jobs"})
+		return
+	}
+	c.JSON(http.StatusOK, jobs)
+}
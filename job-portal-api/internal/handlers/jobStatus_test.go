@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/models/mockmodels"
+	"job-portal-api/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler_TransitionJob(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{
+		Subject: "1",
+	}
+
+	tt := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedBody   string
+		mockService    func(m *mockmodels.MockService)
+	}{
+		{
+			name:           "OK",
+			body:           `{"status":"closed"}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"status":"closed"`,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().TransitionJob(gomock.Any(), gomock.Eq(uint(4)), gomock.Eq(models.JobTransition{Status: models.JobClosed})).
+					Times(1).Return(models.Job{Model: gorm.Model{ID: 4}, Status: models.JobClosed}, nil)
+			},
+		},
+		{
+			name:           "Fail_UnknownStatus",
+			body:           `{"status":"expired"}`,
+			expectedStatus: http.StatusBadRequest,
+			mockService:    func(m *mockmodels.MockService) {},
+		},
+		{
+			name:           "Fail_Transition",
+			body:           `{"status":"published"}`,
+			expectedStatus: http.StatusConflict,
+			expectedBody:   "closed to published",
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().TransitionJob(gomock.Any(), gomock.Eq(uint(4)), gomock.Any()).
+					Times(1).Return(models.Job{}, fmt.Errorf("%w: closed to published", models.ErrJobTransition))
+			},
+		},
+		{
+			name:           "Fail_Schedule",
+			body:           `{"status":"published","expires_at":"2000-01-01T00:00:00Z"}`,
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().TransitionJob(gomock.Any(), gomock.Eq(uint(4)), gomock.Any()).
+					Times(1).Return(models.Job{}, fmt.Errorf("%w: expires_at must be in the future", models.ErrJobSchedule))
+			},
+		},
+		{
+			name:           "Fail_NotFound",
+			body:           `{"status":"paused"}`,
+			expectedStatus: http.StatusNotFound,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewJobByJobId(gomock.Any(), gomock.Eq(uint(4)), gomock.Any()).Times(1).Return(nil, nil)
+			},
+		},
+		{
+			name:           "Fail_Viewer",
+			body:           `{"status":"closed"}`,
+			expectedStatus: http.StatusForbidden,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).Times(1).
+					Return(models.Membership{Role: models.RoleViewer}, nil)
+				m.EXPECT().ViewUser(gomock.Any(), gomock.Eq(uint(1))).Times(1).Return(models.User{}, nil)
+				m.EXPECT().TransitionJob(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+			mockService.EXPECT().ViewJobByJobId(gomock.Any(), gomock.Eq(uint(4)), gomock.Any()).AnyTimes().
+				Return([]models.Job{{Model: gorm.Model{ID: 4}, CompanyID: 2}}, nil)
+			mockService.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).AnyTimes().
+				Return(models.Membership{Role: models.RoleRecruiter}, nil)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService)}
+			router.POST("/jobs/:jobID/status", h.TransitionJob)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/jobs/4/status", strings.NewReader(tc.body))
+			require.NoError(t, err)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+			require.Contains(t, rec.Body.String(), tc.expectedBody)
+		})
+	}
+}
+
+func TestHandler_ViewCompanyJobs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{
+		Subject: "1",
+	}
+
+	tt := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		mockService    func(m *mockmodels.MockService)
+	}{
+		{
+			name:           "OK_AllStatuses",
+			expectedStatus: http.StatusOK,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewCompanyJobs(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq("")).Times(1).
+					Return([]models.Job{{Status: models.JobDraft}, {Status: models.JobPublished}}, nil)
+			},
+		},
+		{
+			name:           "OK_Drafts",
+			query:          "?status=draft",
+			expectedStatus: http.StatusOK,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewCompanyJobs(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(models.JobDraft)).Times(1).
+					Return([]models.Job{{Status: models.JobDraft}}, nil)
+			},
+		},
+		{
+			name:           "Fail_NotMember",
+			expectedStatus: http.StatusForbidden,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).Times(1).
+					Return(models.Membership{}, gorm.ErrRecordNotFound)
+				m.EXPECT().ViewUser(gomock.Any(), gomock.Eq(uint(1))).Times(1).Return(models.User{}, nil)
+			},
+		},
+		{
+			name:           "Fail_UnknownStatus",
+			query:          "?status=archived",
+			expectedStatus: http.StatusBadRequest,
+			mockService:    func(m *mockmodels.MockService) {},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+			mockService.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).AnyTimes().
+				Return(models.Membership{Role: models.RoleViewer}, nil)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService)}
+			router.GET("/companies/:companyID/jobs", h.ViewCompanyJobs)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/companies/2/jobs"+tc.query, nil)
+			require.NoError(t, err)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+		})
+	}
+}
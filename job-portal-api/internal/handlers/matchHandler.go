@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"context"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog/log"
+)
+
+// limitParam reads the optional "limit" query parameter, defaulting to 20 and capped at 100
+func limitParam(c *gin.Context) int {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		return 20
+	}
+	if limit > 100 {
+		return 100
+	}
+	return limit
+}
+
+// RecommendedJobs lists the jobs matching the logged-in candidate's profile best, with the score breakdown
+func (h *handler) RecommendedJobs(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+	uid, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	matches, err := h.s.ViewJobMatches(ctx, uint(uid), limitParam(c))
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching recommendations"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"jobs": matches})
+}
+
+// RecommendedCandidates lists the candidates matching a job best, with the score breakdown
+func (h *handler) RecommendedCandidates(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	job, ok := h.requireJobRole(c, traceId, claims, models.RoleViewer)
+	if !ok {
+		return
+	}
+
+	matches, err := h.s.ViewCandidateMatches(ctx, job.ID, limitParam(c))
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching recommendations"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"candidates": matches})
+}
+
+// profileChanged asks the matching engine to rescore a candidate, if one is running
+func (h *handler) profileChanged(ctx context.Context, traceId string, userId uint) {
+	if h.me == nil {
+		return
+	}
+	err := h.me.ProfileChanged(ctx, userId)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Msg("scheduling profile rescoring")
+	}
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/models/mockmodels"
+	"job-portal-api/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler_RecommendedJobs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{
+		Subject: "7",
+	}
+
+	mockMatches := []models.JobMatch{{
+		Job:   models.Job{Title: "Backend Engineer", Status: models.JobPublished},
+		Score: 0.75,
+		Breakdown: []models.ScoreComponent{
+			{Name: "skills", Weight: 0.5, Score: 0.5, Detail: "1 of 2 skills, missing grpc"},
+		},
+	}}
+
+	tt := []struct {
+		name             string
+		query            string
+		expectedStatus   int
+		expectedResponse string
+		mockService      func(m *mockmodels.MockService)
+	}{
+		{
+			name:             "OK",
+			expectedStatus:   http.StatusOK,
+			expectedResponse: `{"jobs":[{"job":{"ID":0,"CreatedAt":"0001-01-01T00:00:00Z","UpdatedAt":"0001-01-01T00:00:00Z","DeletedAt":null,"title":"Backend Engineer","experience_required":"","company_id":0,"status":"published"},"score":0.75,"breakdown":[{"name":"skills","weight":0.5,"score":0.5,"detail":"1 of 2 skills, missing grpc"}]}]}`,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewJobMatches(gomock.Any(), gomock.Eq(uint(7)), gomock.Eq(20)).
+					Times(1).Return(mockMatches, nil)
+			},
+		},
+		{
+			name:           "OK_LimitCapped",
+			query:          "?limit=1000",
+			expectedStatus: http.StatusOK,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewJobMatches(gomock.Any(), gomock.Eq(uint(7)), gomock.Eq(100)).
+					Times(1).Return(nil, nil)
+			},
+		},
+		{
+			name:             "Fail_Service",
+			expectedStatus:   http.StatusInternalServerError,
+			expectedResponse: `{"msg":"problem in fetching recommendations"}`,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewJobMatches(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(nil, errors.New("db down"))
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService)}
+			router.GET("/me/recommended-jobs", h.RecommendedJobs)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/me/recommended-jobs"+tc.query, nil)
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+			if tc.expectedResponse != "" {
+				require.Equal(t, tc.expectedResponse, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,488 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// requireCompanyRole makes sure the logged-in user is a member of the company with at least the role min,
+// responding itself when they are not. Platform admins act on any company as owners.
+func (h *handler) requireCompanyRole(c *gin.Context, traceId string, claims jwt.RegisteredClaims, companyId uint, min string) (models.Membership, bool) {
+	ctx := c.Request.Context()
+	uid, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return models.Membership{}, false
+	}
+	m, err := h.s.ViewMembership(ctx, companyId, uint(uid))
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return models.Membership{}, false
+	}
+	if err == nil && models.RoleAtLeast(m.Role, min) {
+		return m, true
+	}
+
+	u, err := h.s.ViewUser(ctx, uint(uid))
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return models.Membership{}, false
+	}
+	if err == nil && u.Admin {
+		return models.Membership{CompanyID: companyId, UserID: uint(uid), Role: models.RoleOwner}, true
+	}
+	c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "requires the " + min + " role in this company"})
+	return models.Membership{}, false
+}
+
+// requireJobRole loads the job named in the URL and makes sure the logged-in user
+// has at least the role min in the company that posted it.
+func (h *handler) requireJobRole(c *gin.Context, traceId string, claims jwt.RegisteredClaims, min string) (models.Job, bool) {
+	jobID, err := strconv.ParseUint(c.Param("jobID"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid job ID"})
+		return models.Job{}, false
+	}
+	jobs, err := h.s.ViewJobByJobId(c.Request.Context(), uint(jobID), claims.Subject)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching job details"})
+		return models.Job{}, false
+	}
+	if len(jobs) == 0 {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "job not found"})
+		return models.Job{}, false
+	}
+	_, ok := h.requireCompanyRole(c, traceId, claims, jobs[0].CompanyID, min)
+	return jobs[0], ok
+}
+
+// companyParam reads the company ID from the URL, responding itself when it is invalid
+func companyParam(c *gin.Context) (uint, bool) {
+	companyID, err := strconv.ParseUint(c.Param("companyID"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid company ID"})
+		return 0, false
+	}
+	return uint(companyID), true
+}
+
+// ViewMembers lists the members of a company and their roles
+func (h *handler) ViewMembers(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	companyID, ok := companyParam(c)
+	if !ok {
+		return
+	}
+	if _, ok := h.requireCompanyRole(c, traceId, claims, companyID, models.RoleViewer); !ok {
+		return
+	}
+
+	members, err := h.s.ViewMemberships(ctx, companyID)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching members"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"members": members})
+}
+
+// UpdateMember changes the role of a member. Admins manage admins, recruiters and viewers,
+// only owners can make someone an owner or change the role of another owner.
+func (h *handler) UpdateMember(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	companyID, ok := companyParam(c)
+	if !ok {
+		return
+	}
+	userID, err := strconv.ParseUint(c.Param("userID"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+	var mu models.MembershipUpdate
+	err = json.NewDecoder(c.Request.Body).Decode(&mu)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	validate := validator.New()
+	err = validate.Struct(mu)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": "invalid role", "error": err.Error()})
+		return
+	}
+
+	me, ok := h.requireCompanyRole(c, traceId, claims, companyID, models.RoleAdmin)
+	if !ok {
+		return
+	}
+	member, err := h.s.ViewMembership(ctx, companyID, uint(userID))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "member not found"})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching member"})
+		return
+	}
+	if (mu.Role == models.RoleOwner || member.Role == models.RoleOwner) && me.Role != models.RoleOwner {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "only owners can grant or change the owner role"})
+		return
+	}
+
+	member, err = h.s.UpdateMembership(ctx, companyID, uint(userID), mu.Role)
+	if errors.Is(err, models.ErrLastOwner) {
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "updating member failed"})
+		return
+	}
+	c.JSON(http.StatusOK, member)
+}
+
+// DeleteMember removes a member from a company. Anyone can leave a company on their own,
+// removing others takes an admin, and removing an owner takes an owner.
+func (h *handler) DeleteMember(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	companyID, ok := companyParam(c)
+	if !ok {
+		return
+	}
+	userID, err := strconv.ParseUint(c.Param("userID"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	if c.Param("userID") != claims.Subject {
+		me, ok := h.requireCompanyRole(c, traceId, claims, companyID, models.RoleAdmin)
+		if !ok {
+			return
+		}
+		member, err := h.s.ViewMembership(ctx, companyID, uint(userID))
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "member not found"})
+			return
+		}
+		if err != nil {
+			log.Error().Err(err).Str("Trace Id", traceId).Send()
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching member"})
+			return
+		}
+		if member.Role == models.RoleOwner && me.Role != models.RoleOwner {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "only owners can remove an owner"})
+			return
+		}
+	}
+
+	err = h.s.DeleteMembership(ctx, companyID, uint(userID))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "member not found"})
+		return
+	}
+	if errors.Is(err, models.ErrLastOwner) {
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "removing member failed"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// CreateInvitation emails an invitation to join the company. Only owners can invite owners.
+func (h *handler) CreateInvitation(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	companyID, ok := companyParam(c)
+	if !ok {
+		return
+	}
+	var ni models.NewInvitation
+	err := json.NewDecoder(c.Request.Body).Decode(&ni)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	validate := validator.New()
+	err = validate.Struct(ni)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": "invalid invitation details", "error": err.Error()})
+		return
+	}
+
+	me, ok := h.requireCompanyRole(c, traceId, claims, companyID, models.RoleAdmin)
+	if !ok {
+		return
+	}
+	if ni.Role == models.RoleOwner && me.Role != models.RoleOwner {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "only owners can invite owners"})
+		return
+	}
+
+	inv, err := h.iv.Invite(ctx, companyID, me.UserID, ni)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "company not found"})
+		return
+	}
+	if errors.Is(err, models.ErrAlreadyMember) {
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "inviting failed"})
+		return
+	}
+	c.JSON(http.StatusCreated, inv)
+}
+
+// ViewInvitations lists the invitations of a company that were not accepted yet
+func (h *handler) ViewInvitations(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	companyID, ok := companyParam(c)
+	if !ok {
+		return
+	}
+	if _, ok := h.requireCompanyRole(c, traceId, claims, companyID, models.RoleAdmin); !ok {
+		return
+	}
+
+	invs, err := h.s.ViewInvitations(ctx, companyID)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching invitations"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"invitations": invs})
+}
+
+// DeleteInvitation revokes an invitation that was not accepted yet
+func (h *handler) DeleteInvitation(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	companyID, ok := companyParam(c)
+	if !ok {
+		return
+	}
+	invitationID, err := strconv.ParseUint(c.Param("invitationID"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid invitation ID"})
+		return
+	}
+	if _, ok := h.requireCompanyRole(c, traceId, claims, companyID, models.RoleAdmin); !ok {
+		return
+	}
+
+	err = h.s.DeleteInvitation(ctx, companyID, uint(invitationID))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "invitation not found"})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "revoking invitation failed"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// AcceptInvitation makes the logged-in user a member of the company that invited them.
+// The token comes from the invitation email, in the query string or as {"token": ...}.
+func (h *handler) AcceptInvitation(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	token := c.Query("token")
+	if token == "" && c.Request.ContentLength != 0 {
+		var body struct {
+			Token string `json:"token"`
+		}
+		err := json.NewDecoder(c.Request.Body).Decode(&body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+		token = body.Token
+	}
+	if token == "" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "token is required"})
+		return
+	}
+	uid, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	m, err := h.s.AcceptInvitation(ctx, token, uint(uid))
+	if errors.Is(err, models.ErrInvitationInvalid) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, models.ErrInvitationEmail) {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, models.ErrAlreadyMember) {
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "accepting invitation failed"})
+		return
+	}
+	c.JSON(http.StatusOK, m)
+}
+
+// ViewMyCompanies lists the companies the logged-in user is a member of, with their role
+func (h *handler) ViewMyCompanies(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+	uid, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	members, err := h.s.ViewUserMemberships(ctx, uint(uid))
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching companies"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"memberships": members})
+}
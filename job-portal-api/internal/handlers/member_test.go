@@ -0,0 +1,367 @@
+package handlers
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/invites"
+	"job-portal-api/internal/mail"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/models/mockmodels"
+	"job-portal-api/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler_UpdateMember(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{
+		Subject: "1",
+	}
+
+	tt := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		mockService    func(m *mockmodels.MockService)
+	}{
+		{
+			name:           "OK",
+			body:           `{"role":"viewer"}`,
+			expectedStatus: http.StatusOK,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).Times(1).
+					Return(models.Membership{UserID: 1, Role: models.RoleAdmin}, nil)
+				m.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(9))).Times(1).
+					Return(models.Membership{UserID: 9, Role: models.RoleRecruiter}, nil)
+				m.EXPECT().UpdateMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(9)), gomock.Eq(models.RoleViewer)).Times(1).
+					Return(models.Membership{UserID: 9, Role: models.RoleViewer}, nil)
+			},
+		},
+		{
+			name:           "Fail_AdminChangingOwner",
+			body:           `{"role":"admin"}`,
+			expectedStatus: http.StatusForbidden,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).Times(1).
+					Return(models.Membership{UserID: 1, Role: models.RoleAdmin}, nil)
+				m.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(9))).Times(1).
+					Return(models.Membership{UserID: 9, Role: models.RoleOwner}, nil)
+			},
+		},
+		{
+			name:           "Fail_LastOwner",
+			body:           `{"role":"admin"}`,
+			expectedStatus: http.StatusConflict,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).Times(1).
+					Return(models.Membership{UserID: 1, Role: models.RoleOwner}, nil)
+				m.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(9))).Times(1).
+					Return(models.Membership{UserID: 9, Role: models.RoleOwner}, nil)
+				m.EXPECT().UpdateMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(9)), gomock.Eq(models.RoleAdmin)).Times(1).
+					Return(models.Membership{}, models.ErrLastOwner)
+			},
+		},
+		{
+			name:           "Fail_Recruiter",
+			body:           `{"role":"viewer"}`,
+			expectedStatus: http.StatusForbidden,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).Times(1).
+					Return(models.Membership{UserID: 1, Role: models.RoleRecruiter}, nil)
+				m.EXPECT().ViewUser(gomock.Any(), gomock.Eq(uint(1))).Times(1).Return(models.User{}, nil)
+			},
+		},
+		{
+			name:           "OK_PlatformAdmin",
+			body:           `{"role":"owner"}`,
+			expectedStatus: http.StatusOK,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).Times(1).
+					Return(models.Membership{}, gorm.ErrRecordNotFound)
+				m.EXPECT().ViewUser(gomock.Any(), gomock.Eq(uint(1))).Times(1).Return(models.User{Admin: true}, nil)
+				m.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(9))).Times(1).
+					Return(models.Membership{UserID: 9, Role: models.RoleViewer}, nil)
+				m.EXPECT().UpdateMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(9)), gomock.Eq(models.RoleOwner)).Times(1).
+					Return(models.Membership{UserID: 9, Role: models.RoleOwner}, nil)
+			},
+		},
+		{
+			name:           "Fail_UnknownRole",
+			body:           `{"role":"boss"}`,
+			expectedStatus: http.StatusBadRequest,
+			mockService:    func(m *mockmodels.MockService) {},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService)}
+			router.PUT("/companies/:companyID/members/:userID", h.UpdateMember)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodPut, "/companies/2/members/9", strings.NewReader(tc.body))
+			require.NoError(t, err)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandler_DeleteMember(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{
+		Subject: "9",
+	}
+
+	tt := []struct {
+		name           string
+		userID         string
+		expectedStatus int
+		mockService    func(m *mockmodels.MockService)
+	}{
+		{
+			name:           "OK_Leave",
+			userID:         "9",
+			expectedStatus: http.StatusNoContent,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().DeleteMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(9))).Times(1).Return(nil)
+			},
+		},
+		{
+			name:           "Fail_LastOwnerLeaving",
+			userID:         "9",
+			expectedStatus: http.StatusConflict,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().DeleteMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(9))).Times(1).Return(models.ErrLastOwner)
+			},
+		},
+		{
+			name:           "Fail_RemovingOthersAsViewer",
+			userID:         "4",
+			expectedStatus: http.StatusForbidden,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(9))).Times(1).
+					Return(models.Membership{UserID: 9, Role: models.RoleViewer}, nil)
+				m.EXPECT().ViewUser(gomock.Any(), gomock.Eq(uint(9))).Times(1).Return(models.User{}, nil)
+				m.EXPECT().DeleteMembership(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService)}
+			router.DELETE("/companies/:companyID/members/:userID", h.DeleteMember)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodDelete, "/companies/2/members/"+tc.userID, nil)
+			require.NoError(t, err)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+		})
+	}
+}
+
+type recordingMailer struct {
+	sent []mail.Message
+}
+
+func (r *recordingMailer) Send(ctx context.Context, m mail.Message) error {
+	r.sent = append(r.sent, m)
+	return nil
+}
+
+func TestHandler_CreateInvitation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{
+		Subject: "1",
+	}
+
+	tt := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedMails  int
+		mockService    func(m *mockmodels.MockService)
+	}{
+		{
+			name:           "OK",
+			body:           `{"email":"asha@example.com","role":"recruiter"}`,
+			expectedStatus: http.StatusCreated,
+			expectedMails:  1,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).Times(1).
+					Return(models.Membership{UserID: 1, Role: models.RoleAdmin}, nil)
+				m.EXPECT().ViewCompany(gomock.Any(), gomock.Eq(uint(2)), gomock.Any()).Times(1).
+					Return(models.Company{Model: gorm.Model{ID: 2}, CompanyName: "Acme"}, nil)
+				m.EXPECT().CreateInvitation(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1)),
+					gomock.Eq(models.NewInvitation{Email: "asha@example.com", Role: models.RoleRecruiter})).Times(1).
+					Return(models.Invitation{Model: gorm.Model{ID: 5}, Email: "asha@example.com", Role: models.RoleRecruiter}, "secret", nil)
+			},
+		},
+		{
+			name:           "Fail_AdminInvitingOwner",
+			body:           `{"email":"asha@example.com","role":"owner"}`,
+			expectedStatus: http.StatusForbidden,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).Times(1).
+					Return(models.Membership{UserID: 1, Role: models.RoleAdmin}, nil)
+			},
+		},
+		{
+			name:           "Fail_AlreadyMember",
+			body:           `{"email":"asha@example.com","role":"viewer"}`,
+			expectedStatus: http.StatusConflict,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).Times(1).
+					Return(models.Membership{UserID: 1, Role: models.RoleOwner}, nil)
+				m.EXPECT().ViewCompany(gomock.Any(), gomock.Eq(uint(2)), gomock.Any()).Times(1).
+					Return(models.Company{Model: gorm.Model{ID: 2}, CompanyName: "Acme"}, nil)
+				m.EXPECT().CreateInvitation(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					Return(models.Invitation{}, "", models.ErrAlreadyMember)
+			},
+		},
+		{
+			name:           "Fail_InvalidEmail",
+			body:           `{"email":"asha","role":"viewer"}`,
+			expectedStatus: http.StatusBadRequest,
+			mockService:    func(m *mockmodels.MockService) {},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+			mailer := &recordingMailer{}
+			iv, err := invites.NewInviter(mockService, mailer, "https://jobs.example.com")
+			require.NoError(t, err)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService), iv: iv}
+			router.POST("/companies/:companyID/invitations", h.CreateInvitation)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/companies/2/invitations", strings.NewReader(tc.body))
+			require.NoError(t, err)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+			require.Len(t, mailer.sent, tc.expectedMails)
+			require.NotContains(t, rec.Body.String(), "secret")
+		})
+	}
+}
+
+func TestHandler_AcceptInvitation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{
+		Subject: "9",
+	}
+
+	tt := []struct {
+		name           string
+		query          string
+		body           string
+		expectedStatus int
+		mockService    func(m *mockmodels.MockService)
+	}{
+		{
+			name:           "OK_Query",
+			query:          "?token=abc",
+			expectedStatus: http.StatusOK,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().AcceptInvitation(gomock.Any(), gomock.Eq("abc"), gomock.Eq(uint(9))).Times(1).
+					Return(models.Membership{CompanyID: 2, UserID: 9, Role: models.RoleRecruiter}, nil)
+			},
+		},
+		{
+			name:           "OK_Body",
+			body:           `{"token":"abc"}`,
+			expectedStatus: http.StatusOK,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().AcceptInvitation(gomock.Any(), gomock.Eq("abc"), gomock.Eq(uint(9))).Times(1).
+					Return(models.Membership{CompanyID: 2, UserID: 9, Role: models.RoleRecruiter}, nil)
+			},
+		},
+		{
+			name:           "Fail_Expired",
+			query:          "?token=abc",
+			expectedStatus: http.StatusNotFound,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().AcceptInvitation(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					Return(models.Membership{}, models.ErrInvitationInvalid)
+			},
+		},
+		{
+			name:           "Fail_OtherEmail",
+			query:          "?token=abc",
+			expectedStatus: http.StatusForbidden,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().AcceptInvitation(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					Return(models.Membership{}, models.ErrInvitationEmail)
+			},
+		},
+		{
+			name:           "Fail_NoToken",
+			expectedStatus: http.StatusBadRequest,
+			mockService:    func(m *mockmodels.MockService) {},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService)}
+			router.POST("/invitations/accept", h.AcceptInvitation)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/invitations/accept"+tc.query, strings.NewReader(tc.body))
+			require.NoError(t, err)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+		})
+	}
+}
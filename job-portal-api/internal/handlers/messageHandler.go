@@ -0,0 +1,406 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/messaging"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/storage"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// streamHeartbeat is how often an idle message stream sends a comment, so proxies keep it open
+const streamHeartbeat = 25 * time.Second
+
+// openConversation loads the conversation about the application named in the URL, starting it if needed,
+// and makes sure the logged-in user takes part in it: its applicant or a member of the company with the MessagingRole.
+func (h *handler) openConversation(c *gin.Context, traceId string, claims jwt.RegisteredClaims) (models.Conversation, bool) {
+	app, ok := h.requireApplicationRole(c, traceId, claims, models.MessagingRole, true)
+	if !ok {
+		return models.Conversation{}, false
+	}
+	conv, err := h.s.OpenConversation(c.Request.Context(), app.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "application not found"})
+		return models.Conversation{}, false
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching conversation"})
+		return models.Conversation{}, false
+	}
+	return conv, true
+}
+
+// publish tells everyone taking part in the conversation about the event.
+// Failing to do so only costs real-time delivery, the event itself is stored.
+func (h *handler) publish(ctx context.Context, traceId string, conv models.Conversation, e messaging.Event) {
+	if h.mh == nil {
+		return
+	}
+	members, err := h.s.ViewMemberships(ctx, conv.CompanyID)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Msg("loading conversation participants")
+		return
+	}
+	ids := []uint{conv.CandidateID}
+	for _, m := range members {
+		if models.RoleAtLeast(m.Role, models.MessagingRole) && m.UserID != conv.CandidateID {
+			ids = append(ids, m.UserID)
+		}
+	}
+	e.ConversationID = conv.ID
+	e.ApplicationID = conv.ApplicationID
+	h.mh.Publish(ids, e)
+}
+
+// ViewMessages lists the messages about an application, newest first, with the read receipts of the thread.
+// Older messages are paged with ?before=<message id>.
+func (h *handler) ViewMessages(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	var before uint64
+	var err error
+	if v := c.Query("before"); v != "" {
+		before, err = strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "before must be a message ID"})
+			return
+		}
+	}
+	limit := 50
+	if v := c.Query("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > 100 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+			return
+		}
+	}
+
+	conv, ok := h.openConversation(c, traceId, claims)
+	if !ok {
+		return
+	}
+	msgs, err := h.s.ViewMessages(ctx, conv.ID, uint(before), limit)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching messages"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"conversation": conv, "messages": msgs})
+}
+
+// SendMessage posts a message of the logged-in user in the conversation about an application
+func (h *handler) SendMessage(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	conv, ok := h.openConversation(c, traceId, claims)
+	if !ok {
+		return
+	}
+
+	var nm models.NewMessage
+	err := json.NewDecoder(c.Request.Body).Decode(&nm)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	validate := validator.New()
+	err = validate.Struct(nm)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": "a message needs a body or an attachment", "error": err.Error()})
+		return
+	}
+
+	uid, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	msg, err := h.s.CreateMessage(ctx, conv.ID, uint(uid), nm)
+	if errors.Is(err, models.ErrInvalidAttachment) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "sending message failed"})
+		return
+	}
+	h.publish(ctx, traceId, conv, messaging.Event{Type: messaging.EventMessage, Message: &msg})
+	c.JSON(http.StatusCreated, msg)
+}
+
+// MarkMessagesRead records that the logged-in user has read the conversation up to a message
+func (h *handler) MarkMessagesRead(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	conv, ok := h.openConversation(c, traceId, claims)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		MessageID uint `json:"message_id" validate:"required"`
+	}
+	err := json.NewDecoder(c.Request.Body).Decode(&body)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	validate := validator.New()
+	err = validate.Struct(body)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": "message_id is required", "error": err.Error()})
+		return
+	}
+
+	uid, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	read, err := h.s.MarkConversationRead(ctx, conv.ID, uint(uid), body.MessageID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "message not found"})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "marking messages read failed"})
+		return
+	}
+	h.publish(ctx, traceId, conv, messaging.Event{Type: messaging.EventRead, Read: &read})
+	c.JSON(http.StatusOK, read)
+}
+
+// UploadMessageAttachment stores a file the logged-in user wants to send in the conversation about an application.
+// The returned document ID goes in the attachment_id of the message.
+func (h *handler) UploadMessageAttachment(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	if _, ok := h.requireApplicationRole(c, traceId, claims, models.MessagingRole, true); !ok {
+		return
+	}
+	uid, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	doc, ok := h.storeUpload(c, traceId, storage.AttachmentPolicy, models.DocumentKindAttachment, uint(uid))
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusCreated, doc)
+}
+
+// MessageAttachment hands out a short-lived download link for the file attached to a message
+func (h *handler) MessageAttachment(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	messageID, err := strconv.ParseUint(c.Param("messageID"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid message ID"})
+		return
+	}
+	conv, ok := h.openConversation(c, traceId, claims)
+	if !ok {
+		return
+	}
+	msg, err := h.s.ViewMessage(ctx, uint(messageID))
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching message"})
+		return
+	}
+	// Messages of other conversations are as good as missing
+	if err != nil || msg.ConversationID != conv.ID || msg.AttachmentID == nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "attachment not found"})
+		return
+	}
+	doc, err := h.s.ViewDocument(ctx, *msg.AttachmentID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "attachment was deleted"})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching attachment"})
+		return
+	}
+	h.signedURL(c, traceId, doc)
+}
+
+// ViewMyConversations lists the conversations of the logged-in user with their unread counts
+func (h *handler) ViewMyConversations(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	uid, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	convs, err := h.s.ViewUserConversations(ctx, uint(uid))
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching conversations"})
+		return
+	}
+	var unread int64
+	for _, conv := range convs {
+		unread += conv.Unread
+	}
+	c.JSON(http.StatusOK, gin.H{"conversations": convs, "unread": unread})
+}
+
+// MessageStream streams new messages and read receipts of the logged-in user's conversations as server-sent events
+func (h *handler) MessageStream(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	uid, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	events, unsubscribe := h.mh.Subscribe(uint(uid))
+	defer unsubscribe()
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	// Stop nginx from buffering the stream
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case e, ok := <-events:
+			if !ok {
+				return false
+			}
+			c.SSEvent(e.Type, e)
+			return true
+		case <-heartbeat.C:
+			_, err := io.WriteString(w, ": ping\n\n")
+			return err == nil
+		}
+	})
+}
@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/messaging"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/models/mockmodels"
+	"job-portal-api/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler_SendMessage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	conv := models.Conversation{Model: gorm.Model{ID: 5}, ApplicationID: 3, CompanyID: 2, CandidateID: 9}
+
+	tt := []struct {
+		name           string
+		subject        string
+		body           string
+		expectedStatus int
+		// delivered is whether the candidate's stream gets the message
+		delivered   bool
+		mockService func(m *mockmodels.MockService)
+	}{
+		{
+			name:           "OK_Recruiter",
+			subject:        "1",
+			body:           `{"body":"Are you free on Monday?"}`,
+			expectedStatus: http.StatusCreated,
+			delivered:      true,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateMessage(gomock.Any(), gomock.Eq(uint(5)), gomock.Eq(uint(1)), gomock.Any()).Times(1).
+					Return(models.Message{ID: 11, ConversationID: 5, SenderID: 1, Body: "Are you free on Monday?"}, nil)
+			},
+		},
+		{
+			name:           "OK_Candidate",
+			subject:        "9",
+			body:           `{"attachment_id":4}`,
+			expectedStatus: http.StatusCreated,
+			delivered:      true,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateMessage(gomock.Any(), gomock.Eq(uint(5)), gomock.Eq(uint(9)), gomock.Any()).Times(1).
+					Return(models.Message{ID: 12, ConversationID: 5, SenderID: 9}, nil)
+			},
+		},
+		{
+			name:           "Fail_Viewer",
+			subject:        "1",
+			body:           `{"body":"Hello"}`,
+			expectedStatus: http.StatusForbidden,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).Times(1).
+					Return(models.Membership{Role: models.RoleViewer}, nil)
+				m.EXPECT().ViewUser(gomock.Any(), gomock.Eq(uint(1))).Times(1).Return(models.User{}, nil)
+				m.EXPECT().CreateMessage(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:           "Fail_Empty",
+			subject:        "1",
+			body:           `{"body":""}`,
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateMessage(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:           "Fail_ForeignAttachment",
+			subject:        "1",
+			body:           `{"attachment_id":4}`,
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateMessage(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					Return(models.Message{}, models.ErrInvalidAttachment)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+			mockService.EXPECT().ViewApplication(gomock.Any(), gomock.Eq(uint(3))).AnyTimes().
+				Return(models.Application{Model: gorm.Model{ID: 3}, JobID: 4, UserID: 9}, nil)
+			mockService.EXPECT().ViewJobByJobId(gomock.Any(), gomock.Eq(uint(4)), gomock.Any()).AnyTimes().
+				Return([]models.Job{{Model: gorm.Model{ID: 4}, CompanyID: 2}}, nil)
+			mockService.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).AnyTimes().
+				Return(models.Membership{Role: models.RoleRecruiter}, nil)
+			mockService.EXPECT().OpenConversation(gomock.Any(), gomock.Eq(uint(3))).AnyTimes().Return(conv, nil)
+			mockService.EXPECT().ViewMemberships(gomock.Any(), gomock.Eq(uint(2))).AnyTimes().
+				Return([]models.Membership{{UserID: 1, Role: models.RoleRecruiter}, {UserID: 6, Role: models.RoleViewer}}, nil)
+
+			hub := messaging.NewHub()
+			candidate, closeCandidate := hub.Subscribe(9)
+			defer closeCandidate()
+			viewer, closeViewer := hub.Subscribe(6)
+			defer closeViewer()
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService), mh: hub}
+			router.POST("/applications/:applicationID/messages", h.SendMessage)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{Subject: tc.subject})
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/applications/3/messages", strings.NewReader(tc.body))
+			require.NoError(t, err)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+			if tc.delivered {
+				require.Len(t, candidate, 1)
+				e := <-candidate
+				require.Equal(t, messaging.EventMessage, e.Type)
+				require.Equal(t, uint(3), e.ApplicationID)
+			} else {
+				require.Len(t, candidate, 0)
+			}
+			// Viewers do not take part in conversations
+			require.Len(t, viewer, 0)
+		})
+	}
+}
+
+func TestHandler_MarkMessagesRead(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{
+		Subject: "9",
+	}
+	conv := models.Conversation{Model: gorm.Model{ID: 5}, ApplicationID: 3, CompanyID: 2, CandidateID: 9}
+
+	tt := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		mockService    func(m *mockmodels.MockService)
+	}{
+		{
+			name:           "OK",
+			body:           `{"message_id":11}`,
+			expectedStatus: http.StatusOK,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().MarkConversationRead(gomock.Any(), gomock.Eq(uint(5)), gomock.Eq(uint(9)), gomock.Eq(uint(11))).Times(1).
+					Return(models.ConversationRead{ConversationID: 5, UserID: 9, LastReadMessageID: 11}, nil)
+			},
+		},
+		{
+			name:           "Fail_OtherConversation",
+			body:           `{"message_id":40}`,
+			expectedStatus: http.StatusNotFound,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().MarkConversationRead(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					Return(models.ConversationRead{}, gorm.ErrRecordNotFound)
+			},
+		},
+		{
+			name:           "Fail_NoMessage",
+			body:           `{}`,
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().MarkConversationRead(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+			mockService.EXPECT().ViewApplication(gomock.Any(), gomock.Eq(uint(3))).AnyTimes().
+				Return(models.Application{Model: gorm.Model{ID: 3}, JobID: 4, UserID: 9}, nil)
+			mockService.EXPECT().OpenConversation(gomock.Any(), gomock.Eq(uint(3))).AnyTimes().Return(conv, nil)
+			mockService.EXPECT().ViewMemberships(gomock.Any(), gomock.Eq(uint(2))).AnyTimes().
+				Return([]models.Membership{{UserID: 1, Role: models.RoleRecruiter}}, nil)
+
+			hub := messaging.NewHub()
+			recruiter, closeRecruiter := hub.Subscribe(1)
+			defer closeRecruiter()
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService), mh: hub}
+			router.POST("/applications/:applicationID/messages/read", h.MarkMessagesRead)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/applications/3/messages/read", strings.NewReader(tc.body))
+			require.NoError(t, err)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+			if tc.expectedStatus == http.StatusOK {
+				e := <-recruiter
+				require.Equal(t, messaging.EventRead, e.Type)
+				require.Equal(t, uint(11), e.Read.LastReadMessageID)
+			}
+		})
+	}
+}
+
+func TestHandler_ViewMyConversations(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	mockService := mockmodels.NewMockService(ctrl)
+	mockService.EXPECT().ViewUserConversations(gomock.Any(), gomock.Eq(uint(1))).Times(1).
+		Return([]models.Conversation{{Model: gorm.Model{ID: 5}, Unread: 2}, {Model: gorm.Model{ID: 6}, Unread: 3}}, nil)
+
+	router := gin.New()
+	h := handler{s: services.NewStore(mockService)}
+	router.GET("/me/conversations", h.ViewMyConversations)
+
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{Subject: "1"})
+	ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/me/conversations", nil)
+	require.NoError(t, err)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `"unread":5}`)
+}
@@ -0,0 +1,477 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/offers"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// decodeOfferTemplate reads and validates an offer template from the request body.
+// On failure the response has already been written and ok is false.
+func decodeOfferTemplate(c *gin.Context, traceId string) (models.NewOfferTemplate, bool) {
+	var nt models.NewOfferTemplate
+	err := json.NewDecoder(c.Request.Body).Decode(&nt)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return models.NewOfferTemplate{}, false
+	}
+	validate := validator.New()
+	err = validate.Struct(nt)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": "invalid offer template", "error": err.Error()})
+		return models.NewOfferTemplate{}, false
+	}
+	return nt, true
+}
+
+// companyOfferTemplate loads the offer template named in the URL and makes sure it belongs to the company in the URL,
+// which the logged-in user administers
+func (h *handler) companyOfferTemplate(c *gin.Context, traceId string, claims jwt.RegisteredClaims) (models.OfferTemplate, bool) {
+	companyID, ok := companyParam(c)
+	if !ok {
+		return models.OfferTemplate{}, false
+	}
+	if _, ok := h.requireCompanyRole(c, traceId, claims, companyID, models.RoleAdmin); !ok {
+		return models.OfferTemplate{}, false
+	}
+	templateID, err := strconv.ParseUint(c.Param("templateID"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid template ID"})
+		return models.OfferTemplate{}, false
+	}
+
+	t, err := h.s.ViewOfferTemplate(c.Request.Context(), uint(templateID))
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && t.CompanyID != companyID) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "offer template not found"})
+		return models.OfferTemplate{}, false
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching offer template"})
+		return models.OfferTemplate{}, false
+	}
+	return t, true
+}
+
+// requireOfferRole loads the offer named in the URL and makes sure the logged-in user
+// is its candidate or has at least the role min in the company that sent it.
+func (h *handler) requireOfferRole(c *gin.Context, traceId string, claims jwt.RegisteredClaims, min string) (models.Offer, bool) {
+	ctx := c.Request.Context()
+	offerID, err := strconv.ParseUint(c.Param("offerID"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid offer ID"})
+		return models.Offer{}, false
+	}
+	offer, err := h.s.ViewOffer(ctx, uint(offerID))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "offer not found"})
+		return models.Offer{}, false
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching offer"})
+		return models.Offer{}, false
+	}
+	if strconv.FormatUint(uint64(offer.CandidateID), 10) == claims.Subject {
+		return offer, true
+	}
+	app, err := h.s.ViewApplication(ctx, offer.ApplicationID)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching application"})
+		return models.Offer{}, false
+	}
+	jobs, err := h.s.ViewJobByJobId(ctx, app.JobID, claims.Subject)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching job details"})
+		return models.Offer{}, false
+	}
+	if len(jobs) == 0 {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "offer not found"})
+		return models.Offer{}, false
+	}
+	_, ok := h.requireCompanyRole(c, traceId, claims, jobs[0].CompanyID, min)
+	return offer, ok
+}
+
+// offerError responds to the errors of sending or answering an offer, reporting whether there was one
+func offerError(c *gin.Context, traceId string, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "offer not found"})
+	case errors.Is(err, offers.ErrUnknownTemplate):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrOfferNotAllowed), errors.Is(err, models.ErrOfferNotPending),
+		errors.Is(err, models.ErrApplicationWithdrawn):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "offer failed"})
+	}
+	return true
+}
+
+// CreateOfferTemplate adds an offer letter template to a company
+func (h *handler) CreateOfferTemplate(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	companyID, ok := companyParam(c)
+	if !ok {
+		return
+	}
+	nt, ok := decodeOfferTemplate(c, traceId)
+	if !ok {
+		return
+	}
+	if _, ok := h.requireCompanyRole(c, traceId, claims, companyID, models.RoleAdmin); !ok {
+		return
+	}
+	t, err := h.s.CreateOfferTemplate(ctx, companyID, nt)
+	if errors.Is(err, models.ErrInvalidOfferTemplate) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "saving offer template failed"})
+		return
+	}
+	c.JSON(http.StatusCreated, t)
+}
+
+// ViewOfferTemplates lists the offer letter templates of a company with the placeholders they can use
+func (h *handler) ViewOfferTemplates(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	companyID, ok := companyParam(c)
+	if !ok {
+		return
+	}
+	if _, ok := h.requireCompanyRole(c, traceId, claims, companyID, models.RoleRecruiter); !ok {
+		return
+	}
+	ts, err := h.s.ViewOfferTemplates(ctx, companyID)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching offer templates"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"templates": ts, "placeholders": models.OfferPlaceholders})
+}
+
+// UpdateOfferTemplate replaces the text of an offer letter template
+func (h *handler) UpdateOfferTemplate(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	nt, ok := decodeOfferTemplate(c, traceId)
+	if !ok {
+		return
+	}
+	t, ok := h.companyOfferTemplate(c, traceId, claims)
+	if !ok {
+		return
+	}
+	t, err := h.s.UpdateOfferTemplate(ctx, t.ID, nt)
+	if errors.Is(err, models.ErrInvalidOfferTemplate) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "saving offer template failed"})
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+// DeleteOfferTemplate removes an offer letter template. Offers sent with it keep their letter.
+func (h *handler) DeleteOfferTemplate(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	t, ok := h.companyOfferTemplate(c, traceId, claims)
+	if !ok {
+		return
+	}
+	err := h.s.DeleteOfferTemplate(ctx, t.ID)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "deleting offer template failed"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// SendOffer writes an offer letter for an application from one of the company's templates and sends it to the candidate
+func (h *handler) SendOffer(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	var no models.NewOffer
+	err := json.NewDecoder(c.Request.Body).Decode(&no)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	validate := validator.New()
+	err = validate.Struct(no)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": "invalid offer", "error": err.Error()})
+		return
+	}
+
+	app, ok := h.requireApplicationRole(c, traceId, claims, models.RoleRecruiter, false)
+	if !ok {
+		return
+	}
+	uid, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	offer, err := h.of.Issue(ctx, app, uint(uid), no)
+	if offerError(c, traceId, err) {
+		return
+	}
+	c.JSON(http.StatusCreated, offer)
+}
+
+// ViewApplicationOffers lists the offers sent for an application, newest first
+func (h *handler) ViewApplicationOffers(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	app, ok := h.requireApplicationRole(c, traceId, claims, models.RoleViewer, true)
+	if !ok {
+		return
+	}
+	sent, err := h.s.ViewApplicationOffers(ctx, app.ID)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching offers"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"offers": sent})
+}
+
+// ViewMyOffers lists the offers the logged-in candidate was sent, newest first
+func (h *handler) ViewMyOffers(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+	uid, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	sent, err := h.s.ViewOffersByCandidate(ctx, uint(uid))
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching offers"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"offers": sent})
+}
+
+// OfferLetter hands out a short-lived download link for the PDF letter of an offer
+func (h *handler) OfferLetter(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	offer, ok := h.requireOfferRole(c, traceId, claims, models.RoleViewer)
+	if !ok {
+		return
+	}
+	doc, err := h.s.ViewDocument(ctx, offer.DocumentID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "offer letter not found"})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching offer letter"})
+		return
+	}
+	h.signedURL(c, traceId, doc)
+}
+
+// AcceptOffer records the logged-in candidate accepting their offer, which marks the application hired
+func (h *handler) AcceptOffer(c *gin.Context) {
+	h.respondOffer(c, true)
+}
+
+// DeclineOffer records the logged-in candidate declining their offer, optionally saying why
+func (h *handler) DeclineOffer(c *gin.Context) {
+	h.respondOffer(c, false)
+}
+
+func (h *handler) respondOffer(c *gin.Context, accept bool) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	offerID, err := strconv.ParseUint(c.Param("offerID"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid offer ID"})
+		return
+	}
+	var body models.OfferResponse
+	if !accept && c.Request.ContentLength != 0 {
+		err = json.NewDecoder(c.Request.Body).Decode(&body)
+		if err != nil {
+			log.Error().Err(err).Str("Trace Id", traceId).Send()
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+		validate := validator.New()
+		err = validate.Struct(body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": "reason is too long", "error": err.Error()})
+			return
+		}
+	}
+
+	uid, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	// Only the candidate answers; anyone else gets a not found from the store
+	offer, err := h.s.RespondOffer(ctx, uint(offerID), uint(uid), accept, body.Reason)
+	if offerError(c, traceId, err) {
+		return
+	}
+	c.JSON(http.StatusOK, offer)
+}
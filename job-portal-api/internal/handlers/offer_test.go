@@ -0,0 +1,359 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/models/mockmodels"
+	"job-portal-api/internal/offers"
+	"job-portal-api/internal/services"
+	"job-portal-api/internal/storage"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler_CreateOfferTemplate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{
+		Subject: "1",
+	}
+	valid := `{"name":"Standard","title":"Offer of employment","body":"Dear {{candidate_name}},\n\nWelcome aboard."}`
+
+	tt := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		mockService    func(m *mockmodels.MockService)
+	}{
+		{
+			name:           "OK",
+			body:           valid,
+			expectedStatus: http.StatusCreated,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateOfferTemplate(gomock.Any(), gomock.Eq(uint(2)), gomock.Any()).Times(1).
+					Return(models.OfferTemplate{CompanyID: 2, Name: "Standard"}, nil)
+			},
+		},
+		{
+			name:           "Fail_NoBody",
+			body:           `{"name":"Standard"}`,
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateOfferTemplate(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:           "Fail_Placeholder",
+			body:           `{"name":"Standard","body":"Dear {{first_name}}"}`,
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateOfferTemplate(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					Return(models.OfferTemplate{}, fmt.Errorf("%w: unknown placeholder {{first_name}}", models.ErrInvalidOfferTemplate))
+			},
+		},
+		{
+			name:           "Fail_Recruiter",
+			body:           valid,
+			expectedStatus: http.StatusForbidden,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).Times(1).
+					Return(models.Membership{Role: models.RoleRecruiter}, nil)
+				m.EXPECT().ViewUser(gomock.Any(), gomock.Eq(uint(1))).Times(1).Return(models.User{}, nil)
+				m.EXPECT().CreateOfferTemplate(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+			mockService.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).AnyTimes().
+				Return(models.Membership{Role: models.RoleAdmin}, nil)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService)}
+			router.POST("/companies/:companyID/offer-templates", h.CreateOfferTemplate)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/companies/2/offer-templates", strings.NewReader(tc.body))
+			require.NoError(t, err)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandler_SendOffer(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{
+		Subject: "1",
+	}
+	valid := `{"template_id":6,"salary":85000,"currency":"EUR","start_date":"2026-04-01"}`
+
+	tt := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		mockService    func(m *mockmodels.MockService)
+	}{
+		{
+			name:           "OK",
+			body:           valid,
+			expectedStatus: http.StatusCreated,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateOffer(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, offer models.Offer, doc models.Document) (models.Offer, error) {
+						require.Equal(t, uint(3), offer.ApplicationID)
+						require.Equal(t, uint(1), offer.IssuedBy)
+						require.Equal(t, models.DocumentKindOffer, doc.Kind)
+						offer.Status = models.OfferPending
+						return offer, nil
+					})
+			},
+		},
+		{
+			name:           "Fail_StartDate",
+			body:           `{"template_id":6,"salary":85000,"currency":"EUR","start_date":"next monday"}`,
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateOffer(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:           "Fail_OtherCompanyTemplate",
+			body:           valid,
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewOfferTemplate(gomock.Any(), gomock.Eq(uint(6))).Times(1).
+					Return(models.OfferTemplate{Model: gorm.Model{ID: 6}, CompanyID: 8, Body: "Welcome"}, nil)
+				m.EXPECT().CreateOffer(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:           "Fail_Rejected",
+			body:           valid,
+			expectedStatus: http.StatusConflict,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateOffer(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					Return(models.Offer{}, models.ErrOfferNotAllowed)
+			},
+		},
+		{
+			name:           "Fail_Viewer",
+			body:           valid,
+			expectedStatus: http.StatusForbidden,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).Times(1).
+					Return(models.Membership{Role: models.RoleViewer}, nil)
+				m.EXPECT().ViewUser(gomock.Any(), gomock.Eq(uint(1))).Times(1).Return(models.User{}, nil)
+				m.EXPECT().CreateOffer(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+			mockService.EXPECT().ViewApplication(gomock.Any(), gomock.Eq(uint(3))).AnyTimes().
+				Return(models.Application{Model: gorm.Model{ID: 3}, JobID: 4, UserID: 9}, nil)
+			mockService.EXPECT().ViewJobByJobId(gomock.Any(), gomock.Eq(uint(4)), gomock.Any()).AnyTimes().
+				Return([]models.Job{{Model: gorm.Model{ID: 4}, CompanyID: 2, Title: "Go developer"}}, nil)
+			mockService.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).AnyTimes().
+				Return(models.Membership{Role: models.RoleRecruiter}, nil)
+			mockService.EXPECT().ViewOfferTemplate(gomock.Any(), gomock.Eq(uint(6))).AnyTimes().
+				Return(models.OfferTemplate{Model: gorm.Model{ID: 6}, CompanyID: 2, Body: "Dear {{candidate_name}}"}, nil)
+			mockService.EXPECT().ViewCompany(gomock.Any(), gomock.Eq(uint(2)), gomock.Any()).AnyTimes().
+				Return(models.Company{Model: gorm.Model{ID: 2}, CompanyName: "Acme"}, nil)
+			mockService.EXPECT().ViewUser(gomock.Any(), gomock.Eq(uint(9))).AnyTimes().
+				Return(models.User{Model: gorm.Model{ID: 9}, Name: "Asha"}, nil)
+
+			bs, err := storage.NewLocalStore(t.TempDir(), "http://localhost:8080/files", []byte("secret"))
+			require.NoError(t, err)
+			of, err := offers.NewIssuer(mockService, bs)
+			require.NoError(t, err)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService), bs: bs, of: of}
+			router.POST("/applications/:applicationID/offers", h.SendOffer)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/applications/3/offers", strings.NewReader(tc.body))
+			require.NoError(t, err)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandler_RespondOffer(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{
+		Subject: "9",
+	}
+
+	tt := []struct {
+		name           string
+		path           string
+		body           string
+		expectedStatus int
+		mockService    func(m *mockmodels.MockService)
+	}{
+		{
+			name:           "OK_Accept",
+			path:           "/offers/5/accept",
+			expectedStatus: http.StatusOK,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().RespondOffer(gomock.Any(), gomock.Eq(uint(5)), gomock.Eq(uint(9)), gomock.Eq(true), gomock.Eq("")).Times(1).
+					Return(models.Offer{Status: models.OfferAccepted}, nil)
+			},
+		},
+		{
+			name:           "OK_Decline",
+			path:           "/offers/5/decline",
+			body:           `{"reason":"Accepted another offer"}`,
+			expectedStatus: http.StatusOK,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().RespondOffer(gomock.Any(), gomock.Eq(uint(5)), gomock.Eq(uint(9)), gomock.Eq(false), gomock.Eq("Accepted another offer")).Times(1).
+					Return(models.Offer{Status: models.OfferDeclined}, nil)
+			},
+		},
+		{
+			name:           "Fail_Answered",
+			path:           "/offers/5/decline",
+			expectedStatus: http.StatusConflict,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().RespondOffer(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					Return(models.Offer{}, models.ErrOfferNotPending)
+			},
+		},
+		{
+			name:           "Fail_NotTheirs",
+			path:           "/offers/6/accept",
+			expectedStatus: http.StatusNotFound,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().RespondOffer(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					Return(models.Offer{}, gorm.ErrRecordNotFound)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService)}
+			router.POST("/offers/:offerID/accept", h.AcceptOffer)
+			router.POST("/offers/:offerID/decline", h.DeclineOffer)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, tc.path, strings.NewReader(tc.body))
+			require.NoError(t, err)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandler_OfferLetter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tt := []struct {
+		name           string
+		subject        string
+		expectedStatus int
+		mockService    func(m *mockmodels.MockService)
+	}{
+		{
+			name:           "OK_Candidate",
+			subject:        "9",
+			expectedStatus: http.StatusOK,
+			mockService:    func(m *mockmodels.MockService) {},
+		},
+		{
+			name:           "OK_Recruiter",
+			subject:        "1",
+			expectedStatus: http.StatusOK,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).Times(1).
+					Return(models.Membership{Role: models.RoleRecruiter}, nil)
+			},
+		},
+		{
+			name:           "Fail_Outsider",
+			subject:        "7",
+			expectedStatus: http.StatusForbidden,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(7))).Times(1).
+					Return(models.Membership{}, gorm.ErrRecordNotFound)
+				m.EXPECT().ViewUser(gomock.Any(), gomock.Eq(uint(7))).Times(1).Return(models.User{}, nil)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+			mockService.EXPECT().ViewOffer(gomock.Any(), gomock.Eq(uint(5))).AnyTimes().
+				Return(models.Offer{Model: gorm.Model{ID: 5}, ApplicationID: 3, CandidateID: 9, DocumentID: 11}, nil)
+			mockService.EXPECT().ViewApplication(gomock.Any(), gomock.Eq(uint(3))).AnyTimes().
+				Return(models.Application{Model: gorm.Model{ID: 3}, JobID: 4, UserID: 9}, nil)
+			mockService.EXPECT().ViewJobByJobId(gomock.Any(), gomock.Eq(uint(4)), gomock.Any()).AnyTimes().
+				Return([]models.Job{{Model: gorm.Model{ID: 4}, CompanyID: 2}}, nil)
+			mockService.EXPECT().ViewDocument(gomock.Any(), gomock.Eq(uint(11))).AnyTimes().
+				Return(models.Document{Model: gorm.Model{ID: 11}, Kind: models.DocumentKindOffer, StorageKey: "offers/3/letter.pdf"}, nil)
+
+			bs, err := storage.NewLocalStore(t.TempDir(), "http://localhost:8080/files", []byte("secret"))
+			require.NoError(t, err)
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService), bs: bs}
+			router.GET("/offers/:offerID/letter", h.OfferLetter)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{Subject: tc.subject})
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/offers/5/letter", nil)
+			require.NoError(t, err)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+			if tc.expectedStatus == http.StatusOK {
+				require.Contains(t, rec.Body.String(), "offers/3/letter.pdf")
+			}
+		})
+	}
+}
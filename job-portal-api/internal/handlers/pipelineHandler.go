@@ -0,0 +1,288 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// pipelineError responds to the errors of saving a pipeline or moving an application through one,
+// reporting whether there was one
+func pipelineError(c *gin.Context, traceId string, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "not found"})
+	case errors.Is(err, models.ErrInvalidPipeline), errors.Is(err, models.ErrUnknownStage):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrStageInUse), errors.Is(err, models.ErrApplicationWithdrawn):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem with the pipeline"})
+	}
+	return true
+}
+
+// decodePipeline reads and validates the stages of a pipeline being saved, responding itself when they are invalid
+func decodePipeline(c *gin.Context, traceId string) (models.PipelineUpdate, bool) {
+	var pu models.PipelineUpdate
+	err := json.NewDecoder(c.Request.Body).Decode(&pu)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return models.PipelineUpdate{}, false
+	}
+	validate := validator.New()
+	err = validate.Struct(pu)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": "a pipeline needs 1 to 20 stages with distinct names", "error": err.Error()})
+		return models.PipelineUpdate{}, false
+	}
+	return pu, true
+}
+
+// ViewCompanyPipeline returns the default pipeline of a company
+func (h *handler) ViewCompanyPipeline(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	companyID, ok := companyParam(c)
+	if !ok {
+		return
+	}
+	if _, ok := h.requireCompanyRole(c, traceId, claims, companyID, models.RoleViewer); !ok {
+		return
+	}
+	p, err := h.s.ViewCompanyPipeline(ctx, companyID)
+	if pipelineError(c, traceId, err) {
+		return
+	}
+	c.JSON(http.StatusOK, p)
+}
+
+// UpdateCompanyPipeline replaces the default pipeline of a company
+func (h *handler) UpdateCompanyPipeline(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	companyID, ok := companyParam(c)
+	if !ok {
+		return
+	}
+	pu, ok := decodePipeline(c, traceId)
+	if !ok {
+		return
+	}
+	if _, ok := h.requireCompanyRole(c, traceId, claims, companyID, models.RoleAdmin); !ok {
+		return
+	}
+	p, err := h.s.UpdateCompanyPipeline(ctx, companyID, pu)
+	if pipelineError(c, traceId, err) {
+		return
+	}
+	c.JSON(http.StatusOK, p)
+}
+
+// ViewJobPipeline returns the pipeline the applications to a job go through
+func (h *handler) ViewJobPipeline(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	job, ok := h.requireJobRole(c, traceId, claims, models.RoleViewer)
+	if !ok {
+		return
+	}
+	p, err := h.s.ViewJobPipeline(ctx, job.ID)
+	if pipelineError(c, traceId, err) {
+		return
+	}
+	c.JSON(http.StatusOK, p)
+}
+
+// UpdateJobPipeline gives a job a pipeline of its own instead of its company's default, or replaces it
+func (h *handler) UpdateJobPipeline(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	pu, ok := decodePipeline(c, traceId)
+	if !ok {
+		return
+	}
+	job, ok := h.requireJobRole(c, traceId, claims, models.RoleRecruiter)
+	if !ok {
+		return
+	}
+	p, err := h.s.UpdateJobPipeline(ctx, job.ID, pu)
+	if pipelineError(c, traceId, err) {
+		return
+	}
+	c.JSON(http.StatusOK, p)
+}
+
+// ViewJobBoard returns the applications to a job grouped by the stage they are in, with counts
+func (h *handler) ViewJobBoard(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	job, ok := h.requireJobRole(c, traceId, claims, models.RoleViewer)
+	if !ok {
+		return
+	}
+	board, err := h.s.ViewBoard(ctx, job.ID)
+	if pipelineError(c, traceId, err) {
+		return
+	}
+	c.JSON(http.StatusOK, board)
+}
+
+// MoveApplication moves an application to another stage of its job's pipeline
+func (h *handler) MoveApplication(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	var sm models.StageMove
+	err := json.NewDecoder(c.Request.Body).Decode(&sm)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	validate := validator.New()
+	err = validate.Struct(sm)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": "stage_id is required", "error": err.Error()})
+		return
+	}
+
+	app, ok := h.requireApplicationRole(c, traceId, claims, models.RoleRecruiter, false)
+	if !ok {
+		return
+	}
+	uid, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	app, err = h.s.MoveApplication(ctx, app.ID, sm.StageID, uint(uid))
+	if pipelineError(c, traceId, err) {
+		return
+	}
+	c.JSON(http.StatusOK, app)
+}
+
+// ViewApplicationStages lists when an application entered each stage it went through
+func (h *handler) ViewApplicationStages(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	app, ok := h.requireApplicationRole(c, traceId, claims, models.RoleViewer, false)
+	if !ok {
+		return
+	}
+	changes, err := h.s.ViewApplicationStageChanges(ctx, app.ID)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching stage history"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"stages": changes})
+}
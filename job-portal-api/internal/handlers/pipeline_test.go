@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/models/mockmodels"
+	"job-portal-api/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler_UpdateJobPipeline(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{
+		Subject: "1",
+	}
+	valid := `{"stages":[{"id":11,"name":"Applied"},{"name":"Phone Screen"},{"name":"Tech Interview"},{"id":14,"name":"Offer"}]}`
+
+	tt := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		mockService    func(m *mockmodels.MockService)
+	}{
+		{
+			name:           "OK",
+			body:           valid,
+			expectedStatus: http.StatusOK,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().UpdateJobPipeline(gomock.Any(), gomock.Eq(uint(4)), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, _ uint, pu models.PipelineUpdate) (models.Pipeline, error) {
+						require.Len(t, pu.Stages, 4)
+						require.Equal(t, uint(11), pu.Stages[0].ID)
+						return models.Pipeline{CompanyID: 2}, nil
+					})
+			},
+		},
+		{
+			name:           "Fail_DuplicateNames",
+			body:           `{"stages":[{"name":"Onsite"},{"name":"Onsite"}]}`,
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().UpdateJobPipeline(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:           "Fail_NoStages",
+			body:           `{"stages":[]}`,
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().UpdateJobPipeline(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:           "Fail_Viewer",
+			body:           valid,
+			expectedStatus: http.StatusForbidden,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).Times(1).
+					Return(models.Membership{Role: models.RoleViewer}, nil)
+				m.EXPECT().ViewUser(gomock.Any(), gomock.Eq(uint(1))).Times(1).Return(models.User{}, nil)
+				m.EXPECT().UpdateJobPipeline(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:           "Fail_StageInUse",
+			body:           valid,
+			expectedStatus: http.StatusConflict,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().UpdateJobPipeline(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					Return(models.Pipeline{}, fmt.Errorf("%w: 3 in Screening", models.ErrStageInUse))
+			},
+		},
+		{
+			name:           "Fail_ForeignStage",
+			body:           valid,
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().UpdateJobPipeline(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					Return(models.Pipeline{}, fmt.Errorf("%w: stage 14 is not part of it", models.ErrInvalidPipeline))
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+			mockService.EXPECT().ViewJobByJobId(gomock.Any(), gomock.Eq(uint(4)), gomock.Any()).AnyTimes().
+				Return([]models.Job{{Model: gorm.Model{ID: 4}, CompanyID: 2}}, nil)
+			mockService.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).AnyTimes().
+				Return(models.Membership{Role: models.RoleRecruiter}, nil)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService)}
+			router.PUT("/jobs/:jobID/pipeline", h.UpdateJobPipeline)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodPut, "/jobs/4/pipeline", strings.NewReader(tc.body))
+			require.NoError(t, err)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandler_MoveApplication(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tt := []struct {
+		name           string
+		subject        string
+		body           string
+		expectedStatus int
+		mockService    func(m *mockmodels.MockService)
+	}{
+		{
+			name:           "OK",
+			subject:        "1",
+			body:           `{"stage_id":12}`,
+			expectedStatus: http.StatusOK,
+			mockService: func(m *mockmodels.MockService) {
+				stage := uint(12)
+				m.EXPECT().MoveApplication(gomock.Any(), gomock.Eq(uint(3)), gomock.Eq(uint(12)), gomock.Eq(uint(1))).Times(1).
+					Return(models.Application{Model: gorm.Model{ID: 3}, JobID: 4, StageID: &stage}, nil)
+			},
+		},
+		{
+			name:           "Fail_Candidate",
+			subject:        "9",
+			body:           `{"stage_id":12}`,
+			expectedStatus: http.StatusForbidden,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(9))).Times(1).
+					Return(models.Membership{}, gorm.ErrRecordNotFound)
+				m.EXPECT().ViewUser(gomock.Any(), gomock.Eq(uint(9))).Times(1).Return(models.User{}, nil)
+				m.EXPECT().MoveApplication(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:           "Fail_UnknownStage",
+			subject:        "1",
+			body:           `{"stage_id":99}`,
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().MoveApplication(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					Return(models.Application{}, models.ErrUnknownStage)
+			},
+		},
+		{
+			name:           "Fail_Withdrawn",
+			subject:        "1",
+			body:           `{"stage_id":12}`,
+			expectedStatus: http.StatusConflict,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().MoveApplication(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					Return(models.Application{}, models.ErrApplicationWithdrawn)
+			},
+		},
+		{
+			name:           "Fail_NoStage",
+			subject:        "1",
+			body:           `{}`,
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().MoveApplication(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+			mockService.EXPECT().ViewApplication(gomock.Any(), gomock.Eq(uint(3))).AnyTimes().
+				Return(models.Application{Model: gorm.Model{ID: 3}, JobID: 4, UserID: 9}, nil)
+			mockService.EXPECT().ViewJobByJobId(gomock.Any(), gomock.Eq(uint(4)), gomock.Any()).AnyTimes().
+				Return([]models.Job{{Model: gorm.Model{ID: 4}, CompanyID: 2}}, nil)
+			mockService.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).AnyTimes().
+				Return(models.Membership{Role: models.RoleRecruiter}, nil)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService)}
+			router.POST("/applications/:applicationID/stage", h.MoveApplication)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{Subject: tc.subject})
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/applications/3/stage", strings.NewReader(tc.body))
+			require.NoError(t, err)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandler_ViewJobBoard(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	mockService := mockmodels.NewMockService(ctrl)
+	mockService.EXPECT().ViewJobByJobId(gomock.Any(), gomock.Eq(uint(4)), gomock.Any()).AnyTimes().
+		Return([]models.Job{{Model: gorm.Model{ID: 4}, CompanyID: 2}}, nil)
+	mockService.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).AnyTimes().
+		Return(models.Membership{Role: models.RoleViewer}, nil)
+	mockService.EXPECT().ViewBoard(gomock.Any(), gomock.Eq(uint(4))).Times(1).
+		Return(models.Board{JobID: 4, Total: 2, Columns: []models.BoardColumn{
+			{Stage: models.PipelineStage{Name: "Applied"}, Count: 2, Applications: []models.Application{{JobID: 4}, {JobID: 4}}},
+			{Stage: models.PipelineStage{Name: "Offer"}, Applications: []models.Application{}},
+		}}, nil)
+
+	router := gin.New()
+	h := handler{s: services.NewStore(mockService)}
+	router.GET("/jobs/:jobID/board", h.ViewJobBoard)
+
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{Subject: "1"})
+	ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/jobs/4/board", nil)
+	require.NoError(t, err)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `"name":"Offer"`)
+	require.Contains(t, rec.Body.String(), `"total":2`)
+}
@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/models/mockmodels"
+	"job-portal-api/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler_UpdateMyProfile(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{
+		Subject: "7",
+	}
+
+	np := models.NewProfile{
+		Headline: "Backend engineer",
+		Skills:   []string{"Go", "PostgreSQL"},
+		Experiences: []models.NewExperience{{
+			Title:     "Software Engineer",
+			Company:   "TEKsystem",
+			StartDate: "2019-04",
+			Current:   true,
+		}},
+		DesiredSalaryMin: 100000,
+		DesiredSalaryMax: 150000,
+		SalaryCurrency:   "USD",
+	}
+	mockProfile := models.Profile{
+		Model:            gorm.Model{ID: 1},
+		UserID:           7,
+		Headline:         "Backend engineer",
+		Skills:           []string{"go", "postgresql"},
+		DesiredSalaryMin: 100000,
+		DesiredSalaryMax: 150000,
+		SalaryCurrency:   "USD",
+	}
+
+	tt := []struct {
+		name             string
+		body             any
+		expectedStatus   int
+		expectedResponse string
+		mockService      func(m *mockmodels.MockService)
+	}{
+		{
+			name:             "OK",
+			body:             np,
+			expectedStatus:   http.StatusOK,
+			expectedResponse: `{"ID":1,"CreatedAt":"0001-01-01T00:00:00Z","UpdatedAt":"0001-01-01T00:00:00Z","DeletedAt":null,"user_id":7,"headline":"Backend engineer","summary":"","skills":["go","postgresql"],"experiences":null,"educations":null,"desired_roles":null,"desired_locations":null,"desired_salary_min":100000,"desired_salary_max":150000,"salary_currency":"USD","links":null,"discoverable":false}`,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().UpdateProfile(gomock.Any(), gomock.Eq(np), gomock.Eq(uint(7))).
+					Times(1).Return(mockProfile, nil)
+			},
+		},
+		{
+			name: "Fail_CurrentJobWithEndDate",
+			body: models.NewProfile{
+				Headline: "Backend engineer",
+				Experiences: []models.NewExperience{{
+					Title:     "Software Engineer",
+					Company:   "TEKsystem",
+					StartDate: "2019-04",
+					EndDate:   "2021-01",
+					Current:   true,
+				}},
+			},
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().UpdateProfile(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name: "Fail_SalaryRangeInverted",
+			body: models.NewProfile{
+				Headline:         "Backend engineer",
+				DesiredSalaryMin: 150000,
+				DesiredSalaryMax: 100000,
+				SalaryCurrency:   "USD",
+			},
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().UpdateProfile(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name: "Fail_SalaryWithoutCurrency",
+			body: models.NewProfile{
+				Headline:         "Backend engineer",
+				DesiredSalaryMin: 100000,
+			},
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().UpdateProfile(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService)}
+			router.PUT("/me/profile", h.UpdateMyProfile)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			body, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			req, err := http.NewRequestWithContext(ctx, http.MethodPut, "/me/profile", bytes.NewReader(body))
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+			if tc.expectedResponse != "" {
+				require.Equal(t, tc.expectedResponse, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_ViewMyProfile(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{
+		Subject: "7",
+	}
+
+	tt := []struct {
+		name             string
+		expectedStatus   int
+		expectedResponse string
+		mockService      func(m *mockmodels.MockService)
+	}{
+		{
+			name:             "NotFound",
+			expectedStatus:   http.StatusNotFound,
+			expectedResponse: `{"msg":"profile not created yet"}`,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewProfile(gomock.Any(), gomock.Eq(uint(7))).
+					Times(1).Return(models.Profile{}, gorm.ErrRecordNotFound)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService)}
+			router.GET("/me/profile", h.ViewMyProfile)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/me/profile", nil)
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+			require.Equal(t, tc.expectedResponse, rec.Body.String())
+		})
+	}
+}
+
+func TestHandler_ViewCandidateProfile(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{
+		Subject: "7",
+	}
+
+	tt := []struct {
+		name             string
+		expectedStatus   int
+		expectedResponse string
+		mockService      func(m *mockmodels.MockService)
+	}{
+		{
+			name:           "OK",
+			expectedStatus: http.StatusOK,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewCandidateProfile(gomock.Any(), gomock.Eq(uint(7)), gomock.Eq(uint(9))).
+					Times(1).Return(models.Profile{UserID: 9, Headline: "Go developer", Discoverable: true}, nil)
+			},
+		},
+		{
+			name:             "Hidden",
+			expectedStatus:   http.StatusNotFound,
+			expectedResponse: `{"msg":"profile not found"}`,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewCandidateProfile(gomock.Any(), gomock.Eq(uint(7)), gomock.Eq(uint(9))).
+					Times(1).Return(models.Profile{}, models.ErrCandidateNotFound)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService)}
+			router.GET("/candidates/:userID/profile", h.ViewCandidateProfile)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/candidates/9/profile", nil)
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+			if tc.expectedResponse != "" {
+				require.Equal(t, tc.expectedResponse, rec.Body.String())
+			} else {
+				require.Contains(t, rec.Body.String(), `"headline":"Go developer"`)
+			}
+		})
+	}
+}
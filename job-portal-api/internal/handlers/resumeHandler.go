@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// queueResumeParse records a pending parse of doc and hands it to the background pipeline
+func (h *handler) queueResumeParse(ctx context.Context, doc models.Document) (models.ResumeParse, error) {
+	rp, err := h.s.StartResumeParse(ctx, doc.ID, doc.OwnerID)
+	if err != nil {
+		return models.ResumeParse{}, err
+	}
+	err = h.rp.Enqueue(ctx, doc.ID)
+	if err != nil {
+		return models.ResumeParse{}, err
+	}
+	return rp, nil
+}
+
+// ParseResume (re)starts the parsing of one of the candidate's resumes
+func (h *handler) ParseResume(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	doc, ok := h.myDocument(c, traceId, claims)
+	if !ok {
+		return
+	}
+	if doc.Kind != models.DocumentKindResume {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "only resumes can be parsed"})
+		return
+	}
+
+	rp, err := h.queueResumeParse(ctx, doc)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Msg("queueing resume parse")
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"msg": "resume parsing is busy, try again later"})
+		return
+	}
+	c.JSON(http.StatusAccepted, rp)
+}
+
+// ViewResumeParse returns the parsing status and, once done, the draft extracted from the resume
+func (h *handler) ViewResumeParse(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	doc, ok := h.myDocument(c, traceId, claims)
+	if !ok {
+		return
+	}
+	rp, err := h.s.ViewResumeParse(ctx, doc.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "resume has not been parsed"})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching parse result"})
+		return
+	}
+	c.JSON(http.StatusOK, rp)
+}
+
+// ConfirmResumeParse copies the confirmed parts of the draft into the candidate's profile
+func (h *handler) ConfirmResumeParse(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	var rc models.ResumeConfirmation
+	err := json.NewDecoder(c.Request.Body).Decode(&rc)
+	if err != nil && !errors.Is(err, io.EOF) {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if !rc.Skills && !rc.Experiences && !rc.Educations {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "select at least one of skills, experiences or educations"})
+		return
+	}
+
+	doc, ok := h.myDocument(c, traceId, claims)
+	if !ok {
+		return
+	}
+
+	// The candidate may send a corrected draft, otherwise the stored one is used
+	draft := rc.Draft
+	if draft == nil {
+		rp, err := h.s.ViewResumeParse(ctx, doc.ID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "resume has not been parsed"})
+			return
+		}
+		if err != nil {
+			log.Error().Err(err).Str("Trace Id", traceId).Send()
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching parse result"})
+			return
+		}
+		if rp.Status != models.ResumeParseDone {
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{"msg": "resume parsing is " + rp.Status})
+			return
+		}
+		draft = &rp.Draft
+	}
+
+	// Parsed entries are best effort, leave out the ones a profile would not accept
+	validate := validator.New()
+	var usable models.ResumeDraft
+	usable.Skills = draft.Skills
+	for _, e := range draft.Experiences {
+		if validate.Struct(e) == nil {
+			usable.Experiences = append(usable.Experiences, e)
+		}
+	}
+	for _, e := range draft.Educations {
+		if validate.Struct(e) == nil {
+			usable.Educations = append(usable.Educations, e)
+		}
+	}
+
+	profile, err := h.s.ViewProfile(ctx, doc.OwnerID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching profile"})
+		return
+	}
+	np := models.MergeDraft(profile, usable, rc)
+	err = validate.Struct(np)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": "profile would be invalid, please fill it in by hand", "error": err.Error()})
+		return
+	}
+
+	updated, err := h.s.UpdateProfile(ctx, np, doc.OwnerID)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "profile update failed"})
+		return
+	}
+	h.profileChanged(ctx, traceId, doc.OwnerID)
+	c.JSON(http.StatusOK, updated)
+}
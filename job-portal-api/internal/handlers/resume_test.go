@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/models/mockmodels"
+	"job-portal-api/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler_ConfirmResumeParse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{
+		Subject: "7",
+	}
+	doc := models.Document{Model: gorm.Model{ID: 3}, OwnerID: 7, Kind: models.DocumentKindResume}
+	draft := models.ResumeDraft{
+		Skills: []string{"go", "docker"},
+		Experiences: []models.NewExperience{
+			{Title: "Software Engineer", Company: "Globex", StartDate: "2017-06", EndDate: "2020-12"},
+			// Missing company, cannot be saved and is skipped
+			{Title: "Intern", StartDate: "2016-01", EndDate: "2016-06"},
+		},
+	}
+
+	tt := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		mockService    func(m *mockmodels.MockService)
+	}{
+		{
+			name:           "OK",
+			body:           `{"skills":true,"experiences":true}`,
+			expectedStatus: http.StatusOK,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewDocument(gomock.Any(), gomock.Eq(uint(3))).Times(1).Return(doc, nil)
+				m.EXPECT().ViewResumeParse(gomock.Any(), gomock.Eq(uint(3))).Times(1).
+					Return(models.ResumeParse{DocumentID: 3, Status: models.ResumeParseDone, Draft: draft}, nil)
+				m.EXPECT().ViewProfile(gomock.Any(), gomock.Eq(uint(7))).Times(1).
+					Return(models.Profile{Skills: []string{"sql"}}, nil)
+				m.EXPECT().UpdateProfile(gomock.Any(), gomock.Eq(models.NewProfile{
+					Headline:    "Software Engineer",
+					Skills:      []string{"sql", "go", "docker"},
+					Experiences: draft.Experiences[:1],
+				}), gomock.Eq(uint(7))).Times(1).Return(models.Profile{}, nil)
+			},
+		},
+		{
+			name:           "Fail_StillPending",
+			body:           `{"skills":true}`,
+			expectedStatus: http.StatusConflict,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewDocument(gomock.Any(), gomock.Eq(uint(3))).Times(1).Return(doc, nil)
+				m.EXPECT().ViewResumeParse(gomock.Any(), gomock.Eq(uint(3))).Times(1).
+					Return(models.ResumeParse{DocumentID: 3, Status: models.ResumeParsePending}, nil)
+				m.EXPECT().UpdateProfile(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:           "Fail_NothingSelected",
+			body:           `{}`,
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().UpdateProfile(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService)}
+			router.POST("/me/documents/:documentID/parse/confirm", h.ConfirmResumeParse)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/me/documents/3/parse/confirm", bytes.NewBufferString(tc.body))
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code, rec.Body.String())
+		})
+	}
+}
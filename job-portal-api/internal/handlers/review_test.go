@@ -0,0 +1,288 @@
+package handlers
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/models/mockmodels"
+	"job-portal-api/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler_CreateCompanyReview(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{
+		Subject: "7",
+	}
+	body := `{"title":"Good place","pros":"Kind people","cons":"Slow releases","employment_status":"former","overall":4,"culture":5}`
+
+	tt := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		mockService    func(m *mockmodels.MockService)
+	}{
+		{
+			name:           "OK",
+			body:           body,
+			expectedStatus: http.StatusCreated,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateCompanyReview(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(7)), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, companyId, userId uint, nr models.NewCompanyReview) (models.CompanyReview, error) {
+						require.Equal(t, 4, nr.Overall)
+						require.Equal(t, 5, *nr.Culture)
+						require.Nil(t, nr.Compensation)
+						return models.CompanyReview{CompanyID: companyId, UserID: userId, Overall: nr.Overall, Status: models.ReviewPending}, nil
+					})
+			},
+		},
+		{
+			name:           "Fail_Rating",
+			body:           strings.Replace(body, `"overall":4`, `"overall":6`, 1),
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateCompanyReview(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:           "Fail_OwnCompany",
+			body:           body,
+			expectedStatus: http.StatusForbidden,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateCompanyReview(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					Return(models.CompanyReview{}, models.ErrReviewOwnCompany)
+			},
+		},
+		{
+			name:           "Fail_Twice",
+			body:           body,
+			expectedStatus: http.StatusConflict,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateCompanyReview(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					Return(models.CompanyReview{}, gorm.ErrDuplicatedKey)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService)}
+			router.POST("/companies/:companyID/reviews", h.CreateCompanyReview)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/companies/2/reviews", strings.NewReader(tc.body))
+			require.NoError(t, err)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandler_ModerateReview(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{
+		Subject: "1",
+	}
+
+	tt := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		mockService    func(m *mockmodels.MockService)
+	}{
+		{
+			name:           "OK",
+			body:           `{"status":"approved"}`,
+			expectedStatus: http.StatusOK,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewUser(gomock.Any(), gomock.Eq(uint(1))).Times(1).Return(models.User{Admin: true}, nil)
+				m.EXPECT().ModerateReview(gomock.Any(), gomock.Eq(uint(4)), gomock.Eq(uint(1)),
+					gomock.Eq(models.ReviewModeration{Status: models.ReviewApproved})).Times(1).
+					Return(models.CompanyReview{Status: models.ReviewApproved}, nil)
+			},
+		},
+		{
+			name:           "Fail_RejectWithoutNote",
+			body:           `{"status":"rejected"}`,
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewUser(gomock.Any(), gomock.Eq(uint(1))).Times(1).Return(models.User{Admin: true}, nil)
+				m.EXPECT().ModerateReview(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:           "Fail_NotAdmin",
+			body:           `{"status":"approved"}`,
+			expectedStatus: http.StatusForbidden,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewUser(gomock.Any(), gomock.Eq(uint(1))).Times(1).Return(models.User{}, nil)
+				m.EXPECT().ModerateReview(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService)}
+			router.POST("/admin/reviews/:reviewID/moderation", h.ModerateReview)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/admin/reviews/4/moderation", strings.NewReader(tc.body))
+			require.NoError(t, err)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandler_CompanyPage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	culture := 4.5
+
+	tt := []struct {
+		name           string
+		expectedStatus int
+		expectedBody   string
+		mockService    func(m *mockmodels.MockService)
+	}{
+		{
+			name:           "OK",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"tech_stack":["go","postgres"],"rating":{"average":4,"count":3,"culture":4.5}`,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewCompany(gomock.Any(), gomock.Eq(uint(2)), gomock.Any()).Times(1).
+					Return(models.Company{Model: gorm.Model{ID: 2}, CompanyName: "Acme", TechStack: []string{"go", "postgres"}}, nil)
+				m.EXPECT().ViewCompanyRating(gomock.Any(), gomock.Eq(uint(2))).Times(1).
+					Return(models.CompanyRating{RatingSummary: models.RatingSummary{CompanyID: 2, Average: 4, Count: 3}, Culture: &culture}, nil)
+			},
+		},
+		{
+			name:           "Fail_NotFound",
+			expectedStatus: http.StatusNotFound,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewCompany(gomock.Any(), gomock.Eq(uint(2)), gomock.Any()).Times(1).Return(models.Company{}, nil)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService)}
+			router.GET("/companies/:companyID", h.CompanyPage)
+
+			ctx := context.WithValue(context.Background(), middleware.TraceIdKey, "fake-trace-id")
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/companies/2", nil)
+			require.NoError(t, err)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+			require.Contains(t, rec.Body.String(), tc.expectedBody)
+		})
+	}
+}
+
+func TestHandler_UpdateCompany(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{
+		Subject: "1",
+	}
+	body := `{"company_name":"Acme","founded_year":"2016","location":"Berlin","website":"https://acme.example.com","size":"51-200","benefits":["Remote work"]}`
+
+	tt := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		mockService    func(m *mockmodels.MockService)
+	}{
+		{
+			name:           "OK",
+			body:           body,
+			expectedStatus: http.StatusOK,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).Times(1).
+					Return(models.Membership{Role: models.RoleAdmin}, nil)
+				m.EXPECT().UpdateCompany(gomock.Any(), gomock.Eq(uint(2)), gomock.Any()).Times(1).
+					Return(models.Company{Model: gorm.Model{ID: 2}, CompanyName: "Acme", Size: "51-200"}, nil)
+			},
+		},
+		{
+			name:           "Fail_Size",
+			body:           strings.Replace(body, "51-200", "huge", 1),
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).Times(1).
+					Return(models.Membership{Role: models.RoleAdmin}, nil)
+				m.EXPECT().UpdateCompany(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:           "Fail_Recruiter",
+			body:           body,
+			expectedStatus: http.StatusForbidden,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).Times(1).
+					Return(models.Membership{Role: models.RoleRecruiter}, nil)
+				m.EXPECT().ViewUser(gomock.Any(), gomock.Eq(uint(1))).Times(1).Return(models.User{}, nil)
+				m.EXPECT().UpdateCompany(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService)}
+			router.PUT("/companies/:companyID", h.UpdateCompany)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodPut, "/companies/2", strings.NewReader(tc.body))
+			require.NoError(t, err)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"errors"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/salary"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// SalaryInsights reports salary percentiles of the jobs posted over the last year. The query narrows the
+// postings by title, experience and location, groups them by group_by (title, experience and/or location,
+// comma separated) and gives figures in currency and per period (hourly, monthly or annual)
+func (h *handler) SalaryInsights(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	q, err := salary.ParseQuery(c.Query("group_by"), c.Query("currency"), c.Query("period"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	q.Filter.Title = c.Query("title")
+	q.Filter.ExperienceLevel = c.Query("experience")
+	q.Filter.Location = c.Query("location")
+
+	r, err := h.sr.Report(ctx, q, time.Now())
+	if errors.Is(err, salary.ErrUnknownCurrency) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in building salary insights"})
+		return
+	}
+	c.JSON(http.StatusOK, r)
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/models/mockmodels"
+	"job-portal-api/internal/salary"
+	"job-portal-api/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler_SalaryInsights(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{
+		Subject: "7",
+	}
+	postings := []models.SalaryPosting{
+		{CompanyID: 1, Title: "Go developer", SalaryMin: 100000, SalaryCurrency: "USD"},
+		{CompanyID: 2, Title: "Go developer", SalaryMin: 10000, SalaryCurrency: "EUR", SalaryPeriod: models.PayMonthly},
+		{CompanyID: 3, Title: "Designer", SalaryMin: 90000, SalaryCurrency: "USD"},
+	}
+	rates, err := salary.ParseRates("EUR=1.1", "USD")
+	require.NoError(t, err)
+
+	tt := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedBody   string
+		mockService    func(m *mockmodels.MockService)
+	}{
+		{
+			name:           "OK",
+			query:          "?title=developer&period=monthly",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"buckets":[{"title":"Go developer","samples":2,"percentiles":{"p10":8600,"p25":9000,"p50":9667,"p75":10333,"p90":10733}}],"suppressed":1,"unconverted":0`,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().SalaryPostings(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, f models.SalaryFilter) ([]models.SalaryPosting, error) {
+						require.Equal(t, "developer", f.Title)
+						require.False(t, f.Since.IsZero())
+						return postings, nil
+					})
+			},
+		},
+		{
+			name:           "Fail_GroupBy",
+			query:          "?group_by=company",
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().SalaryPostings(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:           "Fail_Currency",
+			query:          "?currency=JPY",
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().SalaryPostings(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+
+			router := gin.New()
+			ms := services.NewStore(mockService)
+			h := handler{s: ms, sr: salary.NewReporter(ms, rates, 2)}
+			router.GET("/insights/salaries", h.SalaryInsights)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/insights/salaries"+tc.query, nil)
+			require.NoError(t, err)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+			require.Contains(t, rec.Body.String(), tc.expectedBody)
+		})
+	}
+}
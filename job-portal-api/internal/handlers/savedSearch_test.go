@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/models/mockmodels"
+	"job-portal-api/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler_CreateSavedSearch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{
+		Subject: "7",
+	}
+
+	ns := models.NewSavedSearch{
+		Name:      "Go in Pune",
+		Keywords:  "backend",
+		Location:  "Pune",
+		Skills:    []string{"Go"},
+		Frequency: models.FrequencyWeekly,
+	}
+
+	tt := []struct {
+		name           string
+		body           any
+		expectedStatus int
+		mockService    func(m *mockmodels.MockService)
+	}{
+		{
+			name:           "OK",
+			body:           ns,
+			expectedStatus: http.StatusCreated,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateSavedSearch(gomock.Any(), gomock.Eq(ns), gomock.Eq(uint(7))).
+					Times(1).Return(models.SavedSearch{Model: gorm.Model{ID: 1}, UserID: 7, Name: ns.Name}, nil)
+			},
+		},
+		{
+			name:           "Fail_UnknownFrequency",
+			body:           models.NewSavedSearch{Name: "Go in Pune", Frequency: "hourly"},
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateSavedSearch(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:           "Fail_MissingName",
+			body:           models.NewSavedSearch{Frequency: models.FrequencyDaily},
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateSavedSearch(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService)}
+			router.POST("/me/saved-searches", h.CreateSavedSearch)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			body, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/me/saved-searches", bytes.NewReader(body))
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandler_UnsubscribeSavedSearch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tt := []struct {
+		name             string
+		method           string
+		query            string
+		expectedStatus   int
+		expectedResponse string
+		mockService      func(m *mockmodels.MockService)
+	}{
+		{
+			name:           "Confirm",
+			method:         http.MethodGet,
+			query:          "?token=abc",
+			expectedStatus: http.StatusOK,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().UnsubscribeSavedSearch(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:             "OK",
+			method:           http.MethodPost,
+			query:            "?token=abc",
+			expectedStatus:   http.StatusOK,
+			expectedResponse: `{"msg":"you will no longer receive alerts for Go in Pune"}`,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().UnsubscribeSavedSearch(gomock.Any(), gomock.Eq("abc")).
+					Times(1).Return(models.SavedSearch{Name: "Go in Pune"}, nil)
+			},
+		},
+		{
+			name:           "Fail_UnknownToken",
+			method:         http.MethodPost,
+			query:          "?token=nope",
+			expectedStatus: http.StatusNotFound,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().UnsubscribeSavedSearch(gomock.Any(), gomock.Eq("nope")).
+					Times(1).Return(models.SavedSearch{}, gorm.ErrRecordNotFound)
+			},
+		},
+		{
+			name:           "Fail_MissingToken",
+			method:         http.MethodPost,
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().UnsubscribeSavedSearch(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService)}
+			router.GET("/saved-searches/unsubscribe", h.ConfirmUnsubscribeSavedSearch)
+			router.POST("/saved-searches/unsubscribe", h.UnsubscribeSavedSearch)
+
+			ctx := context.WithValue(context.Background(), middleware.TraceIdKey, "fake-trace-id")
+			req, err := http.NewRequestWithContext(ctx, tc.method, "/saved-searches/unsubscribe"+tc.query, nil)
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+			if tc.expectedResponse != "" {
+				require.Equal(t, tc.expectedResponse, rec.Body.String())
+			}
+			if tc.method == http.MethodGet {
+				require.Contains(t, rec.Body.String(), `<form method="post" action="/saved-searches/unsubscribe?token=abc">`)
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// SetScorecardTemplate creates or replaces what the interviewers of a job rate candidates on
+func (h *handler) SetScorecardTemplate(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	var nt models.NewScorecardTemplate
+	err := json.NewDecoder(c.Request.Body).Decode(&nt)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	validate := validator.New()
+	err = validate.Struct(nt)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": "a template needs 1 to 20 distinct competencies and a scale of 2 to 10", "error": err.Error()})
+		return
+	}
+
+	job, ok := h.requireJobRole(c, traceId, claims, models.RoleRecruiter)
+	if !ok {
+		return
+	}
+	t, err := h.s.SetScorecardTemplate(ctx, job.ID, nt)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "saving scorecard template failed"})
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+// ViewScorecardTemplate returns what the interviewers of a job rate candidates on
+func (h *handler) ViewScorecardTemplate(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	job, ok := h.requireJobRole(c, traceId, claims, models.RoleViewer)
+	if !ok {
+		return
+	}
+	t, err := h.s.ViewScorecardTemplate(ctx, job.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "job has no scorecard template"})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching scorecard template"})
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+// SubmitScorecard records the logged-in interviewer's feedback on an application at a stage of its pipeline
+func (h *handler) SubmitScorecard(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	var ns models.NewScorecard
+	err := json.NewDecoder(c.Request.Body).Decode(&ns)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	validate := validator.New()
+	err = validate.Struct(ns)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": "invalid scorecard", "error": err.Error()})
+		return
+	}
+
+	app, ok := h.requireApplicationRole(c, traceId, claims, models.RoleRecruiter, false)
+	if !ok {
+		return
+	}
+	uid, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	sc, err := h.s.CreateScorecard(ctx, app.ID, uint(uid), ns)
+	switch {
+	case errors.Is(err, models.ErrInvalidScorecard), errors.Is(err, models.ErrUnknownStage):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, models.ErrNoScorecardTemplate):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"msg": "scorecard already submitted for this stage"})
+		return
+	case err != nil:
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "submitting scorecard failed"})
+		return
+	}
+	c.JSON(http.StatusCreated, sc)
+}
+
+// ViewApplicationScorecards lists the scorecards of an application with their summary.
+// Interviewers only see the feedback of others at the stages they have submitted their own at.
+func (h *handler) ViewApplicationScorecards(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	app, m, ok := h.requireApplicationMember(c, traceId, claims, models.RoleViewer, false)
+	if !ok {
+		return
+	}
+	uid, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	cards, err := h.s.ViewApplicationScorecards(ctx, app.ID)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching scorecards"})
+		return
+	}
+	visible := cards
+	if !models.RoleAtLeast(m.Role, models.DecisionRole) {
+		visible = models.VisibleScorecards(cards, uint(uid))
+	}
+	summary := models.SummarizeScorecards(app.ID, visible)
+	summary.CandidateID = app.UserID
+	c.JSON(http.StatusOK, gin.H{"scorecards": visible, "hidden": len(cards) - len(visible), "summary": summary})
+}
+
+// ViewJobScorecardSummary ranks the candidates of a job by their aggregated scorecards, for the hiring decision
+func (h *handler) ViewJobScorecardSummary(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	job, ok := h.requireJobRole(c, traceId, claims, models.DecisionRole)
+	if !ok {
+		return
+	}
+	cards, err := h.s.ViewJobScorecards(ctx, job.ID)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching scorecards"})
+		return
+	}
+	apps, err := h.s.ViewApplicationsByJob(ctx, job.ID, models.ApplicationFilter{})
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching applications"})
+		return
+	}
+	candidates := make(map[uint]uint, len(apps))
+	for _, app := range apps {
+		candidates[app.ID] = app.UserID
+	}
+	sums := models.SummarizeJobScorecards(cards)
+	for i := range sums {
+		sums[i].CandidateID = candidates[sums[i].ApplicationID]
+	}
+	c.JSON(http.StatusOK, gin.H{"job_id": job.ID, "candidates": sums})
+}
@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/models/mockmodels"
+	"job-portal-api/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler_SubmitScorecard(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{
+		Subject: "1",
+	}
+	valid := `{"stage_id":12,"ratings":[{"competency":"Go","score":4},{"competency":"Communication","score":3}],"recommendation":"yes","notes":"Solid"}`
+
+	tt := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		mockService    func(m *mockmodels.MockService)
+	}{
+		{
+			name:           "OK",
+			body:           valid,
+			expectedStatus: http.StatusCreated,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateScorecard(gomock.Any(), gomock.Eq(uint(3)), gomock.Eq(uint(1)), gomock.Any()).Times(1).
+					Return(models.Scorecard{ApplicationID: 3, StageID: 12, InterviewerID: 1, Scale: 5, Recommendation: models.RecommendYes}, nil)
+			},
+		},
+		{
+			name:           "Fail_Recommendation",
+			body:           `{"stage_id":12,"ratings":[{"competency":"Go","score":4}],"recommendation":"maybe"}`,
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateScorecard(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:           "Fail_RatedTwice",
+			body:           `{"stage_id":12,"ratings":[{"competency":"Go","score":4},{"competency":"Go","score":2}],"recommendation":"no"}`,
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateScorecard(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:           "Fail_OffTemplate",
+			body:           valid,
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateScorecard(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					Return(models.Scorecard{}, fmt.Errorf("%w: System design is not rated", models.ErrInvalidScorecard))
+			},
+		},
+		{
+			name:           "Fail_NoTemplate",
+			body:           valid,
+			expectedStatus: http.StatusConflict,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateScorecard(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					Return(models.Scorecard{}, models.ErrNoScorecardTemplate)
+			},
+		},
+		{
+			name:           "Fail_AlreadySubmitted",
+			body:           valid,
+			expectedStatus: http.StatusConflict,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateScorecard(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					Return(models.Scorecard{}, gorm.ErrDuplicatedKey)
+			},
+		},
+		{
+			name:           "Fail_Viewer",
+			body:           valid,
+			expectedStatus: http.StatusForbidden,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).Times(1).
+					Return(models.Membership{Role: models.RoleViewer}, nil)
+				m.EXPECT().ViewUser(gomock.Any(), gomock.Eq(uint(1))).Times(1).Return(models.User{}, nil)
+				m.EXPECT().CreateScorecard(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+			mockService.EXPECT().ViewApplication(gomock.Any(), gomock.Eq(uint(3))).AnyTimes().
+				Return(models.Application{Model: gorm.Model{ID: 3}, JobID: 4, UserID: 9}, nil)
+			mockService.EXPECT().ViewJobByJobId(gomock.Any(), gomock.Eq(uint(4)), gomock.Any()).AnyTimes().
+				Return([]models.Job{{Model: gorm.Model{ID: 4}, CompanyID: 2}}, nil)
+			mockService.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).AnyTimes().
+				Return(models.Membership{Role: models.RoleRecruiter}, nil)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService)}
+			router.POST("/applications/:applicationID/scorecards", h.SubmitScorecard)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/applications/3/scorecards", strings.NewReader(tc.body))
+			require.NoError(t, err)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandler_ViewApplicationScorecards(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	// Interviewer 5 gave feedback at both stages, interviewer 1 only at the first
+	cards := []models.Scorecard{
+		{ApplicationID: 3, StageID: 11, InterviewerID: 5, Scale: 5, Recommendation: models.RecommendYes,
+			Ratings: []models.Rating{{Competency: "Go", Score: 5}, {Competency: "Communication", Score: 3}}},
+		{ApplicationID: 3, StageID: 11, InterviewerID: 1, Scale: 5, Recommendation: models.RecommendStrongYes,
+			Ratings: []models.Rating{{Competency: "Go", Score: 4}, {Competency: "Communication", Score: 5}}},
+		{ApplicationID: 3, StageID: 12, InterviewerID: 5, Scale: 3, Recommendation: models.RecommendNo,
+			Ratings: []models.Rating{{Competency: "Go", Score: 1}}},
+	}
+
+	tt := []struct {
+		name            string
+		role            string
+		expectedVisible int
+		expectedScore   float64
+	}{
+		// The stage 12 scorecard stays hidden until interviewer 1 submits their own there
+		{name: "Interviewer", role: models.RoleRecruiter, expectedVisible: 2, expectedScore: 81.3},
+		{name: "HiringManager", role: models.RoleAdmin, expectedVisible: 3, expectedScore: 65},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			mockService.EXPECT().ViewApplication(gomock.Any(), gomock.Eq(uint(3))).AnyTimes().
+				Return(models.Application{Model: gorm.Model{ID: 3}, JobID: 4, UserID: 9}, nil)
+			mockService.EXPECT().ViewJobByJobId(gomock.Any(), gomock.Eq(uint(4)), gomock.Any()).AnyTimes().
+				Return([]models.Job{{Model: gorm.Model{ID: 4}, CompanyID: 2}}, nil)
+			mockService.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).AnyTimes().
+				Return(models.Membership{Role: tc.role}, nil)
+			mockService.EXPECT().ViewApplicationScorecards(gomock.Any(), gomock.Eq(uint(3))).Times(1).Return(cards, nil)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService)}
+			router.GET("/applications/:applicationID/scorecards", h.ViewApplicationScorecards)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{Subject: "1"})
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/applications/3/scorecards", nil)
+			require.NoError(t, err)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, http.StatusOK, rec.Code)
+			var body struct {
+				Scorecards []models.Scorecard      `json:"scorecards"`
+				Hidden     int                     `json:"hidden"`
+				Summary    models.ScorecardSummary `json:"summary"`
+			}
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			require.Len(t, body.Scorecards, tc.expectedVisible)
+			require.Equal(t, len(cards)-tc.expectedVisible, body.Hidden)
+			require.Equal(t, tc.expectedScore, body.Summary.Score)
+			require.Equal(t, uint(9), body.Summary.CandidateID)
+		})
+	}
+}
@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/models/mockmodels"
+	"job-portal-api/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler_SetScreeningQuestions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{
+		Subject: "1",
+	}
+	valid := `{"questions":[{"prompt":"Are you authorized to work in the EU?","type":"yes_no","knockout":true,"accepted":["yes"]},{"prompt":"Years of Go?","type":"number","knockout":true,"min":2}]}`
+
+	tt := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		mockService    func(m *mockmodels.MockService)
+	}{
+		{
+			name:           "OK",
+			body:           valid,
+			expectedStatus: http.StatusOK,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().SetScreeningQuestions(gomock.Any(), gomock.Eq(uint(4)), gomock.Any()).Times(1).
+					Return([]models.ScreeningQuestion{{JobID: 4, Prompt: "Are you authorized to work in the EU?", Type: models.QuestionYesNo}}, nil)
+			},
+		},
+		{
+			name:           "Fail_Type",
+			body:           `{"questions":[{"prompt":"Favourite colour?","type":"color"}]}`,
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().SetScreeningQuestions(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:           "Fail_InvalidQuestion",
+			body:           `{"questions":[{"prompt":"Cover letter","type":"text","knockout":true}]}`,
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().SetScreeningQuestions(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					Return(nil, fmt.Errorf("question 1: %w: text questions cannot be knockout questions", models.ErrInvalidQuestion))
+			},
+		},
+		{
+			name:           "Fail_Viewer",
+			body:           valid,
+			expectedStatus: http.StatusForbidden,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).Times(1).
+					Return(models.Membership{Role: models.RoleViewer}, nil)
+				m.EXPECT().ViewUser(gomock.Any(), gomock.Eq(uint(1))).Times(1).Return(models.User{}, nil)
+				m.EXPECT().SetScreeningQuestions(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+			mockService.EXPECT().ViewJobByJobId(gomock.Any(), gomock.Eq(uint(4)), gomock.Any()).AnyTimes().
+				Return([]models.Job{{Model: gorm.Model{ID: 4}, CompanyID: 2}}, nil)
+			mockService.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).AnyTimes().
+				Return(models.Membership{Role: models.RoleRecruiter}, nil)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService)}
+			router.PUT("/jobs/:jobID/screening-questions", h.SetScreeningQuestions)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodPut, "/jobs/4/screening-questions", strings.NewReader(tc.body))
+			require.NoError(t, err)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandler_JobQuestions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	min := 2.0
+	qs := []models.ScreeningQuestion{
+		{JobID: 4, Prompt: "Are you authorized to work in the EU?", Type: models.QuestionYesNo, Required: true,
+			Knockout: true, Accepted: []string{"yes"}, KnockoutMessage: "EU only"},
+		{JobID: 4, Prompt: "Years of Go?", Type: models.QuestionNumber, Required: true, Knockout: true, Min: &min},
+	}
+
+	tt := []struct {
+		name           string
+		status         string
+		expectedStatus int
+	}{
+		{name: "OK", status: models.JobPublished, expectedStatus: http.StatusOK},
+		{name: "Fail_Draft", status: models.JobDraft, expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			mockService.EXPECT().ViewJobByJobId(gomock.Any(), gomock.Eq(uint(4)), gomock.Any()).AnyTimes().
+				Return([]models.Job{{Model: gorm.Model{ID: 4}, CompanyID: 2, Status: tc.status}}, nil)
+			mockService.EXPECT().ViewScreeningQuestions(gomock.Any(), gomock.Eq(uint(4))).AnyTimes().Return(qs, nil)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService)}
+			router.GET("/jobs/:jobID/questions", h.JobQuestions)
+
+			ctx := context.WithValue(context.Background(), middleware.TraceIdKey, "fake-trace-id")
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/jobs/4/questions", nil)
+			require.NoError(t, err)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+			require.NotContains(t, rec.Body.String(), "EU only")
+			var body struct {
+				Questions []map[string]any `json:"questions"`
+			}
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			require.Len(t, body.Questions, 2)
+			for _, q := range body.Questions {
+				require.NotContains(t, q, "knockout")
+				require.NotContains(t, q, "accepted")
+				require.NotContains(t, q, "min")
+				require.Equal(t, true, q["required"])
+			}
+		})
+	}
+}
+
+func TestHandler_ViewJobApplicationsFiltered(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{
+		Subject: "1",
+	}
+
+	tt := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		mockService    func(m *mockmodels.MockService)
+	}{
+		{
+			name:           "OK",
+			query:          "?status=submitted&answer[7]=yes&answer_min[8]=2.5",
+			expectedStatus: http.StatusOK,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewApplicationsByJob(gomock.Any(), gomock.Eq(uint(4)), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, _ uint, f models.ApplicationFilter) ([]models.Application, error) {
+						require.Equal(t, models.ApplicationStatusSubmitted, f.Status)
+						require.Equal(t, "yes", f.Answers[7])
+						require.Equal(t, 2.5, f.AnswersMin[8])
+						require.Empty(t, f.AnswersMax)
+						return []models.Application{{JobID: 4, UserID: 9}}, nil
+					})
+			},
+		},
+		{
+			name:           "Fail_QuestionID",
+			query:          "?answer[first]=yes",
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewApplicationsByJob(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:           "Fail_Number",
+			query:          "?answer_max[8]=lots",
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewApplicationsByJob(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+			mockService.EXPECT().ViewJobByJobId(gomock.Any(), gomock.Eq(uint(4)), gomock.Any()).AnyTimes().
+				Return([]models.Job{{Model: gorm.Model{ID: 4}, CompanyID: 2}}, nil)
+			mockService.EXPECT().ViewMembership(gomock.Any(), gomock.Eq(uint(2)), gomock.Eq(uint(1))).AnyTimes().
+				Return(models.Membership{Role: models.RoleViewer}, nil)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService)}
+			router.GET("/jobs/:jobID/applications", h.ViewJobApplications)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/jobs/4/applications"+tc.query, nil)
+			require.NoError(t, err)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandler_ApplyScreening(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{
+		Subject: "9",
+	}
+
+	tt := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		mockService    func(m *mockmodels.MockService)
+	}{
+		{
+			name:           "OK_KnockedOut",
+			body:           `{"answers":[{"question_id":7,"value":"no"}]}`,
+			expectedStatus: http.StatusCreated,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateApplication(gomock.Any(), gomock.Any(), gomock.Eq(uint(4)), gomock.Eq(uint(9))).Times(1).
+					Return(models.Application{JobID: 4, UserID: 9, Status: models.ApplicationStatusRejected, StatusMessage: "EU only"}, nil)
+			},
+		},
+		{
+			name:           "Fail_AnsweredTwice",
+			body:           `{"answers":[{"question_id":7,"value":"no"},{"question_id":7,"value":"yes"}]}`,
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateApplication(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:           "Fail_InvalidAnswers",
+			body:           `{"answers":[{"question_id":7,"value":"maybe"}]}`,
+			expectedStatus: http.StatusBadRequest,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().CreateApplication(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					Return(models.Application{}, fmt.Errorf("%w: %q needs yes or no", models.ErrInvalidAnswers, "Are you authorized to work in the EU?"))
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService)}
+			router.POST("/jobs/:jobID/apply", h.Apply)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/jobs/4/apply", strings.NewReader(tc.body))
+			require.NoError(t, err)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+		})
+	}
+}
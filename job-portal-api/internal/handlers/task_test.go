@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/models/mockmodels"
+	"job-portal-api/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler_ViewDeadTasks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{
+		Subject: "1",
+	}
+
+	tt := []struct {
+		name             string
+		expectedStatus   int
+		expectedResponse string
+		mockService      func(m *mockmodels.MockService)
+	}{
+		{
+			name:             "OK",
+			expectedStatus:   http.StatusOK,
+			expectedResponse: `[{"id":4,"kind":"resume.parse","payload":null,"status":"dead","run_at":"0001-01-01T00:00:00Z","attempts":5,"max_attempts":5,"last_error":"timeout","created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}]`,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewUser(gomock.Any(), gomock.Eq(uint(1))).Times(1).Return(models.User{Admin: true}, nil)
+				m.EXPECT().ViewDeadTasks(gomock.Any(), gomock.Eq(20)).Times(1).Return([]models.Task{{
+					ID: 4, Kind: "resume.parse", Status: models.TaskDead, Attempts: 5, MaxAttempts: 5, LastError: "timeout",
+				}}, nil)
+			},
+		},
+		{
+			name:             "Fail_NotAdmin",
+			expectedStatus:   http.StatusForbidden,
+			expectedResponse: `{"error":"Forbidden"}`,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewUser(gomock.Any(), gomock.Eq(uint(1))).Times(1).Return(models.User{}, nil)
+				m.EXPECT().ViewDeadTasks(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService)}
+			router.GET("/admin/tasks/dead", h.ViewDeadTasks)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/admin/tasks/dead", nil)
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+			require.JSONEq(t, tc.expectedResponse, rec.Body.String())
+		})
+	}
+}
+
+func TestHandler_RequeueDeadTask(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fakeClaims := jwt.RegisteredClaims{
+		Subject: "1",
+	}
+
+	tt := []struct {
+		name           string
+		taskID         string
+		expectedStatus int
+		mockService    func(m *mockmodels.MockService)
+	}{
+		{
+			name:           "OK",
+			taskID:         "4",
+			expectedStatus: http.StatusNoContent,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewUser(gomock.Any(), gomock.Eq(uint(1))).Times(1).Return(models.User{Admin: true}, nil)
+				m.EXPECT().RequeueDeadTask(gomock.Any(), gomock.Eq(uint(4))).Times(1).Return(nil)
+			},
+		},
+		{
+			name:           "Fail_NotDead",
+			taskID:         "5",
+			expectedStatus: http.StatusNotFound,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewUser(gomock.Any(), gomock.Eq(uint(1))).Times(1).Return(models.User{Admin: true}, nil)
+				m.EXPECT().RequeueDeadTask(gomock.Any(), gomock.Eq(uint(5))).Times(1).Return(gorm.ErrRecordNotFound)
+			},
+		},
+		{
+			name:           "Fail_NotAdmin",
+			taskID:         "4",
+			expectedStatus: http.StatusForbidden,
+			mockService: func(m *mockmodels.MockService) {
+				m.EXPECT().ViewUser(gomock.Any(), gomock.Eq(uint(1))).Times(1).Return(models.User{}, nil)
+				m.EXPECT().RequeueDeadTask(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mockmodels.NewMockService(ctrl)
+			tc.mockService(mockService)
+
+			router := gin.New()
+			h := handler{s: services.NewStore(mockService)}
+			router.POST("/admin/tasks/:taskID/requeue", h.RequeueDeadTask)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, auth.Key, fakeClaims)
+			ctx = context.WithValue(ctx, middleware.TraceIdKey, "fake-trace-id")
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/admin/tasks/"+tc.taskID+"/requeue", nil)
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+		})
+	}
+}
@@ -2,12 +2,23 @@ package handlers
 
 import (
 	"encoding/json"
+	"job-portal-api/internal/analytics"
 	"job-portal-api/internal/auth"
+	"job-portal-api/internal/feed"
+	"job-portal-api/internal/interviews"
+	"job-portal-api/internal/invites"
+	"job-portal-api/internal/matching"
+	"job-portal-api/internal/messaging"
 	"job-portal-api/internal/middleware"
 	"job-portal-api/internal/models"
+	"job-portal-api/internal/offers"
 	"net/http"
 
+	"job-portal-api/internal/resume"
+	"job-portal-api/internal/salary"
 	"job-portal-api/internal/services"
+	"job-portal-api/internal/storage"
+	"job-portal-api/internal/webhooks"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -15,8 +26,20 @@ import (
 )
 
 type handler struct {
-	s services.Store
-	a *auth.Auth
+	s  services.Store
+	a  *auth.Auth
+	bs storage.BlobStore
+	sc storage.Scanner
+	rp *resume.Pipeline
+	me *matching.Engine
+	wh *webhooks.Dispatcher
+	fr *feed.Renderer
+	iv *invites.Inviter
+	ic *interviews.Coordinator
+	mh *messaging.Hub
+	of *offers.Issuer
+	sr *salary.Reporter
+	vs *analytics.Visitors
 }
 
 // Signup is a method for the handler struct which handles user registration
@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// CreateWebhook subscribes a URL of the company to domain events.
+// The signing secret is only part of this response.
+func (h *handler) CreateWebhook(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	companyID, err := strconv.ParseUint(c.Param("companyID"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid company ID"})
+		return
+	}
+	if _, ok := h.requireCompanyRole(c, traceId, claims, uint(companyID), models.RoleAdmin); !ok {
+		return
+	}
+
+	var nw models.NewWebhook
+	err = json.NewDecoder(c.Request.Body).Decode(&nw)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	validate := validator.New()
+	err = validate.Struct(nw)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": "invalid webhook details", "error": err.Error()})
+		return
+	}
+	for _, t := range nw.EventTypes {
+		if !slices.Contains(models.WebhookEvents, t) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": "unknown event type " + t, "event_types": models.WebhookEvents})
+			return
+		}
+	}
+
+	w, secret, err := h.s.CreateWebhook(ctx, nw, uint(companyID))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "company not found"})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "creating webhook failed"})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"webhook": w, "secret": secret})
+}
+
+// ViewWebhooks lists the webhooks of a company
+func (h *handler) ViewWebhooks(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	companyID, err := strconv.ParseUint(c.Param("companyID"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid company ID"})
+		return
+	}
+	if _, ok := h.requireCompanyRole(c, traceId, claims, uint(companyID), models.RoleAdmin); !ok {
+		return
+	}
+
+	hooks, err := h.s.ViewWebhooks(ctx, uint(companyID))
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching webhooks"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"webhooks": hooks})
+}
+
+// DeleteWebhook unsubscribes one of the company's webhooks
+func (h *handler) DeleteWebhook(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	w, ok := h.companyWebhook(c, traceId, claims)
+	if !ok {
+		return
+	}
+	err := h.s.DeleteWebhook(ctx, w.ID)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "deleting webhook failed"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// EnableWebhook turns a webhook disabled after failed deliveries back on
+func (h *handler) EnableWebhook(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	w, ok := h.companyWebhook(c, traceId, claims)
+	if !ok {
+		return
+	}
+	w, err := h.s.EnableWebhook(ctx, w.ID)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "enabling webhook failed"})
+		return
+	}
+	c.JSON(http.StatusOK, w)
+}
+
+// ViewWebhookDeliveries shows the latest delivery attempts of a webhook, newest first
+func (h *handler) ViewWebhookDeliveries(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	w, ok := h.companyWebhook(c, traceId, claims)
+	if !ok {
+		return
+	}
+	deliveries, err := h.s.ViewWebhookDeliveries(ctx, w.ID, limitParam(c))
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching deliveries"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"deliveries": deliveries})
+}
+
+// SendTestWebhook delivers a test event to a webhook right away and reports how it went
+func (h *handler) SendTestWebhook(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middleware.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	if h.wh == nil {
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"msg": "webhooks are not available"})
+		return
+	}
+	w, ok := h.companyWebhook(c, traceId, claims)
+	if !ok {
+		return
+	}
+	delivery, err := h.wh.SendTest(ctx, w)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "sending test event failed"})
+		return
+	}
+	c.JSON(http.StatusOK, delivery)
+}
+
+// companyWebhook loads the webhook named in the URL and makes sure it belongs to the company in the URL,
+// which the logged-in user administers
+func (h *handler) companyWebhook(c *gin.Context, traceId string, claims jwt.RegisteredClaims) (models.Webhook, bool) {
+	companyID, err := strconv.ParseUint(c.Param("companyID"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid company ID"})
+		return models.Webhook{}, false
+	}
+	if _, ok := h.requireCompanyRole(c, traceId, claims, uint(companyID), models.RoleAdmin); !ok {
+		return models.Webhook{}, false
+	}
+	webhookID, err := strconv.ParseUint(c.Param("webhookID"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid webhook ID"})
+		return models.Webhook{}, false
+	}
+
+	w, err := h.s.ViewWebhook(c.Request.Context(), uint(webhookID))
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && w.CompanyID != uint(companyID)) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "webhook not found"})
+		return models.Webhook{}, false
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "problem in fetching webhook"})
+		return models.Webhook{}, false
+	}
+	return w, true
+}
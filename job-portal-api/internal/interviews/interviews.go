@@ -0,0 +1,355 @@
+// Package interviews keeps candidates and recruiters informed about the interviews planned between them:
+// it mails proposals, calendar invitations and cancellations, and reminds both sides before an interview.
+package interviews
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+
+	"job-portal-api/internal/calendar"
+	"job-portal-api/internal/mail"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/queue"
+)
+
+// Store is the part of the data layer the coordinator needs.
+type Store interface {
+	CreateInterview(ctx context.Context, applicationId, recruiterId uint, ni models.NewInterview) (models.Interview, error)
+	ViewInterview(ctx context.Context, interviewId uint) (models.Interview, error)
+	SelectInterviewSlot(ctx context.Context, interviewId, slotId, candidateId uint) (models.Interview, error)
+	CancelInterview(ctx context.Context, interviewId uint, reason string) (models.Interview, error)
+	ViewUser(ctx context.Context, userId uint) (models.User, error)
+	ViewJobByJobId(ctx context.Context, jobById uint, userId string) ([]models.Job, error)
+	ViewCompany(ctx context.Context, companyID uint, userId string) (models.Company, error)
+}
+
+// KindReminder is the queue task reminding both sides of an upcoming interview.
+const KindReminder = "interviews.remind"
+
+// reminderLeads are how long before an interview the reminders go out.
+var reminderLeads = []time.Duration{24 * time.Hour, time.Hour}
+
+// Coordinator plans interviews and mails everyone involved about them.
+// Emails are best effort: a change is kept even when its notification cannot be handed to the mailer.
+type Coordinator struct {
+	store   Store
+	mailer  mail.Mailer
+	q       *queue.Queue
+	baseURL string
+	// host makes the calendar UIDs of interviews unique to this portal
+	host string
+	now  func() time.Time
+}
+
+// NewCoordinator returns a coordinator linking to the API at baseURL.
+// Reminders are enqueued on q, which also runs them.
+func NewCoordinator(store Store, mailer mail.Mailer, q *queue.Queue, baseURL string) (*Coordinator, error) {
+	if store == nil || mailer == nil || q == nil {
+		return nil, errors.New("store, mailer and queue cannot be nil")
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base url %w", err)
+	}
+	co := &Coordinator{
+		store:   store,
+		mailer:  mailer,
+		q:       q,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		host:    u.Hostname(),
+		now:     time.Now,
+	}
+	q.Handle(KindReminder, func(ctx context.Context, payload json.RawMessage) error {
+		var r reminder
+		err := json.Unmarshal(payload, &r)
+		if err != nil {
+			return queue.Permanent(err)
+		}
+		return co.remind(ctx, r)
+	})
+	return co, nil
+}
+
+// reminder is the payload of a reminder task. Sequence tells whether the interview changed since it was enqueued.
+type reminder struct {
+	InterviewID uint `json:"interview_id"`
+	Sequence    int  `json:"sequence"`
+}
+
+// participants of an interview and what it is about, as needed for emails and calendars.
+type participants struct {
+	candidate models.User
+	recruiter models.User
+	title     string
+	company   string
+}
+
+// Propose offers the candidate of the application slots for an interview with the recruiter.
+func (co *Coordinator) Propose(ctx context.Context, applicationId, recruiterId uint, ni models.NewInterview) (models.Interview, error) {
+	in, err := co.store.CreateInterview(ctx, applicationId, recruiterId, ni)
+	if err != nil {
+		return models.Interview{}, err
+	}
+	p, err := co.participants(ctx, in)
+	if err != nil {
+		log.Error().Err(err).Uint("interview", in.ID).Msg("loading interview participants")
+		return in, nil
+	}
+	co.send(ctx, in, co.composeProposal(in, p))
+	return in, nil
+}
+
+// Select schedules the interview in the slot the candidate picked and sends both sides a calendar invitation.
+func (co *Coordinator) Select(ctx context.Context, interviewId, slotId, candidateId uint) (models.Interview, error) {
+	in, err := co.store.SelectInterviewSlot(ctx, interviewId, slotId, candidateId)
+	if err != nil {
+		return models.Interview{}, err
+	}
+	co.scheduleReminders(ctx, in)
+	p, err := co.participants(ctx, in)
+	if err != nil {
+		log.Error().Err(err).Uint("interview", in.ID).Msg("loading interview participants")
+		return in, nil
+	}
+	invite := co.attachment(in, p, calendar.MethodRequest)
+	for _, to := range []models.User{p.candidate, p.recruiter} {
+		m := co.composeScheduled(in, p, to)
+		m.Attachments = []mail.Attachment{invite}
+		co.send(ctx, in, m)
+	}
+	return in, nil
+}
+
+// Cancel calls off the interview. Both sides hear about it once it was scheduled,
+// only the candidate while it was still a proposal.
+func (co *Coordinator) Cancel(ctx context.Context, interviewId uint, reason string) (models.Interview, error) {
+	in, err := co.store.CancelInterview(ctx, interviewId, reason)
+	if err != nil {
+		return models.Interview{}, err
+	}
+	p, err := co.participants(ctx, in)
+	if err != nil {
+		log.Error().Err(err).Uint("interview", in.ID).Msg("loading interview participants")
+		return in, nil
+	}
+	if in.StartsAt == nil {
+		co.send(ctx, in, co.composeCancelled(in, p, p.candidate))
+		return in, nil
+	}
+	cancel := co.attachment(in, p, calendar.MethodCancel)
+	for _, to := range []models.User{p.candidate, p.recruiter} {
+		m := co.composeCancelled(in, p, to)
+		m.Attachments = []mail.Attachment{cancel}
+		co.send(ctx, in, m)
+	}
+	return in, nil
+}
+
+// Calendar renders the scheduled and cancelled interviews among ins as an iCalendar feed.
+func (co *Coordinator) Calendar(ctx context.Context, name string, ins []models.Interview) ([]byte, error) {
+	c := calendar.Calendar{Name: name}
+	for _, in := range ins {
+		if in.StartsAt == nil {
+			continue
+		}
+		p, err := co.participants(ctx, in)
+		if err != nil {
+			return nil, err
+		}
+		c.Events = append(c.Events, co.event(in, p))
+	}
+	return c.Bytes(), nil
+}
+
+// FeedURL is the address calendar apps subscribe to for the feed with the given token.
+func (co *Coordinator) FeedURL(token string) string {
+	return co.baseURL + "/calendar/" + url.PathEscape(token) + ".ics"
+}
+
+// scheduleReminders enqueues the reminders of a scheduled interview, skipping those already due.
+// Rescheduling bumps the sequence, so reminders for an older time are dropped when they run.
+func (co *Coordinator) scheduleReminders(ctx context.Context, in models.Interview) {
+	now := co.now()
+	for _, lead := range reminderLeads {
+		at := in.StartsAt.Add(-lead)
+		if !at.After(now) {
+			continue
+		}
+		err := co.q.Enqueue(ctx, KindReminder, reminder{InterviewID: in.ID, Sequence: in.Sequence},
+			queue.At(at), queue.Unique(fmt.Sprintf("interview-reminder:%d:%d:%s", in.ID, in.Sequence, lead)))
+		if err != nil {
+			log.Error().Err(err).Uint("interview", in.ID).Msg("scheduling interview reminder")
+		}
+	}
+}
+
+// remind mails both sides that the interview is coming up, unless it changed since the reminder was planned.
+func (co *Coordinator) remind(ctx context.Context, r reminder) error {
+	in, err := co.store.ViewInterview(ctx, r.InterviewID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if in.Status != models.InterviewScheduled || in.Sequence != r.Sequence || !in.StartsAt.After(co.now()) {
+		return nil
+	}
+	p, err := co.participants(ctx, in)
+	if err != nil {
+		return err
+	}
+	for _, to := range []models.User{p.candidate, p.recruiter} {
+		err = co.mailer.Send(ctx, co.composeReminder(in, p, to))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (co *Coordinator) participants(ctx context.Context, in models.Interview) (participants, error) {
+	var p participants
+	var err error
+	p.candidate, err = co.store.ViewUser(ctx, in.CandidateID)
+	if err != nil {
+		return participants{}, err
+	}
+	p.recruiter, err = co.store.ViewUser(ctx, in.RecruiterID)
+	if err != nil {
+		return participants{}, err
+	}
+	jobs, err := co.store.ViewJobByJobId(ctx, in.JobID, "")
+	if err != nil {
+		return participants{}, err
+	}
+	if len(jobs) > 0 {
+		p.title = jobs[0].Title
+	}
+	company, err := co.store.ViewCompany(ctx, in.CompanyID, "")
+	if err != nil {
+		return participants{}, err
+	}
+	p.company = company.CompanyName
+	return p, nil
+}
+
+func (co *Coordinator) send(ctx context.Context, in models.Interview, m mail.Message) {
+	err := co.mailer.Send(ctx, m)
+	if err != nil {
+		log.Error().Err(err).Uint("interview", in.ID).Str("to", m.To).Msg("sending interview email")
+	}
+}
+
+func (co *Coordinator) event(in models.Interview, p participants) calendar.Event {
+	details := fmt.Sprintf("Interview of %s for %s at %s.", p.candidate.Name, p.title, p.company)
+	if in.Notes != "" {
+		details += "\n\n" + in.Notes
+	}
+	return calendar.Event{
+		UID:       fmt.Sprintf("interview-%d@%s", in.ID, co.host),
+		Sequence:  in.Sequence,
+		Start:     *in.StartsAt,
+		End:       *in.EndsAt,
+		Summary:   fmt.Sprintf("Interview: %s, %s", p.title, p.company),
+		Location:  in.Location,
+		Details:   details,
+		Organizer: calendar.Person{Name: p.recruiter.Name, Email: p.recruiter.Email},
+		Attendees: []calendar.Person{{Name: p.candidate.Name, Email: p.candidate.Email}},
+		Cancelled: in.Status == models.InterviewCancelled,
+		Stamp:     in.UpdatedAt,
+	}
+}
+
+func (co *Coordinator) attachment(in models.Interview, p participants, method string) mail.Attachment {
+	c := calendar.Calendar{Method: method, Events: []calendar.Event{co.event(in, p)}}
+	return mail.Attachment{
+		Filename:    "interview.ics",
+		ContentType: calendar.ContentType + "; method=" + method,
+		Content:     c.Bytes(),
+	}
+}
+
+func (co *Coordinator) composeProposal(in models.Interview, p participants) mail.Message {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Hi %s,\n\n%s would like to interview you for %s at %s.\n", p.candidate.Name, p.recruiter.Name, p.title, p.company)
+	fmt.Fprintf(&b, "The interview takes %d minutes. Pick one of these times:\n\n", in.DurationMinutes)
+	for _, s := range in.Slots {
+		fmt.Fprintf(&b, "  [slot %d] %s\n", s.ID, localTime(s.StartsAt, in.Timezone))
+	}
+	fmt.Fprintf(&b, "\nChoose a slot by its number:\n  POST %s/interviews/%d/select {\"slot_id\": <number>}\n", co.baseURL, in.ID)
+	if in.Location != "" {
+		fmt.Fprintf(&b, "\nLocation: %s\n", in.Location)
+	}
+	if in.Notes != "" {
+		fmt.Fprintf(&b, "\n%s\n", in.Notes)
+	}
+	return mail.Message{
+		To:      p.candidate.Email,
+		Subject: fmt.Sprintf("Interview for %s at %s: pick a time", p.title, p.company),
+		Body:    b.String(),
+	}
+}
+
+func (co *Coordinator) composeScheduled(in models.Interview, p participants, to models.User) mail.Message {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Hi %s,\n\nThe interview of %s for %s at %s is scheduled for %s (%d minutes).\n",
+		to.Name, p.candidate.Name, p.title, p.company, localTime(*in.StartsAt, in.Timezone), in.DurationMinutes)
+	if in.Location != "" {
+		fmt.Fprintf(&b, "\nLocation: %s\n", in.Location)
+	}
+	b.WriteString("\nThe attached invitation adds it to your calendar.\n")
+	return mail.Message{
+		To:      to.Email,
+		Subject: fmt.Sprintf("Interview scheduled: %s at %s", p.title, p.company),
+		Body:    b.String(),
+	}
+}
+
+func (co *Coordinator) composeCancelled(in models.Interview, p participants, to models.User) mail.Message {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Hi %s,\n\nThe interview of %s for %s at %s", to.Name, p.candidate.Name, p.title, p.company)
+	if in.StartsAt != nil {
+		fmt.Fprintf(&b, " on %s", localTime(*in.StartsAt, in.Timezone))
+	}
+	b.WriteString(" was cancelled.\n")
+	if in.CancelReason != "" {
+		fmt.Fprintf(&b, "\nReason: %s\n", in.CancelReason)
+	}
+	return mail.Message{
+		To:      to.Email,
+		Subject: fmt.Sprintf("Interview cancelled: %s at %s", p.title, p.company),
+		Body:    b.String(),
+	}
+}
+
+func (co *Coordinator) composeReminder(in models.Interview, p participants, to models.User) mail.Message {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Hi %s,\n\nA reminder that the interview of %s for %s at %s starts on %s.\n",
+		to.Name, p.candidate.Name, p.title, p.company, localTime(*in.StartsAt, in.Timezone))
+	if in.Location != "" {
+		fmt.Fprintf(&b, "\nLocation: %s\n", in.Location)
+	}
+	return mail.Message{
+		To:      to.Email,
+		Subject: fmt.Sprintf("Reminder: interview for %s at %s", p.title, p.company),
+		Body:    b.String(),
+	}
+}
+
+// localTime shows t in the interview's timezone, falling back to UTC for unknown zones.
+func localTime(t time.Time, timezone string) string {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	return t.In(loc).Format("Monday 2 January 2006 at 15:04 MST") + " (" + loc.String() + ")"
+}
@@ -0,0 +1,230 @@
+package interviews
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"job-portal-api/internal/mail"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/queue"
+)
+
+// fakeStore keeps one interview, its participants and queued tasks in memory.
+type fakeStore struct {
+	mu        sync.Mutex
+	interview models.Interview
+	tasks     []models.Task
+}
+
+func (f *fakeStore) CreateInterview(ctx context.Context, applicationId, recruiterId uint, ni models.NewInterview) (models.Interview, error) {
+	return f.interview, nil
+}
+
+func (f *fakeStore) ViewInterview(ctx context.Context, interviewId uint) (models.Interview, error) {
+	if interviewId != f.interview.ID {
+		return models.Interview{}, gorm.ErrRecordNotFound
+	}
+	return f.interview, nil
+}
+
+func (f *fakeStore) SelectInterviewSlot(ctx context.Context, interviewId, slotId, candidateId uint) (models.Interview, error) {
+	for _, s := range f.interview.Slots {
+		if s.ID == slotId {
+			start, end := s.StartsAt, s.EndsAt
+			f.interview.StartsAt, f.interview.EndsAt = &start, &end
+		}
+	}
+	f.interview.Status = models.InterviewScheduled
+	f.interview.Sequence++
+	return f.interview, nil
+}
+
+func (f *fakeStore) CancelInterview(ctx context.Context, interviewId uint, reason string) (models.Interview, error) {
+	f.interview.Status = models.InterviewCancelled
+	f.interview.CancelReason = reason
+	f.interview.Sequence++
+	return f.interview, nil
+}
+
+func (f *fakeStore) ViewUser(ctx context.Context, userId uint) (models.User, error) {
+	users := map[uint]models.User{
+		1: {Model: gorm.Model{ID: 1}, Name: "Ravi", Email: "ravi@acme.example"},
+		9: {Model: gorm.Model{ID: 9}, Name: "Asha", Email: "asha@example.com"},
+	}
+	return users[userId], nil
+}
+
+func (f *fakeStore) ViewJobByJobId(ctx context.Context, jobId uint, userId string) ([]models.Job, error) {
+	return []models.Job{{Model: gorm.Model{ID: jobId}, Title: "Go developer", CompanyID: 2}}, nil
+}
+
+func (f *fakeStore) ViewCompany(ctx context.Context, companyID uint, userId string) (models.Company, error) {
+	return models.Company{Model: gorm.Model{ID: companyID}, CompanyName: "Acme"}, nil
+}
+
+func (f *fakeStore) EnqueueTask(ctx context.Context, t models.Task) (models.Task, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, old := range f.tasks {
+		if t.UniqueKey != nil && old.UniqueKey != nil && *old.UniqueKey == *t.UniqueKey {
+			return t, nil
+		}
+	}
+	f.tasks = append(f.tasks, t)
+	return t, nil
+}
+
+func (f *fakeStore) ClaimTasks(ctx context.Context, kinds []string, worker string, limit int, now time.Time) ([]models.Task, error) {
+	return nil, nil
+}
+func (f *fakeStore) FinishTask(ctx context.Context, taskId uint) error { return nil }
+func (f *fakeStore) RetryTask(ctx context.Context, taskId uint, runAt time.Time, lastErr string) error {
+	return nil
+}
+func (f *fakeStore) BuryTask(ctx context.Context, taskId uint, lastErr string) error { return nil }
+func (f *fakeStore) RequeueStaleTasks(ctx context.Context, lockedBefore time.Time) (int64, error) {
+	return 0, nil
+}
+func (f *fakeStore) PurgeFinishedTasks(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+type recordingMailer struct {
+	sent []mail.Message
+}
+
+func (r *recordingMailer) Send(ctx context.Context, m mail.Message) error {
+	r.sent = append(r.sent, m)
+	return nil
+}
+
+func newCoordinator(t *testing.T, f *fakeStore, m mail.Mailer, now time.Time) *Coordinator {
+	q, err := queue.New(f, queue.Options{})
+	require.NoError(t, err)
+	co, err := NewCoordinator(f, m, q, "https://jobs.example.com")
+	require.NoError(t, err)
+	co.now = func() time.Time { return now }
+	return co
+}
+
+func proposed(now time.Time) models.Interview {
+	start := now.Add(72 * time.Hour)
+	return models.Interview{
+		Model:           gorm.Model{ID: 7, UpdatedAt: now},
+		JobID:           4,
+		CompanyID:       2,
+		CandidateID:     9,
+		RecruiterID:     1,
+		Status:          models.InterviewProposed,
+		Timezone:        "Asia/Kolkata",
+		DurationMinutes: 45,
+		Slots: []models.InterviewSlot{
+			{ID: 1, StartsAt: start, EndsAt: start.Add(45 * time.Minute)},
+			{ID: 2, StartsAt: start.Add(2 * time.Hour), EndsAt: start.Add(2*time.Hour + 45*time.Minute)},
+		},
+	}
+}
+
+func TestCoordinator_Propose(t *testing.T) {
+	now := time.Date(2026, 11, 2, 4, 30, 0, 0, time.UTC)
+	f := &fakeStore{interview: proposed(now)}
+	m := &recordingMailer{}
+	co := newCoordinator(t, f, m, now)
+
+	_, err := co.Propose(context.Background(), 3, 1, models.NewInterview{})
+	require.NoError(t, err)
+
+	require.Len(t, m.sent, 1)
+	require.Equal(t, "asha@example.com", m.sent[0].To)
+	// Slots are shown in the interview's timezone
+	require.Contains(t, m.sent[0].Body, "[slot 1] Thursday 5 November 2026 at 10:00 IST (Asia/Kolkata)")
+	require.Contains(t, m.sent[0].Body, "POST https://jobs.example.com/interviews/7/select")
+	require.Empty(t, f.tasks)
+}
+
+func TestCoordinator_Select(t *testing.T) {
+	now := time.Date(2026, 11, 2, 4, 30, 0, 0, time.UTC)
+	f := &fakeStore{interview: proposed(now)}
+	m := &recordingMailer{}
+	co := newCoordinator(t, f, m, now)
+
+	in, err := co.Select(context.Background(), 7, 2, 9)
+	require.NoError(t, err)
+	require.Equal(t, models.InterviewScheduled, in.Status)
+
+	// Both sides get a calendar invitation
+	require.Len(t, m.sent, 2)
+	require.Equal(t, "asha@example.com", m.sent[0].To)
+	require.Equal(t, "ravi@acme.example", m.sent[1].To)
+	for _, msg := range m.sent {
+		require.Len(t, msg.Attachments, 1)
+		ics := string(msg.Attachments[0].Content)
+		require.Contains(t, ics, "METHOD:REQUEST")
+		require.Contains(t, ics, "UID:interview-7@jobs.example.com")
+		require.Contains(t, ics, "DTSTART:"+in.StartsAt.UTC().Format("20060102T150405Z"))
+	}
+
+	// Reminders a day and an hour before, dropped when enqueued again
+	co.scheduleReminders(context.Background(), in)
+	require.Len(t, f.tasks, 2)
+	require.Equal(t, KindReminder, f.tasks[0].Kind)
+	require.Equal(t, in.StartsAt.Add(-24*time.Hour), f.tasks[0].RunAt)
+	require.Equal(t, in.StartsAt.Add(-time.Hour), f.tasks[1].RunAt)
+	var r reminder
+	require.NoError(t, json.Unmarshal(f.tasks[0].Payload, &r))
+	require.Equal(t, reminder{InterviewID: 7, Sequence: 1}, r)
+}
+
+func TestCoordinator_Remind(t *testing.T) {
+	now := time.Date(2026, 11, 2, 4, 30, 0, 0, time.UTC)
+	f := &fakeStore{interview: proposed(now)}
+	m := &recordingMailer{}
+	co := newCoordinator(t, f, m, now)
+	_, err := co.Select(context.Background(), 7, 1, 9)
+	require.NoError(t, err)
+	m.sent = nil
+
+	// A reminder planned for an earlier version of the interview is dropped
+	require.NoError(t, co.remind(context.Background(), reminder{InterviewID: 7, Sequence: 0}))
+	require.Empty(t, m.sent)
+	// So is one for an interview that no longer exists
+	require.NoError(t, co.remind(context.Background(), reminder{InterviewID: 8, Sequence: 1}))
+	require.Empty(t, m.sent)
+
+	require.NoError(t, co.remind(context.Background(), reminder{InterviewID: 7, Sequence: 1}))
+	require.Len(t, m.sent, 2)
+	require.True(t, strings.HasPrefix(m.sent[0].Subject, "Reminder:"))
+
+	// Cancelled interviews send no reminders and tell calendars to drop the event
+	m.sent = nil
+	_, err = co.Cancel(context.Background(), 7, "position filled")
+	require.NoError(t, err)
+	require.Len(t, m.sent, 2)
+	require.Contains(t, m.sent[0].Body, "Reason: position filled")
+	require.Contains(t, string(m.sent[0].Attachments[0].Content), "METHOD:CANCEL")
+	require.Contains(t, string(m.sent[0].Attachments[0].Content), "STATUS:CANCELLED")
+	m.sent = nil
+	require.NoError(t, co.remind(context.Background(), reminder{InterviewID: 7, Sequence: 2}))
+	require.Empty(t, m.sent)
+}
+
+func TestCoordinator_CancelProposal(t *testing.T) {
+	now := time.Date(2026, 11, 2, 4, 30, 0, 0, time.UTC)
+	f := &fakeStore{interview: proposed(now)}
+	m := &recordingMailer{}
+	co := newCoordinator(t, f, m, now)
+
+	_, err := co.Cancel(context.Background(), 7, "")
+	require.NoError(t, err)
+	// Only the candidate knew about the proposal
+	require.Len(t, m.sent, 1)
+	require.Equal(t, "asha@example.com", m.sent[0].To)
+	require.Empty(t, m.sent[0].Attachments)
+}
@@ -0,0 +1,120 @@
+// Package invites sends the emails inviting people to join a company, and candidates to apply to its jobs.
+package invites
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"job-portal-api/internal/mail"
+	"job-portal-api/internal/models"
+)
+
+// Store is the part of the data layer the inviter needs.
+type Store interface {
+	CreateInvitation(ctx context.Context, companyId, invitedBy uint, ni models.NewInvitation) (models.Invitation, string, error)
+	ViewCompany(ctx context.Context, companyID uint, userId string) (models.Company, error)
+	CreateJobInvitation(ctx context.Context, companyId, userId, invitedBy uint, ni models.NewJobInvitation) (models.JobInvitation, error)
+	ViewJobByJobId(ctx context.Context, jobById uint, userId string) ([]models.Job, error)
+	ViewUser(ctx context.Context, userId uint) (models.User, error)
+}
+
+// Inviter records invitations and mails their token to the invitee.
+type Inviter struct {
+	store   Store
+	mailer  mail.Mailer
+	baseURL string
+}
+
+// NewInviter returns an inviter linking to the API at baseURL.
+func NewInviter(store Store, mailer mail.Mailer, baseURL string) (*Inviter, error) {
+	if store == nil || mailer == nil {
+		return nil, errors.New("store and mailer cannot be nil")
+	}
+	return &Inviter{store: store, mailer: mailer, baseURL: strings.TrimRight(baseURL, "/")}, nil
+}
+
+// Invite invites the email address of ni to the company on behalf of the user invitedBy.
+// The invitation is kept even if the email cannot be handed to the mailer, it can be revoked and sent again.
+func (iv *Inviter) Invite(ctx context.Context, companyId, invitedBy uint, ni models.NewInvitation) (models.Invitation, error) {
+	company, err := iv.store.ViewCompany(ctx, companyId, "")
+	if err != nil {
+		return models.Invitation{}, err
+	}
+	inv, token, err := iv.store.CreateInvitation(ctx, companyId, invitedBy, ni)
+	if err != nil {
+		return models.Invitation{}, err
+	}
+	err = iv.mailer.Send(ctx, iv.compose(inv, company, token))
+	if err != nil {
+		return inv, fmt.Errorf("sending invitation %w", err)
+	}
+	return inv, nil
+}
+
+func (iv *Inviter) compose(inv models.Invitation, company models.Company, token string) mail.Message {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Hi,\n\nYou were invited to join %s on the job portal as %s.\n\n", company.CompanyName, article(inv.Role))
+	fmt.Fprintf(&b, "Sign up or log in with this email address, then accept the invitation:\n  POST %s\n\n",
+		iv.baseURL+"/invitations/accept?token="+url.QueryEscape(token))
+	fmt.Fprintf(&b, "The invitation expires on %s.\n", inv.ExpiresAt.UTC().Format("2 January 2006 at 15:04 UTC"))
+	return mail.Message{
+		To:      inv.Email,
+		Subject: fmt.Sprintf("Join %s on the job portal", company.CompanyName),
+		Body:    b.String(),
+	}
+}
+
+// InviteToApply invites the candidate to apply to a job of the company on behalf of the user invitedBy.
+// Like invitations to join, the invitation is kept when the email cannot be handed to the mailer.
+func (iv *Inviter) InviteToApply(ctx context.Context, companyId, userId, invitedBy uint, ni models.NewJobInvitation) (models.JobInvitation, error) {
+	inv, err := iv.store.CreateJobInvitation(ctx, companyId, userId, invitedBy, ni)
+	if err != nil {
+		return models.JobInvitation{}, err
+	}
+	company, err := iv.store.ViewCompany(ctx, companyId, "")
+	if err != nil {
+		return inv, err
+	}
+	jobs, err := iv.store.ViewJobByJobId(ctx, inv.JobID, "")
+	if err != nil {
+		return inv, err
+	}
+	if len(jobs) == 0 {
+		return inv, fmt.Errorf("job %d of invitation %d not found", inv.JobID, inv.ID)
+	}
+	candidate, err := iv.store.ViewUser(ctx, userId)
+	if err != nil {
+		return inv, err
+	}
+	err = iv.mailer.Send(ctx, iv.composeApply(inv, company, jobs[0], candidate))
+	if err != nil {
+		return inv, fmt.Errorf("sending invitation to apply %w", err)
+	}
+	return inv, nil
+}
+
+func (iv *Inviter) composeApply(inv models.JobInvitation, company models.Company, job models.Job, candidate models.User) mail.Message {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Hi %s,\n\n%s would like you to apply to their %s opening on the job portal.\n\n", candidate.Name, company.CompanyName, job.Title)
+	if inv.Message != "" {
+		fmt.Fprintf(&b, "%s\n\n", inv.Message)
+	}
+	fmt.Fprintf(&b, "Read the job:\n  GET %s\n\nand apply when you are interested:\n  POST %s\n",
+		fmt.Sprintf("%s/jobs/%d", iv.baseURL, job.ID), fmt.Sprintf("%s/jobs/%d/apply", iv.baseURL, job.ID))
+	return mail.Message{
+		To:      candidate.Email,
+		Subject: fmt.Sprintf("%s invites you to apply: %s", company.CompanyName, job.Title),
+		Body:    b.String(),
+	}
+}
+
+// article puts "a" or "an" in front of a role.
+func article(role string) string {
+	if strings.ContainsAny(role[:1], "aeiou") {
+		return "an " + role
+	}
+	return "a " + role
+}
@@ -0,0 +1,114 @@
+package invites
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"job-portal-api/internal/mail"
+	"job-portal-api/internal/models"
+)
+
+type fakeStore struct {
+	created    []models.NewInvitation
+	invitedErr error
+}
+
+func (f *fakeStore) CreateInvitation(ctx context.Context, companyId, invitedBy uint, ni models.NewInvitation) (models.Invitation, string, error) {
+	f.created = append(f.created, ni)
+	return models.Invitation{
+		Model:     gorm.Model{ID: 3},
+		CompanyID: companyId,
+		Email:     ni.Email,
+		Role:      ni.Role,
+		InvitedBy: invitedBy,
+		ExpiresAt: time.Date(2024, 3, 8, 9, 0, 0, 0, time.UTC),
+	}, "tok en", nil
+}
+
+func (f *fakeStore) ViewCompany(ctx context.Context, companyID uint, userId string) (models.Company, error) {
+	return models.Company{Model: gorm.Model{ID: companyID}, CompanyName: "Acme"}, nil
+}
+
+func (f *fakeStore) CreateJobInvitation(ctx context.Context, companyId, userId, invitedBy uint, ni models.NewJobInvitation) (models.JobInvitation, error) {
+	if f.invitedErr != nil {
+		return models.JobInvitation{}, f.invitedErr
+	}
+	return models.JobInvitation{ID: 4, JobID: ni.JobID, UserID: userId, InvitedBy: invitedBy, Message: ni.Message}, nil
+}
+
+func (f *fakeStore) ViewJobByJobId(ctx context.Context, jobId uint, userId string) ([]models.Job, error) {
+	return []models.Job{{Model: gorm.Model{ID: jobId}, Title: "Go developer", CompanyID: 2}}, nil
+}
+
+func (f *fakeStore) ViewUser(ctx context.Context, userId uint) (models.User, error) {
+	return models.User{Model: gorm.Model{ID: userId}, Name: "Asha", Email: "asha@example.com"}, nil
+}
+
+type recordingMailer struct {
+	sent []mail.Message
+	err  error
+}
+
+func (r *recordingMailer) Send(ctx context.Context, m mail.Message) error {
+	r.sent = append(r.sent, m)
+	return r.err
+}
+
+func TestInvite(t *testing.T) {
+	store := &fakeStore{}
+	mailer := &recordingMailer{}
+	iv, err := NewInviter(store, mailer, "https://jobs.example.com/")
+	require.NoError(t, err)
+
+	inv, err := iv.Invite(context.Background(), 2, 7, models.NewInvitation{Email: "asha@example.com", Role: models.RoleAdmin})
+	require.NoError(t, err)
+	require.Equal(t, uint(3), inv.ID)
+	require.Len(t, mailer.sent, 1)
+	m := mailer.sent[0]
+	require.Equal(t, "asha@example.com", m.To)
+	require.Equal(t, "Join Acme on the job portal", m.Subject)
+	require.Contains(t, m.Body, "join Acme on the job portal as an admin")
+	require.Contains(t, m.Body, "POST https://jobs.example.com/invitations/accept?token=tok+en")
+	require.Contains(t, m.Body, "expires on 8 March 2024 at 09:00 UTC")
+}
+
+func TestInviteMailFailure(t *testing.T) {
+	store := &fakeStore{}
+	iv, err := NewInviter(store, &recordingMailer{err: errors.New("queue down")}, "https://jobs.example.com")
+	require.NoError(t, err)
+
+	inv, err := iv.Invite(context.Background(), 2, 7, models.NewInvitation{Email: "asha@example.com", Role: models.RoleRecruiter})
+	require.Error(t, err)
+	require.Equal(t, uint(3), inv.ID)
+	require.Len(t, store.created, 1)
+}
+
+func TestInviteToApply(t *testing.T) {
+	mailer := &recordingMailer{}
+	iv, err := NewInviter(&fakeStore{}, mailer, "https://jobs.example.com")
+	require.NoError(t, err)
+
+	inv, err := iv.InviteToApply(context.Background(), 2, 9, 7, models.NewJobInvitation{JobID: 5, Message: "Your Go talk was great."})
+	require.NoError(t, err)
+	require.Equal(t, uint(4), inv.ID)
+	require.Len(t, mailer.sent, 1)
+	m := mailer.sent[0]
+	require.Equal(t, "asha@example.com", m.To)
+	require.Equal(t, "Acme invites you to apply: Go developer", m.Subject)
+	require.Contains(t, m.Body, "Hi Asha,\n\nAcme would like you to apply to their Go developer opening")
+	require.Contains(t, m.Body, "Your Go talk was great.")
+	require.Contains(t, m.Body, "GET https://jobs.example.com/jobs/5\n")
+	require.Contains(t, m.Body, "POST https://jobs.example.com/jobs/5/apply")
+
+	// Nothing is sent when the invitation cannot be recorded
+	iv, err = NewInviter(&fakeStore{invitedErr: models.ErrCandidateApplied}, mailer, "https://jobs.example.com")
+	require.NoError(t, err)
+	_, err = iv.InviteToApply(context.Background(), 2, 9, 7, models.NewJobInvitation{JobID: 5})
+	require.ErrorIs(t, err, models.ErrCandidateApplied)
+	require.Len(t, mailer.sent, 1)
+}
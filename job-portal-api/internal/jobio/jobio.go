@@ -0,0 +1,366 @@
+// Package jobio reads and writes job postings in bulk, as CSV or JSON Lines.
+package jobio
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+
+	"job-portal-api/internal/models"
+)
+
+// Supported formats.
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+)
+
+// MaxRows is the most jobs a single import may contain.
+const MaxRows = 1000
+
+// maxLine bounds a single JSON Lines record.
+const maxLine = 1 << 20
+
+// Columns are the CSV columns of a job, named like the JSON fields. Skills are separated by semicolons.
+var Columns = []string{
+	"title", "experience_required", "description", "location", "skills",
+	"min_experience_years", "salary_min", "salary_max", "salary_currency", "salary_period",
+	"status",
+}
+
+// exported columns are only written, imports skip them so an export can be imported again.
+var exported = []string{"id", "created_at"}
+
+// ErrTooManyRows is returned when an import holds more than MaxRows jobs.
+var ErrTooManyRows = fmt.Errorf("an import may contain at most %d jobs", MaxRows)
+
+// Row is a valid job read from line Line of the input.
+type Row struct {
+	Line int
+	Job  models.NewJob
+}
+
+// RowError explains why the job on a line was rejected.
+type RowError struct {
+	Line  int    `json:"line"`
+	Field string `json:"field,omitempty"`
+	Error string `json:"error"`
+}
+
+// Format picks the format from an explicit name, falling back on the content type.
+func Format(name, contentType string) (string, error) {
+	switch strings.ToLower(name) {
+	case FormatCSV:
+		return FormatCSV, nil
+	case FormatJSONL, "ndjson":
+		return FormatJSONL, nil
+	case "":
+	default:
+		return "", fmt.Errorf("unknown format %q", name)
+	}
+	mt, _, _ := mime.ParseMediaType(contentType)
+	switch mt {
+	case "text/csv":
+		return FormatCSV, nil
+	case "application/jsonl", "application/x-ndjson", "application/x-jsonlines":
+		return FormatJSONL, nil
+	}
+	return "", errors.New("set format to csv or jsonl, or send a text/csv or application/x-ndjson body")
+}
+
+// ContentType is the media type of a format.
+func ContentType(format string) string {
+	if format == FormatCSV {
+		return "text/csv; charset=utf-8"
+	}
+	return "application/x-ndjson"
+}
+
+// Read parses and validates every job in r. Rows that fail are reported, not returned;
+// the error is only set when the input as a whole cannot be read.
+func Read(format string, r io.Reader) ([]Row, []RowError, error) {
+	p := parser{validate: newValidator()}
+	var err error
+	switch format {
+	case FormatCSV:
+		err = p.readCSV(r)
+	case FormatJSONL:
+		err = p.readJSONL(r)
+	default:
+		err = fmt.Errorf("unknown format %q", format)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return p.rows, p.errs, nil
+}
+
+type parser struct {
+	validate *validator.Validate
+	rows     []Row
+	errs     []RowError
+}
+
+func (p *parser) add(line int, nj models.NewJob, err error) error {
+	if len(p.rows)+len(p.errs) == MaxRows {
+		return ErrTooManyRows
+	}
+	if err == nil {
+		err = p.validate.Struct(nj)
+	}
+	var verrs validator.ValidationErrors
+	switch {
+	case errors.As(err, &verrs):
+		for _, fe := range verrs {
+			p.errs = append(p.errs, RowError{Line: line, Field: fe.Field(), Error: fieldMessage(fe)})
+		}
+	case err != nil:
+		p.errs = append(p.errs, RowError{Line: line, Error: err.Error()})
+	default:
+		p.rows = append(p.rows, Row{Line: line, Job: nj})
+	}
+	return nil
+}
+
+func (p *parser) readCSV(r io.Reader) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading CSV header: %w", err)
+	}
+	index := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !contains(Columns, name) && !contains(exported, name) {
+			return fmt.Errorf("unknown CSV column %q, expected some of %s", name, strings.Join(Columns, ", "))
+		}
+		index[name] = i
+	}
+	for _, name := range []string{"title", "experience_required"} {
+		if _, ok := index[name]; !ok {
+			return fmt.Errorf("CSV column %q is missing", name)
+		}
+	}
+
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			// The quoting is off, nothing after this can be trusted
+			return err
+		}
+		line, _ := cr.FieldPos(0)
+		nj, err := fromRecord(record, index)
+		if err := p.add(line, nj, err); err != nil {
+			return err
+		}
+	}
+}
+
+func fromRecord(record []string, index map[string]int) (models.NewJob, error) {
+	get := func(name string) string {
+		i, ok := index[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+	var nj models.NewJob
+	var err error
+	number := func(name string) int {
+		v := get(name)
+		if v == "" || err != nil {
+			return 0
+		}
+		n, convErr := strconv.Atoi(v)
+		if convErr != nil {
+			err = fmt.Errorf("%s must be a whole number", name)
+		}
+		return n
+	}
+	nj.Title = get("title")
+	nj.ExperienceLevel = get("experience_required")
+	nj.Description = get("description")
+	nj.Location = get("location")
+	for _, s := range strings.Split(get("skills"), ";") {
+		if s = strings.TrimSpace(s); s != "" {
+			nj.Skills = append(nj.Skills, s)
+		}
+	}
+	nj.MinExperienceYears = number("min_experience_years")
+	nj.SalaryMin = number("salary_min")
+	nj.SalaryMax = number("salary_max")
+	nj.SalaryCurrency = strings.ToUpper(get("salary_currency"))
+	nj.SalaryPeriod = strings.ToLower(get("salary_period"))
+	nj.Status = get("status")
+	return nj, err
+}
+
+// jsonlRow accepts the fields of an export on top of the job itself.
+type jsonlRow struct {
+	models.NewJob
+	ID        json.RawMessage `json:"id"`
+	CreatedAt json.RawMessage `json:"created_at"`
+}
+
+func (p *parser) readJSONL(r io.Reader) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64<<10), maxLine)
+	line := 0
+	for sc.Scan() {
+		line++
+		text := strings.TrimSpace(sc.Text())
+		if text == "" {
+			continue
+		}
+		var row jsonlRow
+		dec := json.NewDecoder(strings.NewReader(text))
+		dec.DisallowUnknownFields()
+		err := dec.Decode(&row)
+		if err == nil && dec.More() {
+			err = errors.New("a line must hold exactly one JSON object")
+		}
+		row.SalaryCurrency = strings.ToUpper(row.SalaryCurrency)
+		row.SalaryPeriod = strings.ToLower(row.SalaryPeriod)
+		if err := p.add(line, row.NewJob, err); err != nil {
+			return err
+		}
+	}
+	if errors.Is(sc.Err(), bufio.ErrTooLong) {
+		return fmt.Errorf("line %d is longer than %d bytes", line+1, maxLine)
+	}
+	return sc.Err()
+}
+
+// Writer streams jobs out in one of the formats.
+type Writer struct {
+	csv *csv.Writer
+	enc *json.Encoder
+}
+
+// exportRow is a job as it is exported.
+type exportRow struct {
+	ID        uint      `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	models.NewJob
+}
+
+// NewWriter returns a writer of the format; CSV starts with a header line.
+func NewWriter(format string, w io.Writer) (*Writer, error) {
+	switch format {
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		err := cw.Write(append(append([]string{}, exported...), Columns...))
+		if err != nil {
+			return nil, err
+		}
+		return &Writer{csv: cw}, nil
+	case FormatJSONL:
+		return &Writer{enc: json.NewEncoder(w)}, nil
+	}
+	return nil, fmt.Errorf("unknown format %q", format)
+}
+
+// Write writes one job.
+func (w *Writer) Write(job models.Job) error {
+	nj := models.NewJob{
+		Title:              job.Title,
+		ExperienceLevel:    job.ExperienceLevel,
+		Description:        job.Description,
+		Location:           job.Location,
+		Skills:             job.Skills,
+		MinExperienceYears: job.MinExperienceYears,
+		SalaryMin:          job.SalaryMin,
+		SalaryMax:          job.SalaryMax,
+		SalaryCurrency:     job.SalaryCurrency,
+		SalaryPeriod:       job.SalaryPeriod,
+		Status:             job.Status,
+	}
+	if w.enc != nil {
+		return w.enc.Encode(exportRow{ID: job.ID, CreatedAt: job.CreatedAt.UTC(), NewJob: nj})
+	}
+	return w.csv.Write([]string{
+		strconv.FormatUint(uint64(job.ID), 10),
+		job.CreatedAt.UTC().Format(time.RFC3339),
+		nj.Title,
+		nj.ExperienceLevel,
+		nj.Description,
+		nj.Location,
+		strings.Join(nj.Skills, ";"),
+		strconv.Itoa(nj.MinExperienceYears),
+		strconv.Itoa(nj.SalaryMin),
+		strconv.Itoa(nj.SalaryMax),
+		nj.SalaryCurrency,
+		nj.SalaryPeriod,
+		nj.Status,
+	})
+}
+
+// Flush writes out anything buffered.
+func (w *Writer) Flush() error {
+	if w.csv != nil {
+		w.csv.Flush()
+		return w.csv.Error()
+	}
+	return nil
+}
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	// Report fields by the names used in the files
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	return v
+}
+
+func fieldMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "required_with":
+		return "is required when a salary is given"
+	case "max":
+		return "must be at most " + fe.Param() + " long"
+	case "gte":
+		return "must be at least " + fe.Param()
+	case "lte":
+		return "must be at most " + fe.Param()
+	case "gtefield":
+		return "must not be less than salary_min"
+	case "iso4217":
+		return "must be an ISO 4217 currency code"
+	case "oneof":
+		return "must be one of " + fe.Param()
+	}
+	return "failed on " + fe.Tag()
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
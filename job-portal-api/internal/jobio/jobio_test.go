@@ -0,0 +1,125 @@
+package jobio
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"job-portal-api/internal/models"
+)
+
+func TestReadCSV(t *testing.T) {
+	in := "title,experience_required,skills,salary_min,salary_max,salary_currency,salary_period,status\n" +
+		"Go developer,senior,Go; Postgres,100,200,usd,Hourly,\n" +
+		",junior,,,,,,\n" +
+		"\"Rust developer, remote\",mid,Rust,abc,,,,\n" +
+		"SRE,senior,,300,200,EUR,,\n" +
+		"SRE,senior,,,,,weekly,closed\n"
+
+	rows, errs, err := Read(FormatCSV, strings.NewReader(in))
+	require.NoError(t, err)
+
+	require.Equal(t, []Row{{Line: 2, Job: models.NewJob{
+		Title: "Go developer", ExperienceLevel: "senior", Skills: []string{"Go", "Postgres"},
+		SalaryMin: 100, SalaryMax: 200, SalaryCurrency: "USD", SalaryPeriod: "hourly",
+	}}}, rows)
+	require.Equal(t, []RowError{
+		{Line: 3, Field: "title", Error: "is required"},
+		{Line: 4, Error: "salary_min must be a whole number"},
+		{Line: 5, Field: "salary_max", Error: "must not be less than salary_min"},
+		{Line: 6, Field: "salary_period", Error: "must be one of hourly monthly annual"},
+		{Line: 6, Field: "status", Error: "must be one of draft published"},
+	}, errs)
+}
+
+func TestReadCSVHeader(t *testing.T) {
+	_, _, err := Read(FormatCSV, strings.NewReader("title,experience_required,salary\nGo,senior,1\n"))
+	require.ErrorContains(t, err, `unknown CSV column "salary"`)
+
+	_, _, err = Read(FormatCSV, strings.NewReader("title\nGo\n"))
+	require.ErrorContains(t, err, `"experience_required" is missing`)
+}
+
+func TestReadJSONL(t *testing.T) {
+	in := `{"title":"Go developer","experience_required":"senior","skills":["Go"]}` + "\n" +
+		"\n" +
+		`{"title":"Go developer","experience_required":"senior","salary":5}` + "\n" +
+		`{"title":"Go developer"` + "\n" +
+		`{"title":"SRE","experience_required":"mid","salary_min":10,"salary_currency":"XXY"}` + "\n"
+
+	rows, errs, err := Read(FormatJSONL, strings.NewReader(in))
+	require.NoError(t, err)
+
+	require.Len(t, rows, 1)
+	require.Equal(t, 1, rows[0].Line)
+	require.Len(t, errs, 3)
+	require.Equal(t, 3, errs[0].Line)
+	require.Contains(t, errs[0].Error, "unknown field")
+	require.Equal(t, 4, errs[1].Line)
+	require.Equal(t, RowError{Line: 5, Field: "salary_currency", Error: "must be an ISO 4217 currency code"}, errs[2])
+}
+
+func TestReadTooManyRows(t *testing.T) {
+	var b strings.Builder
+	for i := 0; i <= MaxRows; i++ {
+		fmt.Fprintf(&b, "{\"title\":\"Job %d\",\"experience_required\":\"mid\"}\n", i)
+	}
+	_, _, err := Read(FormatJSONL, strings.NewReader(b.String()))
+	require.ErrorIs(t, err, ErrTooManyRows)
+}
+
+func TestExportImportsAgain(t *testing.T) {
+	jobs := []models.Job{
+		{Model: gorm.Model{ID: 1, CreatedAt: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+			Title: "Go developer", ExperienceLevel: "senior", Skills: []string{"go", "postgresql"},
+			Description: "Line one\nline \"two\"", SalaryMin: 100, SalaryMax: 200, SalaryCurrency: "USD",
+			Status: models.JobDraft},
+		{Model: gorm.Model{ID: 2}, Title: "SRE", ExperienceLevel: "mid", Location: "Pune"},
+	}
+
+	for _, format := range []string{FormatCSV, FormatJSONL} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewWriter(format, &buf)
+			require.NoError(t, err)
+			for _, j := range jobs {
+				require.NoError(t, w.Write(j))
+			}
+			require.NoError(t, w.Flush())
+
+			rows, errs, err := Read(format, &buf)
+			require.NoError(t, err)
+			require.Empty(t, errs)
+			require.Len(t, rows, 2)
+			require.Equal(t, "Line one\nline \"two\"", rows[0].Job.Description)
+			require.Equal(t, []string{"go", "postgresql"}, rows[0].Job.Skills)
+			require.Equal(t, 200, rows[0].Job.SalaryMax)
+			require.Equal(t, "Pune", rows[1].Job.Location)
+			require.Equal(t, models.JobDraft, rows[0].Job.Status)
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	f, err := Format("", "text/csv; charset=utf-8")
+	require.NoError(t, err)
+	require.Equal(t, FormatCSV, f)
+
+	f, err = Format("", "application/x-ndjson")
+	require.NoError(t, err)
+	require.Equal(t, FormatJSONL, f)
+
+	f, err = Format("JSONL", "text/csv")
+	require.NoError(t, err)
+	require.Equal(t, FormatJSONL, f)
+
+	_, err = Format("", "application/json")
+	require.Error(t, err)
+	_, err = Format("xml", "")
+	require.Error(t, err)
+}
@@ -0,0 +1,63 @@
+// Package lifecycle moves jobs through their schedule: drafts due to be published go live
+// and jobs past their expiry date are taken off the listings.
+package lifecycle
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"job-portal-api/internal/queue"
+)
+
+// batchSize is how many due jobs are changed in one transaction.
+const batchSize = 200
+
+// Store is the part of the data layer the sweeper needs.
+type Store interface {
+	SweepJobs(ctx context.Context, now time.Time, limit int) (int, error)
+}
+
+// Sweeper publishes and expires jobs when their dates come.
+type Sweeper struct {
+	store Store
+	now   func() time.Time
+}
+
+// NewSweeper returns a sweeper over store. Call Register to run it.
+func NewSweeper(store Store) *Sweeper {
+	return &Sweeper{store: store, now: time.Now}
+}
+
+// KindSweep is the queue task publishing and expiring the due jobs.
+const KindSweep = "jobs.sweep"
+
+// Register runs the sweeper on q every minute.
+func (s *Sweeper) Register(q *queue.Queue) error {
+	q.Handle(KindSweep, func(ctx context.Context, _ json.RawMessage) error {
+		return s.RunOnce(ctx)
+	})
+	return q.Schedule("job-sweep", "* * * * *", KindSweep, nil)
+}
+
+// RunOnce changes every job that is due, a batch at a time.
+func (s *Sweeper) RunOnce(ctx context.Context) error {
+	now := s.now().UTC()
+	total := 0
+	for {
+		n, err := s.store.SweepJobs(ctx, now, batchSize)
+		if err != nil {
+			return err
+		}
+		total += n
+		if n < batchSize {
+			break
+		}
+	}
+	if total > 0 {
+		log.Info().Int("jobs", total).Msg("swept scheduled jobs")
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	due   int
+	calls int
+	err   error
+	at    []time.Time
+}
+
+func (f *fakeStore) SweepJobs(ctx context.Context, now time.Time, limit int) (int, error) {
+	f.calls++
+	f.at = append(f.at, now)
+	if f.err != nil {
+		return 0, f.err
+	}
+	n := min(f.due, limit)
+	f.due -= n
+	return n, nil
+}
+
+func TestRunOnceSweepsInBatches(t *testing.T) {
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	store := &fakeStore{due: 2*batchSize + 3}
+	s := NewSweeper(store)
+	s.now = func() time.Time { return now }
+
+	require.NoError(t, s.RunOnce(context.Background()))
+	require.Equal(t, 3, store.calls)
+	require.Zero(t, store.due)
+	for _, at := range store.at {
+		require.Equal(t, now, at)
+	}
+}
+
+func TestRunOnceStopsOnError(t *testing.T) {
+	store := &fakeStore{due: 10, err: errors.New("db down")}
+	require.Error(t, NewSweeper(store).RunOnce(context.Background()))
+	require.Equal(t, 1, store.calls)
+}
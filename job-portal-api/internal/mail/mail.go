@@ -0,0 +1,139 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Message is a plain text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+	// Headers are added on top of From, To, Subject and Date, e.g. List-Unsubscribe.
+	Headers map[string]string
+	// Attachments are sent after the body, e.g. calendar invitations.
+	Attachments []Attachment `json:",omitempty"`
+}
+
+// Attachment is a file sent along with an email.
+type Attachment struct {
+	Filename string
+	// ContentType may carry parameters, e.g. "text/calendar; method=REQUEST".
+	ContentType string
+	Content     []byte
+}
+
+// Mailer delivers emails.
+type Mailer interface {
+	Send(ctx context.Context, m Message) error
+}
+
+// LogMailer writes emails to the log instead of sending them, for development.
+type LogMailer struct{}
+
+func (LogMailer) Send(ctx context.Context, m Message) error {
+	log.Info().Str("to", m.To).Str("subject", m.Subject).Int("attachments", len(m.Attachments)).Msg(m.Body)
+	return nil
+}
+
+// SMTPMailer sends emails through an SMTP server.
+type SMTPMailer struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+// NewSMTPMailer returns a mailer relaying through addr ("host:port").
+// Authentication is skipped when username is empty.
+func NewSMTPMailer(addr, from, username, password string) (*SMTPMailer, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid smtp address %w", err)
+	}
+	if from == "" {
+		return nil, errors.New("sender address cannot be empty")
+	}
+	m := &SMTPMailer{addr: addr, from: from}
+	if username != "" {
+		m.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return m, nil
+}
+
+func (s *SMTPMailer) Send(ctx context.Context, m Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return smtp.SendMail(s.addr, s.auth, s.from, []string{m.To}, Compose(s.from, m, time.Now()))
+}
+
+// Compose renders the message in the RFC 5322 format.
+// Messages with attachments are sent as multipart/mixed with the body as the first part.
+func Compose(from string, m Message, now time.Time) []byte {
+	var b bytes.Buffer
+	header := func(k, v string) {
+		// Header values must not smuggle in extra headers
+		v = strings.NewReplacer("\r", "", "\n", "").Replace(v)
+		fmt.Fprintf(&b, "%s: %s\r\n", k, v)
+	}
+	header("From", from)
+	header("To", m.To)
+	header("Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	header("Date", now.Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+	var mw *multipart.Writer
+	if len(m.Attachments) > 0 {
+		mw = multipart.NewWriter(&b)
+		header("Content-Type", "multipart/mixed; boundary="+mw.Boundary())
+	} else {
+		header("Content-Type", "text/plain; charset=utf-8")
+	}
+	keys := make([]string, 0, len(m.Headers))
+	for k := range m.Headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		header(k, m.Headers[k])
+	}
+	b.WriteString("\r\n")
+	body := strings.ReplaceAll(strings.ReplaceAll(m.Body, "\r\n", "\n"), "\n", "\r\n")
+	if mw == nil {
+		b.WriteString(body)
+		return b.Bytes()
+	}
+
+	// Writing to a bytes.Buffer cannot fail
+	part, _ := mw.CreatePart(textproto.MIMEHeader{"Content-Type": {"text/plain; charset=utf-8"}})
+	part.Write([]byte(body))
+	for _, a := range m.Attachments {
+		filename := strings.NewReplacer("\r", "", "\n", "", `"`, "").Replace(a.Filename)
+		part, _ = mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {a.ContentType + `; name="` + filename + `"`},
+			"Content-Disposition":       {`attachment; filename="` + filename + `"`},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		enc := base64.StdEncoding.EncodeToString(a.Content)
+		for len(enc) > 76 {
+			part.Write([]byte(enc[:76] + "\r\n"))
+			enc = enc[76:]
+		}
+		part.Write([]byte(enc + "\r\n"))
+	}
+	mw.Close()
+	return b.Bytes()
+}
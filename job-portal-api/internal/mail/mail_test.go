@@ -0,0 +1,69 @@
+package mail
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompose(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	t.Run("PlainText", func(t *testing.T) {
+		raw := Compose("jobs@example.com", Message{To: "asha@example.com", Subject: "Hi", Body: "one\ntwo"}, now)
+		msg, err := mail.ReadMessage(bytes.NewReader(raw))
+		require.NoError(t, err)
+		require.Equal(t, "text/plain; charset=utf-8", msg.Header.Get("Content-Type"))
+		body, err := io.ReadAll(msg.Body)
+		require.NoError(t, err)
+		require.Equal(t, "one\r\ntwo", string(body))
+	})
+
+	t.Run("Attachments", func(t *testing.T) {
+		ics := []byte(strings.Repeat("BEGIN:VCALENDAR\r\n", 10))
+		raw := Compose("jobs@example.com", Message{
+			To:      "asha@example.com",
+			Subject: "Interview",
+			Body:    "See you then",
+			Attachments: []Attachment{
+				{Filename: "invite.ics", ContentType: "text/calendar; charset=utf-8; method=REQUEST", Content: ics},
+			},
+		}, now)
+		msg, err := mail.ReadMessage(bytes.NewReader(raw))
+		require.NoError(t, err)
+		mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+		require.NoError(t, err)
+		require.Equal(t, "multipart/mixed", mediaType)
+
+		mr := multipart.NewReader(msg.Body, params["boundary"])
+		part, err := mr.NextPart()
+		require.NoError(t, err)
+		body, err := io.ReadAll(part)
+		require.NoError(t, err)
+		require.Equal(t, "See you then", string(body))
+
+		part, err = mr.NextPart()
+		require.NoError(t, err)
+		require.Equal(t, "invite.ics", part.FileName())
+		require.Contains(t, part.Header.Get("Content-Type"), "method=REQUEST")
+		// The multipart reader decodes quoted-printable only, base64 is left to the caller
+		enc, err := io.ReadAll(part)
+		require.NoError(t, err)
+		for _, line := range strings.Split(strings.TrimSpace(string(enc)), "\r\n") {
+			require.LessOrEqual(t, len(line), 76)
+		}
+		content, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(string(enc), "\r\n", ""))
+		require.NoError(t, err)
+		require.Equal(t, ics, content)
+		_, err = mr.NextPart()
+		require.ErrorIs(t, err, io.EOF)
+	})
+}
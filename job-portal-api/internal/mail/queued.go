@@ -0,0 +1,38 @@
+package mail
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"job-portal-api/internal/queue"
+)
+
+// KindSend is the queue task delivering one email.
+const KindSend = "mail.send"
+
+// QueuedMailer hands emails to the background queue, which delivers them through another mailer
+// and retries when the mail server is unavailable.
+type QueuedMailer struct {
+	q *queue.Queue
+}
+
+// NewQueuedMailer returns a mailer enqueueing on q and delivering through m.
+func NewQueuedMailer(q *queue.Queue, m Mailer) (*QueuedMailer, error) {
+	if q == nil || m == nil {
+		return nil, errors.New("queue and mailer cannot be nil")
+	}
+	q.Handle(KindSend, func(ctx context.Context, payload json.RawMessage) error {
+		var msg Message
+		err := json.Unmarshal(payload, &msg)
+		if err != nil {
+			return queue.Permanent(err)
+		}
+		return m.Send(ctx, msg)
+	})
+	return &QueuedMailer{q: q}, nil
+}
+
+func (qm *QueuedMailer) Send(ctx context.Context, m Message) error {
+	return qm.q.Enqueue(ctx, KindSend, m)
+}
@@ -0,0 +1,218 @@
+package matching
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/queue"
+)
+
+// Queue tasks of the engine.
+const (
+	KindScoreJob     = "match.job"
+	KindScoreProfile = "match.profile"
+	KindRescore      = "match.rescore"
+)
+
+// MinScore is the lowest score worth storing; weaker matches are never recommended.
+const MinScore = 0.3
+
+// batchSize is how many jobs or profiles are loaded at once while rescoring.
+const batchSize = 200
+
+// Store is the part of the data layer the engine needs.
+type Store interface {
+	ViewJobByJobId(ctx context.Context, jobId uint, userId string) ([]models.Job, error)
+	ViewProfile(ctx context.Context, userId uint) (models.Profile, error)
+	ProfilesAfter(ctx context.Context, afterId uint, limit int) ([]models.Profile, error)
+	ApplicantProfiles(ctx context.Context, jobId uint) ([]models.Profile, error)
+	JobsAfter(ctx context.Context, afterId uint, limit int) ([]models.Job, error)
+	ReplaceJobMatches(ctx context.Context, jobId uint, scores []models.MatchScore) error
+	ReplaceProfileMatches(ctx context.Context, userId uint, scores []models.MatchScore) error
+	CountStaleMatchScores(ctx context.Context, weights string) (int64, error)
+}
+
+type scoreTask struct {
+	JobID  uint `json:"job_id,omitempty"`
+	UserID uint `json:"user_id,omitempty"`
+}
+
+// Engine keeps the stored match scores up to date.
+// A changed job is scored against every discoverable profile and its applicants, a changed profile against every job,
+// in queue workers, so recommendations are plain reads.
+type Engine struct {
+	store   Store
+	weights Weights
+	q       *queue.Queue
+	now     func() time.Time
+}
+
+// NewEngine returns an engine scoring with the given weights and running its work on q.
+func NewEngine(store Store, w Weights, q *queue.Queue) (*Engine, error) {
+	if store == nil || q == nil {
+		return nil, errors.New("store and queue cannot be nil")
+	}
+	e := &Engine{store: store, weights: w, q: q, now: time.Now}
+	q.Handle(KindScoreJob, e.handle)
+	q.Handle(KindScoreProfile, e.handle)
+	q.Handle(KindRescore, func(ctx context.Context, _ json.RawMessage) error {
+		return e.RescoreIfStale(ctx)
+	})
+	return e, nil
+}
+
+// JobChanged schedules rescoring of a created, updated or deleted job.
+func (e *Engine) JobChanged(ctx context.Context, jobId uint) error {
+	return e.q.Enqueue(ctx, KindScoreJob, scoreTask{JobID: jobId})
+}
+
+// ProfileChanged schedules rescoring of a created, updated or deleted profile.
+func (e *Engine) ProfileChanged(ctx context.Context, userId uint) error {
+	return e.q.Enqueue(ctx, KindScoreProfile, scoreTask{UserID: userId})
+}
+
+// ScheduleRescore queues a check for scores computed with other weights, done once at startup.
+func (e *Engine) ScheduleRescore(ctx context.Context) error {
+	return e.q.Enqueue(ctx, KindRescore, nil)
+}
+
+func (e *Engine) handle(ctx context.Context, payload json.RawMessage) error {
+	var t scoreTask
+	err := json.Unmarshal(payload, &t)
+	if err != nil {
+		return queue.Permanent(err)
+	}
+	if t.JobID != 0 {
+		return e.ScoreJob(ctx, t.JobID)
+	}
+	return e.ScoreProfile(ctx, t.UserID)
+}
+
+// RescoreIfStale rescores every job when stored scores were computed with other weights,
+// which happens after the weights are tuned.
+func (e *Engine) RescoreIfStale(ctx context.Context) error {
+	n, err := e.store.CountStaleMatchScores(ctx, e.weights.Fingerprint())
+	if err != nil || n == 0 {
+		return err
+	}
+	log.Info().Int64("stale", n).Msg("matching : weights changed, rescoring all jobs")
+	var after uint
+	for {
+		jobs, err := e.store.JobsAfter(ctx, after, batchSize)
+		if err != nil {
+			return err
+		}
+		if len(jobs) == 0 {
+			return nil
+		}
+		for _, j := range jobs {
+			err = e.scoreJob(ctx, j)
+			if err != nil {
+				return err
+			}
+		}
+		after = jobs[len(jobs)-1].ID
+	}
+}
+
+// ScoreJob recomputes the scores of one job against all discoverable profiles and the profiles of its applicants.
+func (e *Engine) ScoreJob(ctx context.Context, jobId uint) error {
+	jobs, err := e.store.ViewJobByJobId(ctx, jobId, "")
+	if err != nil {
+		return err
+	}
+	if len(jobs) == 0 || jobs[0].Status != models.JobPublished {
+		// The job is gone or no longer listed, so are its matches
+		return e.store.ReplaceJobMatches(ctx, jobId, nil)
+	}
+	return e.scoreJob(ctx, jobs[0])
+}
+
+func (e *Engine) scoreJob(ctx context.Context, job models.Job) error {
+	var scores []models.MatchScore
+	scored := map[uint]bool{}
+	add := func(p models.Profile) {
+		if scored[p.UserID] {
+			return
+		}
+		scored[p.UserID] = true
+		if ms, ok := e.match(job, p); ok {
+			scores = append(scores, ms)
+		}
+	}
+	var after uint
+	for {
+		profiles, err := e.store.ProfilesAfter(ctx, after, batchSize)
+		if err != nil {
+			return err
+		}
+		if len(profiles) == 0 {
+			break
+		}
+		for _, p := range profiles {
+			add(p)
+		}
+		after = profiles[len(profiles)-1].ID
+	}
+	// Applicants are ranked for the job whether they are discoverable or not
+	applicants, err := e.store.ApplicantProfiles(ctx, job.ID)
+	if err != nil {
+		return err
+	}
+	for _, p := range applicants {
+		add(p)
+	}
+	return e.store.ReplaceJobMatches(ctx, job.ID, scores)
+}
+
+// ScoreProfile recomputes the scores of one candidate against all jobs.
+func (e *Engine) ScoreProfile(ctx context.Context, userId uint) error {
+	p, err := e.store.ViewProfile(ctx, userId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return e.store.ReplaceProfileMatches(ctx, userId, nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	var scores []models.MatchScore
+	var after uint
+	for {
+		jobs, err := e.store.JobsAfter(ctx, after, batchSize)
+		if err != nil {
+			return err
+		}
+		if len(jobs) == 0 {
+			break
+		}
+		for _, j := range jobs {
+			if ms, ok := e.match(j, p); ok {
+				scores = append(scores, ms)
+			}
+		}
+		after = jobs[len(jobs)-1].ID
+	}
+	return e.store.ReplaceProfileMatches(ctx, userId, scores)
+}
+
+func (e *Engine) match(job models.Job, p models.Profile) (models.MatchScore, bool) {
+	now := e.now()
+	total, breakdown := Score(job, p, e.weights, now)
+	if total < MinScore {
+		return models.MatchScore{}, false
+	}
+	return models.MatchScore{
+		JobID:      job.ID,
+		UserID:     p.UserID,
+		Score:      total,
+		Breakdown:  breakdown,
+		Weights:    e.weights.Fingerprint(),
+		ComputedAt: now,
+	}, true
+}
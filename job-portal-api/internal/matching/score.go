@@ -0,0 +1,257 @@
+package matching
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"job-portal-api/internal/models"
+)
+
+// Weights sets how much each component counts towards the total score.
+// Only the ratio between weights matters.
+type Weights struct {
+	Skills     float64
+	Experience float64
+	Location   float64
+	Salary     float64
+}
+
+// DefaultWeights favours skills, then experience.
+var DefaultWeights = Weights{Skills: 0.5, Experience: 0.25, Location: 0.15, Salary: 0.1}
+
+// ParseWeights reads weights in the "skills=0.5,experience=0.25,location=0.15,salary=0.1" form.
+// Components left out keep their default weight.
+func ParseWeights(s string) (Weights, error) {
+	w := DefaultWeights
+	if strings.TrimSpace(s) == "" {
+		return w, nil
+	}
+	for _, part := range strings.Split(s, ",") {
+		name, val, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return Weights{}, fmt.Errorf("invalid weight %q", part)
+		}
+		f, err := strconv.ParseFloat(val, 64)
+		if err != nil || f < 0 {
+			return Weights{}, fmt.Errorf("invalid weight %q", part)
+		}
+		switch strings.ToLower(name) {
+		case "skills":
+			w.Skills = f
+		case "experience":
+			w.Experience = f
+		case "location":
+			w.Location = f
+		case "salary":
+			w.Salary = f
+		default:
+			return Weights{}, fmt.Errorf("unknown weight %q", name)
+		}
+	}
+	if w.Skills+w.Experience+w.Location+w.Salary == 0 {
+		return Weights{}, fmt.Errorf("weights cannot all be zero")
+	}
+	return w, nil
+}
+
+// Fingerprint identifies a set of weights, so scores computed with other weights can be spotted.
+func (w Weights) Fingerprint() string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%g|%g|%g|%g", w.Skills, w.Experience, w.Location, w.Salary)))
+	return hex.EncodeToString(sum[:8])
+}
+
+// levelYears turns the free-form experience level of a job into the years it usually implies.
+var levelYears = map[string]int{
+	"intern":    0,
+	"entry":     0,
+	"junior":    1,
+	"mid":       3,
+	"middle":    3,
+	"senior":    5,
+	"lead":      7,
+	"staff":     8,
+	"principal": 10,
+}
+
+// requiredYears is the experience a job asks for, preferring the explicit number of years.
+func requiredYears(job models.Job) float64 {
+	if job.MinExperienceYears > 0 {
+		return float64(job.MinExperienceYears)
+	}
+	level := strings.ToLower(job.ExperienceLevel)
+	best := 0
+	for word, years := range levelYears {
+		if strings.Contains(level, word) && years > best {
+			best = years
+		}
+	}
+	return float64(best)
+}
+
+// Score compares a job with a candidate profile and explains how the total came about.
+// now is used to measure ongoing positions.
+func Score(job models.Job, p models.Profile, w Weights, now time.Time) (float64, []models.ScoreComponent) {
+	components := []models.ScoreComponent{
+		skillsScore(job, p, w.Skills),
+		experienceScore(job, p, w.Experience, now),
+		locationScore(job, p, w.Location),
+		salaryScore(job, p, w.Salary),
+	}
+	var total, weights float64
+	for _, c := range components {
+		total += c.Weight * c.Score
+		weights += c.Weight
+	}
+	if weights == 0 {
+		return 0, components
+	}
+	return round(total / weights), components
+}
+
+func skillsScore(job models.Job, p models.Profile, weight float64) models.ScoreComponent {
+	c := models.ScoreComponent{Name: "skills", Weight: weight}
+	required := models.NormalizeSkills(job.Skills)
+	if len(required) == 0 {
+		c.Score = 0.5
+		c.Detail = "job lists no skills"
+		return c
+	}
+	has := map[string]bool{}
+	for _, s := range models.NormalizeSkills(p.Skills) {
+		has[s] = true
+	}
+	var matched, missing []string
+	for _, s := range required {
+		if has[s] {
+			matched = append(matched, s)
+		} else {
+			missing = append(missing, s)
+		}
+	}
+	c.Score = round(float64(len(matched)) / float64(len(required)))
+	c.Detail = fmt.Sprintf("%d of %d skills", len(matched), len(required))
+	if len(missing) > 0 {
+		c.Detail += ", missing " + strings.Join(missing, ", ")
+	}
+	return c
+}
+
+func experienceScore(job models.Job, p models.Profile, weight float64, now time.Time) models.ScoreComponent {
+	c := models.ScoreComponent{Name: "experience", Weight: weight}
+	need := requiredYears(job)
+	have := ExperienceYears(p.Experiences, now)
+	if need == 0 {
+		c.Score = 1
+		c.Detail = fmt.Sprintf("no minimum, candidate has %.1f years", have)
+		return c
+	}
+	c.Score = round(math.Min(1, have/need))
+	c.Detail = fmt.Sprintf("%.1f of %.0f years", have, need)
+	return c
+}
+
+// ExperienceYears adds up the time covered by the experience entries, counting overlapping periods once.
+func ExperienceYears(exps []models.Experience, now time.Time) float64 {
+	type span struct{ from, to time.Time }
+	var spans []span
+	for _, e := range exps {
+		from, err := time.Parse("2006-01", e.StartDate)
+		if err != nil {
+			continue
+		}
+		to := now
+		if !e.Current {
+			to, err = time.Parse("2006-01", e.EndDate)
+			if err != nil {
+				continue
+			}
+			// An entry ending in a month covers that whole month
+			to = to.AddDate(0, 1, 0)
+		}
+		if to.After(from) {
+			spans = append(spans, span{from, to})
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].from.Before(spans[j].from) })
+
+	var total time.Duration
+	var cur *span
+	for i := range spans {
+		s := spans[i]
+		if cur != nil && !s.from.After(cur.to) {
+			if s.to.After(cur.to) {
+				cur.to = s.to
+			}
+			continue
+		}
+		if cur != nil {
+			total += cur.to.Sub(cur.from)
+		}
+		cur = &s
+	}
+	if cur != nil {
+		total += cur.to.Sub(cur.from)
+	}
+	return math.Round(total.Hours()/24/365.25*10) / 10
+}
+
+func locationScore(job models.Job, p models.Profile, weight float64) models.ScoreComponent {
+	c := models.ScoreComponent{Name: "location", Weight: weight}
+	loc := strings.ToLower(strings.TrimSpace(job.Location))
+	if loc == "" || len(p.DesiredLocations) == 0 {
+		c.Score = 0.5
+		c.Detail = "no location to compare"
+		return c
+	}
+	for _, want := range p.DesiredLocations {
+		w := strings.ToLower(strings.TrimSpace(want))
+		if w == "" {
+			continue
+		}
+		if strings.Contains(loc, w) || strings.Contains(w, loc) || (w == "remote" && strings.Contains(loc, "remote")) {
+			c.Score = 1
+			c.Detail = "matches " + want
+			return c
+		}
+	}
+	c.Detail = job.Location + " is not among the desired locations"
+	return c
+}
+
+func salaryScore(job models.Job, p models.Profile, weight float64) models.ScoreComponent {
+	c := models.ScoreComponent{Name: "salary", Weight: weight}
+	if job.SalaryMax == 0 && job.SalaryMin == 0 || p.DesiredSalaryMin == 0 {
+		c.Score = 0.5
+		c.Detail = "no salary to compare"
+		return c
+	}
+	if !strings.EqualFold(job.SalaryCurrency, p.SalaryCurrency) {
+		c.Score = 0.5
+		c.Detail = "salaries are in different currencies"
+		return c
+	}
+	// Candidates state the yearly salary they expect
+	top := job.SalaryMax
+	if top == 0 {
+		top = job.SalaryMin
+	}
+	top = job.AnnualSalary(top)
+	if top >= p.DesiredSalaryMin {
+		c.Score = 1
+		c.Detail = "salary range meets the expectation"
+		return c
+	}
+	c.Score = round(float64(top) / float64(p.DesiredSalaryMin))
+	c.Detail = fmt.Sprintf("pays up to %d, candidate expects %d", top, p.DesiredSalaryMin)
+	return c
+}
+
+func round(f float64) float64 {
+	return math.Round(f*1000) / 1000
+}
@@ -0,0 +1,82 @@
+package matching
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"job-portal-api/internal/models"
+)
+
+func TestScore(t *testing.T) {
+	now := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	job := models.Job{
+		Title:              "Backend Engineer",
+		Location:           "Pune, India",
+		Skills:             []string{"Go", "PostgreSQL", "Kubernetes", "gRPC"},
+		MinExperienceYears: 4,
+		SalaryMin:          1500000,
+		SalaryMax:          2500000,
+		SalaryCurrency:     "INR",
+	}
+	p := models.Profile{
+		Skills:           []string{"go", "postgresql", "kubernetes", "docker"},
+		DesiredLocations: []string{"Pune"},
+		DesiredSalaryMin: 2000000,
+		SalaryCurrency:   "INR",
+		Experiences: []models.Experience{
+			{StartDate: "2020-01", EndDate: "2021-12"},
+			// Overlapping periods count once
+			{StartDate: "2021-06", Current: true},
+		},
+	}
+
+	total, breakdown := Score(job, p, DefaultWeights, now)
+	require.Equal(t, []models.ScoreComponent{
+		{Name: "skills", Weight: 0.5, Score: 0.75, Detail: "3 of 4 skills, missing grpc"},
+		{Name: "experience", Weight: 0.25, Score: 1, Detail: "4.0 of 4 years"},
+		{Name: "location", Weight: 0.15, Score: 1, Detail: "matches Pune"},
+		{Name: "salary", Weight: 0.1, Score: 1, Detail: "salary range meets the expectation"},
+	}, breakdown)
+	require.Equal(t, 0.875, total)
+}
+
+func TestParseWeights(t *testing.T) {
+	tt := []struct {
+		name    string
+		in      string
+		want    Weights
+		wantErr bool
+	}{
+		{name: "Empty", in: "", want: DefaultWeights},
+		{name: "Partial", in: "skills=1, salary=0", want: Weights{Skills: 1, Experience: 0.25, Location: 0.15}},
+		{name: "Unknown", in: "luck=1", wantErr: true},
+		{name: "Negative", in: "skills=-1", wantErr: true},
+		{name: "AllZero", in: "skills=0,experience=0,location=0,salary=0", wantErr: true},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			w, err := ParseWeights(tc.in)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, w)
+		})
+	}
+}
+
+func TestSalaryScorePeriod(t *testing.T) {
+	p := models.Profile{DesiredSalaryMin: 100000, SalaryCurrency: "USD"}
+	// 40 an hour is 83200 a year
+	job := models.Job{SalaryMin: 30, SalaryMax: 40, SalaryCurrency: "USD", SalaryPeriod: models.PayHourly}
+	c := salaryScore(job, p, 1)
+	require.Equal(t, 0.832, c.Score)
+	require.Equal(t, "pays up to 83200, candidate expects 100000", c.Detail)
+
+	job.SalaryPeriod = models.PayMonthly
+	job.SalaryMax = 9000
+	require.Equal(t, 1.0, salaryScore(job, p, 1).Score)
+}
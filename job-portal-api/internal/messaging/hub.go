@@ -0,0 +1,103 @@
+// Package messaging delivers new messages and read receipts to the participants of a conversation
+// while they are connected, so clients do not have to poll.
+package messaging
+
+import (
+	"sync"
+
+	"github.com/rs/zerolog/log"
+
+	"job-portal-api/internal/models"
+)
+
+// Event types sent to subscribers.
+const (
+	EventMessage = "message"
+	EventRead    = "read"
+)
+
+// Event is something that happened in a conversation the subscriber takes part in.
+type Event struct {
+	Type           string                   `json:"type"`
+	ConversationID uint                     `json:"conversation_id"`
+	ApplicationID  uint                     `json:"application_id"`
+	Message        *models.Message          `json:"message,omitempty"`
+	Read           *models.ConversationRead `json:"read,omitempty"`
+}
+
+// buffer is how many events may wait for a slow subscriber before it misses some.
+const buffer = 32
+
+// Hub fans events out to the streams users have open in this process.
+// Events are not stored: a client that was disconnected or fell behind catches up by listing the messages.
+type Hub struct {
+	mu     sync.RWMutex
+	subs   map[uint]map[chan Event]struct{}
+	closed bool
+}
+
+// NewHub returns an empty hub.
+func NewHub() *Hub {
+	return &Hub{subs: map[uint]map[chan Event]struct{}{}}
+}
+
+// Subscribe opens a stream of the events for the user. Call the returned function to close it.
+// A user may have several streams open, e.g. one per browser tab.
+func (h *Hub) Subscribe(userId uint) (<-chan Event, func()) {
+	ch := make(chan Event, buffer)
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		close(ch)
+		return ch, func() {}
+	}
+	if h.subs[userId] == nil {
+		h.subs[userId] = map[chan Event]struct{}{}
+	}
+	h.subs[userId][ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		// The stream is gone already when it was closed twice or the hub was closed
+		if _, ok := h.subs[userId][ch]; !ok {
+			return
+		}
+		delete(h.subs[userId], ch)
+		if len(h.subs[userId]) == 0 {
+			delete(h.subs, userId)
+		}
+		close(ch)
+	}
+}
+
+// Close ends every open stream and the ones opened afterwards, for the server to shut down
+// without waiting on clients that never hang up.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for _, chs := range h.subs {
+		for ch := range chs {
+			close(ch)
+		}
+	}
+	h.subs = map[uint]map[chan Event]struct{}{}
+}
+
+// Publish sends the event to every open stream of the users. It never blocks:
+// a stream whose buffer is full misses the event.
+func (h *Hub) Publish(userIds []uint, e Event) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for _, id := range userIds {
+		for ch := range h.subs[id] {
+			select {
+			case ch <- e:
+			default:
+				log.Warn().Uint("user", id).Str("event", e.Type).Msg("message stream is falling behind, dropping event")
+			}
+		}
+	}
+}
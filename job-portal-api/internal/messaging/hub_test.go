@@ -0,0 +1,58 @@
+package messaging
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"job-portal-api/internal/models"
+)
+
+func TestHub(t *testing.T) {
+	h := NewHub()
+	tab1, close1 := h.Subscribe(9)
+	tab2, close2 := h.Subscribe(9)
+	other, closeOther := h.Subscribe(4)
+	defer closeOther()
+
+	msg := &models.Message{ID: 1, ConversationID: 3, SenderID: 1, Body: "Hi"}
+	h.Publish([]uint{1, 9}, Event{Type: EventMessage, ConversationID: 3, Message: msg})
+
+	// Every stream of a participant gets the event, others get nothing
+	require.Equal(t, msg, (<-tab1).Message)
+	require.Equal(t, msg, (<-tab2).Message)
+	require.Len(t, other, 0)
+
+	// Closed streams are dropped, closing twice is fine
+	close1()
+	close1()
+	_, open := <-tab1
+	require.False(t, open)
+	h.Publish([]uint{9}, Event{Type: EventRead, ConversationID: 3})
+	require.Equal(t, EventRead, (<-tab2).Type)
+
+	// A full stream misses events instead of blocking the sender
+	for i := 0; i < buffer+5; i++ {
+		h.Publish([]uint{9}, Event{Type: EventMessage, ConversationID: 3})
+	}
+	require.Len(t, tab2, buffer)
+	close2()
+	require.Empty(t, h.subs[9])
+}
+
+func TestHubClose(t *testing.T) {
+	h := NewHub()
+	tab, closeTab := h.Subscribe(9)
+	h.Close()
+
+	// Open streams end, and closing them afterwards is fine
+	_, open := <-tab
+	require.False(t, open)
+	closeTab()
+
+	late, closeLate := h.Subscribe(9)
+	defer closeLate()
+	_, open = <-late
+	require.False(t, open)
+	h.Publish([]uint{9}, Event{Type: EventRead})
+}
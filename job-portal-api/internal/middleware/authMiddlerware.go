@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"job-portal-api/internal/auth"
+	"job-portal-api/internal/models"
 	"net/http"
 	"strings"
 
@@ -59,6 +60,9 @@ func (m *Mid) Authenticate(next gin.HandlerFunc) gin.HandlerFunc {
 
 		// If the token is valid, then add it to the context
 		ctx = context.WithValue(ctx, auth.Key, claims)
+		actor, _ := models.AuditActorFrom(ctx)
+		actor.UserID = claims.Subject
+		ctx = models.WithAuditActor(ctx, actor)
 
 		// Creates a new request with the updated context and assign it back to the gin context
 		req := c.Request.WithContext(ctx)
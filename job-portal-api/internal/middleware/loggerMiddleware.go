@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"context"
+	"job-portal-api/internal/models"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -24,6 +25,14 @@ func (m *Mid) Log() gin.HandlerFunc {
 		// Add the trace id in context so it can be used by upcoming processes in this request's lifecycle
 		ctx = context.WithValue(ctx, TraceIdKey, traceId)
 
+		// Writes made while handling the request are attributed to it in the audit log;
+		// Authenticate adds the user once the token is checked
+		ctx = models.WithAuditActor(ctx, models.AuditActor{
+			TraceID:   traceId,
+			IP:        c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+		})
+
 		// The 'WithContext' method on 'c.Request' creates a new copy of the request ('req'),
 		// but with an updated context ('ctx') that contains our trace ID.
 		// The original request does not get changed by this; we're simply creating a new version of it ('req').
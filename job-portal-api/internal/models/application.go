@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ApplicationStatusSubmitted = "submitted"
+	ApplicationStatusWithdrawn = "withdrawn"
+	// ApplicationStatusRejected is set when a knockout question rejects the application
+	ApplicationStatusRejected = "rejected"
+	// ApplicationStatusOffered is set when the candidate is sent an offer, and left once they answer it
+	ApplicationStatusOffered       = "offered"
+	ApplicationStatusHired         = "hired"
+	ApplicationStatusOfferDeclined = "offer_declined"
+)
+
+// Application is a candidate applying to a job, optionally with one of their resumes attached.
+type Application struct {
+	gorm.Model
+	JobID            uint   `json:"job_id" gorm:"uniqueIndex:idx_application_job_user"`
+	UserID           uint   `json:"user_id" gorm:"uniqueIndex:idx_application_job_user"`
+	ResumeDocumentID *uint  `json:"resume_document_id"`
+	CoverLetter      string `json:"cover_letter"`
+	Status           string `json:"status"`
+	// StatusMessage tells the candidate why the application has its status
+	StatusMessage string `json:"status_message,omitempty"`
+	// Answers to the screening questions of the job
+	Answers []ApplicationAnswer `json:"answers,omitempty" gorm:"foreignKey:ApplicationID"`
+	// StageID is where the application is in its job's pipeline, empty for applications submitted before pipelines existed
+	StageID        *uint      `json:"stage_id,omitempty" gorm:"index"`
+	StageEnteredAt *time.Time `json:"stage_entered_at,omitempty"`
+}
+
+type NewApplication struct {
+	ResumeDocumentID *uint       `json:"resume_document_id"`
+	CoverLetter      string      `json:"cover_letter" validate:"max=10000"`
+	Answers          []NewAnswer `json:"answers" validate:"max=30,unique=QuestionID,dive"`
+}
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidResume is returned when an application references a document that is not one of the applicant's resumes.
+var ErrInvalidResume = errors.New("resume not found")
+
+// ErrJobNotOpen is returned when applying to a job that is not published.
+var ErrJobNotOpen = errors.New("job is not open for applications")
+
+// CreateApplication submits an application of the user to the given job.
+func (s *Conn) CreateApplication(ctx context.Context, na NewApplication, jobId uint, userId uint) (Application, error) {
+	app := Application{
+		JobID:            jobId,
+		UserID:           userId,
+		ResumeDocumentID: na.ResumeDocumentID,
+		CoverLetter:      na.CoverLetter,
+		Status:           ApplicationStatusSubmitted,
+	}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// The job has to exist and be listed
+		var job Job
+		err := tx.Select("id", "company_id", "status").First(&job, jobId).Error
+		if err != nil {
+			return err
+		}
+		if job.Status != JobPublished {
+			return ErrJobNotOpen
+		}
+
+		// An attached resume must belong to the applicant
+		if na.ResumeDocumentID != nil {
+			var doc Document
+			err = tx.Where("id = ? AND owner_id = ? AND kind = ?", *na.ResumeDocumentID, userId, DocumentKindResume).
+				First(&doc).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidResume
+			}
+			if err != nil {
+				return err
+			}
+		}
+		// Knockout questions reject the application right away
+		var qs []ScreeningQuestion
+		err = tx.Where("job_id = ?", jobId).Order("position").Find(&qs).Error
+		if err != nil {
+			return err
+		}
+		answers, knockout, err := screen(qs, na.Answers)
+		if err != nil {
+			return err
+		}
+		app.Answers = answers
+		if knockout != "" {
+			app.Status, app.StatusMessage = ApplicationStatusRejected, knockout
+		}
+
+		err = placeApplication(tx, &app, job, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+		err = tx.Create(&app).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Create(&ApplicationStageChange{ApplicationID: app.ID, ToStageID: *app.StageID}).Error
+		if err != nil {
+			return err
+		}
+		err = recordActivity(tx, JobActivity{JobID: job.ID, CompanyID: job.CompanyID, Kind: ActivityApply, ApplicationID: &app.ID})
+		if err != nil {
+			return err
+		}
+		return recordEvent(tx, AggregateApplication, app.ID, EventApplicationSubmitted, app)
+	})
+	if err != nil {
+		return Application{}, err
+	}
+	return app, nil
+}
+
+func (s *Conn) ViewApplication(ctx context.Context, applicationId uint) (Application, error) {
+	var app Application
+	err := s.db.WithContext(ctx).Preload("Answers").First(&app, applicationId).Error
+	if err != nil {
+		return Application{}, err
+	}
+	return app, nil
+}
+
+// ViewApplicationsByJob lists the applications received for a job matching the filter, oldest first.
+func (s *Conn) ViewApplicationsByJob(ctx context.Context, jobId uint, f ApplicationFilter) ([]Application, error) {
+	var apps = make([]Application, 0, 10)
+	err := s.db.WithContext(ctx).Preload("Answers").Scopes(filterApplications(f)).
+		Where("job_id = ?", jobId).Order("created_at").Find(&apps).Error
+	if err != nil {
+		return nil, err
+	}
+	return apps, nil
+}
+
+// ViewApplicationsByUser lists the applications a candidate has submitted, newest first.
+func (s *Conn) ViewApplicationsByUser(ctx context.Context, userId uint) ([]Application, error) {
+	var apps = make([]Application, 0, 10)
+	err := s.db.WithContext(ctx).Preload("Answers").Where("user_id = ?", userId).Order("created_at desc").Find(&apps).Error
+	if err != nil {
+		return nil, err
+	}
+	return apps, nil
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+// Audit actions.
+const (
+	AuditCreate = "create"
+	AuditUpdate = "update"
+	AuditDelete = "delete"
+)
+
+// AuditEntry records one mutation of one row. Entries are written by a gorm callback and never changed.
+type AuditEntry struct {
+	ID         uint                   `json:"id" gorm:"primarykey"`
+	ActorID    string                 `json:"actor_id" gorm:"index"`
+	Action     string                 `json:"action" gorm:"index"`
+	EntityType string                 `json:"entity_type" gorm:"index:idx_audit_entity,priority:1"`
+	EntityID   string                 `json:"entity_id" gorm:"index:idx_audit_entity,priority:2"`
+	Changes    map[string]AuditChange `json:"changes" gorm:"serializer:json"`
+	TraceID    string                 `json:"trace_id" gorm:"index"`
+	IP         string                 `json:"ip"`
+	UserAgent  string                 `json:"user_agent"`
+	CreatedAt  time.Time              `json:"created_at" gorm:"index"`
+}
+
+// AuditChange is the value of a column before and after the mutation.
+// From is missing for created rows and To for deleted ones.
+type AuditChange struct {
+	From any `json:"from,omitempty"`
+	To   any `json:"to,omitempty"`
+}
+
+// AuditFilter narrows down the audit log; empty fields match everything.
+type AuditFilter struct {
+	ActorID    string
+	Action     string
+	EntityType string
+	EntityID   string
+	TraceID    string
+	Since      time.Time
+	Until      time.Time
+	// BeforeID pages backwards through the log, it is the id of the last entry already seen.
+	BeforeID uint
+	Limit    int
+}
+
+// AuditSystem is the actor of the changes the system makes by itself, like background tasks and migrations.
+const AuditSystem = "system"
+
+// AuditActor is who made a change, and through which request.
+type AuditActor struct {
+	UserID    string
+	TraceID   string
+	IP        string
+	UserAgent string
+}
+
+type auditKey struct{}
+
+// WithAuditActor stores the actor in ctx, so every write done with ctx is attributed to them.
+func WithAuditActor(ctx context.Context, a AuditActor) context.Context {
+	return context.WithValue(ctx, auditKey{}, a)
+}
+
+// AuditActorFrom returns the actor stored in ctx, if any.
+func AuditActorFrom(ctx context.Context) (AuditActor, bool) {
+	a, ok := ctx.Value(auditKey{}).(AuditActor)
+	return a, ok
+}
@@ -0,0 +1,319 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// ErrAuditAppendOnly is returned when something tries to change or remove audit entries.
+var ErrAuditAppendOnly = errors.New("audit entries cannot be changed")
+
+// unaudited are the bookkeeping tables that change too often, and only on behalf of the system, to be worth auditing.
+var unaudited = map[reflect.Type]bool{
+	reflect.TypeOf(AuditEntry{}):      true,
+	reflect.TypeOf(Task{}):            true,
+	reflect.TypeOf(OutboxEvent{}):     true,
+	reflect.TypeOf(WebhookDelivery{}): true,
+	reflect.TypeOf(SentAlert{}):       true,
+	reflect.TypeOf(MatchScore{}):      true,
+	reflect.TypeOf(JobActivity{}):     true,
+	reflect.TypeOf(JobDailyStat{}):    true,
+}
+
+// redacted columns show up in the audit log as changed, without their values.
+var redacted = map[string]bool{
+	"password_hash":     true,
+	"secret":            true,
+	"unsubscribe_token": true,
+	"token_hash":        true,
+	"token":             true,
+}
+
+const auditBeforeKey = "audit:before"
+
+// registerAudit hooks the audit log into every create, update and delete going through db,
+// so nobody has to remember writing it. Entries are written in the same transaction as the change.
+func registerAudit(db *gorm.DB) error {
+	if db.Callback().Create().Get("audit:create") != nil {
+		return nil
+	}
+	err := db.Callback().Create().After("gorm:create").Register("audit:create", auditCreate)
+	if err != nil {
+		return err
+	}
+	err = db.Callback().Update().Before("gorm:update").Register("audit:before_update", auditBefore)
+	if err != nil {
+		return err
+	}
+	err = db.Callback().Update().After("gorm:update").Register("audit:update", auditUpdate)
+	if err != nil {
+		return err
+	}
+	err = db.Callback().Delete().Before("gorm:delete").Register("audit:before_delete", auditBefore)
+	if err != nil {
+		return err
+	}
+	return db.Callback().Delete().After("gorm:delete").Register("audit:delete", auditDelete)
+}
+
+func audited(stmt *gorm.Statement) bool {
+	return stmt.Schema != nil && !unaudited[stmt.Schema.ModelType]
+}
+
+func auditCreate(db *gorm.DB) {
+	stmt := db.Statement
+	// Inserts skipped by ON CONFLICT DO NOTHING created nothing
+	if db.Error != nil || db.RowsAffected == 0 || !audited(stmt) {
+		return
+	}
+	var entries []AuditEntry
+	eachRow(stmt.ReflectValue, func(rv reflect.Value) {
+		row := map[string]any{}
+		for _, f := range stmt.Schema.Fields {
+			if f.DBName == "" || !f.Readable {
+				continue
+			}
+			row[f.DBName], _ = f.ValueOf(stmt.Context, rv)
+		}
+		entries = append(entries, auditEntry(stmt, AuditCreate, row, diffRows(nil, row)))
+	})
+	writeAudit(db, entries)
+}
+
+// auditBefore keeps the rows about to be updated or deleted, to diff them afterwards.
+func auditBefore(db *gorm.DB) {
+	stmt := db.Statement
+	if stmt.Schema != nil && stmt.Schema.ModelType == reflect.TypeOf(AuditEntry{}) {
+		db.AddError(ErrAuditAppendOnly)
+		return
+	}
+	if db.Error != nil || !audited(stmt) {
+		return
+	}
+	rows, err := affectedRows(db)
+	if err != nil {
+		db.AddError(fmt.Errorf("reading rows for the audit log: %w", err))
+		return
+	}
+	stmt.Settings.Store(auditBeforeKey, rows)
+}
+
+func auditUpdate(db *gorm.DB) {
+	stmt := db.Statement
+	before := beforeRows(db)
+	pk := stmt.Schema.PrioritizedPrimaryField
+	if db.Error != nil || len(before) == 0 || pk == nil {
+		return
+	}
+	ids := make([]any, 0, len(before))
+	for _, row := range before {
+		ids = append(ids, row[pk.DBName])
+	}
+	var after []map[string]any
+	err := db.Session(&gorm.Session{NewDB: true}).Table(stmt.Table).
+		Where(clause.IN{Column: clause.Column{Name: pk.DBName}, Values: ids}).Find(&after).Error
+	if err != nil {
+		db.AddError(fmt.Errorf("reading rows for the audit log: %w", err))
+		return
+	}
+	afterByID := make(map[string]map[string]any, len(after))
+	for _, row := range after {
+		afterByID[fmt.Sprint(row[pk.DBName])] = row
+	}
+
+	var entries []AuditEntry
+	for _, row := range before {
+		changes := diffRows(row, afterByID[fmt.Sprint(row[pk.DBName])])
+		delete(changes, "updated_at")
+		if len(changes) > 0 {
+			entries = append(entries, auditEntry(stmt, AuditUpdate, row, changes))
+		}
+	}
+	writeAudit(db, entries)
+}
+
+func auditDelete(db *gorm.DB) {
+	before := beforeRows(db)
+	if db.Error != nil {
+		return
+	}
+	entries := make([]AuditEntry, 0, len(before))
+	for _, row := range before {
+		entries = append(entries, auditEntry(db.Statement, AuditDelete, row, diffRows(row, nil)))
+	}
+	writeAudit(db, entries)
+}
+
+func beforeRows(db *gorm.DB) []map[string]any {
+	v, ok := db.Statement.Settings.Load(auditBeforeKey)
+	if !ok {
+		return nil
+	}
+	rows, _ := v.([]map[string]any)
+	return rows
+}
+
+// affectedRows loads the rows an update or delete is going to touch: the model passed in, or whatever its conditions match.
+func affectedRows(db *gorm.DB) ([]map[string]any, error) {
+	stmt := db.Statement
+	q := db.Session(&gorm.Session{NewDB: true}).Table(stmt.Table)
+	conds := 0
+	if c, ok := stmt.Clauses["WHERE"]; ok {
+		if w, ok := c.Expression.(clause.Where); ok && len(w.Exprs) > 0 {
+			q = q.Clauses(w)
+			conds++
+		}
+	}
+	if rv := reflect.Indirect(stmt.ReflectValue); rv.Kind() == reflect.Struct {
+		for _, f := range stmt.Schema.PrimaryFields {
+			if v, zero := f.ValueOf(stmt.Context, rv); !zero {
+				q = q.Where(clause.Eq{Column: clause.Column{Name: f.DBName}, Value: v})
+				conds++
+			}
+		}
+	}
+	if conds == 0 {
+		// gorm refuses updates and deletes without conditions
+		return nil, nil
+	}
+	if f := stmt.Schema.LookUpField("DeletedAt"); f != nil && !stmt.Unscoped {
+		q = q.Where(clause.Eq{Column: clause.Column{Name: f.DBName}, Value: nil})
+	}
+	var rows []map[string]any
+	err := q.Find(&rows).Error
+	return rows, err
+}
+
+func eachRow(v reflect.Value, fn func(rv reflect.Value)) {
+	rv := reflect.Indirect(v)
+	switch rv.Kind() {
+	case reflect.Struct:
+		fn(rv)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if elem := reflect.Indirect(rv.Index(i)); elem.Kind() == reflect.Struct {
+				fn(elem)
+			}
+		}
+	}
+}
+
+// diffRows lists the columns whose value differs between the two rows; a nil row stands for a row that does not exist.
+func diffRows(from, to map[string]any) map[string]AuditChange {
+	changes := map[string]AuditChange{}
+	for col, v := range to {
+		if from == nil {
+			if !isNull(v) {
+				changes[col] = AuditChange{To: auditValue(col, v)}
+			}
+			continue
+		}
+		if old := from[col]; !sameValue(old, v) {
+			changes[col] = AuditChange{From: auditValue(col, old), To: auditValue(col, v)}
+		}
+	}
+	if to == nil {
+		for col, v := range from {
+			if !isNull(v) {
+				changes[col] = AuditChange{From: auditValue(col, v)}
+			}
+		}
+	}
+	return changes
+}
+
+func auditValue(col string, v any) any {
+	if redacted[col] {
+		return "[redacted]"
+	}
+	if b, ok := v.([]byte); ok {
+		return string(b)
+	}
+	return v
+}
+
+func isNull(v any) bool {
+	b, err := json.Marshal(auditValue("", v))
+	return err == nil && string(b) == "null"
+}
+
+func sameValue(a, b any) bool {
+	ja, erra := json.Marshal(auditValue("", a))
+	jb, errb := json.Marshal(auditValue("", b))
+	return erra == nil && errb == nil && string(ja) == string(jb)
+}
+
+func auditEntry(stmt *gorm.Statement, action string, row map[string]any, changes map[string]AuditChange) AuditEntry {
+	a, _ := AuditActorFrom(stmt.Context)
+	return AuditEntry{
+		ActorID:    a.UserID,
+		Action:     action,
+		EntityType: stmt.Table,
+		EntityID:   primaryKey(stmt.Schema, row),
+		Changes:    changes,
+		TraceID:    a.TraceID,
+		IP:         a.IP,
+		UserAgent:  a.UserAgent,
+	}
+}
+
+func primaryKey(s *schema.Schema, row map[string]any) string {
+	parts := make([]string, 0, len(s.PrimaryFieldDBNames))
+	for _, col := range s.PrimaryFieldDBNames {
+		parts = append(parts, fmt.Sprint(row[col]))
+	}
+	return strings.Join(parts, ",")
+}
+
+func writeAudit(db *gorm.DB, entries []AuditEntry) {
+	if len(entries) == 0 {
+		return
+	}
+	err := db.Session(&gorm.Session{NewDB: true}).Create(&entries).Error
+	if err != nil {
+		db.AddError(fmt.Errorf("writing audit log: %w", err))
+	}
+}
+
+// ViewAuditLog lists audit entries matching the filter, newest first.
+func (s *Conn) ViewAuditLog(ctx context.Context, f AuditFilter) ([]AuditEntry, error) {
+	q := s.db.WithContext(ctx).Model(&AuditEntry{})
+	if f.ActorID != "" {
+		q = q.Where("actor_id = ?", f.ActorID)
+	}
+	if f.Action != "" {
+		q = q.Where("action = ?", f.Action)
+	}
+	if f.EntityType != "" {
+		q = q.Where("entity_type = ?", f.EntityType)
+	}
+	if f.EntityID != "" {
+		q = q.Where("entity_id = ?", f.EntityID)
+	}
+	if f.TraceID != "" {
+		q = q.Where("trace_id = ?", f.TraceID)
+	}
+	if !f.Since.IsZero() {
+		q = q.Where("created_at >= ?", f.Since)
+	}
+	if !f.Until.IsZero() {
+		q = q.Where("created_at < ?", f.Until)
+	}
+	if f.BeforeID > 0 {
+		q = q.Where("id < ?", f.BeforeID)
+	}
+	var entries = make([]AuditEntry, 0, f.Limit)
+	err := q.Order("id desc").Limit(f.Limit).Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Bookmark is a job a candidate saved for later.
+type Bookmark struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at"`
+	UserID    uint      `json:"user_id" gorm:"uniqueIndex:idx_bookmark;not null"`
+	JobID     uint      `json:"job_id" gorm:"uniqueIndex:idx_bookmark;index"`
+}
+
+// CompanyFollow is a candidate following a company, to hear about the jobs it posts.
+type CompanyFollow struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at"`
+	UserID    uint      `json:"user_id" gorm:"uniqueIndex:idx_company_follow;not null"`
+	CompanyID uint      `json:"company_id" gorm:"uniqueIndex:idx_company_follow;index"`
+}
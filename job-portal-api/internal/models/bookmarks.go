@@ -0,0 +1,131 @@
+package models
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BookmarkJob saves a listed job for the candidate. Bookmarking a job twice keeps the first bookmark.
+func (s *Conn) BookmarkJob(ctx context.Context, userId, jobId uint) (Bookmark, error) {
+	var b Bookmark
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var job Job
+		err := tx.Scopes(listed).Select("id").First(&job, jobId).Error
+		if err != nil {
+			return err
+		}
+		b = Bookmark{UserID: userId, JobID: job.ID}
+		err = tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "user_id"}, {Name: "job_id"}}, DoNothing: true}).
+			Create(&b).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("user_id = ? AND job_id = ?", userId, jobId).First(&b).Error
+	})
+	if err != nil {
+		return Bookmark{}, err
+	}
+	return b, nil
+}
+
+// DeleteBookmark removes the candidate's bookmark of the job.
+func (s *Conn) DeleteBookmark(ctx context.Context, userId, jobId uint) error {
+	res := s.db.WithContext(ctx).Where("user_id = ? AND job_id = ?", userId, jobId).Delete(&Bookmark{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ViewBookmarkedJobs lists the jobs the candidate bookmarked, the last bookmarked first.
+// Jobs that stopped being listed are kept with their status so the candidate can tell.
+func (s *Conn) ViewBookmarkedJobs(ctx context.Context, userId uint) ([]Job, error) {
+	var jobs = make([]Job, 0, 10)
+	err := s.db.WithContext(ctx).Joins("JOIN bookmarks ON bookmarks.job_id = jobs.id").
+		Where("bookmarks.user_id = ?", userId).Order("bookmarks.id desc").Find(&jobs).Error
+	if err != nil {
+		return nil, err
+	}
+	bookmarked := true
+	for i := range jobs {
+		jobs[i].Bookmarked = &bookmarked
+	}
+	return jobs, nil
+}
+
+// BookmarkedJobIDs returns which of the given jobs the candidate bookmarked.
+func (s *Conn) BookmarkedJobIDs(ctx context.Context, userId uint, jobIds []uint) ([]uint, error) {
+	var ids []uint
+	if len(jobIds) == 0 {
+		return ids, nil
+	}
+	err := s.db.WithContext(ctx).Model(&Bookmark{}).Where("user_id = ? AND job_id IN ?", userId, jobIds).
+		Pluck("job_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// FollowCompany makes the candidate follow the company. Following a company twice keeps the first follow.
+func (s *Conn) FollowCompany(ctx context.Context, userId, companyId uint) (CompanyFollow, error) {
+	var f CompanyFollow
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var company Company
+		err := tx.Select("id").First(&company, companyId).Error
+		if err != nil {
+			return err
+		}
+		f = CompanyFollow{UserID: userId, CompanyID: company.ID}
+		err = tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "user_id"}, {Name: "company_id"}}, DoNothing: true}).
+			Create(&f).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("user_id = ? AND company_id = ?", userId, companyId).First(&f).Error
+	})
+	if err != nil {
+		return CompanyFollow{}, err
+	}
+	return f, nil
+}
+
+// UnfollowCompany stops the candidate following the company.
+func (s *Conn) UnfollowCompany(ctx context.Context, userId, companyId uint) error {
+	res := s.db.WithContext(ctx).Where("user_id = ? AND company_id = ?", userId, companyId).Delete(&CompanyFollow{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ViewFollowedCompanies lists the companies the candidate follows, the last followed first.
+func (s *Conn) ViewFollowedCompanies(ctx context.Context, userId uint) ([]Company, error) {
+	var companies = make([]Company, 0, 10)
+	err := s.db.WithContext(ctx).Joins("JOIN company_follows ON company_follows.company_id = companies.id").
+		Where("company_follows.user_id = ?", userId).Order("company_follows.id desc").Find(&companies).Error
+	if err != nil {
+		return nil, err
+	}
+	return companies, nil
+}
+
+// CompanyFollowers lists the users following the company, with their name and email only.
+func (s *Conn) CompanyFollowers(ctx context.Context, companyId uint) ([]User, error) {
+	var users = make([]User, 0, 10)
+	err := s.db.WithContext(ctx).Select("users.id", "users.name", "users.email").
+		Joins("JOIN company_follows ON company_follows.user_id = users.id").
+		Where("company_follows.company_id = ?", companyId).Order("users.id").Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CandidateSearch holds the filters recruiters search discoverable candidates with. Empty filters match everyone.
+type CandidateSearch struct {
+	// Keywords are looked up in the headline and summary of the profile
+	Keywords string
+	// Skills must all be on the profile
+	Skills   []string
+	Location string
+	Role     string
+}
+
+// Candidate is a discoverable profile found by a search, with the name of its user.
+type Candidate struct {
+	Name string `json:"name"`
+	// ExperienceYears is left for the caller to fill in, it depends on the day the search runs
+	ExperienceYears float64 `json:"experience_years"`
+	Profile
+}
+
+// TalentPool is a named list of candidates a company keeps to reach out to later.
+type TalentPool struct {
+	gorm.Model
+	CompanyID   uint   `json:"company_id" gorm:"uniqueIndex:idx_talent_pool_company_name;not null"`
+	Name        string `json:"name" gorm:"uniqueIndex:idx_talent_pool_company_name"`
+	Description string `json:"description"`
+	CreatedBy   uint   `json:"created_by"`
+	// Size is the number of candidates in the pool, filled in when listing pools
+	Size int64 `json:"size" gorm:"-"`
+}
+
+type NewTalentPool struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	Description string `json:"description" validate:"max=1000"`
+}
+
+// PoolMember is a candidate saved into a talent pool, with the tags and notes of the recruiters.
+type PoolMember struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	PoolID    uint      `json:"pool_id" gorm:"uniqueIndex:idx_pool_member;not null"`
+	UserID    uint      `json:"user_id" gorm:"uniqueIndex:idx_pool_member;index"`
+	Tags      []string  `json:"tags" gorm:"serializer:json"`
+	Notes     string    `json:"notes"`
+	AddedBy   uint      `json:"added_by"`
+}
+
+// NewPoolMember is the payload saving a candidate into a pool, or replacing their tags and notes.
+type NewPoolMember struct {
+	Tags  []string `json:"tags" validate:"max=20,dive,required,max=50"`
+	Notes string   `json:"notes" validate:"max=5000"`
+}
+
+// PoolCandidate is a member of a pool as recruiters list it, with the name and headline of the candidate.
+type PoolCandidate struct {
+	PoolMember
+	Name     string `json:"name"`
+	Headline string `json:"headline"`
+}
+
+// JobInvitation records a recruiter inviting a candidate to apply to one of the company's jobs.
+// A candidate is invited to a job at most once.
+type JobInvitation struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at"`
+	JobID     uint      `json:"job_id" gorm:"uniqueIndex:idx_job_invitation;not null"`
+	UserID    uint      `json:"user_id" gorm:"uniqueIndex:idx_job_invitation;index"`
+	InvitedBy uint      `json:"invited_by"`
+	Message   string    `json:"message"`
+}
+
+type NewJobInvitation struct {
+	JobID   uint   `json:"job_id" validate:"required"`
+	Message string `json:"message" validate:"max=2000"`
+}
@@ -0,0 +1,305 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrCandidateNotFound is returned for candidates the company cannot see: they are not discoverable
+	// and never applied to one of its jobs.
+	ErrCandidateNotFound = errors.New("candidate not found")
+	// ErrCandidateApplied is returned when inviting a candidate to a job they already applied to.
+	ErrCandidateApplied = errors.New("candidate already applied to the job")
+)
+
+// contains builds an ILIKE pattern matching s anywhere, with the wildcards of s escaped.
+func contains(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + r.Replace(strings.TrimSpace(s)) + "%"
+}
+
+// SearchCandidates finds up to limit discoverable profiles matching q, the most recently updated first.
+// Roles and locations are compared to what candidates wish for, each keyword has to be in the headline or summary.
+func (s *Conn) SearchCandidates(ctx context.Context, q CandidateSearch, limit int) ([]Candidate, error) {
+	db := s.db.WithContext(ctx).Where("discoverable")
+	if skills := NormalizeSkills(q.Skills); len(skills) > 0 {
+		b, err := json.Marshal(skills)
+		if err != nil {
+			return nil, err
+		}
+		db = db.Where("skills::jsonb @> ?::jsonb", string(b))
+	}
+	for _, kw := range strings.Fields(q.Keywords) {
+		db = db.Where("(headline ILIKE ? OR summary ILIKE ?)", contains(kw), contains(kw))
+	}
+	if strings.TrimSpace(q.Location) != "" {
+		db = db.Where("desired_locations ILIKE ?", contains(q.Location))
+	}
+	if strings.TrimSpace(q.Role) != "" {
+		db = db.Where("(desired_roles ILIKE ? OR headline ILIKE ?)", contains(q.Role), contains(q.Role))
+	}
+
+	var ps []Profile
+	err := db.Preload("Experiences").Order("updated_at desc").Limit(limit).Find(&ps).Error
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uint, 0, len(ps))
+	for _, p := range ps {
+		ids = append(ids, p.UserID)
+	}
+	var users []User
+	if len(ids) > 0 {
+		err = s.db.WithContext(ctx).Select("id", "name").Where("id IN ?", ids).Find(&users).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+	names := make(map[uint]string, len(users))
+	for _, u := range users {
+		names[u.ID] = u.Name
+	}
+
+	cs := make([]Candidate, 0, len(ps))
+	for _, p := range ps {
+		cs = append(cs, Candidate{Name: names[p.UserID], Profile: p})
+	}
+	return cs, nil
+}
+
+// candidateVisible makes sure the company can see the candidate: they are discoverable or applied to one of its jobs.
+func candidateVisible(tx *gorm.DB, companyId, userId uint) error {
+	var n int64
+	err := tx.Model(&Profile{}).Where("user_id = ? AND discoverable", userId).Count(&n).Error
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return nil
+	}
+	err = tx.Model(&Application{}).Joins("JOIN jobs ON jobs.id = applications.job_id").
+		Where("applications.user_id = ? AND jobs.company_id = ?", userId, companyId).Count(&n).Error
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrCandidateNotFound
+	}
+	return nil
+}
+
+// ViewCandidateProfile returns the profile of a candidate as seen by another user. Only discoverable profiles
+// are shown, except to members of a company the candidate applied to.
+func (s *Conn) ViewCandidateProfile(ctx context.Context, viewerId, userId uint) (Profile, error) {
+	p, err := s.ViewProfile(ctx, userId)
+	if err != nil {
+		return Profile{}, err
+	}
+	if p.Discoverable || viewerId == userId {
+		return p, nil
+	}
+	var n int64
+	err = s.db.WithContext(ctx).Model(&Application{}).
+		Joins("JOIN jobs ON jobs.id = applications.job_id").
+		Joins("JOIN memberships ON memberships.company_id = jobs.company_id AND memberships.deleted_at IS NULL").
+		Where("applications.user_id = ? AND memberships.user_id = ?", userId, viewerId).Count(&n).Error
+	if err != nil {
+		return Profile{}, err
+	}
+	if n == 0 {
+		return Profile{}, ErrCandidateNotFound
+	}
+	return p, nil
+}
+
+// CreateTalentPool adds a talent pool to the company. Pool names are unique within a company.
+func (s *Conn) CreateTalentPool(ctx context.Context, companyId, createdBy uint, np NewTalentPool) (TalentPool, error) {
+	pool := TalentPool{CompanyID: companyId, Name: np.Name, Description: np.Description, CreatedBy: createdBy}
+	err := s.db.WithContext(ctx).Create(&pool).Error
+	if err != nil {
+		return TalentPool{}, err
+	}
+	return pool, nil
+}
+
+// ViewTalentPools lists the talent pools of the company by name, with the number of candidates in each.
+func (s *Conn) ViewTalentPools(ctx context.Context, companyId uint) ([]TalentPool, error) {
+	var pools = make([]TalentPool, 0, 10)
+	err := s.db.WithContext(ctx).Where("company_id = ?", companyId).Order("name").Find(&pools).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(pools) == 0 {
+		return pools, nil
+	}
+	ids := make([]uint, 0, len(pools))
+	for _, p := range pools {
+		ids = append(ids, p.ID)
+	}
+	var sizes []struct {
+		PoolID uint
+		N      int64
+	}
+	err = s.db.WithContext(ctx).Model(&PoolMember{}).Select("pool_id, count(*) AS n").
+		Where("pool_id IN ?", ids).Group("pool_id").Scan(&sizes).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, sz := range sizes {
+		for i := range pools {
+			if pools[i].ID == sz.PoolID {
+				pools[i].Size = sz.N
+			}
+		}
+	}
+	return pools, nil
+}
+
+func (s *Conn) ViewTalentPool(ctx context.Context, poolId uint) (TalentPool, error) {
+	var pool TalentPool
+	err := s.db.WithContext(ctx).First(&pool, poolId).Error
+	if err != nil {
+		return TalentPool{}, err
+	}
+	return pool, nil
+}
+
+// UpdateTalentPool renames a talent pool and replaces its description.
+func (s *Conn) UpdateTalentPool(ctx context.Context, poolId uint, np NewTalentPool) (TalentPool, error) {
+	var pool TalentPool
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.First(&pool, poolId).Error
+		if err != nil {
+			return err
+		}
+		pool.Name, pool.Description = np.Name, np.Description
+		return tx.Model(&pool).Select("name", "description").Updates(&pool).Error
+	})
+	if err != nil {
+		return TalentPool{}, err
+	}
+	return pool, nil
+}
+
+// DeleteTalentPool removes a talent pool and the candidates saved in it.
+// Pools are deleted for good so their name can be used again.
+func (s *Conn) DeleteTalentPool(ctx context.Context, poolId uint) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("pool_id = ?", poolId).Delete(&PoolMember{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&TalentPool{}, poolId).Error
+	})
+}
+
+// SavePoolMember saves the candidate into the pool, or replaces their tags and notes when they are in it already.
+// Tags are normalized like skills so that they can be filtered on.
+func (s *Conn) SavePoolMember(ctx context.Context, poolId, userId, addedBy uint, nm NewPoolMember) (PoolMember, error) {
+	var m PoolMember
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var pool TalentPool
+		err := tx.First(&pool, poolId).Error
+		if err != nil {
+			return err
+		}
+		err = candidateVisible(tx, pool.CompanyID, userId)
+		if err != nil {
+			return err
+		}
+		err = tx.Where("pool_id = ? AND user_id = ?", poolId, userId).First(&m).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			m = PoolMember{PoolID: poolId, UserID: userId, AddedBy: addedBy}
+		} else if err != nil {
+			return err
+		}
+		m.Tags, m.Notes = NormalizeSkills(nm.Tags), nm.Notes
+		return tx.Save(&m).Error
+	})
+	if err != nil {
+		return PoolMember{}, err
+	}
+	return m, nil
+}
+
+// ViewPoolCandidates lists the candidates of the pool, the last saved first. A non-empty tag keeps those tagged with it.
+func (s *Conn) ViewPoolCandidates(ctx context.Context, poolId uint, tag string) ([]PoolCandidate, error) {
+	db := s.db.WithContext(ctx).Model(&PoolMember{}).
+		Select("pool_members.*, users.name, profiles.headline").
+		Joins("JOIN users ON users.id = pool_members.user_id").
+		Joins("LEFT JOIN profiles ON profiles.user_id = pool_members.user_id AND profiles.deleted_at IS NULL").
+		Where("pool_members.pool_id = ?", poolId)
+	if tags := NormalizeSkills([]string{tag}); len(tags) > 0 {
+		b, err := json.Marshal(tags)
+		if err != nil {
+			return nil, err
+		}
+		db = db.Where("pool_members.tags::jsonb @> ?::jsonb", string(b))
+	}
+	var cs = make([]PoolCandidate, 0, 20)
+	err := db.Order("pool_members.id desc").Find(&cs).Error
+	if err != nil {
+		return nil, err
+	}
+	return cs, nil
+}
+
+// DeletePoolMember takes the candidate out of the pool.
+func (s *Conn) DeletePoolMember(ctx context.Context, poolId, userId uint) error {
+	res := s.db.WithContext(ctx).Where("pool_id = ? AND user_id = ?", poolId, userId).Delete(&PoolMember{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// CreateJobInvitation records the candidate being invited to apply to a published job of the company.
+// Jobs of other companies are not found, and a candidate is only invited once to a job they have not applied to.
+func (s *Conn) CreateJobInvitation(ctx context.Context, companyId, userId, invitedBy uint, ni NewJobInvitation) (JobInvitation, error) {
+	inv := JobInvitation{JobID: ni.JobID, UserID: userId, InvitedBy: invitedBy, Message: ni.Message}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var job Job
+		err := tx.Select("id", "company_id", "status").Where("company_id = ?", companyId).First(&job, ni.JobID).Error
+		if err != nil {
+			return err
+		}
+		if job.Status != JobPublished {
+			return ErrJobNotOpen
+		}
+		err = candidateVisible(tx, companyId, userId)
+		if err != nil {
+			return err
+		}
+		var n int64
+		err = tx.Model(&Application{}).Where("job_id = ? AND user_id = ?", job.ID, userId).Count(&n).Error
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			return ErrCandidateApplied
+		}
+		return tx.Create(&inv).Error
+	})
+	if err != nil {
+		return JobInvitation{}, err
+	}
+	return inv, nil
+}
+
+// ViewJobInvitationsByUser lists the jobs the candidate was invited to apply to, newest first.
+func (s *Conn) ViewJobInvitationsByUser(ctx context.Context, userId uint) ([]JobInvitation, error) {
+	var invs = make([]JobInvitation, 0, 5)
+	err := s.db.WithContext(ctx).Where("user_id = ?", userId).Order("id desc").Find(&invs).Error
+	if err != nil {
+		return nil, err
+	}
+	return invs, nil
+}
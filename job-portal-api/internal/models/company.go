@@ -2,6 +2,10 @@ package models
 
 import (
 	"context"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
 )
 
 // Define the function CreatInventory, which belongs to the struct 'Conn'.
@@ -15,9 +19,27 @@ func (d *Conn) CreateCompany(ctx context.Context, ni NewCompany, userId int) (Co
 		CompanyName: ni.CompanyName,
 		FoundedYear: ni.FoundedYear,
 		Location:    ni.Location,
+		Description: ni.Description,
+		Website:     ni.Website,
+		Size:        ni.Size,
+		Industry:    ni.Industry,
+		Benefits:    trimAll(ni.Benefits),
+		TechStack:   NormalizeSkills(ni.TechStack),
+		GeoLocation: locate(ni.Location),
 		//Jobs:        ni.Jobs,
 	}
-	err := d.db.Create(&cmp).Error
+	err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(&cmp).Error
+		if err != nil {
+			return err
+		}
+		// Whoever creates the company owns it
+		err = tx.Create(&Membership{CompanyID: cmp.ID, UserID: uint(userId), Role: RoleOwner}).Error
+		if err != nil {
+			return err
+		}
+		return recordEvent(tx, AggregateCompany, cmp.ID, EventCompanyCreated, cmp)
+	})
 	if err != nil {
 		return Company{}, err
 	}
@@ -27,17 +49,176 @@ func (d *Conn) CreateCompany(ctx context.Context, ni NewCompany, userId int) (Co
 
 }
 
+// UpdateCompany replaces the details and profile of a company.
+func (s *Conn) UpdateCompany(ctx context.Context, companyId uint, nc NewCompany) (Company, error) {
+	var cmp Company
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.First(&cmp, companyId).Error
+		if err != nil {
+			return err
+		}
+		cmp.CompanyName, cmp.FoundedYear, cmp.Location = nc.CompanyName, nc.FoundedYear, nc.Location
+		cmp.Description, cmp.Website, cmp.Size, cmp.Industry = nc.Description, nc.Website, nc.Size, nc.Industry
+		cmp.Benefits, cmp.TechStack = trimAll(nc.Benefits), NormalizeSkills(nc.TechStack)
+		cmp.GeoLocation = locate(nc.Location)
+		err = tx.Model(&cmp).Select("company_name", "founded_year", "location", "description", "website", "size",
+			"industry", "benefits", "tech_stack", "city", "region", "country", "latitude", "longitude", "located_at").Updates(&cmp).Error
+		if err != nil {
+			return err
+		}
+		// Jobs without a location of their own are where the company is
+		return tx.Model(&Job{}).Where("company_id = ? AND COALESCE(TRIM(location), '') = ''", companyId).
+			Updates(cmp.GeoLocation.columns()).Error
+	})
+	if err != nil {
+		return Company{}, err
+	}
+	return cmp, nil
+}
+
+// trimAll trims the entries of a list and drops the empty ones.
+func trimAll(list []string) []string {
+	out := make([]string, 0, len(list))
+	for _, v := range list {
+		v = strings.TrimSpace(v)
+		if v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
 func (s *Conn) CreateJob(ctx context.Context, job Job, userId string) (Job, error) {
 	// Create a new 'Inventory' struct named 'inv'.
 	// Initialize it with parameters from the 'NewInventory' struct and the `userId` passed to the function.
-	result := s.db.Create(&job)
-	if result.Error != nil {
-		return Job{}, result.Error
+	job.Skills = NormalizeSkills(job.Skills)
+	event, err := job.start(time.Now().UTC())
+	if err != nil {
+		return Job{}, err
+	}
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var location string
+		if strings.TrimSpace(job.Location) == "" {
+			loc, err := companyLocation(tx, job.CompanyID)
+			if err != nil {
+				return err
+			}
+			location = loc
+		}
+		job.locate(location)
+		err := tx.Create(&job).Error
+		if err != nil {
+			return err
+		}
+		if event == "" {
+			return nil
+		}
+		return recordEvent(tx, AggregateJob, job.ID, event, job)
+	})
+	if err != nil {
+		return Job{}, err
 	}
 	return job, nil
 
 }
 
+// CreateJobs posts many jobs of a company at once, all of them or none.
+func (s *Conn) CreateJobs(ctx context.Context, companyId uint, njs []NewJob) ([]Job, error) {
+	now := time.Now().UTC()
+	jobs := make([]Job, 0, len(njs))
+	events := make([]string, 0, len(njs))
+	for _, nj := range njs {
+		job := Job{
+			Title:              nj.Title,
+			ExperienceLevel:    nj.ExperienceLevel,
+			CompanyID:          companyId,
+			Description:        nj.Description,
+			Location:           nj.Location,
+			Skills:             NormalizeSkills(nj.Skills),
+			MinExperienceYears: nj.MinExperienceYears,
+			SalaryMin:          nj.SalaryMin,
+			SalaryMax:          nj.SalaryMax,
+			SalaryCurrency:     nj.SalaryCurrency,
+			SalaryPeriod:       nj.SalaryPeriod,
+			Status:             nj.Status,
+		}
+		event, err := job.start(now)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+		events = append(events, event)
+	}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		location, err := companyLocation(tx, companyId)
+		if err != nil {
+			return err
+		}
+		for i := range jobs {
+			jobs[i].locate(location)
+		}
+		err = tx.CreateInBatches(&jobs, 100).Error
+		if err != nil {
+			return err
+		}
+		for i, job := range jobs {
+			if events[i] == "" {
+				continue
+			}
+			err = recordEvent(tx, AggregateJob, job.ID, events[i], job)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// EachCompanyJob calls fn with every job of the company, oldest first, loading them in batches.
+func (s *Conn) EachCompanyJob(ctx context.Context, companyId uint, fn func(job Job) error) error {
+	var batch []Job
+	return s.db.WithContext(ctx).Where("company_id = ?", companyId).
+		FindInBatches(&batch, 500, func(tx *gorm.DB, n int) error {
+			for _, job := range batch {
+				err := fn(job)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
+
+// FeedJobs pages through published jobs in the order they were last updated, starting after the job
+// afterId updated at since; a zero since starts at the beginning.
+func (s *Conn) FeedJobs(ctx context.Context, since time.Time, afterId uint, limit int) ([]Job, error) {
+	var jobs = make([]Job, 0, limit)
+	err := s.db.WithContext(ctx).Scopes(listed).
+		Where("updated_at > ? OR (updated_at = ? AND id > ?)", since, since, afterId).
+		Order("updated_at, id").Limit(limit).Find(&jobs).Error
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// ViewCompanies loads the companies with the given ids.
+func (s *Conn) ViewCompanies(ctx context.Context, companyIds []uint) ([]Company, error) {
+	var companies []Company
+	if len(companyIds) == 0 {
+		return companies, nil
+	}
+	err := s.db.WithContext(ctx).Where("id IN ?", companyIds).Find(&companies).Error
+	if err != nil {
+		return nil, err
+	}
+	return companies, nil
+}
+
 func (s *Conn) ViewCompanyAll(ctx context.Context, companyId string) ([]Company, error) {
 	var cmp = make([]Company, 0, 10)
 	tx := s.db.Find(&cmp)
@@ -59,7 +240,7 @@ func (s *Conn) ViewCompany(ctx context.Context, companyID uint, UserId string) (
 
 func (s *Conn) ViewJobByCompId(ctx context.Context, companyID uint, UserId string) ([]Job, error) {
 	var job []Job
-	result := s.db.Where("company_id = ?", companyID).Find(&job)
+	result := s.db.Scopes(listed).Where("company_id = ?", companyID).Find(&job)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -77,7 +258,7 @@ func (s *Conn) ViewJobByJobId(ctx context.Context, jobID uint, UserId string) ([
 
 func (s *Conn) ViewJob(ctx context.Context, userId string) ([]Job, error) {
 	var cmp = make([]Job, 0, 10)
-	tx := s.db.Find(&cmp)
+	tx := s.db.Scopes(listed).Find(&cmp)
 	err := tx.Find(&cmp).Error
 	if err != nil {
 		return nil, err
@@ -87,7 +268,7 @@ func (s *Conn) ViewJob(ctx context.Context, userId string) ([]Job, error) {
 
 func (s *Conn) ViewJobAll(ctx context.Context, companyId string) ([]Job, error) {
 	var job = make([]Job, 0, 10)
-	tx := s.db.Find(&job)
+	tx := s.db.Scopes(listed).Find(&job)
 	err := tx.Find(&job).Error
 	if err != nil {
 		return nil, err
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MessagingRole is the least role of a company member taking part in the conversations about its applications.
+const MessagingRole = RoleRecruiter
+
+// Conversation is the message thread between a candidate and the company about one application.
+type Conversation struct {
+	gorm.Model
+	ApplicationID uint       `json:"application_id" gorm:"uniqueIndex;not null"`
+	CompanyID     uint       `json:"company_id" gorm:"index"`
+	CandidateID   uint       `json:"candidate_id" gorm:"index"`
+	LastMessageAt *time.Time `json:"last_message_at,omitempty"`
+	// Reads are the read receipts of everyone who has opened the thread
+	Reads []ConversationRead `json:"reads,omitempty"`
+	// Unread is how many messages from others the user listing their conversations has not read
+	Unread int64 `json:"unread" gorm:"-"`
+}
+
+// Message is one message in a conversation, optionally with a file attached.
+type Message struct {
+	ID             uint      `json:"id" gorm:"primarykey"`
+	CreatedAt      time.Time `json:"created_at"`
+	ConversationID uint      `json:"conversation_id" gorm:"index;not null"`
+	SenderID       uint      `json:"sender_id"`
+	Body           string    `json:"body"`
+	AttachmentID   *uint     `json:"attachment_id,omitempty"`
+}
+
+// ConversationRead is a read receipt: how far a participant has read a conversation.
+type ConversationRead struct {
+	ConversationID    uint      `json:"conversation_id" gorm:"primaryKey"`
+	UserID            uint      `json:"user_id" gorm:"primaryKey"`
+	LastReadMessageID uint      `json:"last_read_message_id"`
+	ReadAt            time.Time `json:"read_at"`
+}
+
+// NewMessage is the payload accepted when sending a message. The attachment is a document
+// uploaded by the sender for the conversation; a message needs a body, an attachment or both.
+type NewMessage struct {
+	Body         string `json:"body" validate:"required_without=AttachmentID,max=5000"`
+	AttachmentID *uint  `json:"attachment_id"`
+}
@@ -0,0 +1,181 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrInvalidAttachment is returned when a message references a document that is not an attachment uploaded by its sender.
+var ErrInvalidAttachment = errors.New("attachment not found")
+
+// OpenConversation returns the conversation about the application, starting it on first use.
+func (s *Conn) OpenConversation(ctx context.Context, applicationId uint) (Conversation, error) {
+	var conv Conversation
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Preload("Reads").Where("application_id = ?", applicationId).First(&conv).Error
+		if err == nil || !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		var app Application
+		err = tx.Select("id", "job_id", "user_id").First(&app, applicationId).Error
+		if err != nil {
+			return err
+		}
+		var job Job
+		err = tx.Select("id", "company_id").First(&job, app.JobID).Error
+		if err != nil {
+			return err
+		}
+		conv = Conversation{ApplicationID: app.ID, CompanyID: job.CompanyID, CandidateID: app.UserID}
+		// Someone else may be opening it at the same time
+		err = tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "application_id"}}, DoNothing: true}).
+			Create(&conv).Error
+		if err != nil {
+			return err
+		}
+		return tx.Preload("Reads").Where("application_id = ?", applicationId).First(&conv).Error
+	})
+	if err != nil {
+		return Conversation{}, err
+	}
+	return conv, nil
+}
+
+// CreateMessage adds a message of the sender to the conversation. The sender has read everything up to it.
+func (s *Conn) CreateMessage(ctx context.Context, conversationId, senderId uint, nm NewMessage) (Message, error) {
+	msg := Message{ConversationID: conversationId, SenderID: senderId, Body: nm.Body, AttachmentID: nm.AttachmentID}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if nm.AttachmentID != nil {
+			var doc Document
+			err := tx.Select("id").Where("id = ? AND owner_id = ? AND kind = ?", *nm.AttachmentID, senderId, DocumentKindAttachment).
+				First(&doc).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidAttachment
+			}
+			if err != nil {
+				return err
+			}
+		}
+		err := tx.Create(&msg).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&Conversation{}).Where("id = ?", conversationId).Update("last_message_at", msg.CreatedAt).Error
+		if err != nil {
+			return err
+		}
+		_, err = markRead(tx, conversationId, senderId, msg.ID, msg.CreatedAt)
+		return err
+	})
+	if err != nil {
+		return Message{}, err
+	}
+	return msg, nil
+}
+
+// ViewMessages lists the messages of a conversation newest first, up to limit of them sent before the message before.
+// A before of zero starts at the latest message.
+func (s *Conn) ViewMessages(ctx context.Context, conversationId, before uint, limit int) ([]Message, error) {
+	var msgs = make([]Message, 0, limit)
+	tx := s.db.WithContext(ctx).Where("conversation_id = ?", conversationId)
+	if before > 0 {
+		tx = tx.Where("id < ?", before)
+	}
+	err := tx.Order("id desc").Limit(limit).Find(&msgs).Error
+	if err != nil {
+		return nil, err
+	}
+	return msgs, nil
+}
+
+// ViewMessage returns a single message.
+func (s *Conn) ViewMessage(ctx context.Context, messageId uint) (Message, error) {
+	var msg Message
+	err := s.db.WithContext(ctx).First(&msg, messageId).Error
+	if err != nil {
+		return Message{}, err
+	}
+	return msg, nil
+}
+
+// MarkConversationRead records that the user has read the conversation up to the message.
+// Read receipts never move backwards.
+func (s *Conn) MarkConversationRead(ctx context.Context, conversationId, userId, messageId uint) (ConversationRead, error) {
+	var read ConversationRead
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var msg Message
+		err := tx.Select("id").Where("id = ? AND conversation_id = ?", messageId, conversationId).First(&msg).Error
+		if err != nil {
+			return err
+		}
+		read, err = markRead(tx, conversationId, userId, messageId, time.Now().UTC())
+		return err
+	})
+	if err != nil {
+		return ConversationRead{}, err
+	}
+	return read, nil
+}
+
+func markRead(tx *gorm.DB, conversationId, userId, messageId uint, at time.Time) (ConversationRead, error) {
+	read := ConversationRead{ConversationID: conversationId, UserID: userId, LastReadMessageID: messageId, ReadAt: at}
+	err := tx.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "conversation_id"}, {Name: "user_id"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"last_read_message_id": gorm.Expr("GREATEST(conversation_reads.last_read_message_id, excluded.last_read_message_id)"),
+			"read_at":              gorm.Expr("CASE WHEN excluded.last_read_message_id > conversation_reads.last_read_message_id THEN excluded.read_at ELSE conversation_reads.read_at END"),
+		}),
+	}).Create(&read).Error
+	if err != nil {
+		return ConversationRead{}, err
+	}
+	err = tx.Where("conversation_id = ? AND user_id = ?", conversationId, userId).First(&read).Error
+	if err != nil {
+		return ConversationRead{}, err
+	}
+	return read, nil
+}
+
+// ViewUserConversations lists the conversations the user takes part in, as candidate or as a member
+// of the company with at least the MessagingRole, most recently active first, with their unread counts.
+func (s *Conn) ViewUserConversations(ctx context.Context, userId uint) ([]Conversation, error) {
+	var convs = make([]Conversation, 0, 10)
+	err := s.db.WithContext(ctx).
+		Where("candidate_id = ? OR company_id IN (?)", userId,
+			s.db.Model(&Membership{}).Select("company_id").Where("user_id = ? AND role IN ?", userId, RolesAtLeast(MessagingRole))).
+		Order("last_message_at DESC NULLS LAST, id DESC").Find(&convs).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(convs) == 0 {
+		return convs, nil
+	}
+	ids := make([]uint, 0, len(convs))
+	for _, c := range convs {
+		ids = append(ids, c.ID)
+	}
+	var counts []struct {
+		ConversationID uint
+		Unread         int64
+	}
+	err = s.db.WithContext(ctx).Table("messages AS m").
+		Select("m.conversation_id, count(*) AS unread").
+		Joins("LEFT JOIN conversation_reads r ON r.conversation_id = m.conversation_id AND r.user_id = ?", userId).
+		Where("m.conversation_id IN ? AND m.sender_id <> ? AND m.id > COALESCE(r.last_read_message_id, 0)", ids, userId).
+		Group("m.conversation_id").Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+	unread := make(map[uint]int64, len(counts))
+	for _, c := range counts {
+		unread[c.ConversationID] = c.Unread
+	}
+	for i := range convs {
+		convs[i].Unread = unread[convs[i].ID]
+	}
+	return convs, nil
+}
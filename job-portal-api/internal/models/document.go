@@ -0,0 +1,26 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+const (
+	DocumentKindResume = "resume"
+	DocumentKindLogo   = "logo"
+	// DocumentKindAttachment files are sent along with messages
+	DocumentKindAttachment = "attachment"
+	// DocumentKindOffer files are offer letters generated for candidates
+	DocumentKindOffer = "offer"
+)
+
+// Document is the metadata of an uploaded file. The bytes themselves live in the blob store under StorageKey.
+type Document struct {
+	gorm.Model
+	OwnerID     uint   `json:"owner_id" gorm:"index"`
+	Kind        string `json:"kind"`
+	FileName    string `json:"file_name"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	Checksum    string `json:"checksum"`
+	StorageKey  string `json:"-" gorm:"uniqueIndex"`
+}
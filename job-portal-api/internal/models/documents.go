@@ -0,0 +1,76 @@
+package models
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateDocument records the metadata of a file that has already been written to the blob store.
+func (s *Conn) CreateDocument(ctx context.Context, doc Document) (Document, error) {
+	err := s.db.WithContext(ctx).Create(&doc).Error
+	if err != nil {
+		return Document{}, err
+	}
+	return doc, nil
+}
+
+// ViewDocuments lists the documents uploaded by the given user, newest first.
+func (s *Conn) ViewDocuments(ctx context.Context, ownerId uint) ([]Document, error) {
+	var docs = make([]Document, 0, 10)
+	err := s.db.WithContext(ctx).Where("owner_id = ?", ownerId).Order("created_at desc").Find(&docs).Error
+	if err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+func (s *Conn) ViewDocument(ctx context.Context, documentId uint) (Document, error) {
+	var doc Document
+	err := s.db.WithContext(ctx).First(&doc, documentId).Error
+	if err != nil {
+		return Document{}, err
+	}
+	return doc, nil
+}
+
+// DeleteDocument removes the metadata row of a document. The caller deletes the blob itself.
+func (s *Conn) DeleteDocument(ctx context.Context, documentId uint) error {
+	return s.db.WithContext(ctx).Delete(&Document{}, documentId).Error
+}
+
+// SetCompanyLogo points the company at an uploaded logo document and deletes the document of the logo
+// it replaces, which it returns so the caller can delete its blob. It is zero when there was none.
+func (s *Conn) SetCompanyLogo(ctx context.Context, companyId uint, documentId uint) (Company, Document, error) {
+	var cmp Company
+	var old Document
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&cmp, companyId).Error
+		if err != nil {
+			return err
+		}
+		previous := cmp.LogoDocumentID
+		cmp.LogoDocumentID = &documentId
+		err = tx.Model(&cmp).Update("logo_document_id", documentId).Error
+		if err != nil {
+			return err
+		}
+		if previous == nil || *previous == documentId {
+			return nil
+		}
+		err = tx.First(&old, *previous).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return tx.Delete(&old).Error
+	})
+	if err != nil {
+		return Company{}, Document{}, err
+	}
+	return cmp, old, nil
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Domain event types, named <aggregate>.<what happened>.
+const (
+	EventUserRegistered = "user.registered"
+	EventCompanyCreated = "company.created"
+	EventJobPosted      = "job.posted"
+	// EventJobClosed is recorded when a job stops being listed, closed by a recruiter or expired.
+	EventJobClosed            = "job.closed"
+	EventApplicationSubmitted = "application.submitted"
+	// EventApplicationMoved is recorded when an application moves to another stage of its pipeline.
+	EventApplicationMoved = "application.moved"
+	// EventApplicationOffered is recorded when the candidate is sent an offer, EventApplicationHired and
+	// EventApplicationOfferDeclined when they answer it.
+	EventApplicationOffered       = "application.offered"
+	EventApplicationHired         = "application.hired"
+	EventApplicationOfferDeclined = "application.offer_declined"
+)
+
+// Aggregate types events belong to. Events of one aggregate are published in the order they happened.
+const (
+	AggregateUser        = "user"
+	AggregateCompany     = "company"
+	AggregateJob         = "job"
+	AggregateApplication = "application"
+)
+
+// OutboxEvent is a domain event written in the same transaction as the change it describes,
+// and published afterwards by the relay. Payload is the JSON of the changed record.
+type OutboxEvent struct {
+	ID            uint            `json:"id" gorm:"primarykey"`
+	Type          string          `json:"type" gorm:"not null;index"`
+	AggregateType string          `json:"aggregate_type" gorm:"not null;index:idx_outbox_aggregate,priority:1"`
+	AggregateID   uint            `json:"aggregate_id" gorm:"index:idx_outbox_aggregate,priority:2"`
+	Payload       json.RawMessage `json:"payload"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	PublishedAt   *time.Time      `json:"published_at,omitempty" gorm:"index"`
+	Attempts      int             `json:"-"`
+	LastError     string          `json:"-"`
+	// NextAttemptAt holds a failed event back until it is retried, DeadAt is set once it ran out of attempts.
+	NextAttemptAt *time.Time `json:"-"`
+	DeadAt        *time.Time `json:"dead_at,omitempty" gorm:"index"`
+}
+
+// EventRetry is how the relay retries events that failed to publish.
+type EventRetry struct {
+	// MaxAttempts is how often an event is tried before it is declared dead.
+	MaxAttempts int
+	// Backoff is the wait before retrying after the given attempt.
+	Backoff func(attempt int) time.Duration
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// relayLockKey is the Postgres advisory lock held while relaying, so only one process publishes at a time.
+const relayLockKey = 7_310_032
+
+// recordEvent adds an event to the outbox inside the caller's transaction.
+func recordEvent(tx *gorm.DB, aggregateType string, aggregateId uint, eventType string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return tx.Create(&OutboxEvent{
+		Type:          eventType,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateId,
+		Payload:       data,
+		OccurredAt:    time.Now().UTC(),
+	}).Error
+}
+
+// RelayEvents hands up to limit unpublished events to publish, oldest first, and marks the delivered ones.
+// When an event fails it is retried after a backoff, and later events of the same aggregate wait for it so their
+// order is kept. An event failing retry.MaxAttempts times is declared dead and no longer holds the others back.
+// It returns how many events were published; another process already relaying makes it return 0.
+func (s *Conn) RelayEvents(ctx context.Context, limit int, retry EventRetry, publish func(e OutboxEvent) error) (int, error) {
+	published := 0
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var locked bool
+		err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", relayLockKey).Scan(&locked).Error
+		if err != nil || !locked {
+			return err
+		}
+
+		now := time.Now().UTC()
+		var events []OutboxEvent
+		err = tx.Where("published_at IS NULL AND dead_at IS NULL").
+			// Skip the events waiting for a retry, and those queued behind one
+			Where(`NOT EXISTS (SELECT 1 FROM outbox_events w WHERE w.aggregate_type = outbox_events.aggregate_type
+				AND w.aggregate_id = outbox_events.aggregate_id AND w.id <= outbox_events.id
+				AND w.published_at IS NULL AND w.dead_at IS NULL AND w.next_attempt_at > ?)`, now).
+			Order("id").Limit(limit).Find(&events).Error
+		if err != nil {
+			return err
+		}
+
+		blocked := map[string]bool{}
+		for _, e := range events {
+			key := fmt.Sprintf("%s:%d", e.AggregateType, e.AggregateID)
+			if blocked[key] {
+				continue
+			}
+			perr := publish(e)
+			if perr != nil {
+				blocked[key] = true
+				failed := map[string]any{"attempts": e.Attempts + 1, "last_error": perr.Error()}
+				if e.Attempts+1 >= retry.MaxAttempts {
+					failed["dead_at"] = now
+				} else {
+					failed["next_attempt_at"] = now.Add(retry.Backoff(e.Attempts + 1))
+				}
+				err = tx.Model(&OutboxEvent{}).Where("id = ?", e.ID).Updates(failed).Error
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			err = tx.Model(&OutboxEvent{}).Where("id = ?", e.ID).Update("published_at", time.Now().UTC()).Error
+			if err != nil {
+				return err
+			}
+			published++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return published, nil
+}
+
+// PurgePublishedEvents deletes events published before the given time.
+func (s *Conn) PurgePublishedEvents(ctx context.Context, before time.Time) (int64, error) {
+	tx := s.db.WithContext(ctx).Where("published_at < ?", before).Delete(&OutboxEvent{})
+	return tx.RowsAffected, tx.Error
+}
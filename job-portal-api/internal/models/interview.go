@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Interview statuses. Recruiters propose slots, the interview is scheduled once the candidate picks one.
+const (
+	InterviewProposed  = "proposed"
+	InterviewScheduled = "scheduled"
+	InterviewCancelled = "cancelled"
+)
+
+// Interview of a candidate for a job they applied to, held by the recruiter who proposed it.
+// Times are stored in UTC; Timezone is where the interview is planned and how times are shown to people.
+type Interview struct {
+	gorm.Model
+	ApplicationID   uint   `json:"application_id" gorm:"index"`
+	JobID           uint   `json:"job_id"`
+	CompanyID       uint   `json:"company_id" gorm:"index"`
+	CandidateID     uint   `json:"candidate_id" gorm:"index"`
+	RecruiterID     uint   `json:"recruiter_id" gorm:"index"`
+	Status          string `json:"status" gorm:"not null;default:proposed"`
+	Timezone        string `json:"timezone"`
+	DurationMinutes int    `json:"duration_minutes"`
+	Location        string `json:"location,omitempty"`
+	Notes           string `json:"notes,omitempty"`
+	// StartsAt and EndsAt are set from the slot the candidate picked
+	StartsAt *time.Time `json:"starts_at,omitempty" gorm:"index"`
+	EndsAt   *time.Time `json:"ends_at,omitempty"`
+	// Sequence counts the changes calendars were told about, as iCalendar's SEQUENCE
+	Sequence     int             `json:"sequence"`
+	CancelReason string          `json:"cancel_reason,omitempty"`
+	Slots        []InterviewSlot `json:"slots,omitempty"`
+}
+
+// InterviewSlot is one of the times a recruiter offered for an interview.
+type InterviewSlot struct {
+	ID          uint      `json:"id" gorm:"primarykey"`
+	InterviewID uint      `json:"-" gorm:"index"`
+	StartsAt    time.Time `json:"starts_at"`
+	EndsAt      time.Time `json:"ends_at"`
+}
+
+// NewInterview proposes slots for an interview. Slots are either RFC 3339 times
+// or local times like "2026-11-02T10:00" in the interview's timezone.
+type NewInterview struct {
+	Slots           []string `json:"slots" validate:"required,min=1,max=10,dive,required"`
+	DurationMinutes int      `json:"duration_minutes" validate:"required,min=15,max=480"`
+	Timezone        string   `json:"timezone" validate:"required,timezone"`
+	Location        string   `json:"location" validate:"max=500"`
+	Notes           string   `json:"notes" validate:"max=2000"`
+}
+
+// CalendarFeed is the secret address a user subscribes their calendar app to,
+// so it works without logging in.
+type CalendarFeed struct {
+	gorm.Model
+	UserID uint   `gorm:"uniqueIndex"`
+	Token  string `gorm:"uniqueIndex;not null"`
+}
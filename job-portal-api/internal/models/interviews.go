@@ -0,0 +1,283 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrApplicationWithdrawn is returned when planning an interview for a withdrawn application.
+	ErrApplicationWithdrawn = errors.New("application was withdrawn")
+	// ErrInterviewSlot is returned for slots that are malformed, in the past or not offered for the interview.
+	ErrInterviewSlot = errors.New("invalid interview slot")
+	// ErrInterviewConflict is returned when a slot overlaps another scheduled interview of the recruiter.
+	ErrInterviewConflict = errors.New("overlaps another interview of the recruiter")
+	// ErrInterviewState is returned when the interview cannot change in its current status.
+	ErrInterviewState = errors.New("interview cannot be changed")
+)
+
+// localSlot is how slots without a UTC offset are written, read in the interview's timezone.
+const localSlot = "2006-01-02T15:04"
+
+// slotTimes parses the proposed slots, sorted and without duplicates.
+func (ni NewInterview) slotTimes(now time.Time) ([]InterviewSlot, error) {
+	loc, err := time.LoadLocation(ni.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unknown timezone %s", ErrInterviewSlot, ni.Timezone)
+	}
+	duration := time.Duration(ni.DurationMinutes) * time.Minute
+	seen := map[time.Time]bool{}
+	slots := make([]InterviewSlot, 0, len(ni.Slots))
+	for _, s := range ni.Slots {
+		start, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			start, err = time.ParseInLocation(localSlot, s, loc)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q is not a time", ErrInterviewSlot, s)
+		}
+		start = start.UTC()
+		if !start.After(now) {
+			return nil, fmt.Errorf("%w: %s is in the past", ErrInterviewSlot, s)
+		}
+		if seen[start] {
+			continue
+		}
+		seen[start] = true
+		slots = append(slots, InterviewSlot{StartsAt: start, EndsAt: start.Add(duration)})
+	}
+	sort.Slice(slots, func(i, j int) bool { return slots[i].StartsAt.Before(slots[j].StartsAt) })
+	return slots, nil
+}
+
+// CreateInterview proposes an interview for the application, held by the recruiter in one of the slots.
+// Slots overlapping the recruiter's scheduled interviews are rejected.
+func (s *Conn) CreateInterview(ctx context.Context, applicationId, recruiterId uint, ni NewInterview) (Interview, error) {
+	slots, err := ni.slotTimes(time.Now().UTC())
+	if err != nil {
+		return Interview{}, err
+	}
+	var in Interview
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var app Application
+		err := tx.First(&app, applicationId).Error
+		if err != nil {
+			return err
+		}
+		if app.Status == ApplicationStatusWithdrawn {
+			return ErrApplicationWithdrawn
+		}
+		var job Job
+		err = tx.Select("id", "company_id").First(&job, app.JobID).Error
+		if err != nil {
+			return err
+		}
+		err = lockRecruiter(tx, recruiterId)
+		if err != nil {
+			return err
+		}
+		for _, slot := range slots {
+			err = checkOverlap(tx, recruiterId, 0, slot.StartsAt, slot.EndsAt)
+			if err != nil {
+				return err
+			}
+		}
+		in = Interview{
+			ApplicationID:   app.ID,
+			JobID:           job.ID,
+			CompanyID:       job.CompanyID,
+			CandidateID:     app.UserID,
+			RecruiterID:     recruiterId,
+			Status:          InterviewProposed,
+			Timezone:        ni.Timezone,
+			DurationMinutes: ni.DurationMinutes,
+			Location:        ni.Location,
+			Notes:           ni.Notes,
+			Slots:           slots,
+		}
+		return tx.Create(&in).Error
+	})
+	if err != nil {
+		return Interview{}, err
+	}
+	return in, nil
+}
+
+// ViewInterview returns an interview with the slots that were offered.
+func (s *Conn) ViewInterview(ctx context.Context, interviewId uint) (Interview, error) {
+	var in Interview
+	err := s.db.WithContext(ctx).Preload("Slots", orderSlots).First(&in, interviewId).Error
+	if err != nil {
+		return Interview{}, err
+	}
+	return in, nil
+}
+
+// ViewApplicationInterviews lists the interviews planned for an application, oldest first.
+func (s *Conn) ViewApplicationInterviews(ctx context.Context, applicationId uint) ([]Interview, error) {
+	var ins = make([]Interview, 0, 4)
+	err := s.db.WithContext(ctx).Preload("Slots", orderSlots).
+		Where("application_id = ?", applicationId).Order("id").Find(&ins).Error
+	if err != nil {
+		return nil, err
+	}
+	return ins, nil
+}
+
+// ViewUserInterviews lists the interviews the user takes part in, as candidate or recruiter:
+// those waiting for the candidate to pick a slot and those starting after since.
+func (s *Conn) ViewUserInterviews(ctx context.Context, userId uint, since time.Time) ([]Interview, error) {
+	var ins = make([]Interview, 0, 10)
+	err := s.db.WithContext(ctx).Preload("Slots", orderSlots).
+		Where("candidate_id = ? OR recruiter_id = ?", userId, userId).
+		Where(s.db.Where("status = ?", InterviewProposed).Or("starts_at >= ?", since)).
+		Order("starts_at NULLS FIRST, id").Find(&ins).Error
+	if err != nil {
+		return nil, err
+	}
+	return ins, nil
+}
+
+// SelectInterviewSlot schedules the interview in the slot the candidate picked.
+func (s *Conn) SelectInterviewSlot(ctx context.Context, interviewId, slotId, candidateId uint) (Interview, error) {
+	var in Interview
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Slots", orderSlots).First(&in, interviewId).Error
+		if err != nil {
+			return err
+		}
+		if in.CandidateID != candidateId {
+			return gorm.ErrRecordNotFound
+		}
+		if in.Status != InterviewProposed {
+			return fmt.Errorf("%w: interview is %s", ErrInterviewState, in.Status)
+		}
+		var slot *InterviewSlot
+		for i := range in.Slots {
+			if in.Slots[i].ID == slotId {
+				slot = &in.Slots[i]
+			}
+		}
+		if slot == nil {
+			return fmt.Errorf("%w: slot %d was not offered", ErrInterviewSlot, slotId)
+		}
+		if !slot.StartsAt.After(time.Now()) {
+			return fmt.Errorf("%w: slot %d has passed", ErrInterviewSlot, slotId)
+		}
+
+		// The recruiter may have booked the time since proposing it
+		err = lockRecruiter(tx, in.RecruiterID)
+		if err != nil {
+			return err
+		}
+		err = checkOverlap(tx, in.RecruiterID, in.ID, slot.StartsAt, slot.EndsAt)
+		if err != nil {
+			return err
+		}
+		start, end := slot.StartsAt, slot.EndsAt
+		in.StartsAt, in.EndsAt = &start, &end
+		in.Status = InterviewScheduled
+		in.Sequence++
+		return tx.Model(&in).Select("status", "starts_at", "ends_at", "sequence").Updates(&in).Error
+	})
+	if err != nil {
+		return Interview{}, err
+	}
+	return in, nil
+}
+
+// CancelInterview calls off a proposed or scheduled interview.
+func (s *Conn) CancelInterview(ctx context.Context, interviewId uint, reason string) (Interview, error) {
+	var in Interview
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Slots", orderSlots).First(&in, interviewId).Error
+		if err != nil {
+			return err
+		}
+		if in.Status == InterviewCancelled {
+			return fmt.Errorf("%w: interview is %s", ErrInterviewState, in.Status)
+		}
+		if in.StartsAt != nil && !in.StartsAt.After(time.Now()) {
+			return fmt.Errorf("%w: interview has started", ErrInterviewState)
+		}
+		in.Status = InterviewCancelled
+		in.CancelReason = reason
+		in.Sequence++
+		return tx.Model(&in).Select("status", "cancel_reason", "sequence").Updates(&in).Error
+	})
+	if err != nil {
+		return Interview{}, err
+	}
+	return in, nil
+}
+
+// lockRecruiter serializes the scheduling of a recruiter's interviews,
+// so two candidates cannot book overlapping times at once.
+func lockRecruiter(tx *gorm.DB, recruiterId uint) error {
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&User{}, recruiterId).Error
+}
+
+// checkOverlap fails with ErrInterviewConflict when the recruiter has another scheduled interview
+// between start and end. Back to back interviews do not overlap.
+func checkOverlap(tx *gorm.DB, recruiterId, interviewId uint, start, end time.Time) error {
+	var other Interview
+	err := tx.Select("id", "starts_at").
+		Where("recruiter_id = ? AND status = ? AND id <> ?", recruiterId, InterviewScheduled, interviewId).
+		Where("starts_at < ? AND ends_at > ?", end, start).First(&other).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: interview %d at %s", ErrInterviewConflict, other.ID, other.StartsAt.UTC().Format(time.RFC3339))
+}
+
+func orderSlots(db *gorm.DB) *gorm.DB {
+	return db.Order("starts_at")
+}
+
+// CalendarFeedToken returns the token of the user's calendar feed, created on first use.
+// Rotating replaces it, so the old address stops working.
+func (s *Conn) CalendarFeedToken(ctx context.Context, userId uint, rotate bool) (string, error) {
+	var feed CalendarFeed
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", userId).First(&feed).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if err == nil && !rotate {
+			return nil
+		}
+		token, err := secretToken()
+		if err != nil {
+			return err
+		}
+		if feed.ID == 0 {
+			feed = CalendarFeed{UserID: userId, Token: token}
+			return tx.Create(&feed).Error
+		}
+		feed.Token = token
+		return tx.Model(&feed).Update("token", token).Error
+	})
+	if err != nil {
+		return "", err
+	}
+	return feed.Token, nil
+}
+
+// ViewCalendarFeedUser returns the user the calendar feed token belongs to.
+func (s *Conn) ViewCalendarFeedUser(ctx context.Context, token string) (uint, error) {
+	var feed CalendarFeed
+	err := s.db.WithContext(ctx).Where("token = ?", token).First(&feed).Error
+	if err != nil {
+		return 0, err
+	}
+	return feed.UserID, nil
+}
@@ -1,7 +1,12 @@
 package models
 
 import (
+	"math"
+	"time"
+
 	"gorm.io/gorm"
+
+	"job-portal-api/internal/geo"
 )
 
 type Company struct {
@@ -10,14 +15,33 @@ type Company struct {
 	FoundedYear string `json:"founded_year"`
 	Location    string `json:"location"`
 	//	UserId      string `json:"user_id"`
-	Jobs []Job `json:"jobs,omitempty" gorm:"foreignKey:CompanyID"`
+	LogoDocumentID *uint `json:"logo_document_id,omitempty"`
+	GeoLocation
+	// Profile shown to candidates on the company page
+	Description string   `json:"description,omitempty"`
+	Website     string   `json:"website,omitempty"`
+	Size        string   `json:"size,omitempty"`
+	Industry    string   `json:"industry,omitempty" gorm:"index"`
+	Benefits    []string `json:"benefits,omitempty" gorm:"serializer:json"`
+	TechStack   []string `json:"tech_stack,omitempty" gorm:"serializer:json"`
+	Jobs        []Job    `json:"jobs,omitempty" gorm:"foreignKey:CompanyID"`
+	// Rating aggregates the approved reviews of the company, set on the company page only
+	Rating *CompanyRating `json:"rating,omitempty" gorm:"-"`
 }
 
+// NewCompany is a company as it is created, and as its admins update it.
+// Size is a headcount range: 1-10, 11-50, 51-200, 201-500, 501-1000, 1001-5000 or 5001+.
 type NewCompany struct {
-	CompanyName string `json:"company_name" validate:"required"`
-	FoundedYear string `json:"founded_year" validate:"required"`
-	Location    string `json:"location" validate:"required"`
-	Jobs        []Job  `json:"jobs"`
+	CompanyName string   `json:"company_name" validate:"required"`
+	FoundedYear string   `json:"founded_year" validate:"required"`
+	Location    string   `json:"location" validate:"required"`
+	Description string   `json:"description" validate:"max=10000"`
+	Website     string   `json:"website" validate:"omitempty,url,max=300"`
+	Size        string   `json:"size" validate:"omitempty,oneof=1-10 11-50 51-200 201-500 501-1000 1001-5000 5001+"`
+	Industry    string   `json:"industry" validate:"max=100"`
+	Benefits    []string `json:"benefits" validate:"max=30,dive,required,max=200"`
+	TechStack   []string `json:"tech_stack" validate:"max=50,dive,required,max=50"`
+	Jobs        []Job    `json:"jobs"`
 }
 
 type Job struct {
@@ -25,6 +49,119 @@ type Job struct {
 	Title           string `json:"title"`
 	ExperienceLevel string `json:"experience_required"`
 	CompanyID       uint   `json:"company_id"`
+	// Requirements used to match the job against candidate profiles
+	Description        string   `json:"description,omitempty"`
+	Location           string   `json:"location,omitempty"`
+	Skills             []string `json:"skills,omitempty" gorm:"serializer:json"`
+	MinExperienceYears int      `json:"min_experience_years,omitempty"`
+	SalaryMin          int      `json:"salary_min,omitempty"`
+	SalaryMax          int      `json:"salary_max,omitempty"`
+	SalaryCurrency     string   `json:"salary_currency,omitempty"`
+	// SalaryPeriod is what the salary pays for: hourly, monthly or annual, annual when empty
+	SalaryPeriod string `json:"salary_period,omitempty"`
+	// GeoLocation is resolved from Location, or from the location of the company when the job has none
+	GeoLocation
+	// Lifecycle: only published jobs are listed publicly, see JobTransitions
+	Status      string     `json:"status" gorm:"index;not null;default:published"`
+	PublishAt   *time.Time `json:"publish_at,omitempty" gorm:"index"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty" gorm:"index"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	ClosedAt    *time.Time `json:"closed_at,omitempty"`
+	// Bookmarked tells the logged-in candidate whether they bookmarked the job, set in job listings only
+	Bookmarked *bool `json:"bookmarked,omitempty" gorm:"-"`
+	// CompanyRating summarizes the reviews of the company that posted the job, set in job listings only
+	CompanyRating *RatingSummary `json:"company_rating,omitempty" gorm:"-"`
+	// DistanceKm is how far the job is from where the candidate searched around, set in radius searches only
+	DistanceKm *float64 `json:"distance_km,omitempty" gorm:"-"`
+}
+
+// GeoLocation is the city a free-form location is in, as the offline gazetteer resolves it.
+// It is empty when the location names no city the gazetteer knows, like "Remote".
+type GeoLocation struct {
+	City      string   `json:"city,omitempty"`
+	Region    string   `json:"region,omitempty"`
+	Country   string   `json:"country,omitempty"`
+	Latitude  *float64 `json:"latitude,omitempty" gorm:"index"`
+	Longitude *float64 `json:"longitude,omitempty"`
+	// LocatedAt is when the location was last resolved, found or not
+	LocatedAt *time.Time `json:"-"`
+}
+
+// JobSearch holds the filters candidates search listed jobs with. Empty filters match every job.
+type JobSearch struct {
+	// Keywords must each be in the title or description of the job
+	Keywords string
+	// Near and RadiusKm keep the jobs within the radius of a point, the nearest first
+	Near     *geo.Point
+	RadiusKm float64
+	// Box keeps the jobs located within the box
+	Box *geo.Box
+}
+
+// Job statuses. Drafts with a PublishAt are published by the sweeper at that time,
+// and published or paused jobs past their ExpiresAt are expired by it.
+const (
+	JobDraft     = "draft"
+	JobPublished = "published"
+	JobPaused    = "paused"
+	JobClosed    = "closed"
+	JobExpired   = "expired"
+)
+
+// Salary periods. Salaries are compared on a yearly basis, assuming full-time work of 2080 hours a year.
+const (
+	PayHourly  = "hourly"
+	PayMonthly = "monthly"
+	PayAnnual  = "annual"
+)
+
+// PeriodsPerYear is how many of each salary period make a year.
+var PeriodsPerYear = map[string]float64{
+	PayHourly:  2080,
+	PayMonthly: 12,
+	PayAnnual:  1,
+}
+
+// AnnualSalary turns an amount the job pays per its salary period into a yearly amount.
+func (j Job) AnnualSalary(amount int) int {
+	if n, ok := PeriodsPerYear[j.SalaryPeriod]; ok {
+		return int(math.Round(float64(amount) * n))
+	}
+	return amount
+}
+
+// JobTransitions lists the statuses a job may move to from each status.
+// Only the sweeper expires jobs; an expired job can be published again with a later expiry.
+var JobTransitions = map[string][]string{
+	JobDraft:     {JobPublished, JobClosed},
+	JobPublished: {JobPaused, JobClosed, JobExpired},
+	JobPaused:    {JobPublished, JobClosed, JobExpired},
+	JobExpired:   {JobPublished, JobClosed},
+	JobClosed:    {},
+}
+
+// JobTransition is a recruiter's request to change the status or schedule of a job.
+// Publishing with a PublishAt in the future schedules the job instead.
+type JobTransition struct {
+	Status    string     `json:"status" validate:"required,oneof=draft published paused closed"`
+	PublishAt *time.Time `json:"publish_at"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// NewJob is a job posting as recruiters submit it in bulk imports.
+type NewJob struct {
+	Title              string   `json:"title" validate:"required,max=200"`
+	ExperienceLevel    string   `json:"experience_required" validate:"required,max=100"`
+	Description        string   `json:"description" validate:"max=10000"`
+	Location           string   `json:"location" validate:"max=200"`
+	Skills             []string `json:"skills" validate:"max=50,dive,required,max=50"`
+	MinExperienceYears int      `json:"min_experience_years" validate:"gte=0,lte=50"`
+	SalaryMin          int      `json:"salary_min" validate:"gte=0"`
+	SalaryMax          int      `json:"salary_max" validate:"omitempty,gtefield=SalaryMin"`
+	SalaryCurrency     string   `json:"salary_currency" validate:"required_with=SalaryMin SalaryMax,omitempty,iso4217"`
+	SalaryPeriod       string   `json:"salary_period" validate:"omitempty,oneof=hourly monthly annual"`
+	// Status is draft or published, published when empty
+	Status string `json:"status" validate:"omitempty,oneof=draft published"`
 }
 
 /*
@@ -0,0 +1,249 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"job-portal-api/internal/geo"
+)
+
+// ErrJobTransition is returned when a job cannot move to the requested status.
+var ErrJobTransition = errors.New("invalid job status change")
+
+// ErrJobSchedule is returned for publish and expiry dates that cannot work.
+var ErrJobSchedule = errors.New("invalid job schedule")
+
+// listed limits a query to the jobs shown publicly.
+func listed(db *gorm.DB) *gorm.DB {
+	return db.Where("jobs.status = ?", JobPublished)
+}
+
+// start sets the lifecycle of a job about to be created, as if a draft was moved to the requested status.
+// It returns the event to record, if any.
+func (j *Job) start(now time.Time) (string, error) {
+	status := j.Status
+	if status == "" {
+		status = JobPublished
+	}
+	if status != JobDraft && status != JobPublished {
+		return "", fmt.Errorf("%w: new jobs are %s or %s", ErrJobTransition, JobDraft, JobPublished)
+	}
+	t := JobTransition{Status: status, PublishAt: j.PublishAt, ExpiresAt: j.ExpiresAt}
+	j.Status, j.PublishAt, j.ExpiresAt, j.PublishedAt, j.ClosedAt = JobDraft, nil, nil, nil, nil
+	return j.transition(t, now)
+}
+
+// transition applies a recruiter's change to the job and returns the event to record, if any.
+// Staying in the same status is allowed to change the schedule.
+func (j *Job) transition(t JobTransition, now time.Time) (string, error) {
+	if t.Status != j.Status && !slices.Contains(JobTransitions[j.Status], t.Status) {
+		return "", fmt.Errorf("%w: %s to %s", ErrJobTransition, j.Status, t.Status)
+	}
+	if t.ExpiresAt != nil {
+		if !t.ExpiresAt.After(now) {
+			return "", fmt.Errorf("%w: expires_at must be in the future", ErrJobSchedule)
+		}
+		expiresAt := t.ExpiresAt.UTC()
+		j.ExpiresAt = &expiresAt
+	}
+	scheduled := t.PublishAt != nil && t.PublishAt.After(now)
+	if t.PublishAt != nil && t.Status != JobDraft && t.Status != JobPublished {
+		return "", fmt.Errorf("%w: publish_at only applies when publishing", ErrJobSchedule)
+	}
+	if scheduled && j.ExpiresAt != nil && !t.PublishAt.Before(*j.ExpiresAt) {
+		return "", fmt.Errorf("%w: publish_at must be before expires_at", ErrJobSchedule)
+	}
+
+	switch t.Status {
+	case JobDraft:
+		j.PublishAt = nil
+		if scheduled {
+			publishAt := t.PublishAt.UTC()
+			j.PublishAt = &publishAt
+		}
+	case JobPublished:
+		if scheduled {
+			if j.Status != JobDraft {
+				return "", fmt.Errorf("%w: only drafts can be scheduled", ErrJobSchedule)
+			}
+			publishAt := t.PublishAt.UTC()
+			j.PublishAt = &publishAt
+			return "", nil
+		}
+		if j.ExpiresAt != nil && !j.ExpiresAt.After(now) {
+			return "", fmt.Errorf("%w: set a later expires_at to publish again", ErrJobSchedule)
+		}
+		return j.publish(now), nil
+	case JobPaused:
+		j.Status = JobPaused
+	case JobClosed:
+		closedAt := now
+		j.Status = JobClosed
+		j.ClosedAt = &closedAt
+		j.PublishAt = nil
+		return EventJobClosed, nil
+	}
+	j.Status = t.Status
+	return "", nil
+}
+
+// publish lists the job; the first time around it counts as posted.
+func (j *Job) publish(now time.Time) string {
+	j.Status = JobPublished
+	j.PublishAt = nil
+	if j.PublishedAt != nil {
+		return ""
+	}
+	publishedAt := now
+	j.PublishedAt = &publishedAt
+	return EventJobPosted
+}
+
+// expire takes a job past its expiry date off the listings.
+func (j *Job) expire() string {
+	j.Status = JobExpired
+	j.PublishAt = nil
+	return EventJobClosed
+}
+
+// saveLifecycle writes the lifecycle columns of the job and the event the change caused.
+func saveLifecycle(tx *gorm.DB, job *Job, event string) error {
+	err := tx.Model(job).Select("status", "publish_at", "expires_at", "published_at", "closed_at").Updates(job).Error
+	if err != nil {
+		return err
+	}
+	if event == "" {
+		return nil
+	}
+	return recordEvent(tx, AggregateJob, job.ID, event, job)
+}
+
+// TransitionJob changes the status or schedule of a job.
+func (s *Conn) TransitionJob(ctx context.Context, jobId uint, t JobTransition) (Job, error) {
+	var job Job
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&job, jobId).Error
+		if err != nil {
+			return err
+		}
+		event, err := job.transition(t, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+		return saveLifecycle(tx, &job, event)
+	})
+	if err != nil {
+		return Job{}, err
+	}
+	return job, nil
+}
+
+// SweepJobs publishes scheduled drafts and expires jobs that are due at now, at most limit of them.
+// It returns how many jobs changed.
+func (s *Conn) SweepJobs(ctx context.Context, now time.Time, limit int) (int, error) {
+	n := 0
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var due []Job
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("(status = ? AND publish_at <= ?) OR (status IN ? AND expires_at <= ?)",
+				JobDraft, now, []string{JobPublished, JobPaused}, now).
+			Order("id").Limit(limit).Find(&due).Error
+		if err != nil {
+			return err
+		}
+		for i := range due {
+			job := &due[i]
+			var event string
+			if job.ExpiresAt != nil && !job.ExpiresAt.After(now) {
+				// A draft scheduled past its own expiry is never listed
+				event = job.expire()
+			} else {
+				event = job.publish(now)
+			}
+			err = saveLifecycle(tx, job, event)
+			if err != nil {
+				return err
+			}
+		}
+		n = len(due)
+		return nil
+	})
+	return n, err
+}
+
+// ViewCompanyJobs lists every job of a company whatever its status, or only those in status when set.
+func (s *Conn) ViewCompanyJobs(ctx context.Context, companyId uint, status string) ([]Job, error) {
+	var jobs = make([]Job, 0, 10)
+	q := s.db.WithContext(ctx).Where("company_id = ?", companyId)
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+	err := q.Order("id desc").Find(&jobs).Error
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// haversine is the distance in kilometres from a job to the point bound as latitude, latitude, longitude.
+var haversine = fmt.Sprintf(`2 * %g * ASIN(SQRT(LEAST(1, POWER(SIN(RADIANS(jobs.latitude - ?) / 2), 2) +
+	COS(RADIANS(?)) * COS(RADIANS(jobs.latitude)) * POWER(SIN(RADIANS(jobs.longitude - ?) / 2), 2))))`, geo.EarthRadiusKm)
+
+// within limits a query to the jobs located within the box.
+func within(b geo.Box) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("jobs.latitude BETWEEN ? AND ?", b.MinLatitude, b.MaxLatitude)
+		if b.CrossesAntimeridian() {
+			return db.Where("(jobs.longitude >= ? OR jobs.longitude <= ?)", b.MinLongitude, b.MaxLongitude)
+		}
+		return db.Where("jobs.longitude BETWEEN ? AND ?", b.MinLongitude, b.MaxLongitude)
+	}
+}
+
+// SearchJobs finds up to limit listed jobs matching q, the nearest first in radius searches and the newest
+// first otherwise. Radius searches tell how far each job is; jobs whose location is unknown are left out
+// of them, as of box searches.
+func (s *Conn) SearchJobs(ctx context.Context, q JobSearch, limit int) ([]Job, error) {
+	db := s.db.WithContext(ctx).Scopes(listed)
+	for _, kw := range strings.Fields(q.Keywords) {
+		db = db.Where("(jobs.title ILIKE ? OR jobs.description ILIKE ?)", contains(kw), contains(kw))
+	}
+	if q.Box != nil {
+		db = db.Scopes(within(*q.Box))
+	}
+	if q.Near != nil {
+		lat, lng := q.Near.Latitude, q.Near.Longitude
+		// The bounding box narrows the jobs down on the latitude index before distances are measured
+		db = db.Scopes(within(geo.BoundingBox(*q.Near, q.RadiusKm))).
+			Where(haversine+" <= ?", lat, lat, lng, q.RadiusKm).
+			Order(clause.OrderBy{Expression: clause.Expr{
+				SQL: haversine + ", jobs.id DESC", Vars: []interface{}{lat, lat, lng}, WithoutParentheses: true,
+			}})
+	} else {
+		db = db.Order("jobs.id desc")
+	}
+
+	var jobs = make([]Job, 0, limit)
+	err := db.Limit(limit).Find(&jobs).Error
+	if err != nil {
+		return nil, err
+	}
+	if q.Near != nil {
+		for i, job := range jobs {
+			if job.Latitude == nil || job.Longitude == nil {
+				continue
+			}
+			d := math.Round(geo.Distance(*q.Near, geo.Point{Latitude: *job.Latitude, Longitude: *job.Longitude})*10) / 10
+			jobs[i].DistanceKm = &d
+		}
+	}
+	return jobs, nil
+}
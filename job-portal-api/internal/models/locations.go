@@ -0,0 +1,94 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"job-portal-api/internal/geo"
+)
+
+// locate resolves a free-form location with the embedded gazetteer.
+func locate(location string) GeoLocation {
+	now := time.Now().UTC()
+	p, ok := geo.Default().Resolve(location)
+	if !ok {
+		return GeoLocation{LocatedAt: &now}
+	}
+	lat, lng := p.Latitude, p.Longitude
+	return GeoLocation{City: p.City, Region: p.Region, Country: p.Country, Latitude: &lat, Longitude: &lng, LocatedAt: &now}
+}
+
+// locate sets where the job is from its location, falling back on the location of its company.
+func (j *Job) locate(companyLocation string) {
+	location := j.Location
+	if strings.TrimSpace(location) == "" {
+		location = companyLocation
+	}
+	j.GeoLocation = locate(location)
+}
+
+// columns are the values to update a GeoLocation with.
+func (g GeoLocation) columns() map[string]interface{} {
+	return map[string]interface{}{
+		"city": g.City, "region": g.Region, "country": g.Country, "latitude": g.Latitude, "longitude": g.Longitude,
+		"located_at": g.LocatedAt,
+	}
+}
+
+// companyLocation returns the free-form location of a company.
+func companyLocation(tx *gorm.DB, companyId uint) (string, error) {
+	var company Company
+	err := tx.Select("id", "location").First(&company, companyId).Error
+	if err != nil {
+		return "", err
+	}
+	return company.Location, nil
+}
+
+// backfillGeo resolves the locations of the companies and jobs saved before locations were resolved.
+// Rows are marked as located even when no known city was found, so each one is only tried once;
+// clearing located_at has them tried again, e.g. after the gazetteer learned more places.
+func backfillGeo(db *gorm.DB) error {
+	db = db.WithContext(WithAuditActor(context.Background(), AuditActor{UserID: AuditSystem, TraceID: "backfill-geo"}))
+	var companies []Company
+	err := db.Select("id", "location").Where("located_at IS NULL").
+		FindInBatches(&companies, 500, func(_ *gorm.DB, _ int) error {
+			for _, c := range companies {
+				err := db.Model(&Company{}).Where("id = ?", c.ID).Updates(locate(c.Location).columns()).Error
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return err
+	}
+
+	var jobs []Job
+	locations := map[uint]string{}
+	return db.Select("id", "company_id", "location").Where("located_at IS NULL").
+		FindInBatches(&jobs, 500, func(_ *gorm.DB, _ int) error {
+			for _, j := range jobs {
+				if strings.TrimSpace(j.Location) == "" {
+					if _, ok := locations[j.CompanyID]; !ok {
+						loc, err := companyLocation(db, j.CompanyID)
+						if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+							return err
+						}
+						locations[j.CompanyID] = loc
+					}
+				}
+				j.locate(locations[j.CompanyID])
+				err := db.Model(&Job{}).Where("id = ?", j.ID).Updates(j.GeoLocation.columns()).Error
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+)
+
+// MatchScore is the precomputed relevance of a job for a candidate.
+// Scores are refreshed whenever the job or the profile changes, never on read.
+type MatchScore struct {
+	ID         uint             `json:"-" gorm:"primarykey"`
+	JobID      uint             `json:"job_id" gorm:"uniqueIndex:idx_match_job_user"`
+	UserID     uint             `json:"user_id" gorm:"uniqueIndex:idx_match_job_user;index"`
+	Score      float64          `json:"score" gorm:"index"`
+	Breakdown  []ScoreComponent `json:"breakdown" gorm:"serializer:json"`
+	Weights    string           `json:"-"`
+	ComputedAt time.Time        `json:"computed_at"`
+}
+
+// ScoreComponent explains one part of a match score.
+type ScoreComponent struct {
+	Name   string  `json:"name"`
+	Weight float64 `json:"weight"`
+	Score  float64 `json:"score"`
+	Detail string  `json:"detail"`
+}
+
+// JobMatch is a job recommended to a candidate.
+type JobMatch struct {
+	Job       Job              `json:"job"`
+	Score     float64          `json:"score"`
+	Breakdown []ScoreComponent `json:"breakdown"`
+}
+
+// CandidateMatch is a candidate recommended for a job.
+type CandidateMatch struct {
+	Profile   Profile          `json:"profile"`
+	Score     float64          `json:"score"`
+	Breakdown []ScoreComponent `json:"breakdown"`
+}
@@ -0,0 +1,153 @@
+package models
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReplaceJobMatches swaps all the stored scores of a job for the given ones.
+func (s *Conn) ReplaceJobMatches(ctx context.Context, jobId uint, scores []MatchScore) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("job_id = ?", jobId).Delete(&MatchScore{}).Error
+		if err != nil {
+			return err
+		}
+		return upsertMatches(tx, scores)
+	})
+}
+
+// ReplaceProfileMatches swaps all the stored scores of a candidate for the given ones.
+func (s *Conn) ReplaceProfileMatches(ctx context.Context, userId uint, scores []MatchScore) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("user_id = ?", userId).Delete(&MatchScore{}).Error
+		if err != nil {
+			return err
+		}
+		return upsertMatches(tx, scores)
+	})
+}
+
+// upsertMatches inserts scores, overwriting a pair written concurrently by another worker.
+func upsertMatches(tx *gorm.DB, scores []MatchScore) error {
+	if len(scores) == 0 {
+		return nil
+	}
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "job_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"score", "breakdown", "weights", "computed_at"}),
+	}).CreateInBatches(&scores, 500).Error
+}
+
+// CountStaleMatchScores counts the scores computed with weights other than the given fingerprint.
+func (s *Conn) CountStaleMatchScores(ctx context.Context, weights string) (int64, error) {
+	var n int64
+	err := s.db.WithContext(ctx).Model(&MatchScore{}).Where("weights <> ?", weights).Count(&n).Error
+	return n, err
+}
+
+// ViewJobMatches returns the best scoring jobs for a candidate, skipping deleted and unlisted jobs.
+func (s *Conn) ViewJobMatches(ctx context.Context, userId uint, limit int) ([]JobMatch, error) {
+	var scores []MatchScore
+	err := s.db.WithContext(ctx).
+		Joins("JOIN jobs ON jobs.id = match_scores.job_id AND jobs.deleted_at IS NULL AND jobs.status = ?", JobPublished).
+		Where("match_scores.user_id = ?", userId).
+		Order("match_scores.score desc").Limit(limit).Find(&scores).Error
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, 0, len(scores))
+	for _, sc := range scores {
+		ids = append(ids, sc.JobID)
+	}
+	var jobs []Job
+	err = s.db.WithContext(ctx).Where("id IN ?", ids).Find(&jobs).Error
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]Job, len(jobs))
+	for _, j := range jobs {
+		byID[j.ID] = j
+	}
+
+	out := make([]JobMatch, 0, len(scores))
+	for _, sc := range scores {
+		out = append(out, JobMatch{Job: byID[sc.JobID], Score: sc.Score, Breakdown: sc.Breakdown})
+	}
+	return out, nil
+}
+
+// ViewCandidateMatches returns the best scoring candidates for a job among the discoverable ones
+// and those who applied to it.
+func (s *Conn) ViewCandidateMatches(ctx context.Context, jobId uint, limit int) ([]CandidateMatch, error) {
+	var scores []MatchScore
+	err := s.db.WithContext(ctx).
+		Joins("JOIN profiles ON profiles.user_id = match_scores.user_id AND profiles.deleted_at IS NULL").
+		Where("match_scores.job_id = ?", jobId).
+		Where("(profiles.discoverable OR EXISTS (?))", s.db.Model(&Application{}).Select("1").
+			Where("applications.job_id = match_scores.job_id AND applications.user_id = match_scores.user_id")).
+		Order("match_scores.score desc").Limit(limit).Find(&scores).Error
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, 0, len(scores))
+	for _, sc := range scores {
+		ids = append(ids, sc.UserID)
+	}
+	var profiles []Profile
+	err = s.db.WithContext(ctx).Preload("Experiences").Preload("Educations").
+		Where("user_id IN ?", ids).Find(&profiles).Error
+	if err != nil {
+		return nil, err
+	}
+	byUser := make(map[uint]Profile, len(profiles))
+	for _, p := range profiles {
+		byUser[p.UserID] = p
+	}
+
+	out := make([]CandidateMatch, 0, len(scores))
+	for _, sc := range scores {
+		p, ok := byUser[sc.UserID]
+		if !ok {
+			continue
+		}
+		out = append(out, CandidateMatch{Profile: p, Score: sc.Score, Breakdown: sc.Breakdown})
+	}
+	return out, nil
+}
+
+// ProfilesAfter pages through the discoverable profiles by id, with their experience entries loaded.
+func (s *Conn) ProfilesAfter(ctx context.Context, afterId uint, limit int) ([]Profile, error) {
+	var profiles []Profile
+	err := s.db.WithContext(ctx).Preload("Experiences").
+		Where("id > ? AND discoverable", afterId).Order("id").Limit(limit).Find(&profiles).Error
+	if err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+// ApplicantProfiles returns the profiles of the candidates who applied to the job, with their experience entries loaded.
+func (s *Conn) ApplicantProfiles(ctx context.Context, jobId uint) ([]Profile, error) {
+	var profiles []Profile
+	err := s.db.WithContext(ctx).Preload("Experiences").
+		Where("user_id IN (?)", s.db.Model(&Application{}).Select("user_id").Where("job_id = ?", jobId)).
+		Order("id").Find(&profiles).Error
+	if err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+// JobsAfter pages through all published jobs by id.
+func (s *Conn) JobsAfter(ctx context.Context, afterId uint, limit int) ([]Job, error) {
+	var jobs []Job
+	err := s.db.WithContext(ctx).Scopes(listed).Where("id > ?", afterId).Order("id").Limit(limit).Find(&jobs).Error
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
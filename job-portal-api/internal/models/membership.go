@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Roles of a company member, from most to least privileged.
+// Owners manage everything including other owners, admins manage members, webhooks and the company page,
+// recruiters manage jobs and applications, viewers only read.
+const (
+	RoleOwner     = "owner"
+	RoleAdmin     = "admin"
+	RoleRecruiter = "recruiter"
+	RoleViewer    = "viewer"
+)
+
+// roleRanks orders the roles, a higher rank grants everything a lower one does.
+var roleRanks = map[string]int{RoleViewer: 1, RoleRecruiter: 2, RoleAdmin: 3, RoleOwner: 4}
+
+// RoleAtLeast reports whether role grants everything min does.
+func RoleAtLeast(role, min string) bool {
+	return roleRanks[role] > 0 && roleRanks[role] >= roleRanks[min]
+}
+
+// RolesAtLeast lists the roles granting everything min does, for queries filtering on roles.
+func RolesAtLeast(min string) []string {
+	var roles []string
+	for _, r := range []string{RoleOwner, RoleAdmin, RoleRecruiter, RoleViewer} {
+		if RoleAtLeast(r, min) {
+			roles = append(roles, r)
+		}
+	}
+	return roles
+}
+
+// Membership makes a user part of a company with a role.
+type Membership struct {
+	gorm.Model
+	CompanyID uint   `json:"company_id" gorm:"uniqueIndex:idx_membership_company_user;not null"`
+	UserID    uint   `json:"user_id" gorm:"uniqueIndex:idx_membership_company_user;index;not null"`
+	Role      string `json:"role" gorm:"not null"`
+	// Filled in when listing the members of a company
+	Name  string `json:"name,omitempty" gorm:"-"`
+	Email string `json:"email,omitempty" gorm:"-"`
+}
+
+// MembershipUpdate is the payload accepted when changing the role of a member.
+type MembershipUpdate struct {
+	Role string `json:"role" validate:"required,oneof=owner admin recruiter viewer"`
+}
+
+// invitationTTL is how long an invitation can be accepted.
+const invitationTTL = 7 * 24 * time.Hour
+
+// Invitation asks whoever owns Email to join a company with a role.
+// Only a hash of the token mailed to them is kept.
+type Invitation struct {
+	gorm.Model
+	CompanyID  uint       `json:"company_id" gorm:"index;not null"`
+	Email      string     `json:"email"`
+	Role       string     `json:"role"`
+	InvitedBy  uint       `json:"invited_by"`
+	TokenHash  string     `json:"-" gorm:"uniqueIndex;not null"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+	AcceptedBy *uint      `json:"accepted_by,omitempty"`
+}
+
+// NewInvitation is the payload accepted when inviting someone to a company.
+type NewInvitation struct {
+	Email string `json:"email" validate:"required,email,max=200"`
+	Role  string `json:"role" validate:"required,oneof=owner admin recruiter viewer"`
+}
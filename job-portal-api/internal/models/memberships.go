@@ -0,0 +1,246 @@
+package models
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrLastOwner is returned when a change would leave a company without an owner.
+	ErrLastOwner = errors.New("a company needs at least one owner")
+	// ErrAlreadyMember is returned when inviting or adding someone who is already a member.
+	ErrAlreadyMember = errors.New("already a member of the company")
+	// ErrInvitationInvalid is returned for unknown, expired, revoked or used invitation tokens.
+	ErrInvitationInvalid = errors.New("invitation is invalid or expired")
+	// ErrInvitationEmail is returned when an invitation is accepted by a user with another email address.
+	ErrInvitationEmail = errors.New("invitation was sent to another email address")
+)
+
+// ViewMembership returns the membership of the user in the company,
+// gorm.ErrRecordNotFound when they are not a member.
+func (s *Conn) ViewMembership(ctx context.Context, companyId, userId uint) (Membership, error) {
+	var m Membership
+	err := s.db.WithContext(ctx).Where("company_id = ? AND user_id = ?", companyId, userId).First(&m).Error
+	if err != nil {
+		return Membership{}, err
+	}
+	return m, nil
+}
+
+// ViewMemberships lists the members of a company with their names and email addresses.
+func (s *Conn) ViewMemberships(ctx context.Context, companyId uint) ([]Membership, error) {
+	var members = make([]Membership, 0, 10)
+	err := s.db.WithContext(ctx).Where("company_id = ?", companyId).Order("id").Find(&members).Error
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uint, 0, len(members))
+	for _, m := range members {
+		ids = append(ids, m.UserID)
+	}
+	var users []User
+	if len(ids) > 0 {
+		err = s.db.WithContext(ctx).Select("id", "name", "email").Where("id IN ?", ids).Find(&users).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+	byID := make(map[uint]User, len(users))
+	for _, u := range users {
+		byID[u.ID] = u
+	}
+	for i := range members {
+		members[i].Name = byID[members[i].UserID].Name
+		members[i].Email = byID[members[i].UserID].Email
+	}
+	return members, nil
+}
+
+// ViewUserMemberships lists the companies the user is a member of.
+func (s *Conn) ViewUserMemberships(ctx context.Context, userId uint) ([]Membership, error) {
+	var members = make([]Membership, 0, 10)
+	err := s.db.WithContext(ctx).Where("user_id = ?", userId).Order("company_id").Find(&members).Error
+	if err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// UpdateMembership changes the role of a member. The last owner cannot step down.
+func (s *Conn) UpdateMembership(ctx context.Context, companyId, userId uint, role string) (Membership, error) {
+	var m Membership
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("company_id = ? AND user_id = ?", companyId, userId).First(&m).Error
+		if err != nil {
+			return err
+		}
+		if m.Role == RoleOwner && role != RoleOwner {
+			err = keepOwner(tx, companyId)
+			if err != nil {
+				return err
+			}
+		}
+		m.Role = role
+		return tx.Model(&m).Update("role", role).Error
+	})
+	if err != nil {
+		return Membership{}, err
+	}
+	return m, nil
+}
+
+// DeleteMembership removes a member from a company. The last owner cannot leave.
+func (s *Conn) DeleteMembership(ctx context.Context, companyId, userId uint) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var m Membership
+		err := tx.Where("company_id = ? AND user_id = ?", companyId, userId).First(&m).Error
+		if err != nil {
+			return err
+		}
+		if m.Role == RoleOwner {
+			err = keepOwner(tx, companyId)
+			if err != nil {
+				return err
+			}
+		}
+		// Deleted for good, so the user can be invited again
+		return tx.Unscoped().Delete(&m).Error
+	})
+}
+
+// keepOwner fails with ErrLastOwner unless the company has another owner besides the one being removed.
+// The owners are locked, so two owners cannot step down at the same time.
+func keepOwner(tx *gorm.DB, companyId uint) error {
+	var owners []Membership
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("company_id = ? AND role = ?", companyId, RoleOwner).Find(&owners).Error
+	if err != nil {
+		return err
+	}
+	if len(owners) < 2 {
+		return ErrLastOwner
+	}
+	return nil
+}
+
+// CreateInvitation invites the email address to join the company.
+// The token to accept it is returned here and never shown again.
+func (s *Conn) CreateInvitation(ctx context.Context, companyId, invitedBy uint, ni NewInvitation) (Invitation, string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return Invitation{}, "", err
+	}
+	token := hex.EncodeToString(b)
+	inv := Invitation{
+		CompanyID: companyId,
+		Email:     strings.ToLower(strings.TrimSpace(ni.Email)),
+		Role:      ni.Role,
+		InvitedBy: invitedBy,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().UTC().Add(invitationTTL),
+	}
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Select("id").First(&Company{}, companyId).Error
+		if err != nil {
+			return err
+		}
+		var n int64
+		err = tx.Model(&Membership{}).Joins("JOIN users ON users.id = memberships.user_id").
+			Where("memberships.company_id = ? AND lower(users.email) = ?", companyId, inv.Email).Count(&n).Error
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			return ErrAlreadyMember
+		}
+		return tx.Create(&inv).Error
+	})
+	if err != nil {
+		return Invitation{}, "", err
+	}
+	return inv, token, nil
+}
+
+// ViewInvitations lists the invitations of a company that can still be accepted.
+func (s *Conn) ViewInvitations(ctx context.Context, companyId uint) ([]Invitation, error) {
+	var invs = make([]Invitation, 0, 10)
+	err := s.db.WithContext(ctx).
+		Where("company_id = ? AND accepted_at IS NULL AND expires_at > ?", companyId, time.Now().UTC()).
+		Order("id").Find(&invs).Error
+	if err != nil {
+		return nil, err
+	}
+	return invs, nil
+}
+
+// DeleteInvitation revokes a pending invitation of the company.
+func (s *Conn) DeleteInvitation(ctx context.Context, companyId, invitationId uint) error {
+	res := s.db.WithContext(ctx).Where("company_id = ? AND accepted_at IS NULL", companyId).
+		Delete(&Invitation{}, invitationId)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// AcceptInvitation makes the user a member of the company they were invited to.
+// The user has to be registered with the email address the invitation was sent to.
+func (s *Conn) AcceptInvitation(ctx context.Context, token string, userId uint) (Membership, error) {
+	var m Membership
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var inv Invitation
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ?", hashToken(token)).First(&inv).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvitationInvalid
+		}
+		if err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		if inv.AcceptedAt != nil || !inv.ExpiresAt.After(now) {
+			return ErrInvitationInvalid
+		}
+
+		var u User
+		err = tx.Select("id", "email").First(&u, userId).Error
+		if err != nil {
+			return err
+		}
+		if !strings.EqualFold(strings.TrimSpace(u.Email), inv.Email) {
+			return ErrInvitationEmail
+		}
+
+		m = Membership{CompanyID: inv.CompanyID, UserID: userId, Role: inv.Role}
+		err = tx.Create(&m).Error
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return ErrAlreadyMember
+		}
+		if err != nil {
+			return err
+		}
+		return tx.Model(&inv).Updates(map[string]any{"accepted_at": now, "accepted_by": userId}).Error
+	})
+	if err != nil {
+		return Membership{}, err
+	}
+	return m, nil
+}
+
+// hashToken is what is kept of an invitation token.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -1,5 +1,7 @@
 package models
 
+import "gorm.io/gorm"
+
 func (s *Conn) AutoMigrate() error {
 	//if s.db.Migrator().HasTable(&User{}) {
 	//	return nil
@@ -7,13 +9,40 @@ func (s *Conn) AutoMigrate() error {
 
 
 	// AutoMigrate function will ONLY create tables, missing columns and missing indexes, and WON'T change existing column's type or delete unused columns
-	err := s.db.Migrator().AutoMigrate(&User{},&Company{},&Job{})
+	err := s.db.Migrator().AutoMigrate(&User{},&Company{},&Job{},
+		&Profile{}, &Experience{}, &Education{}, &Document{}, &Application{}, &ResumeParse{}, &MatchScore{},
+		&SavedSearch{}, &SentAlert{}, &Task{}, &OutboxEvent{},
+		&Webhook{}, &WebhookDelivery{}, &AuditEntry{}, &Membership{}, &Invitation{},
+		&Interview{}, &InterviewSlot{}, &CalendarFeed{}, &Conversation{}, &Message{}, &ConversationRead{},
+		&PipelineStage{}, &ApplicationStageChange{}, &ScorecardTemplate{}, &Scorecard{},
+		&ScreeningQuestion{}, &ApplicationAnswer{}, &OfferTemplate{}, &Offer{},
+		&TalentPool{}, &PoolMember{}, &JobInvitation{}, &Bookmark{}, &CompanyFollow{},
+		&JobActivity{}, &JobDailyStat{}, &CompanyReview{})
 	if err != nil {
 		// If there is an error while migrating, log the error message and stop the program
 		return err
 	}
 	s.db.AutoMigrate(&Company{}, &Job{})
 
+	// Jobs posted before the lifecycle existed were published when they were created
+	err = s.db.Model(&Job{}).Where("status = ? AND published_at IS NULL", JobPublished).
+		UpdateColumn("published_at", gorm.Expr("created_at")).Error
+	if err != nil {
+		return err
+	}
+
+	// Companies and jobs saved before locations were resolved are placed on the map
+	err = backfillGeo(s.db)
+	if err != nil {
+		return err
+	}
+
+	// Analytics start from the applications already submitted
+	err = backfillJobActivity(s.db)
+	if err != nil {
+		return err
+	}
+
 	// Add foreign key constraint
 
 	return nil
@@ -5,6 +5,7 @@ import (
 
 	models "job-portal-api/internal/models"
 	reflect "reflect"
+	time "time"
 
 	v5 "github.com/golang-jwt/jwt/v5"
 	gomock "go.uber.org/mock/gomock"
@@ -195,3 +196,2158 @@ func (mr *MockServiceMockRecorder) Login(ctx, email, password interface{}) *gomo
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockService)(nil).Login), ctx, email, password)
 }
+
+// ViewProfile mocks base method.
+func (m *MockService) ViewProfile(ctx context.Context, userId uint) (models.Profile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewProfile", ctx, userId)
+	ret0, _ := ret[0].(models.Profile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewProfile indicates an expected call of ViewProfile.
+func (mr *MockServiceMockRecorder) ViewProfile(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewProfile", reflect.TypeOf((*MockService)(nil).ViewProfile), ctx, userId)
+}
+
+// UpdateProfile mocks base method.
+func (m *MockService) UpdateProfile(ctx context.Context, np models.NewProfile, userId uint) (models.Profile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProfile", ctx, np, userId)
+	ret0, _ := ret[0].(models.Profile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateProfile indicates an expected call of UpdateProfile.
+func (mr *MockServiceMockRecorder) UpdateProfile(ctx, np, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProfile", reflect.TypeOf((*MockService)(nil).UpdateProfile), ctx, np, userId)
+}
+
+// DeleteProfile mocks base method.
+func (m *MockService) DeleteProfile(ctx context.Context, userId uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteProfile", ctx, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteProfile indicates an expected call of DeleteProfile.
+func (mr *MockServiceMockRecorder) DeleteProfile(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProfile", reflect.TypeOf((*MockService)(nil).DeleteProfile), ctx, userId)
+}
+
+// CreateDocument mocks base method.
+func (m *MockService) CreateDocument(ctx context.Context, doc models.Document) (models.Document, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDocument", ctx, doc)
+	ret0, _ := ret[0].(models.Document)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDocument indicates an expected call of CreateDocument.
+func (mr *MockServiceMockRecorder) CreateDocument(ctx, doc interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDocument", reflect.TypeOf((*MockService)(nil).CreateDocument), ctx, doc)
+}
+
+// ViewDocuments mocks base method.
+func (m *MockService) ViewDocuments(ctx context.Context, ownerId uint) ([]models.Document, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewDocuments", ctx, ownerId)
+	ret0, _ := ret[0].([]models.Document)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewDocuments indicates an expected call of ViewDocuments.
+func (mr *MockServiceMockRecorder) ViewDocuments(ctx, ownerId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewDocuments", reflect.TypeOf((*MockService)(nil).ViewDocuments), ctx, ownerId)
+}
+
+// ViewDocument mocks base method.
+func (m *MockService) ViewDocument(ctx context.Context, documentId uint) (models.Document, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewDocument", ctx, documentId)
+	ret0, _ := ret[0].(models.Document)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewDocument indicates an expected call of ViewDocument.
+func (mr *MockServiceMockRecorder) ViewDocument(ctx, documentId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewDocument", reflect.TypeOf((*MockService)(nil).ViewDocument), ctx, documentId)
+}
+
+// DeleteDocument mocks base method.
+func (m *MockService) DeleteDocument(ctx context.Context, documentId uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDocument", ctx, documentId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDocument indicates an expected call of DeleteDocument.
+func (mr *MockServiceMockRecorder) DeleteDocument(ctx, documentId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDocument", reflect.TypeOf((*MockService)(nil).DeleteDocument), ctx, documentId)
+}
+
+// SetCompanyLogo mocks base method.
+func (m *MockService) SetCompanyLogo(ctx context.Context, companyId uint, documentId uint) (models.Company, models.Document, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCompanyLogo", ctx, companyId, documentId)
+	ret0, _ := ret[0].(models.Company)
+	ret1, _ := ret[1].(models.Document)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SetCompanyLogo indicates an expected call of SetCompanyLogo.
+func (mr *MockServiceMockRecorder) SetCompanyLogo(ctx, companyId, documentId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCompanyLogo", reflect.TypeOf((*MockService)(nil).SetCompanyLogo), ctx, companyId, documentId)
+}
+
+// CreateApplication mocks base method.
+func (m *MockService) CreateApplication(ctx context.Context, na models.NewApplication, jobId uint, userId uint) (models.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateApplication", ctx, na, jobId, userId)
+	ret0, _ := ret[0].(models.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateApplication indicates an expected call of CreateApplication.
+func (mr *MockServiceMockRecorder) CreateApplication(ctx, na, jobId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateApplication", reflect.TypeOf((*MockService)(nil).CreateApplication), ctx, na, jobId, userId)
+}
+
+// ViewApplication mocks base method.
+func (m *MockService) ViewApplication(ctx context.Context, applicationId uint) (models.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewApplication", ctx, applicationId)
+	ret0, _ := ret[0].(models.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewApplication indicates an expected call of ViewApplication.
+func (mr *MockServiceMockRecorder) ViewApplication(ctx, applicationId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewApplication", reflect.TypeOf((*MockService)(nil).ViewApplication), ctx, applicationId)
+}
+
+// ViewApplicationsByJob mocks base method.
+func (m *MockService) ViewApplicationsByJob(ctx context.Context, jobId uint, f models.ApplicationFilter) ([]models.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewApplicationsByJob", ctx, jobId, f)
+	ret0, _ := ret[0].([]models.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewApplicationsByJob indicates an expected call of ViewApplicationsByJob.
+func (mr *MockServiceMockRecorder) ViewApplicationsByJob(ctx, jobId, f interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewApplicationsByJob", reflect.TypeOf((*MockService)(nil).ViewApplicationsByJob), ctx, jobId, f)
+}
+
+// ViewApplicationsByUser mocks base method.
+func (m *MockService) ViewApplicationsByUser(ctx context.Context, userId uint) ([]models.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewApplicationsByUser", ctx, userId)
+	ret0, _ := ret[0].([]models.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewApplicationsByUser indicates an expected call of ViewApplicationsByUser.
+func (mr *MockServiceMockRecorder) ViewApplicationsByUser(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewApplicationsByUser", reflect.TypeOf((*MockService)(nil).ViewApplicationsByUser), ctx, userId)
+}
+
+// StartResumeParse mocks base method.
+func (m *MockService) StartResumeParse(ctx context.Context, documentId uint, userId uint) (models.ResumeParse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartResumeParse", ctx, documentId, userId)
+	ret0, _ := ret[0].(models.ResumeParse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartResumeParse indicates an expected call of StartResumeParse.
+func (mr *MockServiceMockRecorder) StartResumeParse(ctx, documentId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartResumeParse", reflect.TypeOf((*MockService)(nil).StartResumeParse), ctx, documentId, userId)
+}
+
+// FinishResumeParse mocks base method.
+func (m *MockService) FinishResumeParse(ctx context.Context, documentId uint, draft models.ResumeDraft, parseErr string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishResumeParse", ctx, documentId, draft, parseErr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishResumeParse indicates an expected call of FinishResumeParse.
+func (mr *MockServiceMockRecorder) FinishResumeParse(ctx, documentId, draft, parseErr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishResumeParse", reflect.TypeOf((*MockService)(nil).FinishResumeParse), ctx, documentId, draft, parseErr)
+}
+
+// ViewResumeParse mocks base method.
+func (m *MockService) ViewResumeParse(ctx context.Context, documentId uint) (models.ResumeParse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewResumeParse", ctx, documentId)
+	ret0, _ := ret[0].(models.ResumeParse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewResumeParse indicates an expected call of ViewResumeParse.
+func (mr *MockServiceMockRecorder) ViewResumeParse(ctx, documentId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewResumeParse", reflect.TypeOf((*MockService)(nil).ViewResumeParse), ctx, documentId)
+}
+
+// ReplaceJobMatches mocks base method.
+func (m *MockService) ReplaceJobMatches(ctx context.Context, jobId uint, scores []models.MatchScore) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceJobMatches", ctx, jobId, scores)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceJobMatches indicates an expected call of ReplaceJobMatches.
+func (mr *MockServiceMockRecorder) ReplaceJobMatches(ctx, jobId, scores interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceJobMatches", reflect.TypeOf((*MockService)(nil).ReplaceJobMatches), ctx, jobId, scores)
+}
+
+// ReplaceProfileMatches mocks base method.
+func (m *MockService) ReplaceProfileMatches(ctx context.Context, userId uint, scores []models.MatchScore) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceProfileMatches", ctx, userId, scores)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceProfileMatches indicates an expected call of ReplaceProfileMatches.
+func (mr *MockServiceMockRecorder) ReplaceProfileMatches(ctx, userId, scores interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceProfileMatches", reflect.TypeOf((*MockService)(nil).ReplaceProfileMatches), ctx, userId, scores)
+}
+
+// CountStaleMatchScores mocks base method.
+func (m *MockService) CountStaleMatchScores(ctx context.Context, weights string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountStaleMatchScores", ctx, weights)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountStaleMatchScores indicates an expected call of CountStaleMatchScores.
+func (mr *MockServiceMockRecorder) CountStaleMatchScores(ctx, weights interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountStaleMatchScores", reflect.TypeOf((*MockService)(nil).CountStaleMatchScores), ctx, weights)
+}
+
+// ViewJobMatches mocks base method.
+func (m *MockService) ViewJobMatches(ctx context.Context, userId uint, limit int) ([]models.JobMatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewJobMatches", ctx, userId, limit)
+	ret0, _ := ret[0].([]models.JobMatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewJobMatches indicates an expected call of ViewJobMatches.
+func (mr *MockServiceMockRecorder) ViewJobMatches(ctx, userId, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewJobMatches", reflect.TypeOf((*MockService)(nil).ViewJobMatches), ctx, userId, limit)
+}
+
+// ViewCandidateMatches mocks base method.
+func (m *MockService) ViewCandidateMatches(ctx context.Context, jobId uint, limit int) ([]models.CandidateMatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewCandidateMatches", ctx, jobId, limit)
+	ret0, _ := ret[0].([]models.CandidateMatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewCandidateMatches indicates an expected call of ViewCandidateMatches.
+func (mr *MockServiceMockRecorder) ViewCandidateMatches(ctx, jobId, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewCandidateMatches", reflect.TypeOf((*MockService)(nil).ViewCandidateMatches), ctx, jobId, limit)
+}
+
+// ProfilesAfter mocks base method.
+func (m *MockService) ProfilesAfter(ctx context.Context, afterId uint, limit int) ([]models.Profile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProfilesAfter", ctx, afterId, limit)
+	ret0, _ := ret[0].([]models.Profile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProfilesAfter indicates an expected call of ProfilesAfter.
+func (mr *MockServiceMockRecorder) ProfilesAfter(ctx, afterId, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProfilesAfter", reflect.TypeOf((*MockService)(nil).ProfilesAfter), ctx, afterId, limit)
+}
+
+// JobsAfter mocks base method.
+func (m *MockService) JobsAfter(ctx context.Context, afterId uint, limit int) ([]models.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JobsAfter", ctx, afterId, limit)
+	ret0, _ := ret[0].([]models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// JobsAfter indicates an expected call of JobsAfter.
+func (mr *MockServiceMockRecorder) JobsAfter(ctx, afterId, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JobsAfter", reflect.TypeOf((*MockService)(nil).JobsAfter), ctx, afterId, limit)
+}
+
+// ViewUser mocks base method.
+func (m *MockService) ViewUser(ctx context.Context, userId uint) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewUser", ctx, userId)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewUser indicates an expected call of ViewUser.
+func (mr *MockServiceMockRecorder) ViewUser(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewUser", reflect.TypeOf((*MockService)(nil).ViewUser), ctx, userId)
+}
+
+// CreateSavedSearch mocks base method.
+func (m *MockService) CreateSavedSearch(ctx context.Context, ns models.NewSavedSearch, userId uint) (models.SavedSearch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSavedSearch", ctx, ns, userId)
+	ret0, _ := ret[0].(models.SavedSearch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSavedSearch indicates an expected call of CreateSavedSearch.
+func (mr *MockServiceMockRecorder) CreateSavedSearch(ctx, ns, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSavedSearch", reflect.TypeOf((*MockService)(nil).CreateSavedSearch), ctx, ns, userId)
+}
+
+// ViewSavedSearches mocks base method.
+func (m *MockService) ViewSavedSearches(ctx context.Context, userId uint) ([]models.SavedSearch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewSavedSearches", ctx, userId)
+	ret0, _ := ret[0].([]models.SavedSearch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewSavedSearches indicates an expected call of ViewSavedSearches.
+func (mr *MockServiceMockRecorder) ViewSavedSearches(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewSavedSearches", reflect.TypeOf((*MockService)(nil).ViewSavedSearches), ctx, userId)
+}
+
+// UpdateSavedSearch mocks base method.
+func (m *MockService) UpdateSavedSearch(ctx context.Context, ns models.NewSavedSearch, searchId uint, userId uint) (models.SavedSearch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSavedSearch", ctx, ns, searchId, userId)
+	ret0, _ := ret[0].(models.SavedSearch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSavedSearch indicates an expected call of UpdateSavedSearch.
+func (mr *MockServiceMockRecorder) UpdateSavedSearch(ctx, ns, searchId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSavedSearch", reflect.TypeOf((*MockService)(nil).UpdateSavedSearch), ctx, ns, searchId, userId)
+}
+
+// DeleteSavedSearch mocks base method.
+func (m *MockService) DeleteSavedSearch(ctx context.Context, searchId uint, userId uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSavedSearch", ctx, searchId, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSavedSearch indicates an expected call of DeleteSavedSearch.
+func (mr *MockServiceMockRecorder) DeleteSavedSearch(ctx, searchId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSavedSearch", reflect.TypeOf((*MockService)(nil).DeleteSavedSearch), ctx, searchId, userId)
+}
+
+// UnsubscribeSavedSearch mocks base method.
+func (m *MockService) UnsubscribeSavedSearch(ctx context.Context, token string) (models.SavedSearch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnsubscribeSavedSearch", ctx, token)
+	ret0, _ := ret[0].(models.SavedSearch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnsubscribeSavedSearch indicates an expected call of UnsubscribeSavedSearch.
+func (mr *MockServiceMockRecorder) UnsubscribeSavedSearch(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnsubscribeSavedSearch", reflect.TypeOf((*MockService)(nil).UnsubscribeSavedSearch), ctx, token)
+}
+
+// DueSavedSearches mocks base method.
+func (m *MockService) DueSavedSearches(ctx context.Context, now time.Time, limit int) ([]models.SavedSearch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DueSavedSearches", ctx, now, limit)
+	ret0, _ := ret[0].([]models.SavedSearch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DueSavedSearches indicates an expected call of DueSavedSearches.
+func (mr *MockServiceMockRecorder) DueSavedSearches(ctx, now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DueSavedSearches", reflect.TypeOf((*MockService)(nil).DueSavedSearches), ctx, now, limit)
+}
+
+// JobsPublishedBetween mocks base method.
+func (m *MockService) JobsPublishedBetween(ctx context.Context, from time.Time, to time.Time) ([]models.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JobsPublishedBetween", ctx, from, to)
+	ret0, _ := ret[0].([]models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// JobsPublishedBetween indicates an expected call of JobsPublishedBetween.
+func (mr *MockServiceMockRecorder) JobsPublishedBetween(ctx, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JobsPublishedBetween", reflect.TypeOf((*MockService)(nil).JobsPublishedBetween), ctx, from, to)
+}
+
+// SentJobIDs mocks base method.
+func (m *MockService) SentJobIDs(ctx context.Context, userId uint, jobIds []uint) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SentJobIDs", ctx, userId, jobIds)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SentJobIDs indicates an expected call of SentJobIDs.
+func (mr *MockServiceMockRecorder) SentJobIDs(ctx, userId, jobIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SentJobIDs", reflect.TypeOf((*MockService)(nil).SentJobIDs), ctx, userId, jobIds)
+}
+
+// RecordDigest mocks base method.
+func (m *MockService) RecordDigest(ctx context.Context, userId uint, jobIds []uint, searches []models.SavedSearch, runAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordDigest", ctx, userId, jobIds, searches, runAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordDigest indicates an expected call of RecordDigest.
+func (mr *MockServiceMockRecorder) RecordDigest(ctx, userId, jobIds, searches, runAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordDigest", reflect.TypeOf((*MockService)(nil).RecordDigest), ctx, userId, jobIds, searches, runAt)
+}
+
+// EnqueueTask mocks base method.
+func (m *MockService) EnqueueTask(ctx context.Context, t models.Task) (models.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueTask", ctx, t)
+	ret0, _ := ret[0].(models.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnqueueTask indicates an expected call of EnqueueTask.
+func (mr *MockServiceMockRecorder) EnqueueTask(ctx, t interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueTask", reflect.TypeOf((*MockService)(nil).EnqueueTask), ctx, t)
+}
+
+// ClaimTasks mocks base method.
+func (m *MockService) ClaimTasks(ctx context.Context, kinds []string, worker string, limit int, now time.Time) ([]models.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimTasks", ctx, kinds, worker, limit, now)
+	ret0, _ := ret[0].([]models.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimTasks indicates an expected call of ClaimTasks.
+func (mr *MockServiceMockRecorder) ClaimTasks(ctx, kinds, worker, limit, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimTasks", reflect.TypeOf((*MockService)(nil).ClaimTasks), ctx, kinds, worker, limit, now)
+}
+
+// FinishTask mocks base method.
+func (m *MockService) FinishTask(ctx context.Context, taskId uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishTask", ctx, taskId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishTask indicates an expected call of FinishTask.
+func (mr *MockServiceMockRecorder) FinishTask(ctx, taskId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishTask", reflect.TypeOf((*MockService)(nil).FinishTask), ctx, taskId)
+}
+
+// RetryTask mocks base method.
+func (m *MockService) RetryTask(ctx context.Context, taskId uint, runAt time.Time, lastErr string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetryTask", ctx, taskId, runAt, lastErr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetryTask indicates an expected call of RetryTask.
+func (mr *MockServiceMockRecorder) RetryTask(ctx, taskId, runAt, lastErr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryTask", reflect.TypeOf((*MockService)(nil).RetryTask), ctx, taskId, runAt, lastErr)
+}
+
+// BuryTask mocks base method.
+func (m *MockService) BuryTask(ctx context.Context, taskId uint, lastErr string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BuryTask", ctx, taskId, lastErr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BuryTask indicates an expected call of BuryTask.
+func (mr *MockServiceMockRecorder) BuryTask(ctx, taskId, lastErr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuryTask", reflect.TypeOf((*MockService)(nil).BuryTask), ctx, taskId, lastErr)
+}
+
+// RequeueStaleTasks mocks base method.
+func (m *MockService) RequeueStaleTasks(ctx context.Context, lockedBefore time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequeueStaleTasks", ctx, lockedBefore)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequeueStaleTasks indicates an expected call of RequeueStaleTasks.
+func (mr *MockServiceMockRecorder) RequeueStaleTasks(ctx, lockedBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequeueStaleTasks", reflect.TypeOf((*MockService)(nil).RequeueStaleTasks), ctx, lockedBefore)
+}
+
+// PurgeFinishedTasks mocks base method.
+func (m *MockService) PurgeFinishedTasks(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeFinishedTasks", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeFinishedTasks indicates an expected call of PurgeFinishedTasks.
+func (mr *MockServiceMockRecorder) PurgeFinishedTasks(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeFinishedTasks", reflect.TypeOf((*MockService)(nil).PurgeFinishedTasks), ctx, before)
+}
+
+// ViewDeadTasks mocks base method.
+func (m *MockService) ViewDeadTasks(ctx context.Context, limit int) ([]models.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewDeadTasks", ctx, limit)
+	ret0, _ := ret[0].([]models.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewDeadTasks indicates an expected call of ViewDeadTasks.
+func (mr *MockServiceMockRecorder) ViewDeadTasks(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewDeadTasks", reflect.TypeOf((*MockService)(nil).ViewDeadTasks), ctx, limit)
+}
+
+// RequeueDeadTask mocks base method.
+func (m *MockService) RequeueDeadTask(ctx context.Context, taskId uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequeueDeadTask", ctx, taskId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequeueDeadTask indicates an expected call of RequeueDeadTask.
+func (mr *MockServiceMockRecorder) RequeueDeadTask(ctx, taskId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequeueDeadTask", reflect.TypeOf((*MockService)(nil).RequeueDeadTask), ctx, taskId)
+}
+
+// RelayEvents mocks base method.
+func (m *MockService) RelayEvents(ctx context.Context, limit int, retry models.EventRetry, publish func(e models.OutboxEvent) error) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RelayEvents", ctx, limit, retry, publish)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RelayEvents indicates an expected call of RelayEvents.
+func (mr *MockServiceMockRecorder) RelayEvents(ctx, limit, retry, publish interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RelayEvents", reflect.TypeOf((*MockService)(nil).RelayEvents), ctx, limit, retry, publish)
+}
+
+// PurgePublishedEvents mocks base method.
+func (m *MockService) PurgePublishedEvents(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgePublishedEvents", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgePublishedEvents indicates an expected call of PurgePublishedEvents.
+func (mr *MockServiceMockRecorder) PurgePublishedEvents(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgePublishedEvents", reflect.TypeOf((*MockService)(nil).PurgePublishedEvents), ctx, before)
+}
+
+// CreateWebhook mocks base method.
+func (m *MockService) CreateWebhook(ctx context.Context, nw models.NewWebhook, companyId uint) (models.Webhook, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, nw, companyId)
+	ret0, _ := ret[0].(models.Webhook)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockServiceMockRecorder) CreateWebhook(ctx, nw, companyId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockService)(nil).CreateWebhook), ctx, nw, companyId)
+}
+
+// ViewWebhooks mocks base method.
+func (m *MockService) ViewWebhooks(ctx context.Context, companyId uint) ([]models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewWebhooks", ctx, companyId)
+	ret0, _ := ret[0].([]models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewWebhooks indicates an expected call of ViewWebhooks.
+func (mr *MockServiceMockRecorder) ViewWebhooks(ctx, companyId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewWebhooks", reflect.TypeOf((*MockService)(nil).ViewWebhooks), ctx, companyId)
+}
+
+// ViewWebhook mocks base method.
+func (m *MockService) ViewWebhook(ctx context.Context, webhookId uint) (models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewWebhook", ctx, webhookId)
+	ret0, _ := ret[0].(models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewWebhook indicates an expected call of ViewWebhook.
+func (mr *MockServiceMockRecorder) ViewWebhook(ctx, webhookId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewWebhook", reflect.TypeOf((*MockService)(nil).ViewWebhook), ctx, webhookId)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockService) DeleteWebhook(ctx context.Context, webhookId uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, webhookId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockServiceMockRecorder) DeleteWebhook(ctx, webhookId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockService)(nil).DeleteWebhook), ctx, webhookId)
+}
+
+// EnableWebhook mocks base method.
+func (m *MockService) EnableWebhook(ctx context.Context, webhookId uint) (models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableWebhook", ctx, webhookId)
+	ret0, _ := ret[0].(models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnableWebhook indicates an expected call of EnableWebhook.
+func (mr *MockServiceMockRecorder) EnableWebhook(ctx, webhookId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableWebhook", reflect.TypeOf((*MockService)(nil).EnableWebhook), ctx, webhookId)
+}
+
+// ActiveWebhooks mocks base method.
+func (m *MockService) ActiveWebhooks(ctx context.Context, companyId uint, eventType string) ([]models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ActiveWebhooks", ctx, companyId, eventType)
+	ret0, _ := ret[0].([]models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ActiveWebhooks indicates an expected call of ActiveWebhooks.
+func (mr *MockServiceMockRecorder) ActiveWebhooks(ctx, companyId, eventType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActiveWebhooks", reflect.TypeOf((*MockService)(nil).ActiveWebhooks), ctx, companyId, eventType)
+}
+
+// RecordWebhookDelivery mocks base method.
+func (m *MockService) RecordWebhookDelivery(ctx context.Context, d models.WebhookDelivery, disableAfter int) (models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordWebhookDelivery", ctx, d, disableAfter)
+	ret0, _ := ret[0].(models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordWebhookDelivery indicates an expected call of RecordWebhookDelivery.
+func (mr *MockServiceMockRecorder) RecordWebhookDelivery(ctx, d, disableAfter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordWebhookDelivery", reflect.TypeOf((*MockService)(nil).RecordWebhookDelivery), ctx, d, disableAfter)
+}
+
+// ViewWebhookDeliveries mocks base method.
+func (m *MockService) ViewWebhookDeliveries(ctx context.Context, webhookId uint, limit int) ([]models.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewWebhookDeliveries", ctx, webhookId, limit)
+	ret0, _ := ret[0].([]models.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewWebhookDeliveries indicates an expected call of ViewWebhookDeliveries.
+func (mr *MockServiceMockRecorder) ViewWebhookDeliveries(ctx, webhookId, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewWebhookDeliveries", reflect.TypeOf((*MockService)(nil).ViewWebhookDeliveries), ctx, webhookId, limit)
+}
+
+// ViewAuditLog mocks base method.
+func (m *MockService) ViewAuditLog(ctx context.Context, f models.AuditFilter) ([]models.AuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewAuditLog", ctx, f)
+	ret0, _ := ret[0].([]models.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewAuditLog indicates an expected call of ViewAuditLog.
+func (mr *MockServiceMockRecorder) ViewAuditLog(ctx, f interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewAuditLog", reflect.TypeOf((*MockService)(nil).ViewAuditLog), ctx, f)
+}
+
+// CreateJobs mocks base method.
+func (m *MockService) CreateJobs(ctx context.Context, companyId uint, njs []models.NewJob) ([]models.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateJobs", ctx, companyId, njs)
+	ret0, _ := ret[0].([]models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateJobs indicates an expected call of CreateJobs.
+func (mr *MockServiceMockRecorder) CreateJobs(ctx, companyId, njs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateJobs", reflect.TypeOf((*MockService)(nil).CreateJobs), ctx, companyId, njs)
+}
+
+// EachCompanyJob mocks base method.
+func (m *MockService) EachCompanyJob(ctx context.Context, companyId uint, fn func(job models.Job) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EachCompanyJob", ctx, companyId, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EachCompanyJob indicates an expected call of EachCompanyJob.
+func (mr *MockServiceMockRecorder) EachCompanyJob(ctx, companyId, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EachCompanyJob", reflect.TypeOf((*MockService)(nil).EachCompanyJob), ctx, companyId, fn)
+}
+
+// FeedJobs mocks base method.
+func (m *MockService) FeedJobs(ctx context.Context, since time.Time, afterId uint, limit int) ([]models.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FeedJobs", ctx, since, afterId, limit)
+	ret0, _ := ret[0].([]models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FeedJobs indicates an expected call of FeedJobs.
+func (mr *MockServiceMockRecorder) FeedJobs(ctx, since, afterId, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FeedJobs", reflect.TypeOf((*MockService)(nil).FeedJobs), ctx, since, afterId, limit)
+}
+
+// ViewCompanies mocks base method.
+func (m *MockService) ViewCompanies(ctx context.Context, companyIds []uint) ([]models.Company, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewCompanies", ctx, companyIds)
+	ret0, _ := ret[0].([]models.Company)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewCompanies indicates an expected call of ViewCompanies.
+func (mr *MockServiceMockRecorder) ViewCompanies(ctx, companyIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewCompanies", reflect.TypeOf((*MockService)(nil).ViewCompanies), ctx, companyIds)
+}
+
+// TransitionJob mocks base method.
+func (m *MockService) TransitionJob(ctx context.Context, jobId uint, t models.JobTransition) (models.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransitionJob", ctx, jobId, t)
+	ret0, _ := ret[0].(models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransitionJob indicates an expected call of TransitionJob.
+func (mr *MockServiceMockRecorder) TransitionJob(ctx, jobId, t interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransitionJob", reflect.TypeOf((*MockService)(nil).TransitionJob), ctx, jobId, t)
+}
+
+// SweepJobs mocks base method.
+func (m *MockService) SweepJobs(ctx context.Context, now time.Time, limit int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SweepJobs", ctx, now, limit)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SweepJobs indicates an expected call of SweepJobs.
+func (mr *MockServiceMockRecorder) SweepJobs(ctx, now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SweepJobs", reflect.TypeOf((*MockService)(nil).SweepJobs), ctx, now, limit)
+}
+
+// ViewCompanyJobs mocks base method.
+func (m *MockService) ViewCompanyJobs(ctx context.Context, companyId uint, status string) ([]models.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewCompanyJobs", ctx, companyId, status)
+	ret0, _ := ret[0].([]models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewCompanyJobs indicates an expected call of ViewCompanyJobs.
+func (mr *MockServiceMockRecorder) ViewCompanyJobs(ctx, companyId, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewCompanyJobs", reflect.TypeOf((*MockService)(nil).ViewCompanyJobs), ctx, companyId, status)
+}
+
+// ViewMembership mocks base method.
+func (m *MockService) ViewMembership(ctx context.Context, companyId uint, userId uint) (models.Membership, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewMembership", ctx, companyId, userId)
+	ret0, _ := ret[0].(models.Membership)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewMembership indicates an expected call of ViewMembership.
+func (mr *MockServiceMockRecorder) ViewMembership(ctx, companyId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewMembership", reflect.TypeOf((*MockService)(nil).ViewMembership), ctx, companyId, userId)
+}
+
+// ViewMemberships mocks base method.
+func (m *MockService) ViewMemberships(ctx context.Context, companyId uint) ([]models.Membership, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewMemberships", ctx, companyId)
+	ret0, _ := ret[0].([]models.Membership)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewMemberships indicates an expected call of ViewMemberships.
+func (mr *MockServiceMockRecorder) ViewMemberships(ctx, companyId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewMemberships", reflect.TypeOf((*MockService)(nil).ViewMemberships), ctx, companyId)
+}
+
+// ViewUserMemberships mocks base method.
+func (m *MockService) ViewUserMemberships(ctx context.Context, userId uint) ([]models.Membership, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewUserMemberships", ctx, userId)
+	ret0, _ := ret[0].([]models.Membership)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewUserMemberships indicates an expected call of ViewUserMemberships.
+func (mr *MockServiceMockRecorder) ViewUserMemberships(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewUserMemberships", reflect.TypeOf((*MockService)(nil).ViewUserMemberships), ctx, userId)
+}
+
+// UpdateMembership mocks base method.
+func (m *MockService) UpdateMembership(ctx context.Context, companyId uint, userId uint, role string) (models.Membership, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMembership", ctx, companyId, userId, role)
+	ret0, _ := ret[0].(models.Membership)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateMembership indicates an expected call of UpdateMembership.
+func (mr *MockServiceMockRecorder) UpdateMembership(ctx, companyId, userId, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMembership", reflect.TypeOf((*MockService)(nil).UpdateMembership), ctx, companyId, userId, role)
+}
+
+// DeleteMembership mocks base method.
+func (m *MockService) DeleteMembership(ctx context.Context, companyId uint, userId uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMembership", ctx, companyId, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMembership indicates an expected call of DeleteMembership.
+func (mr *MockServiceMockRecorder) DeleteMembership(ctx, companyId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMembership", reflect.TypeOf((*MockService)(nil).DeleteMembership), ctx, companyId, userId)
+}
+
+// CreateInvitation mocks base method.
+func (m *MockService) CreateInvitation(ctx context.Context, companyId uint, invitedBy uint, ni models.NewInvitation) (models.Invitation, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInvitation", ctx, companyId, invitedBy, ni)
+	ret0, _ := ret[0].(models.Invitation)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateInvitation indicates an expected call of CreateInvitation.
+func (mr *MockServiceMockRecorder) CreateInvitation(ctx, companyId, invitedBy, ni interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInvitation", reflect.TypeOf((*MockService)(nil).CreateInvitation), ctx, companyId, invitedBy, ni)
+}
+
+// ViewInvitations mocks base method.
+func (m *MockService) ViewInvitations(ctx context.Context, companyId uint) ([]models.Invitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewInvitations", ctx, companyId)
+	ret0, _ := ret[0].([]models.Invitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewInvitations indicates an expected call of ViewInvitations.
+func (mr *MockServiceMockRecorder) ViewInvitations(ctx, companyId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewInvitations", reflect.TypeOf((*MockService)(nil).ViewInvitations), ctx, companyId)
+}
+
+// DeleteInvitation mocks base method.
+func (m *MockService) DeleteInvitation(ctx context.Context, companyId uint, invitationId uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteInvitation", ctx, companyId, invitationId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteInvitation indicates an expected call of DeleteInvitation.
+func (mr *MockServiceMockRecorder) DeleteInvitation(ctx, companyId, invitationId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteInvitation", reflect.TypeOf((*MockService)(nil).DeleteInvitation), ctx, companyId, invitationId)
+}
+
+// AcceptInvitation mocks base method.
+func (m *MockService) AcceptInvitation(ctx context.Context, token string, userId uint) (models.Membership, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptInvitation", ctx, token, userId)
+	ret0, _ := ret[0].(models.Membership)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcceptInvitation indicates an expected call of AcceptInvitation.
+func (mr *MockServiceMockRecorder) AcceptInvitation(ctx, token, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptInvitation", reflect.TypeOf((*MockService)(nil).AcceptInvitation), ctx, token, userId)
+}
+
+// CreateInterview mocks base method.
+func (m *MockService) CreateInterview(ctx context.Context, applicationId uint, recruiterId uint, ni models.NewInterview) (models.Interview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInterview", ctx, applicationId, recruiterId, ni)
+	ret0, _ := ret[0].(models.Interview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateInterview indicates an expected call of CreateInterview.
+func (mr *MockServiceMockRecorder) CreateInterview(ctx, applicationId, recruiterId, ni interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInterview", reflect.TypeOf((*MockService)(nil).CreateInterview), ctx, applicationId, recruiterId, ni)
+}
+
+// ViewInterview mocks base method.
+func (m *MockService) ViewInterview(ctx context.Context, interviewId uint) (models.Interview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewInterview", ctx, interviewId)
+	ret0, _ := ret[0].(models.Interview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewInterview indicates an expected call of ViewInterview.
+func (mr *MockServiceMockRecorder) ViewInterview(ctx, interviewId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewInterview", reflect.TypeOf((*MockService)(nil).ViewInterview), ctx, interviewId)
+}
+
+// ViewApplicationInterviews mocks base method.
+func (m *MockService) ViewApplicationInterviews(ctx context.Context, applicationId uint) ([]models.Interview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewApplicationInterviews", ctx, applicationId)
+	ret0, _ := ret[0].([]models.Interview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewApplicationInterviews indicates an expected call of ViewApplicationInterviews.
+func (mr *MockServiceMockRecorder) ViewApplicationInterviews(ctx, applicationId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewApplicationInterviews", reflect.TypeOf((*MockService)(nil).ViewApplicationInterviews), ctx, applicationId)
+}
+
+// ViewUserInterviews mocks base method.
+func (m *MockService) ViewUserInterviews(ctx context.Context, userId uint, since time.Time) ([]models.Interview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewUserInterviews", ctx, userId, since)
+	ret0, _ := ret[0].([]models.Interview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewUserInterviews indicates an expected call of ViewUserInterviews.
+func (mr *MockServiceMockRecorder) ViewUserInterviews(ctx, userId, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewUserInterviews", reflect.TypeOf((*MockService)(nil).ViewUserInterviews), ctx, userId, since)
+}
+
+// SelectInterviewSlot mocks base method.
+func (m *MockService) SelectInterviewSlot(ctx context.Context, interviewId uint, slotId uint, candidateId uint) (models.Interview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectInterviewSlot", ctx, interviewId, slotId, candidateId)
+	ret0, _ := ret[0].(models.Interview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectInterviewSlot indicates an expected call of SelectInterviewSlot.
+func (mr *MockServiceMockRecorder) SelectInterviewSlot(ctx, interviewId, slotId, candidateId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectInterviewSlot", reflect.TypeOf((*MockService)(nil).SelectInterviewSlot), ctx, interviewId, slotId, candidateId)
+}
+
+// CancelInterview mocks base method.
+func (m *MockService) CancelInterview(ctx context.Context, interviewId uint, reason string) (models.Interview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelInterview", ctx, interviewId, reason)
+	ret0, _ := ret[0].(models.Interview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelInterview indicates an expected call of CancelInterview.
+func (mr *MockServiceMockRecorder) CancelInterview(ctx, interviewId, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelInterview", reflect.TypeOf((*MockService)(nil).CancelInterview), ctx, interviewId, reason)
+}
+
+// CalendarFeedToken mocks base method.
+func (m *MockService) CalendarFeedToken(ctx context.Context, userId uint, rotate bool) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CalendarFeedToken", ctx, userId, rotate)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CalendarFeedToken indicates an expected call of CalendarFeedToken.
+func (mr *MockServiceMockRecorder) CalendarFeedToken(ctx, userId, rotate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CalendarFeedToken", reflect.TypeOf((*MockService)(nil).CalendarFeedToken), ctx, userId, rotate)
+}
+
+// ViewCalendarFeedUser mocks base method.
+func (m *MockService) ViewCalendarFeedUser(ctx context.Context, token string) (uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewCalendarFeedUser", ctx, token)
+	ret0, _ := ret[0].(uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewCalendarFeedUser indicates an expected call of ViewCalendarFeedUser.
+func (mr *MockServiceMockRecorder) ViewCalendarFeedUser(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewCalendarFeedUser", reflect.TypeOf((*MockService)(nil).ViewCalendarFeedUser), ctx, token)
+}
+
+// OpenConversation mocks base method.
+func (m *MockService) OpenConversation(ctx context.Context, applicationId uint) (models.Conversation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenConversation", ctx, applicationId)
+	ret0, _ := ret[0].(models.Conversation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenConversation indicates an expected call of OpenConversation.
+func (mr *MockServiceMockRecorder) OpenConversation(ctx, applicationId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenConversation", reflect.TypeOf((*MockService)(nil).OpenConversation), ctx, applicationId)
+}
+
+// CreateMessage mocks base method.
+func (m *MockService) CreateMessage(ctx context.Context, conversationId uint, senderId uint, nm models.NewMessage) (models.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMessage", ctx, conversationId, senderId, nm)
+	ret0, _ := ret[0].(models.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMessage indicates an expected call of CreateMessage.
+func (mr *MockServiceMockRecorder) CreateMessage(ctx, conversationId, senderId, nm interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMessage", reflect.TypeOf((*MockService)(nil).CreateMessage), ctx, conversationId, senderId, nm)
+}
+
+// ViewMessages mocks base method.
+func (m *MockService) ViewMessages(ctx context.Context, conversationId uint, before uint, limit int) ([]models.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewMessages", ctx, conversationId, before, limit)
+	ret0, _ := ret[0].([]models.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewMessages indicates an expected call of ViewMessages.
+func (mr *MockServiceMockRecorder) ViewMessages(ctx, conversationId, before, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewMessages", reflect.TypeOf((*MockService)(nil).ViewMessages), ctx, conversationId, before, limit)
+}
+
+// ViewMessage mocks base method.
+func (m *MockService) ViewMessage(ctx context.Context, messageId uint) (models.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewMessage", ctx, messageId)
+	ret0, _ := ret[0].(models.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewMessage indicates an expected call of ViewMessage.
+func (mr *MockServiceMockRecorder) ViewMessage(ctx, messageId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewMessage", reflect.TypeOf((*MockService)(nil).ViewMessage), ctx, messageId)
+}
+
+// MarkConversationRead mocks base method.
+func (m *MockService) MarkConversationRead(ctx context.Context, conversationId uint, userId uint, messageId uint) (models.ConversationRead, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkConversationRead", ctx, conversationId, userId, messageId)
+	ret0, _ := ret[0].(models.ConversationRead)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkConversationRead indicates an expected call of MarkConversationRead.
+func (mr *MockServiceMockRecorder) MarkConversationRead(ctx, conversationId, userId, messageId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkConversationRead", reflect.TypeOf((*MockService)(nil).MarkConversationRead), ctx, conversationId, userId, messageId)
+}
+
+// ViewUserConversations mocks base method.
+func (m *MockService) ViewUserConversations(ctx context.Context, userId uint) ([]models.Conversation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewUserConversations", ctx, userId)
+	ret0, _ := ret[0].([]models.Conversation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewUserConversations indicates an expected call of ViewUserConversations.
+func (mr *MockServiceMockRecorder) ViewUserConversations(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewUserConversations", reflect.TypeOf((*MockService)(nil).ViewUserConversations), ctx, userId)
+}
+
+// ViewCompanyPipeline mocks base method.
+func (m *MockService) ViewCompanyPipeline(ctx context.Context, companyId uint) (models.Pipeline, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewCompanyPipeline", ctx, companyId)
+	ret0, _ := ret[0].(models.Pipeline)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewCompanyPipeline indicates an expected call of ViewCompanyPipeline.
+func (mr *MockServiceMockRecorder) ViewCompanyPipeline(ctx, companyId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewCompanyPipeline", reflect.TypeOf((*MockService)(nil).ViewCompanyPipeline), ctx, companyId)
+}
+
+// ViewJobPipeline mocks base method.
+func (m *MockService) ViewJobPipeline(ctx context.Context, jobId uint) (models.Pipeline, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewJobPipeline", ctx, jobId)
+	ret0, _ := ret[0].(models.Pipeline)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewJobPipeline indicates an expected call of ViewJobPipeline.
+func (mr *MockServiceMockRecorder) ViewJobPipeline(ctx, jobId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewJobPipeline", reflect.TypeOf((*MockService)(nil).ViewJobPipeline), ctx, jobId)
+}
+
+// UpdateCompanyPipeline mocks base method.
+func (m *MockService) UpdateCompanyPipeline(ctx context.Context, companyId uint, pu models.PipelineUpdate) (models.Pipeline, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCompanyPipeline", ctx, companyId, pu)
+	ret0, _ := ret[0].(models.Pipeline)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCompanyPipeline indicates an expected call of UpdateCompanyPipeline.
+func (mr *MockServiceMockRecorder) UpdateCompanyPipeline(ctx, companyId, pu interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCompanyPipeline", reflect.TypeOf((*MockService)(nil).UpdateCompanyPipeline), ctx, companyId, pu)
+}
+
+// UpdateJobPipeline mocks base method.
+func (m *MockService) UpdateJobPipeline(ctx context.Context, jobId uint, pu models.PipelineUpdate) (models.Pipeline, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateJobPipeline", ctx, jobId, pu)
+	ret0, _ := ret[0].(models.Pipeline)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateJobPipeline indicates an expected call of UpdateJobPipeline.
+func (mr *MockServiceMockRecorder) UpdateJobPipeline(ctx, jobId, pu interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateJobPipeline", reflect.TypeOf((*MockService)(nil).UpdateJobPipeline), ctx, jobId, pu)
+}
+
+// MoveApplication mocks base method.
+func (m *MockService) MoveApplication(ctx context.Context, applicationId uint, stageId uint, movedBy uint) (models.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveApplication", ctx, applicationId, stageId, movedBy)
+	ret0, _ := ret[0].(models.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveApplication indicates an expected call of MoveApplication.
+func (mr *MockServiceMockRecorder) MoveApplication(ctx, applicationId, stageId, movedBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveApplication", reflect.TypeOf((*MockService)(nil).MoveApplication), ctx, applicationId, stageId, movedBy)
+}
+
+// ViewApplicationStageChanges mocks base method.
+func (m *MockService) ViewApplicationStageChanges(ctx context.Context, applicationId uint) ([]models.ApplicationStageChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewApplicationStageChanges", ctx, applicationId)
+	ret0, _ := ret[0].([]models.ApplicationStageChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewApplicationStageChanges indicates an expected call of ViewApplicationStageChanges.
+func (mr *MockServiceMockRecorder) ViewApplicationStageChanges(ctx, applicationId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewApplicationStageChanges", reflect.TypeOf((*MockService)(nil).ViewApplicationStageChanges), ctx, applicationId)
+}
+
+// ViewBoard mocks base method.
+func (m *MockService) ViewBoard(ctx context.Context, jobId uint) (models.Board, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewBoard", ctx, jobId)
+	ret0, _ := ret[0].(models.Board)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewBoard indicates an expected call of ViewBoard.
+func (mr *MockServiceMockRecorder) ViewBoard(ctx, jobId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewBoard", reflect.TypeOf((*MockService)(nil).ViewBoard), ctx, jobId)
+}
+
+// SetScorecardTemplate mocks base method.
+func (m *MockService) SetScorecardTemplate(ctx context.Context, jobId uint, nt models.NewScorecardTemplate) (models.ScorecardTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetScorecardTemplate", ctx, jobId, nt)
+	ret0, _ := ret[0].(models.ScorecardTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetScorecardTemplate indicates an expected call of SetScorecardTemplate.
+func (mr *MockServiceMockRecorder) SetScorecardTemplate(ctx, jobId, nt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetScorecardTemplate", reflect.TypeOf((*MockService)(nil).SetScorecardTemplate), ctx, jobId, nt)
+}
+
+// ViewScorecardTemplate mocks base method.
+func (m *MockService) ViewScorecardTemplate(ctx context.Context, jobId uint) (models.ScorecardTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewScorecardTemplate", ctx, jobId)
+	ret0, _ := ret[0].(models.ScorecardTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewScorecardTemplate indicates an expected call of ViewScorecardTemplate.
+func (mr *MockServiceMockRecorder) ViewScorecardTemplate(ctx, jobId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewScorecardTemplate", reflect.TypeOf((*MockService)(nil).ViewScorecardTemplate), ctx, jobId)
+}
+
+// CreateScorecard mocks base method.
+func (m *MockService) CreateScorecard(ctx context.Context, applicationId uint, interviewerId uint, ns models.NewScorecard) (models.Scorecard, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateScorecard", ctx, applicationId, interviewerId, ns)
+	ret0, _ := ret[0].(models.Scorecard)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateScorecard indicates an expected call of CreateScorecard.
+func (mr *MockServiceMockRecorder) CreateScorecard(ctx, applicationId, interviewerId, ns interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateScorecard", reflect.TypeOf((*MockService)(nil).CreateScorecard), ctx, applicationId, interviewerId, ns)
+}
+
+// ViewApplicationScorecards mocks base method.
+func (m *MockService) ViewApplicationScorecards(ctx context.Context, applicationId uint) ([]models.Scorecard, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewApplicationScorecards", ctx, applicationId)
+	ret0, _ := ret[0].([]models.Scorecard)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewApplicationScorecards indicates an expected call of ViewApplicationScorecards.
+func (mr *MockServiceMockRecorder) ViewApplicationScorecards(ctx, applicationId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewApplicationScorecards", reflect.TypeOf((*MockService)(nil).ViewApplicationScorecards), ctx, applicationId)
+}
+
+// ViewJobScorecards mocks base method.
+func (m *MockService) ViewJobScorecards(ctx context.Context, jobId uint) ([]models.Scorecard, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewJobScorecards", ctx, jobId)
+	ret0, _ := ret[0].([]models.Scorecard)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewJobScorecards indicates an expected call of ViewJobScorecards.
+func (mr *MockServiceMockRecorder) ViewJobScorecards(ctx, jobId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewJobScorecards", reflect.TypeOf((*MockService)(nil).ViewJobScorecards), ctx, jobId)
+}
+
+// SetScreeningQuestions mocks base method.
+func (m *MockService) SetScreeningQuestions(ctx context.Context, jobId uint, su models.ScreeningUpdate) ([]models.ScreeningQuestion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetScreeningQuestions", ctx, jobId, su)
+	ret0, _ := ret[0].([]models.ScreeningQuestion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetScreeningQuestions indicates an expected call of SetScreeningQuestions.
+func (mr *MockServiceMockRecorder) SetScreeningQuestions(ctx, jobId, su interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetScreeningQuestions", reflect.TypeOf((*MockService)(nil).SetScreeningQuestions), ctx, jobId, su)
+}
+
+// ViewScreeningQuestions mocks base method.
+func (m *MockService) ViewScreeningQuestions(ctx context.Context, jobId uint) ([]models.ScreeningQuestion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewScreeningQuestions", ctx, jobId)
+	ret0, _ := ret[0].([]models.ScreeningQuestion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewScreeningQuestions indicates an expected call of ViewScreeningQuestions.
+func (mr *MockServiceMockRecorder) ViewScreeningQuestions(ctx, jobId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewScreeningQuestions", reflect.TypeOf((*MockService)(nil).ViewScreeningQuestions), ctx, jobId)
+}
+
+// CreateOfferTemplate mocks base method.
+func (m *MockService) CreateOfferTemplate(ctx context.Context, companyId uint, nt models.NewOfferTemplate) (models.OfferTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOfferTemplate", ctx, companyId, nt)
+	ret0, _ := ret[0].(models.OfferTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOfferTemplate indicates an expected call of CreateOfferTemplate.
+func (mr *MockServiceMockRecorder) CreateOfferTemplate(ctx, companyId, nt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOfferTemplate", reflect.TypeOf((*MockService)(nil).CreateOfferTemplate), ctx, companyId, nt)
+}
+
+// ViewOfferTemplates mocks base method.
+func (m *MockService) ViewOfferTemplates(ctx context.Context, companyId uint) ([]models.OfferTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewOfferTemplates", ctx, companyId)
+	ret0, _ := ret[0].([]models.OfferTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewOfferTemplates indicates an expected call of ViewOfferTemplates.
+func (mr *MockServiceMockRecorder) ViewOfferTemplates(ctx, companyId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewOfferTemplates", reflect.TypeOf((*MockService)(nil).ViewOfferTemplates), ctx, companyId)
+}
+
+// ViewOfferTemplate mocks base method.
+func (m *MockService) ViewOfferTemplate(ctx context.Context, templateId uint) (models.OfferTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewOfferTemplate", ctx, templateId)
+	ret0, _ := ret[0].(models.OfferTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewOfferTemplate indicates an expected call of ViewOfferTemplate.
+func (mr *MockServiceMockRecorder) ViewOfferTemplate(ctx, templateId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewOfferTemplate", reflect.TypeOf((*MockService)(nil).ViewOfferTemplate), ctx, templateId)
+}
+
+// UpdateOfferTemplate mocks base method.
+func (m *MockService) UpdateOfferTemplate(ctx context.Context, templateId uint, nt models.NewOfferTemplate) (models.OfferTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOfferTemplate", ctx, templateId, nt)
+	ret0, _ := ret[0].(models.OfferTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateOfferTemplate indicates an expected call of UpdateOfferTemplate.
+func (mr *MockServiceMockRecorder) UpdateOfferTemplate(ctx, templateId, nt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOfferTemplate", reflect.TypeOf((*MockService)(nil).UpdateOfferTemplate), ctx, templateId, nt)
+}
+
+// DeleteOfferTemplate mocks base method.
+func (m *MockService) DeleteOfferTemplate(ctx context.Context, templateId uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOfferTemplate", ctx, templateId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOfferTemplate indicates an expected call of DeleteOfferTemplate.
+func (mr *MockServiceMockRecorder) DeleteOfferTemplate(ctx, templateId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOfferTemplate", reflect.TypeOf((*MockService)(nil).DeleteOfferTemplate), ctx, templateId)
+}
+
+// CreateOffer mocks base method.
+func (m *MockService) CreateOffer(ctx context.Context, offer models.Offer, doc models.Document) (models.Offer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOffer", ctx, offer, doc)
+	ret0, _ := ret[0].(models.Offer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOffer indicates an expected call of CreateOffer.
+func (mr *MockServiceMockRecorder) CreateOffer(ctx, offer, doc interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOffer", reflect.TypeOf((*MockService)(nil).CreateOffer), ctx, offer, doc)
+}
+
+// ViewOffer mocks base method.
+func (m *MockService) ViewOffer(ctx context.Context, offerId uint) (models.Offer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewOffer", ctx, offerId)
+	ret0, _ := ret[0].(models.Offer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewOffer indicates an expected call of ViewOffer.
+func (mr *MockServiceMockRecorder) ViewOffer(ctx, offerId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewOffer", reflect.TypeOf((*MockService)(nil).ViewOffer), ctx, offerId)
+}
+
+// ViewApplicationOffers mocks base method.
+func (m *MockService) ViewApplicationOffers(ctx context.Context, applicationId uint) ([]models.Offer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewApplicationOffers", ctx, applicationId)
+	ret0, _ := ret[0].([]models.Offer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewApplicationOffers indicates an expected call of ViewApplicationOffers.
+func (mr *MockServiceMockRecorder) ViewApplicationOffers(ctx, applicationId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewApplicationOffers", reflect.TypeOf((*MockService)(nil).ViewApplicationOffers), ctx, applicationId)
+}
+
+// ViewOffersByCandidate mocks base method.
+func (m *MockService) ViewOffersByCandidate(ctx context.Context, candidateId uint) ([]models.Offer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewOffersByCandidate", ctx, candidateId)
+	ret0, _ := ret[0].([]models.Offer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewOffersByCandidate indicates an expected call of ViewOffersByCandidate.
+func (mr *MockServiceMockRecorder) ViewOffersByCandidate(ctx, candidateId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewOffersByCandidate", reflect.TypeOf((*MockService)(nil).ViewOffersByCandidate), ctx, candidateId)
+}
+
+// RespondOffer mocks base method.
+func (m *MockService) RespondOffer(ctx context.Context, offerId uint, candidateId uint, accept bool, reason string) (models.Offer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RespondOffer", ctx, offerId, candidateId, accept, reason)
+	ret0, _ := ret[0].(models.Offer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RespondOffer indicates an expected call of RespondOffer.
+func (mr *MockServiceMockRecorder) RespondOffer(ctx, offerId, candidateId, accept, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RespondOffer", reflect.TypeOf((*MockService)(nil).RespondOffer), ctx, offerId, candidateId, accept, reason)
+}
+
+// SearchCandidates mocks base method.
+func (m *MockService) SearchCandidates(ctx context.Context, q models.CandidateSearch, limit int) ([]models.Candidate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchCandidates", ctx, q, limit)
+	ret0, _ := ret[0].([]models.Candidate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchCandidates indicates an expected call of SearchCandidates.
+func (mr *MockServiceMockRecorder) SearchCandidates(ctx, q, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchCandidates", reflect.TypeOf((*MockService)(nil).SearchCandidates), ctx, q, limit)
+}
+
+// CreateTalentPool mocks base method.
+func (m *MockService) CreateTalentPool(ctx context.Context, companyId uint, createdBy uint, np models.NewTalentPool) (models.TalentPool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTalentPool", ctx, companyId, createdBy, np)
+	ret0, _ := ret[0].(models.TalentPool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTalentPool indicates an expected call of CreateTalentPool.
+func (mr *MockServiceMockRecorder) CreateTalentPool(ctx, companyId, createdBy, np interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTalentPool", reflect.TypeOf((*MockService)(nil).CreateTalentPool), ctx, companyId, createdBy, np)
+}
+
+// ViewTalentPools mocks base method.
+func (m *MockService) ViewTalentPools(ctx context.Context, companyId uint) ([]models.TalentPool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewTalentPools", ctx, companyId)
+	ret0, _ := ret[0].([]models.TalentPool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewTalentPools indicates an expected call of ViewTalentPools.
+func (mr *MockServiceMockRecorder) ViewTalentPools(ctx, companyId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewTalentPools", reflect.TypeOf((*MockService)(nil).ViewTalentPools), ctx, companyId)
+}
+
+// ViewTalentPool mocks base method.
+func (m *MockService) ViewTalentPool(ctx context.Context, poolId uint) (models.TalentPool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewTalentPool", ctx, poolId)
+	ret0, _ := ret[0].(models.TalentPool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewTalentPool indicates an expected call of ViewTalentPool.
+func (mr *MockServiceMockRecorder) ViewTalentPool(ctx, poolId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewTalentPool", reflect.TypeOf((*MockService)(nil).ViewTalentPool), ctx, poolId)
+}
+
+// UpdateTalentPool mocks base method.
+func (m *MockService) UpdateTalentPool(ctx context.Context, poolId uint, np models.NewTalentPool) (models.TalentPool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTalentPool", ctx, poolId, np)
+	ret0, _ := ret[0].(models.TalentPool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTalentPool indicates an expected call of UpdateTalentPool.
+func (mr *MockServiceMockRecorder) UpdateTalentPool(ctx, poolId, np interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTalentPool", reflect.TypeOf((*MockService)(nil).UpdateTalentPool), ctx, poolId, np)
+}
+
+// DeleteTalentPool mocks base method.
+func (m *MockService) DeleteTalentPool(ctx context.Context, poolId uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTalentPool", ctx, poolId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTalentPool indicates an expected call of DeleteTalentPool.
+func (mr *MockServiceMockRecorder) DeleteTalentPool(ctx, poolId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTalentPool", reflect.TypeOf((*MockService)(nil).DeleteTalentPool), ctx, poolId)
+}
+
+// SavePoolMember mocks base method.
+func (m *MockService) SavePoolMember(ctx context.Context, poolId uint, userId uint, addedBy uint, nm models.NewPoolMember) (models.PoolMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SavePoolMember", ctx, poolId, userId, addedBy, nm)
+	ret0, _ := ret[0].(models.PoolMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SavePoolMember indicates an expected call of SavePoolMember.
+func (mr *MockServiceMockRecorder) SavePoolMember(ctx, poolId, userId, addedBy, nm interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePoolMember", reflect.TypeOf((*MockService)(nil).SavePoolMember), ctx, poolId, userId, addedBy, nm)
+}
+
+// ViewPoolCandidates mocks base method.
+func (m *MockService) ViewPoolCandidates(ctx context.Context, poolId uint, tag string) ([]models.PoolCandidate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewPoolCandidates", ctx, poolId, tag)
+	ret0, _ := ret[0].([]models.PoolCandidate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewPoolCandidates indicates an expected call of ViewPoolCandidates.
+func (mr *MockServiceMockRecorder) ViewPoolCandidates(ctx, poolId, tag interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewPoolCandidates", reflect.TypeOf((*MockService)(nil).ViewPoolCandidates), ctx, poolId, tag)
+}
+
+// DeletePoolMember mocks base method.
+func (m *MockService) DeletePoolMember(ctx context.Context, poolId uint, userId uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePoolMember", ctx, poolId, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePoolMember indicates an expected call of DeletePoolMember.
+func (mr *MockServiceMockRecorder) DeletePoolMember(ctx, poolId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePoolMember", reflect.TypeOf((*MockService)(nil).DeletePoolMember), ctx, poolId, userId)
+}
+
+// CreateJobInvitation mocks base method.
+func (m *MockService) CreateJobInvitation(ctx context.Context, companyId uint, userId uint, invitedBy uint, ni models.NewJobInvitation) (models.JobInvitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateJobInvitation", ctx, companyId, userId, invitedBy, ni)
+	ret0, _ := ret[0].(models.JobInvitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateJobInvitation indicates an expected call of CreateJobInvitation.
+func (mr *MockServiceMockRecorder) CreateJobInvitation(ctx, companyId, userId, invitedBy, ni interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateJobInvitation", reflect.TypeOf((*MockService)(nil).CreateJobInvitation), ctx, companyId, userId, invitedBy, ni)
+}
+
+// ViewJobInvitationsByUser mocks base method.
+func (m *MockService) ViewJobInvitationsByUser(ctx context.Context, userId uint) ([]models.JobInvitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewJobInvitationsByUser", ctx, userId)
+	ret0, _ := ret[0].([]models.JobInvitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewJobInvitationsByUser indicates an expected call of ViewJobInvitationsByUser.
+func (mr *MockServiceMockRecorder) ViewJobInvitationsByUser(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewJobInvitationsByUser", reflect.TypeOf((*MockService)(nil).ViewJobInvitationsByUser), ctx, userId)
+}
+
+// BookmarkJob mocks base method.
+func (m *MockService) BookmarkJob(ctx context.Context, userId uint, jobId uint) (models.Bookmark, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BookmarkJob", ctx, userId, jobId)
+	ret0, _ := ret[0].(models.Bookmark)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BookmarkJob indicates an expected call of BookmarkJob.
+func (mr *MockServiceMockRecorder) BookmarkJob(ctx, userId, jobId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BookmarkJob", reflect.TypeOf((*MockService)(nil).BookmarkJob), ctx, userId, jobId)
+}
+
+// DeleteBookmark mocks base method.
+func (m *MockService) DeleteBookmark(ctx context.Context, userId uint, jobId uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBookmark", ctx, userId, jobId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBookmark indicates an expected call of DeleteBookmark.
+func (mr *MockServiceMockRecorder) DeleteBookmark(ctx, userId, jobId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBookmark", reflect.TypeOf((*MockService)(nil).DeleteBookmark), ctx, userId, jobId)
+}
+
+// ViewBookmarkedJobs mocks base method.
+func (m *MockService) ViewBookmarkedJobs(ctx context.Context, userId uint) ([]models.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewBookmarkedJobs", ctx, userId)
+	ret0, _ := ret[0].([]models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewBookmarkedJobs indicates an expected call of ViewBookmarkedJobs.
+func (mr *MockServiceMockRecorder) ViewBookmarkedJobs(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewBookmarkedJobs", reflect.TypeOf((*MockService)(nil).ViewBookmarkedJobs), ctx, userId)
+}
+
+// BookmarkedJobIDs mocks base method.
+func (m *MockService) BookmarkedJobIDs(ctx context.Context, userId uint, jobIds []uint) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BookmarkedJobIDs", ctx, userId, jobIds)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BookmarkedJobIDs indicates an expected call of BookmarkedJobIDs.
+func (mr *MockServiceMockRecorder) BookmarkedJobIDs(ctx, userId, jobIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BookmarkedJobIDs", reflect.TypeOf((*MockService)(nil).BookmarkedJobIDs), ctx, userId, jobIds)
+}
+
+// FollowCompany mocks base method.
+func (m *MockService) FollowCompany(ctx context.Context, userId uint, companyId uint) (models.CompanyFollow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FollowCompany", ctx, userId, companyId)
+	ret0, _ := ret[0].(models.CompanyFollow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FollowCompany indicates an expected call of FollowCompany.
+func (mr *MockServiceMockRecorder) FollowCompany(ctx, userId, companyId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FollowCompany", reflect.TypeOf((*MockService)(nil).FollowCompany), ctx, userId, companyId)
+}
+
+// UnfollowCompany mocks base method.
+func (m *MockService) UnfollowCompany(ctx context.Context, userId uint, companyId uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnfollowCompany", ctx, userId, companyId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnfollowCompany indicates an expected call of UnfollowCompany.
+func (mr *MockServiceMockRecorder) UnfollowCompany(ctx, userId, companyId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnfollowCompany", reflect.TypeOf((*MockService)(nil).UnfollowCompany), ctx, userId, companyId)
+}
+
+// ViewFollowedCompanies mocks base method.
+func (m *MockService) ViewFollowedCompanies(ctx context.Context, userId uint) ([]models.Company, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewFollowedCompanies", ctx, userId)
+	ret0, _ := ret[0].([]models.Company)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewFollowedCompanies indicates an expected call of ViewFollowedCompanies.
+func (mr *MockServiceMockRecorder) ViewFollowedCompanies(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewFollowedCompanies", reflect.TypeOf((*MockService)(nil).ViewFollowedCompanies), ctx, userId)
+}
+
+// CompanyFollowers mocks base method.
+func (m *MockService) CompanyFollowers(ctx context.Context, companyId uint) ([]models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompanyFollowers", ctx, companyId)
+	ret0, _ := ret[0].([]models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompanyFollowers indicates an expected call of CompanyFollowers.
+func (mr *MockServiceMockRecorder) CompanyFollowers(ctx, companyId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompanyFollowers", reflect.TypeOf((*MockService)(nil).CompanyFollowers), ctx, companyId)
+}
+
+// RecordJobView mocks base method.
+func (m *MockService) RecordJobView(ctx context.Context, jobId uint, companyId uint, visitor string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordJobView", ctx, jobId, companyId, visitor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordJobView indicates an expected call of RecordJobView.
+func (mr *MockServiceMockRecorder) RecordJobView(ctx, jobId, companyId, visitor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordJobView", reflect.TypeOf((*MockService)(nil).RecordJobView), ctx, jobId, companyId, visitor)
+}
+
+// RecordApplyStart mocks base method.
+func (m *MockService) RecordApplyStart(ctx context.Context, jobId uint, companyId uint, visitor string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordApplyStart", ctx, jobId, companyId, visitor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordApplyStart indicates an expected call of RecordApplyStart.
+func (mr *MockServiceMockRecorder) RecordApplyStart(ctx, jobId, companyId, visitor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordApplyStart", reflect.TypeOf((*MockService)(nil).RecordApplyStart), ctx, jobId, companyId, visitor)
+}
+
+// RollupJobStats mocks base method.
+func (m *MockService) RollupJobStats(ctx context.Context, since time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollupJobStats", ctx, since)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RollupJobStats indicates an expected call of RollupJobStats.
+func (mr *MockServiceMockRecorder) RollupJobStats(ctx, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollupJobStats", reflect.TypeOf((*MockService)(nil).RollupJobStats), ctx, since)
+}
+
+// JobStatSeries mocks base method.
+func (m *MockService) JobStatSeries(ctx context.Context, scope models.StatScope, from time.Time, to time.Time, interval string) ([]models.StatPoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JobStatSeries", ctx, scope, from, to, interval)
+	ret0, _ := ret[0].([]models.StatPoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// JobStatSeries indicates an expected call of JobStatSeries.
+func (mr *MockServiceMockRecorder) JobStatSeries(ctx, scope, from, to, interval interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JobStatSeries", reflect.TypeOf((*MockService)(nil).JobStatSeries), ctx, scope, from, to, interval)
+}
+
+// MedianTimeToHire mocks base method.
+func (m *MockService) MedianTimeToHire(ctx context.Context, scope models.StatScope, from time.Time, to time.Time) (*float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MedianTimeToHire", ctx, scope, from, to)
+	ret0, _ := ret[0].(*float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MedianTimeToHire indicates an expected call of MedianTimeToHire.
+func (mr *MockServiceMockRecorder) MedianTimeToHire(ctx, scope, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MedianTimeToHire", reflect.TypeOf((*MockService)(nil).MedianTimeToHire), ctx, scope, from, to)
+}
+
+// MedianTimeInStage mocks base method.
+func (m *MockService) MedianTimeInStage(ctx context.Context, scope models.StatScope, from time.Time, to time.Time) ([]models.StageTime, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MedianTimeInStage", ctx, scope, from, to)
+	ret0, _ := ret[0].([]models.StageTime)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MedianTimeInStage indicates an expected call of MedianTimeInStage.
+func (mr *MockServiceMockRecorder) MedianTimeInStage(ctx, scope, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MedianTimeInStage", reflect.TypeOf((*MockService)(nil).MedianTimeInStage), ctx, scope, from, to)
+}
+
+// UpdateCompany mocks base method.
+func (m *MockService) UpdateCompany(ctx context.Context, companyId uint, nc models.NewCompany) (models.Company, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCompany", ctx, companyId, nc)
+	ret0, _ := ret[0].(models.Company)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCompany indicates an expected call of UpdateCompany.
+func (mr *MockServiceMockRecorder) UpdateCompany(ctx, companyId, nc interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCompany", reflect.TypeOf((*MockService)(nil).UpdateCompany), ctx, companyId, nc)
+}
+
+// CreateCompanyReview mocks base method.
+func (m *MockService) CreateCompanyReview(ctx context.Context, companyId uint, userId uint, nr models.NewCompanyReview) (models.CompanyReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCompanyReview", ctx, companyId, userId, nr)
+	ret0, _ := ret[0].(models.CompanyReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCompanyReview indicates an expected call of CreateCompanyReview.
+func (mr *MockServiceMockRecorder) CreateCompanyReview(ctx, companyId, userId, nr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCompanyReview", reflect.TypeOf((*MockService)(nil).CreateCompanyReview), ctx, companyId, userId, nr)
+}
+
+// UpdateCompanyReview mocks base method.
+func (m *MockService) UpdateCompanyReview(ctx context.Context, reviewId uint, userId uint, nr models.NewCompanyReview) (models.CompanyReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCompanyReview", ctx, reviewId, userId, nr)
+	ret0, _ := ret[0].(models.CompanyReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCompanyReview indicates an expected call of UpdateCompanyReview.
+func (mr *MockServiceMockRecorder) UpdateCompanyReview(ctx, reviewId, userId, nr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCompanyReview", reflect.TypeOf((*MockService)(nil).UpdateCompanyReview), ctx, reviewId, userId, nr)
+}
+
+// DeleteCompanyReview mocks base method.
+func (m *MockService) DeleteCompanyReview(ctx context.Context, reviewId uint, userId uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCompanyReview", ctx, reviewId, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCompanyReview indicates an expected call of DeleteCompanyReview.
+func (mr *MockServiceMockRecorder) DeleteCompanyReview(ctx, reviewId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCompanyReview", reflect.TypeOf((*MockService)(nil).DeleteCompanyReview), ctx, reviewId, userId)
+}
+
+// ViewCompanyReviews mocks base method.
+func (m *MockService) ViewCompanyReviews(ctx context.Context, companyId uint, limit int) ([]models.CompanyReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewCompanyReviews", ctx, companyId, limit)
+	ret0, _ := ret[0].([]models.CompanyReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewCompanyReviews indicates an expected call of ViewCompanyReviews.
+func (mr *MockServiceMockRecorder) ViewCompanyReviews(ctx, companyId, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewCompanyReviews", reflect.TypeOf((*MockService)(nil).ViewCompanyReviews), ctx, companyId, limit)
+}
+
+// ViewReviewsByUser mocks base method.
+func (m *MockService) ViewReviewsByUser(ctx context.Context, userId uint) ([]models.CompanyReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewReviewsByUser", ctx, userId)
+	ret0, _ := ret[0].([]models.CompanyReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewReviewsByUser indicates an expected call of ViewReviewsByUser.
+func (mr *MockServiceMockRecorder) ViewReviewsByUser(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewReviewsByUser", reflect.TypeOf((*MockService)(nil).ViewReviewsByUser), ctx, userId)
+}
+
+// ViewPendingReviews mocks base method.
+func (m *MockService) ViewPendingReviews(ctx context.Context, limit int) ([]models.CompanyReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewPendingReviews", ctx, limit)
+	ret0, _ := ret[0].([]models.CompanyReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewPendingReviews indicates an expected call of ViewPendingReviews.
+func (mr *MockServiceMockRecorder) ViewPendingReviews(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewPendingReviews", reflect.TypeOf((*MockService)(nil).ViewPendingReviews), ctx, limit)
+}
+
+// ModerateReview mocks base method.
+func (m *MockService) ModerateReview(ctx context.Context, reviewId uint, moderatorId uint, rm models.ReviewModeration) (models.CompanyReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModerateReview", ctx, reviewId, moderatorId, rm)
+	ret0, _ := ret[0].(models.CompanyReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ModerateReview indicates an expected call of ModerateReview.
+func (mr *MockServiceMockRecorder) ModerateReview(ctx, reviewId, moderatorId, rm interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModerateReview", reflect.TypeOf((*MockService)(nil).ModerateReview), ctx, reviewId, moderatorId, rm)
+}
+
+// ViewCompanyRating mocks base method.
+func (m *MockService) ViewCompanyRating(ctx context.Context, companyId uint) (models.CompanyRating, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewCompanyRating", ctx, companyId)
+	ret0, _ := ret[0].(models.CompanyRating)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewCompanyRating indicates an expected call of ViewCompanyRating.
+func (mr *MockServiceMockRecorder) ViewCompanyRating(ctx, companyId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewCompanyRating", reflect.TypeOf((*MockService)(nil).ViewCompanyRating), ctx, companyId)
+}
+
+// CompanyRatings mocks base method.
+func (m *MockService) CompanyRatings(ctx context.Context, companyIds []uint) ([]models.RatingSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompanyRatings", ctx, companyIds)
+	ret0, _ := ret[0].([]models.RatingSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompanyRatings indicates an expected call of CompanyRatings.
+func (mr *MockServiceMockRecorder) CompanyRatings(ctx, companyIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompanyRatings", reflect.TypeOf((*MockService)(nil).CompanyRatings), ctx, companyIds)
+}
+
+// SalaryPostings mocks base method.
+func (m *MockService) SalaryPostings(ctx context.Context, f models.SalaryFilter) ([]models.SalaryPosting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SalaryPostings", ctx, f)
+	ret0, _ := ret[0].([]models.SalaryPosting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SalaryPostings indicates an expected call of SalaryPostings.
+func (mr *MockServiceMockRecorder) SalaryPostings(ctx, f interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SalaryPostings", reflect.TypeOf((*MockService)(nil).SalaryPostings), ctx, f)
+}
+
+// SearchJobs mocks base method.
+func (m *MockService) SearchJobs(ctx context.Context, q models.JobSearch, limit int) ([]models.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchJobs", ctx, q, limit)
+	ret0, _ := ret[0].([]models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchJobs indicates an expected call of SearchJobs.
+func (mr *MockServiceMockRecorder) SearchJobs(ctx, q, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchJobs", reflect.TypeOf((*MockService)(nil).SearchJobs), ctx, q, limit)
+}
+
+// ViewCandidateProfile mocks base method.
+func (m *MockService) ViewCandidateProfile(ctx context.Context, viewerId uint, userId uint) (models.Profile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewCandidateProfile", ctx, viewerId, userId)
+	ret0, _ := ret[0].(models.Profile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewCandidateProfile indicates an expected call of ViewCandidateProfile.
+func (mr *MockServiceMockRecorder) ViewCandidateProfile(ctx, viewerId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewCandidateProfile", reflect.TypeOf((*MockService)(nil).ViewCandidateProfile), ctx, viewerId, userId)
+}
+
+// ApplicantProfiles mocks base method.
+func (m *MockService) ApplicantProfiles(ctx context.Context, jobId uint) ([]models.Profile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplicantProfiles", ctx, jobId)
+	ret0, _ := ret[0].([]models.Profile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplicantProfiles indicates an expected call of ApplicantProfiles.
+func (mr *MockServiceMockRecorder) ApplicantProfiles(ctx, jobId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplicantProfiles", reflect.TypeOf((*MockService)(nil).ApplicantProfiles), ctx, jobId)
+}
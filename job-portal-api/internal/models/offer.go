@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Offer statuses. A pending offer is superseded when the candidate is sent a new one.
+const (
+	OfferPending    = "pending"
+	OfferAccepted   = "accepted"
+	OfferDeclined   = "declined"
+	OfferSuperseded = "superseded"
+)
+
+// OfferPlaceholders are the fields an offer template can refer to, written {{name}} in its title and body.
+var OfferPlaceholders = []string{"candidate_name", "job_title", "company_name", "salary", "start_date"}
+
+// OfferTemplate is a company's model offer letter. Its placeholders are filled in for each offer.
+type OfferTemplate struct {
+	gorm.Model
+	CompanyID uint   `json:"company_id" gorm:"index;not null"`
+	Name      string `json:"name"`
+	// Title heads the letter, the body follows in paragraphs separated by blank lines
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+// NewOfferTemplate is the payload accepted when creating or updating an offer template.
+type NewOfferTemplate struct {
+	Name  string `json:"name" validate:"required,max=100"`
+	Title string `json:"title" validate:"max=200"`
+	Body  string `json:"body" validate:"required,max=20000"`
+}
+
+// Offer is an offer letter sent to the candidate of an application. The letter itself is a PDF in the blob store.
+type Offer struct {
+	gorm.Model
+	ApplicationID uint      `json:"application_id" gorm:"index;not null"`
+	CandidateID   uint      `json:"candidate_id" gorm:"index;not null"`
+	TemplateID    uint      `json:"template_id"`
+	DocumentID    uint      `json:"document_id"`
+	Salary        int       `json:"salary"`
+	Currency      string    `json:"currency"`
+	StartDate     time.Time `json:"start_date" gorm:"type:date"`
+	Status        string    `json:"status" gorm:"index;not null"`
+	IssuedBy      uint      `json:"issued_by"`
+	// RespondedAt and DeclineReason are set once the candidate accepts or declines
+	RespondedAt   *time.Time `json:"responded_at,omitempty"`
+	DeclineReason string     `json:"decline_reason,omitempty"`
+}
+
+// NewOffer is the payload accepted when sending an offer. StartDate is a day, as YYYY-MM-DD.
+type NewOffer struct {
+	TemplateID uint   `json:"template_id" validate:"required"`
+	Salary     int    `json:"salary" validate:"required,gt=0"`
+	Currency   string `json:"currency" validate:"required,iso4217"`
+	StartDate  string `json:"start_date" validate:"required,datetime=2006-01-02"`
+}
+
+// OfferResponse is the payload accepted when a candidate declines an offer.
+type OfferResponse struct {
+	Reason string `json:"reason" validate:"max=1000"`
+}
@@ -0,0 +1,232 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrInvalidOfferTemplate is returned when an offer template refers to fields that are not OfferPlaceholders.
+	ErrInvalidOfferTemplate = errors.New("invalid offer template")
+	// ErrOfferNotAllowed is returned when sending an offer for an application that was rejected or already hired.
+	ErrOfferNotAllowed = errors.New("application cannot receive an offer")
+	// ErrOfferNotPending is returned when answering an offer that was already answered or superseded.
+	ErrOfferNotPending = errors.New("offer is no longer pending")
+)
+
+var placeholder = regexp.MustCompile(`\{\{\s*([A-Za-z_]+)\s*\}\}`)
+
+// check makes sure the template only refers to known placeholders.
+func (nt NewOfferTemplate) check() error {
+	for _, m := range placeholder.FindAllStringSubmatch(nt.Title+"\n"+nt.Body, -1) {
+		if !slices.Contains(OfferPlaceholders, m[1]) {
+			return fmt.Errorf("%w: unknown placeholder %s, use one of %v", ErrInvalidOfferTemplate, m[0], OfferPlaceholders)
+		}
+	}
+	return nil
+}
+
+// Fill returns the title and body of the template with its placeholders replaced by the given fields.
+func (t OfferTemplate) Fill(fields map[string]string) (string, string) {
+	fill := func(s string) string {
+		return placeholder.ReplaceAllStringFunc(s, func(m string) string {
+			return fields[placeholder.FindStringSubmatch(m)[1]]
+		})
+	}
+	return fill(t.Title), fill(t.Body)
+}
+
+// CreateOfferTemplate adds an offer template to the company.
+func (s *Conn) CreateOfferTemplate(ctx context.Context, companyId uint, nt NewOfferTemplate) (OfferTemplate, error) {
+	err := nt.check()
+	if err != nil {
+		return OfferTemplate{}, err
+	}
+	t := OfferTemplate{CompanyID: companyId, Name: nt.Name, Title: nt.Title, Body: nt.Body}
+	err = s.db.WithContext(ctx).Create(&t).Error
+	if err != nil {
+		return OfferTemplate{}, err
+	}
+	return t, nil
+}
+
+// ViewOfferTemplates lists the offer templates of the company by name.
+func (s *Conn) ViewOfferTemplates(ctx context.Context, companyId uint) ([]OfferTemplate, error) {
+	var ts = make([]OfferTemplate, 0, 10)
+	err := s.db.WithContext(ctx).Where("company_id = ?", companyId).Order("name").Find(&ts).Error
+	if err != nil {
+		return nil, err
+	}
+	return ts, nil
+}
+
+func (s *Conn) ViewOfferTemplate(ctx context.Context, templateId uint) (OfferTemplate, error) {
+	var t OfferTemplate
+	err := s.db.WithContext(ctx).First(&t, templateId).Error
+	if err != nil {
+		return OfferTemplate{}, err
+	}
+	return t, nil
+}
+
+// UpdateOfferTemplate replaces the name and text of an offer template. Offers already sent keep their letter.
+func (s *Conn) UpdateOfferTemplate(ctx context.Context, templateId uint, nt NewOfferTemplate) (OfferTemplate, error) {
+	err := nt.check()
+	if err != nil {
+		return OfferTemplate{}, err
+	}
+	var t OfferTemplate
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.First(&t, templateId).Error
+		if err != nil {
+			return err
+		}
+		t.Name, t.Title, t.Body = nt.Name, nt.Title, nt.Body
+		return tx.Model(&t).Select("name", "title", "body").Updates(&t).Error
+	})
+	if err != nil {
+		return OfferTemplate{}, err
+	}
+	return t, nil
+}
+
+func (s *Conn) DeleteOfferTemplate(ctx context.Context, templateId uint) error {
+	return s.db.WithContext(ctx).Delete(&OfferTemplate{}, templateId).Error
+}
+
+// CreateOffer records an offer whose letter has already been written to the blob store as doc,
+// supersedes the offer the candidate had pending and marks the application offered.
+func (s *Conn) CreateOffer(ctx context.Context, offer Offer, doc Document) (Offer, error) {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var app Application
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&app, offer.ApplicationID).Error
+		if err != nil {
+			return err
+		}
+		switch app.Status {
+		case ApplicationStatusWithdrawn:
+			return ErrApplicationWithdrawn
+		case ApplicationStatusRejected, ApplicationStatusHired:
+			return ErrOfferNotAllowed
+		}
+		err = tx.Model(&Offer{}).Where("application_id = ? AND status = ?", app.ID, OfferPending).
+			Update("status", OfferSuperseded).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Create(&doc).Error
+		if err != nil {
+			return err
+		}
+		offer.CandidateID, offer.DocumentID, offer.Status = app.UserID, doc.ID, OfferPending
+		err = tx.Create(&offer).Error
+		if err != nil {
+			return err
+		}
+		app.Status, app.StatusMessage = ApplicationStatusOffered, ""
+		err = tx.Model(&app).Select("status", "status_message").Updates(&app).Error
+		if err != nil {
+			return err
+		}
+		return recordEvent(tx, AggregateApplication, app.ID, EventApplicationOffered, app)
+	})
+	if err != nil {
+		return Offer{}, err
+	}
+	return offer, nil
+}
+
+func (s *Conn) ViewOffer(ctx context.Context, offerId uint) (Offer, error) {
+	var offer Offer
+	err := s.db.WithContext(ctx).First(&offer, offerId).Error
+	if err != nil {
+		return Offer{}, err
+	}
+	return offer, nil
+}
+
+// ViewApplicationOffers lists the offers sent for the application, newest first.
+func (s *Conn) ViewApplicationOffers(ctx context.Context, applicationId uint) ([]Offer, error) {
+	var offers = make([]Offer, 0, 2)
+	err := s.db.WithContext(ctx).Where("application_id = ?", applicationId).Order("id desc").Find(&offers).Error
+	if err != nil {
+		return nil, err
+	}
+	return offers, nil
+}
+
+// ViewOffersByCandidate lists the offers the candidate was sent, newest first.
+func (s *Conn) ViewOffersByCandidate(ctx context.Context, candidateId uint) ([]Offer, error) {
+	var offers = make([]Offer, 0, 2)
+	err := s.db.WithContext(ctx).Where("candidate_id = ?", candidateId).Order("id desc").Find(&offers).Error
+	if err != nil {
+		return nil, err
+	}
+	return offers, nil
+}
+
+// RespondOffer records the candidate accepting or declining a pending offer, and updates the application to match:
+// hired or offer_declined. Offers of other candidates are not found.
+func (s *Conn) RespondOffer(ctx context.Context, offerId, candidateId uint, accept bool, reason string) (Offer, error) {
+	var offer Offer
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("candidate_id = ?", candidateId).First(&offer, offerId).Error
+		if err != nil {
+			return err
+		}
+		if offer.Status != OfferPending {
+			return ErrOfferNotPending
+		}
+		var app Application
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&app, offer.ApplicationID).Error
+		if err != nil {
+			return err
+		}
+		if app.Status == ApplicationStatusWithdrawn {
+			return ErrApplicationWithdrawn
+		}
+
+		now := time.Now().UTC()
+		offer.RespondedAt = &now
+		event := EventApplicationHired
+		offer.Status, app.Status = OfferAccepted, ApplicationStatusHired
+		if !accept {
+			event = EventApplicationOfferDeclined
+			offer.Status, app.Status, offer.DeclineReason = OfferDeclined, ApplicationStatusOfferDeclined, reason
+		}
+		err = tx.Model(&offer).Select("status", "responded_at", "decline_reason").Updates(&offer).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&app).Select("status").Updates(&app).Error
+		if err != nil {
+			return err
+		}
+		if accept {
+			var job Job
+			err = tx.Select("id", "company_id").First(&job, app.JobID).Error
+			if err != nil {
+				return err
+			}
+			err = recordActivity(tx, JobActivity{
+				JobID: job.ID, CompanyID: job.CompanyID, Kind: ActivityHire, OccurredAt: now, ApplicationID: &app.ID,
+				Seconds: int64(now.Sub(app.CreatedAt) / time.Second),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return recordEvent(tx, AggregateApplication, app.ID, event, app)
+	})
+	if err != nil {
+		return Offer{}, err
+	}
+	return offer, nil
+}
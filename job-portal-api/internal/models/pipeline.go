@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DefaultPipeline names the stages a company starts with until it defines its own.
+var DefaultPipeline = []string{"Applied", "Screening", "Interview", "Offer", "Hired"}
+
+// PipelineStage is one step of a hiring pipeline. Stages without a job make up the default pipeline
+// of their company, used by every job that does not define its own.
+type PipelineStage struct {
+	gorm.Model
+	CompanyID uint   `json:"company_id" gorm:"index;not null"`
+	JobID     *uint  `json:"job_id,omitempty" gorm:"index"`
+	Name      string `json:"name" gorm:"not null"`
+	Position  int    `json:"position"`
+}
+
+// ApplicationStageChange records an application moving between stages of its pipeline.
+// FromStageID is empty for the stage an application was placed in when submitted.
+type ApplicationStageChange struct {
+	ID            uint      `json:"id" gorm:"primarykey"`
+	CreatedAt     time.Time `json:"created_at"`
+	ApplicationID uint      `json:"application_id" gorm:"index;not null"`
+	FromStageID   *uint     `json:"from_stage_id,omitempty"`
+	ToStageID     uint      `json:"to_stage_id"`
+	// MovedBy is empty for changes made by the system
+	MovedBy *uint `json:"moved_by,omitempty"`
+}
+
+// StageUpdate is one stage of a pipeline being saved. Stages with an ID keep it, and the applications in them;
+// stages without one are added.
+type StageUpdate struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name" validate:"required,max=50"`
+}
+
+// PipelineUpdate is the payload accepted when replacing a pipeline, its stages in order.
+type PipelineUpdate struct {
+	Stages []StageUpdate `json:"stages" validate:"required,min=1,max=20,unique=Name,dive"`
+}
+
+// StageMove is the payload accepted when moving an application to another stage.
+type StageMove struct {
+	StageID uint `json:"stage_id" validate:"required"`
+}
+
+// Pipeline is the ordered stages a job's applications go through.
+// Inherited tells a job that follows its company's default pipeline.
+type Pipeline struct {
+	CompanyID uint            `json:"company_id"`
+	JobID     *uint           `json:"job_id,omitempty"`
+	Inherited bool            `json:"inherited"`
+	Stages    []PipelineStage `json:"stages"`
+}
+
+// BoardColumn is a stage of a job's board with the applications in it, oldest first.
+type BoardColumn struct {
+	Stage        PipelineStage `json:"stage"`
+	Count        int           `json:"count"`
+	Applications []Application `json:"applications"`
+}
+
+// Board groups the live applications of a job by the stage they are in.
+type Board struct {
+	JobID     uint          `json:"job_id"`
+	Columns   []BoardColumn `json:"columns"`
+	Total     int           `json:"total"`
+	Withdrawn int64         `json:"withdrawn"`
+	Rejected  int64         `json:"rejected"`
+}
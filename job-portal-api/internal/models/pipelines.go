@@ -0,0 +1,346 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrInvalidPipeline is returned when a pipeline being saved references stages it cannot keep.
+	ErrInvalidPipeline = errors.New("invalid pipeline")
+	// ErrStageInUse is returned when a pipeline being saved drops a stage that still has applications.
+	ErrStageInUse = errors.New("stage still has applications")
+	// ErrUnknownStage is returned when moving an application to a stage outside its job's pipeline.
+	ErrUnknownStage = errors.New("stage is not part of the job's pipeline")
+)
+
+// companyStages returns the default pipeline of the company, starting it from DefaultPipeline on first use.
+func companyStages(tx *gorm.DB, companyId uint) ([]PipelineStage, error) {
+	var stages []PipelineStage
+	err := tx.Where("company_id = ? AND job_id IS NULL", companyId).Order("position").Find(&stages).Error
+	if err != nil || len(stages) > 0 {
+		return stages, err
+	}
+	// Lock the company so concurrent first uses create the stages once
+	err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&Company{}, companyId).Error
+	if err != nil {
+		return nil, err
+	}
+	err = tx.Where("company_id = ? AND job_id IS NULL", companyId).Order("position").Find(&stages).Error
+	if err != nil || len(stages) > 0 {
+		return stages, err
+	}
+	for i, name := range DefaultPipeline {
+		stages = append(stages, PipelineStage{CompanyID: companyId, Name: name, Position: i})
+	}
+	err = tx.Create(&stages).Error
+	if err != nil {
+		return nil, err
+	}
+	return stages, nil
+}
+
+// jobStages returns the stages of the job's pipeline and whether they are its company's default.
+func jobStages(tx *gorm.DB, job Job) ([]PipelineStage, bool, error) {
+	var stages []PipelineStage
+	err := tx.Where("job_id = ?", job.ID).Order("position").Find(&stages).Error
+	if err != nil {
+		return nil, false, err
+	}
+	if len(stages) > 0 {
+		return stages, false, nil
+	}
+	stages, err = companyStages(tx, job.CompanyID)
+	return stages, true, err
+}
+
+// ViewCompanyPipeline returns the default pipeline of the company.
+func (s *Conn) ViewCompanyPipeline(ctx context.Context, companyId uint) (Pipeline, error) {
+	p := Pipeline{CompanyID: companyId}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		p.Stages, err = companyStages(tx, companyId)
+		return err
+	})
+	if err != nil {
+		return Pipeline{}, err
+	}
+	return p, nil
+}
+
+// ViewJobPipeline returns the pipeline the job's applications go through, its own or its company's default.
+func (s *Conn) ViewJobPipeline(ctx context.Context, jobId uint) (Pipeline, error) {
+	var p Pipeline
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var job Job
+		err := tx.Select("id", "company_id").First(&job, jobId).Error
+		if err != nil {
+			return err
+		}
+		p = Pipeline{CompanyID: job.CompanyID, JobID: &job.ID}
+		p.Stages, p.Inherited, err = jobStages(tx, job)
+		return err
+	})
+	if err != nil {
+		return Pipeline{}, err
+	}
+	return p, nil
+}
+
+// UpdateCompanyPipeline replaces the default pipeline of the company.
+// Jobs with a pipeline of their own are not affected.
+func (s *Conn) UpdateCompanyPipeline(ctx context.Context, companyId uint, pu PipelineUpdate) (Pipeline, error) {
+	p := Pipeline{CompanyID: companyId}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		current, err := companyStages(tx, companyId)
+		if err != nil {
+			return err
+		}
+		p.Stages, err = saveStages(tx, companyId, nil, current, pu.Stages)
+		return err
+	})
+	if err != nil {
+		return Pipeline{}, err
+	}
+	return p, nil
+}
+
+// UpdateJobPipeline gives the job a pipeline of its own, or replaces the one it has.
+// A job leaving its company's default pipeline may keep stages of it by ID; its applications in them move along.
+func (s *Conn) UpdateJobPipeline(ctx context.Context, jobId uint, pu PipelineUpdate) (Pipeline, error) {
+	var p Pipeline
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var job Job
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "company_id").First(&job, jobId).Error
+		if err != nil {
+			return err
+		}
+		current, _, err := jobStages(tx, job)
+		if err != nil {
+			return err
+		}
+		p = Pipeline{CompanyID: job.CompanyID, JobID: &job.ID}
+		p.Stages, err = saveStages(tx, job.CompanyID, &job.ID, current, pu.Stages)
+		return err
+	})
+	if err != nil {
+		return Pipeline{}, err
+	}
+	return p, nil
+}
+
+// saveStages turns the current stages of a pipeline into the updated ones, in their order.
+// A nil jobId saves the company's default pipeline.
+func saveStages(tx *gorm.DB, companyId uint, jobId *uint, current []PipelineStage, updates []StageUpdate) ([]PipelineStage, error) {
+	byID := make(map[uint]PipelineStage, len(current))
+	for _, st := range current {
+		byID[st.ID] = st
+	}
+	kept := make(map[uint]bool, len(updates))
+	saved := make([]PipelineStage, 0, len(updates))
+	for i, u := range updates {
+		st, ok := byID[u.ID]
+		if u.ID != 0 && !ok {
+			return nil, fmt.Errorf("%w: stage %d is not part of it", ErrInvalidPipeline, u.ID)
+		}
+		if u.ID != 0 && kept[u.ID] {
+			return nil, fmt.Errorf("%w: stage %d is listed twice", ErrInvalidPipeline, u.ID)
+		}
+		kept[u.ID] = true
+
+		if ok && sameJob(st.JobID, jobId) {
+			st.Name, st.Position = u.Name, i
+			err := tx.Model(&st).Select("name", "position").Updates(&st).Error
+			if err != nil {
+				return nil, err
+			}
+			saved = append(saved, st)
+			continue
+		}
+		next := PipelineStage{CompanyID: companyId, JobID: jobId, Name: u.Name, Position: i}
+		err := tx.Create(&next).Error
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			// The job takes its applications along from the company's stage
+			err = tx.Model(&Application{}).Where("job_id = ? AND stage_id = ?", *jobId, st.ID).Update("stage_id", next.ID).Error
+			if err != nil {
+				return nil, err
+			}
+		}
+		saved = append(saved, next)
+	}
+
+	for _, st := range current {
+		if kept[st.ID] {
+			continue
+		}
+		q := tx.Model(&Application{}).Where("stage_id = ?", st.ID)
+		if jobId != nil {
+			q = q.Where("job_id = ?", *jobId)
+		}
+		var n int64
+		err := q.Count(&n).Error
+		if err != nil {
+			return nil, err
+		}
+		if n > 0 {
+			return nil, fmt.Errorf("%w: %d in %s", ErrStageInUse, n, st.Name)
+		}
+		// Stages of the company's default pipeline stay when a job stops using them
+		if sameJob(st.JobID, jobId) {
+			err = tx.Delete(&st).Error
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return saved, nil
+}
+
+func sameJob(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// placeApplication puts a new application in the first stage of its job's pipeline.
+func placeApplication(tx *gorm.DB, app *Application, job Job, at time.Time) error {
+	stages, _, err := jobStages(tx, job)
+	if err != nil {
+		return err
+	}
+	app.StageID, app.StageEnteredAt = &stages[0].ID, &at
+	return nil
+}
+
+// MoveApplication moves the application to another stage of its job's pipeline and records when it did.
+// Moving it to the stage it is in changes nothing.
+func (s *Conn) MoveApplication(ctx context.Context, applicationId, stageId, movedBy uint) (Application, error) {
+	var app Application
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&app, applicationId).Error
+		if err != nil {
+			return err
+		}
+		if app.Status == ApplicationStatusWithdrawn {
+			return ErrApplicationWithdrawn
+		}
+		if app.StageID != nil && *app.StageID == stageId {
+			return nil
+		}
+		var job Job
+		err = tx.Select("id", "company_id").First(&job, app.JobID).Error
+		if err != nil {
+			return err
+		}
+		stages, _, err := jobStages(tx, job)
+		if err != nil {
+			return err
+		}
+		found := false
+		for _, st := range stages {
+			found = found || st.ID == stageId
+		}
+		if !found {
+			return ErrUnknownStage
+		}
+
+		now := time.Now().UTC()
+		change := ApplicationStageChange{ApplicationID: app.ID, FromStageID: app.StageID, ToStageID: stageId, MovedBy: &movedBy}
+		if app.StageID != nil && app.StageEnteredAt != nil {
+			err = recordActivity(tx, JobActivity{
+				JobID: job.ID, CompanyID: job.CompanyID, Kind: ActivityStage, OccurredAt: now, ApplicationID: &app.ID,
+				StageID: app.StageID, Seconds: int64(now.Sub(*app.StageEnteredAt) / time.Second),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		app.StageID, app.StageEnteredAt = &stageId, &now
+		err = tx.Model(&app).Select("stage_id", "stage_entered_at").Updates(&app).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Create(&change).Error
+		if err != nil {
+			return err
+		}
+		return recordEvent(tx, AggregateApplication, app.ID, EventApplicationMoved, app)
+	})
+	if err != nil {
+		return Application{}, err
+	}
+	return app, nil
+}
+
+// ViewApplicationStageChanges lists the stages the application went through, oldest first.
+func (s *Conn) ViewApplicationStageChanges(ctx context.Context, applicationId uint) ([]ApplicationStageChange, error) {
+	var changes = make([]ApplicationStageChange, 0, 10)
+	err := s.db.WithContext(ctx).Where("application_id = ?", applicationId).Order("id").Find(&changes).Error
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// ViewBoard groups the applications of the job that were neither withdrawn nor rejected by the stage of its pipeline they are in.
+func (s *Conn) ViewBoard(ctx context.Context, jobId uint) (Board, error) {
+	board := Board{JobID: jobId}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var job Job
+		err := tx.Select("id", "company_id").First(&job, jobId).Error
+		if err != nil {
+			return err
+		}
+		stages, _, err := jobStages(tx, job)
+		if err != nil {
+			return err
+		}
+		var apps []Application
+		closed := []string{ApplicationStatusWithdrawn, ApplicationStatusRejected}
+		err = tx.Where("job_id = ? AND status NOT IN ?", jobId, closed).Order("created_at").Find(&apps).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&Application{}).Where("job_id = ? AND status = ?", jobId, ApplicationStatusWithdrawn).
+			Count(&board.Withdrawn).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&Application{}).Where("job_id = ? AND status = ?", jobId, ApplicationStatusRejected).
+			Count(&board.Rejected).Error
+		if err != nil {
+			return err
+		}
+
+		columns := make(map[uint]int, len(stages))
+		board.Columns = make([]BoardColumn, 0, len(stages))
+		for i, st := range stages {
+			columns[st.ID] = i
+			board.Columns = append(board.Columns, BoardColumn{Stage: st, Applications: []Application{}})
+		}
+		for _, app := range apps {
+			// Applications submitted before pipelines existed wait in the first stage
+			i := 0
+			if app.StageID != nil {
+				i = columns[*app.StageID]
+			}
+			board.Columns[i].Applications = append(board.Columns[i].Applications, app)
+			board.Columns[i].Count++
+		}
+		board.Total = len(apps)
+		return nil
+	})
+	if err != nil {
+		return Board{}, err
+	}
+	return board, nil
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// Profile holds the structured candidate data attached to a user.
+// There is at most one profile per user.
+type Profile struct {
+	gorm.Model
+	UserID           uint          `json:"user_id" gorm:"uniqueIndex;not null"`
+	Headline         string        `json:"headline"`
+	Summary          string        `json:"summary"`
+	Skills           []string      `json:"skills" gorm:"serializer:json"`
+	Experiences      []Experience  `json:"experiences" gorm:"foreignKey:ProfileID"`
+	Educations       []Education   `json:"educations" gorm:"foreignKey:ProfileID"`
+	DesiredRoles     []string      `json:"desired_roles" gorm:"serializer:json"`
+	DesiredLocations []string      `json:"desired_locations" gorm:"serializer:json"`
+	DesiredSalaryMin int           `json:"desired_salary_min"`
+	DesiredSalaryMax int           `json:"desired_salary_max"`
+	SalaryCurrency   string        `json:"salary_currency"`
+	Links            []ProfileLink `json:"links" gorm:"serializer:json"`
+	// Discoverable candidates opted in to being found by recruiters searching for candidates
+	Discoverable bool `json:"discoverable" gorm:"index"`
+}
+
+// Experience is a single work history entry of a profile.
+// Dates are kept as "YYYY-MM" strings since that is the precision candidates give.
+type Experience struct {
+	gorm.Model
+	ProfileID   uint   `json:"profile_id"`
+	Title       string `json:"title"`
+	Company     string `json:"company"`
+	Location    string `json:"location"`
+	StartDate   string `json:"start_date"`
+	EndDate     string `json:"end_date"`
+	Current     bool   `json:"current"`
+	Description string `json:"description"`
+}
+
+// Education is a single education entry of a profile.
+type Education struct {
+	gorm.Model
+	ProfileID    uint   `json:"profile_id"`
+	Institution  string `json:"institution"`
+	Degree       string `json:"degree"`
+	FieldOfStudy string `json:"field_of_study"`
+	StartYear    int    `json:"start_year"`
+	EndYear      int    `json:"end_year"`
+}
+
+// ProfileLink is an external link such as a portfolio, GitHub or LinkedIn page.
+type ProfileLink struct {
+	Label string `json:"label" validate:"required,max=50"`
+	URL   string `json:"url" validate:"required,url"`
+}
+
+// NewProfile is the payload accepted when a candidate creates or replaces their profile.
+type NewProfile struct {
+	Headline         string          `json:"headline" validate:"required,max=200"`
+	Summary          string          `json:"summary" validate:"max=5000"`
+	Skills           []string        `json:"skills" validate:"max=100,dive,required,max=50"`
+	Experiences      []NewExperience `json:"experiences" validate:"max=50,dive"`
+	Educations       []NewEducation  `json:"educations" validate:"max=20,dive"`
+	DesiredRoles     []string        `json:"desired_roles" validate:"max=20,dive,required,max=100"`
+	DesiredLocations []string        `json:"desired_locations" validate:"max=20,dive,required,max=100"`
+	DesiredSalaryMin int             `json:"desired_salary_min" validate:"gte=0"`
+	DesiredSalaryMax int             `json:"desired_salary_max" validate:"omitempty,gtefield=DesiredSalaryMin"`
+	SalaryCurrency   string          `json:"salary_currency" validate:"required_with=DesiredSalaryMin DesiredSalaryMax,omitempty,iso4217"`
+	Links            []ProfileLink   `json:"links" validate:"max=20,dive"`
+	Discoverable     bool            `json:"discoverable"`
+}
+
+type NewExperience struct {
+	Title       string `json:"title" validate:"required,max=200"`
+	Company     string `json:"company" validate:"required,max=200"`
+	Location    string `json:"location" validate:"max=200"`
+	StartDate   string `json:"start_date" validate:"required,datetime=2006-01"`
+	EndDate     string `json:"end_date" validate:"required_without=Current,excluded_with=Current,omitempty,datetime=2006-01"`
+	Current     bool   `json:"current"`
+	Description string `json:"description" validate:"max=5000"`
+}
+
+type NewEducation struct {
+	Institution  string `json:"institution" validate:"required,max=200"`
+	Degree       string `json:"degree" validate:"max=200"`
+	FieldOfStudy string `json:"field_of_study" validate:"max=200"`
+	StartYear    int    `json:"start_year" validate:"omitempty,gte=1900,lte=2100"`
+	EndYear      int    `json:"end_year" validate:"omitempty,gtefield=StartYear,lte=2100"`
+}
@@ -0,0 +1,141 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ViewProfile fetches the profile of the given user along with its experience and education entries.
+func (s *Conn) ViewProfile(ctx context.Context, userId uint) (Profile, error) {
+	var p Profile
+	err := s.db.WithContext(ctx).
+		Preload("Experiences", func(db *gorm.DB) *gorm.DB { return db.Order("start_date desc") }).
+		Preload("Educations", func(db *gorm.DB) *gorm.DB { return db.Order("start_year desc") }).
+		Where("user_id = ?", userId).First(&p).Error
+	if err != nil {
+		return Profile{}, err
+	}
+	return p, nil
+}
+
+// UpdateProfile creates the profile of the given user or replaces it entirely with np.
+// Experience and education entries are replaced as a whole, so the payload is always the full profile.
+func (s *Conn) UpdateProfile(ctx context.Context, np NewProfile, userId uint) (Profile, error) {
+	var p Profile
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("user_id = ?", userId).First(&p).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		p.UserID = userId
+		p.Headline = np.Headline
+		p.Summary = np.Summary
+		p.Skills = NormalizeSkills(np.Skills)
+		p.DesiredRoles = np.DesiredRoles
+		p.DesiredLocations = np.DesiredLocations
+		p.DesiredSalaryMin = np.DesiredSalaryMin
+		p.DesiredSalaryMax = np.DesiredSalaryMax
+		p.SalaryCurrency = strings.ToUpper(np.SalaryCurrency)
+		p.Links = np.Links
+		p.Discoverable = np.Discoverable
+		p.Experiences = nil
+		p.Educations = nil
+
+		// Save inserts the row when it does not exist yet and updates every column otherwise.
+		err = tx.Omit("Experiences", "Educations").Save(&p).Error
+		if err != nil {
+			return err
+		}
+
+		// Old entries are removed for good, the profile itself keeps the history.
+		err = tx.Unscoped().Where("profile_id = ?", p.ID).Delete(&Experience{}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Unscoped().Where("profile_id = ?", p.ID).Delete(&Education{}).Error
+		if err != nil {
+			return err
+		}
+
+		for _, ne := range np.Experiences {
+			p.Experiences = append(p.Experiences, Experience{
+				ProfileID:   p.ID,
+				Title:       ne.Title,
+				Company:     ne.Company,
+				Location:    ne.Location,
+				StartDate:   ne.StartDate,
+				EndDate:     ne.EndDate,
+				Current:     ne.Current,
+				Description: ne.Description,
+			})
+		}
+		for _, ne := range np.Educations {
+			p.Educations = append(p.Educations, Education{
+				ProfileID:    p.ID,
+				Institution:  ne.Institution,
+				Degree:       ne.Degree,
+				FieldOfStudy: ne.FieldOfStudy,
+				StartYear:    ne.StartYear,
+				EndYear:      ne.EndYear,
+			})
+		}
+		if len(p.Experiences) > 0 {
+			err = tx.Create(&p.Experiences).Error
+			if err != nil {
+				return err
+			}
+		}
+		if len(p.Educations) > 0 {
+			err = tx.Create(&p.Educations).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return Profile{}, err
+	}
+	return p, nil
+}
+
+// DeleteProfile removes the profile of the given user together with its entries.
+// Rows are deleted for good so the user can create a fresh profile afterwards.
+func (s *Conn) DeleteProfile(ctx context.Context, userId uint) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var p Profile
+		err := tx.Where("user_id = ?", userId).First(&p).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Unscoped().Where("profile_id = ?", p.ID).Delete(&Experience{}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Unscoped().Where("profile_id = ?", p.ID).Delete(&Education{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&p).Error
+	})
+}
+
+// NormalizeSkills trims and lower-cases skills and drops duplicates while keeping the original order,
+// so that skills can be compared across profiles and jobs.
+func NormalizeSkills(skills []string) []string {
+	seen := make(map[string]bool, len(skills))
+	out := make([]string, 0, len(skills))
+	for _, sk := range skills {
+		sk = strings.ToLower(strings.Join(strings.Fields(sk), " "))
+		if sk == "" || seen[sk] {
+			continue
+		}
+		seen[sk] = true
+		out = append(out, sk)
+	}
+	return out
+}
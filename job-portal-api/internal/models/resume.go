@@ -0,0 +1,119 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+const (
+	ResumeParsePending = "pending"
+	ResumeParseDone    = "done"
+	ResumeParseFailed  = "failed"
+)
+
+// ResumeDraft is what the resume parser could pull out of a resume.
+// Nothing in it reaches the profile until the candidate confirms it.
+type ResumeDraft struct {
+	Name        string          `json:"name"`
+	Email       string          `json:"email"`
+	Phone       string          `json:"phone"`
+	Links       []string        `json:"links"`
+	Skills      []string        `json:"skills"`
+	Experiences []NewExperience `json:"experiences"`
+	Educations  []NewEducation  `json:"educations"`
+}
+
+// ResumeParse tracks the parsing of one uploaded resume.
+type ResumeParse struct {
+	gorm.Model
+	DocumentID uint        `json:"document_id" gorm:"uniqueIndex"`
+	UserID     uint        `json:"user_id" gorm:"index"`
+	Status     string      `json:"status"`
+	Error      string      `json:"error,omitempty"`
+	Draft      ResumeDraft `json:"draft" gorm:"serializer:json"`
+}
+
+// ResumeConfirmation is sent by the candidate to copy (parts of) a draft into their profile.
+// Draft may carry the candidate's corrections; when it is nil the stored draft is used.
+type ResumeConfirmation struct {
+	Skills      bool         `json:"skills"`
+	Experiences bool         `json:"experiences"`
+	Educations  bool         `json:"educations"`
+	Draft       *ResumeDraft `json:"draft"`
+}
+
+// MergeDraft builds the profile payload resulting from confirming the selected parts of d on top of p.
+// Skills are added to the existing ones; experience and education entries are appended unless
+// an identical entry already exists.
+func MergeDraft(p Profile, d ResumeDraft, rc ResumeConfirmation) NewProfile {
+	np := NewProfile{
+		Headline:         p.Headline,
+		Summary:          p.Summary,
+		Skills:           p.Skills,
+		DesiredRoles:     p.DesiredRoles,
+		DesiredLocations: p.DesiredLocations,
+		DesiredSalaryMin: p.DesiredSalaryMin,
+		DesiredSalaryMax: p.DesiredSalaryMax,
+		SalaryCurrency:   p.SalaryCurrency,
+		Links:            p.Links,
+	}
+	for _, e := range p.Experiences {
+		np.Experiences = append(np.Experiences, NewExperience{
+			Title:       e.Title,
+			Company:     e.Company,
+			Location:    e.Location,
+			StartDate:   e.StartDate,
+			EndDate:     e.EndDate,
+			Current:     e.Current,
+			Description: e.Description,
+		})
+	}
+	for _, e := range p.Educations {
+		np.Educations = append(np.Educations, NewEducation{
+			Institution:  e.Institution,
+			Degree:       e.Degree,
+			FieldOfStudy: e.FieldOfStudy,
+			StartYear:    e.StartYear,
+			EndYear:      e.EndYear,
+		})
+	}
+
+	if rc.Skills {
+		np.Skills = NormalizeSkills(append(append([]string(nil), np.Skills...), d.Skills...))
+	}
+	if rc.Experiences {
+		for _, e := range d.Experiences {
+			if !containsExperience(np.Experiences, e) {
+				np.Experiences = append(np.Experiences, e)
+			}
+		}
+	}
+	if rc.Educations {
+		for _, e := range d.Educations {
+			if !containsEducation(np.Educations, e) {
+				np.Educations = append(np.Educations, e)
+			}
+		}
+	}
+	if np.Headline == "" && len(np.Experiences) > 0 {
+		np.Headline = np.Experiences[0].Title
+	}
+	return np
+}
+
+func containsExperience(list []NewExperience, e NewExperience) bool {
+	for _, x := range list {
+		if x.Title == e.Title && x.Company == e.Company && x.StartDate == e.StartDate {
+			return true
+		}
+	}
+	return false
+}
+
+func containsEducation(list []NewEducation, e NewEducation) bool {
+	for _, x := range list {
+		if x.Institution == e.Institution && x.Degree == e.Degree {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"context"
+
+	"gorm.io/gorm/clause"
+)
+
+// StartResumeParse marks the resume as waiting to be parsed, resetting the result of an earlier run.
+func (s *Conn) StartResumeParse(ctx context.Context, documentId uint, userId uint) (ResumeParse, error) {
+	rp := ResumeParse{
+		DocumentID: documentId,
+		UserID:     userId,
+		Status:     ResumeParsePending,
+	}
+	err := s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "document_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"status", "error", "draft", "updated_at"}),
+	}).Create(&rp).Error
+	if err != nil {
+		return ResumeParse{}, err
+	}
+	return rp, nil
+}
+
+// FinishResumeParse stores the outcome of parsing a resume. A non empty parseErr marks the run as failed.
+func (s *Conn) FinishResumeParse(ctx context.Context, documentId uint, draft ResumeDraft, parseErr string) error {
+	status := ResumeParseDone
+	if parseErr != "" {
+		status = ResumeParseFailed
+	}
+	// Select makes sure an empty error or draft overwrites the previous run
+	return s.db.WithContext(ctx).Model(&ResumeParse{}).Where("document_id = ?", documentId).
+		Select("status", "error", "draft").
+		Updates(&ResumeParse{Status: status, Error: parseErr, Draft: draft}).Error
+}
+
+func (s *Conn) ViewResumeParse(ctx context.Context, documentId uint) (ResumeParse, error) {
+	var rp ResumeParse
+	err := s.db.WithContext(ctx).Where("document_id = ?", documentId).First(&rp).Error
+	if err != nil {
+		return ResumeParse{}, err
+	}
+	return rp, nil
+}
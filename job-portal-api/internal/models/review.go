@@ -0,0 +1,78 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Review statuses. Reviews wait for a platform admin to approve them before they are shown or rated.
+const (
+	ReviewPending  = "pending"
+	ReviewApproved = "approved"
+	ReviewRejected = "rejected"
+)
+
+// CompanyReview is a current or former employee reviewing a company, with star ratings from 1 to 5.
+// Only the overall rating is required; categories left out are not counted in their averages.
+type CompanyReview struct {
+	gorm.Model
+	CompanyID uint `json:"company_id" gorm:"uniqueIndex:idx_company_review_author;index:idx_company_review_status,priority:1;not null"`
+	// UserID is the author, shown to them only; reviews are published anonymously
+	UserID           uint   `json:"user_id,omitempty" gorm:"uniqueIndex:idx_company_review_author;not null"`
+	Title            string `json:"title"`
+	Pros             string `json:"pros"`
+	Cons             string `json:"cons"`
+	JobTitle         string `json:"job_title,omitempty"`
+	EmploymentStatus string `json:"employment_status"`
+	Overall          int    `json:"overall" gorm:"not null"`
+	WorkLifeBalance  *int   `json:"work_life_balance,omitempty"`
+	Compensation     *int   `json:"compensation,omitempty"`
+	Culture          *int   `json:"culture,omitempty"`
+	Management       *int   `json:"management,omitempty"`
+	CareerGrowth     *int   `json:"career_growth,omitempty"`
+	Status           string `json:"status" gorm:"index:idx_company_review_status,priority:2;not null"`
+	// ModerationNote tells the author why the review was rejected
+	ModerationNote string     `json:"moderation_note,omitempty"`
+	ModeratedBy    *uint      `json:"moderated_by,omitempty"`
+	ModeratedAt    *time.Time `json:"moderated_at,omitempty"`
+}
+
+// NewCompanyReview is the payload accepted when writing or editing a review.
+type NewCompanyReview struct {
+	Title            string `json:"title" validate:"required,max=200"`
+	Pros             string `json:"pros" validate:"required,max=5000"`
+	Cons             string `json:"cons" validate:"required,max=5000"`
+	JobTitle         string `json:"job_title" validate:"max=200"`
+	EmploymentStatus string `json:"employment_status" validate:"required,oneof=current former"`
+	Overall          int    `json:"overall" validate:"required,min=1,max=5"`
+	WorkLifeBalance  *int   `json:"work_life_balance" validate:"omitempty,min=1,max=5"`
+	Compensation     *int   `json:"compensation" validate:"omitempty,min=1,max=5"`
+	Culture          *int   `json:"culture" validate:"omitempty,min=1,max=5"`
+	Management       *int   `json:"management" validate:"omitempty,min=1,max=5"`
+	CareerGrowth     *int   `json:"career_growth" validate:"omitempty,min=1,max=5"`
+}
+
+// ReviewModeration is a platform admin's decision on a review.
+type ReviewModeration struct {
+	Status string `json:"status" validate:"required,oneof=approved rejected"`
+	Note   string `json:"note" validate:"required_if=Status rejected,max=1000"`
+}
+
+// RatingSummary is the average overall rating of a company over its approved reviews.
+type RatingSummary struct {
+	CompanyID uint    `json:"-"`
+	Average   float64 `json:"average"`
+	Count     int64   `json:"count"`
+}
+
+// CompanyRating is the rating of a company overall and by category. A category is empty
+// when no approved review rated it.
+type CompanyRating struct {
+	RatingSummary
+	WorkLifeBalance *float64 `json:"work_life_balance,omitempty"`
+	Compensation    *float64 `json:"compensation,omitempty"`
+	Culture         *float64 `json:"culture,omitempty"`
+	Management      *float64 `json:"management,omitempty"`
+	CareerGrowth    *float64 `json:"career_growth,omitempty"`
+}
@@ -0,0 +1,162 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrReviewOwnCompany is returned when a member of a company reviews it.
+var ErrReviewOwnCompany = errors.New("members of a company cannot review it")
+
+// applyReview copies a review as written onto r.
+func (r *CompanyReview) applyReview(nr NewCompanyReview) {
+	r.Title, r.Pros, r.Cons, r.JobTitle, r.EmploymentStatus = nr.Title, nr.Pros, nr.Cons, nr.JobTitle, nr.EmploymentStatus
+	r.Overall, r.WorkLifeBalance, r.Compensation = nr.Overall, nr.WorkLifeBalance, nr.Compensation
+	r.Culture, r.Management, r.CareerGrowth = nr.Culture, nr.Management, nr.CareerGrowth
+}
+
+// CreateCompanyReview adds the user's review of a company, waiting for moderation.
+// A user reviews a company once; a second review is a gorm.ErrDuplicatedKey.
+func (s *Conn) CreateCompanyReview(ctx context.Context, companyId, userId uint, nr NewCompanyReview) (CompanyReview, error) {
+	r := CompanyReview{CompanyID: companyId, UserID: userId, Status: ReviewPending}
+	r.applyReview(nr)
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Select("id").First(&Company{}, companyId).Error
+		if err != nil {
+			return err
+		}
+		var members int64
+		err = tx.Model(&Membership{}).Where("company_id = ? AND user_id = ?", companyId, userId).Count(&members).Error
+		if err != nil {
+			return err
+		}
+		if members > 0 {
+			return ErrReviewOwnCompany
+		}
+		return tx.Create(&r).Error
+	})
+	if err != nil {
+		return CompanyReview{}, err
+	}
+	return r, nil
+}
+
+// UpdateCompanyReview lets the author rewrite their review, which waits for moderation again.
+// Reviews of other users are not found.
+func (s *Conn) UpdateCompanyReview(ctx context.Context, reviewId, userId uint, nr NewCompanyReview) (CompanyReview, error) {
+	var r CompanyReview
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", userId).First(&r, reviewId).Error
+		if err != nil {
+			return err
+		}
+		r.applyReview(nr)
+		r.Status, r.ModerationNote, r.ModeratedBy, r.ModeratedAt = ReviewPending, "", nil, nil
+		return tx.Model(&r).Select("title", "pros", "cons", "job_title", "employment_status", "overall",
+			"work_life_balance", "compensation", "culture", "management", "career_growth",
+			"status", "moderation_note", "moderated_by", "moderated_at").Updates(&r).Error
+	})
+	if err != nil {
+		return CompanyReview{}, err
+	}
+	return r, nil
+}
+
+// DeleteCompanyReview removes the author's review for good, so they may review the company again.
+func (s *Conn) DeleteCompanyReview(ctx context.Context, reviewId, userId uint) error {
+	res := s.db.WithContext(ctx).Unscoped().Where("id = ? AND user_id = ?", reviewId, userId).Delete(&CompanyReview{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ViewCompanyReviews lists up to limit approved reviews of a company, the newest first, without their authors.
+func (s *Conn) ViewCompanyReviews(ctx context.Context, companyId uint, limit int) ([]CompanyReview, error) {
+	var reviews = make([]CompanyReview, 0, limit)
+	err := s.db.WithContext(ctx).Where("company_id = ? AND status = ?", companyId, ReviewApproved).
+		Order("id desc").Limit(limit).Find(&reviews).Error
+	if err != nil {
+		return nil, err
+	}
+	for i := range reviews {
+		reviews[i].UserID, reviews[i].ModeratedBy = 0, nil
+	}
+	return reviews, nil
+}
+
+// ViewReviewsByUser lists the reviews the user wrote, whatever their status, the newest first.
+func (s *Conn) ViewReviewsByUser(ctx context.Context, userId uint) ([]CompanyReview, error) {
+	var reviews = make([]CompanyReview, 0, 10)
+	err := s.db.WithContext(ctx).Where("user_id = ?", userId).Order("id desc").Find(&reviews).Error
+	if err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
+
+// ViewPendingReviews is the moderation queue: up to limit reviews waiting for a decision, the oldest first.
+func (s *Conn) ViewPendingReviews(ctx context.Context, limit int) ([]CompanyReview, error) {
+	var reviews = make([]CompanyReview, 0, limit)
+	err := s.db.WithContext(ctx).Where("status = ?", ReviewPending).Order("id").Limit(limit).Find(&reviews).Error
+	if err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
+
+// ModerateReview records a platform admin approving or rejecting a review. Approved reviews can still be
+// taken down by rejecting them.
+func (s *Conn) ModerateReview(ctx context.Context, reviewId, moderatorId uint, rm ReviewModeration) (CompanyReview, error) {
+	var r CompanyReview
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&r, reviewId).Error
+		if err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		r.Status, r.ModerationNote, r.ModeratedBy, r.ModeratedAt = rm.Status, rm.Note, &moderatorId, &now
+		return tx.Model(&r).Select("status", "moderation_note", "moderated_by", "moderated_at").Updates(&r).Error
+	})
+	if err != nil {
+		return CompanyReview{}, err
+	}
+	return r, nil
+}
+
+// ViewCompanyRating averages the approved reviews of a company, overall and by category.
+func (s *Conn) ViewCompanyRating(ctx context.Context, companyId uint) (CompanyRating, error) {
+	var rating CompanyRating
+	err := s.db.WithContext(ctx).Model(&CompanyReview{}).
+		Select(`company_id, AVG(overall) AS average, COUNT(*) AS count, AVG(work_life_balance) AS work_life_balance,
+			AVG(compensation) AS compensation, AVG(culture) AS culture, AVG(management) AS management,
+			AVG(career_growth) AS career_growth`).
+		Where("company_id = ? AND status = ?", companyId, ReviewApproved).Group("company_id").Scan(&rating).Error
+	if err != nil {
+		return CompanyRating{}, err
+	}
+	rating.CompanyID = companyId
+	return rating, nil
+}
+
+// CompanyRatings returns the rating summary of those of the given companies that have approved reviews.
+func (s *Conn) CompanyRatings(ctx context.Context, companyIds []uint) ([]RatingSummary, error) {
+	var ratings []RatingSummary
+	if len(companyIds) == 0 {
+		return ratings, nil
+	}
+	err := s.db.WithContext(ctx).Model(&CompanyReview{}).
+		Select("company_id, AVG(overall) AS average, COUNT(*) AS count").
+		Where("company_id IN ? AND status = ?", companyIds, ReviewApproved).Group("company_id").Scan(&ratings).Error
+	if err != nil {
+		return nil, err
+	}
+	return ratings, nil
+}
@@ -0,0 +1,27 @@
+package models
+
+import "context"
+
+// SalaryPostings lists the salaries of jobs that went live and gave one, whatever their status since,
+// the oldest first.
+func (s *Conn) SalaryPostings(ctx context.Context, f SalaryFilter) ([]SalaryPosting, error) {
+	var postings []SalaryPosting
+	db := s.db.WithContext(ctx).Model(&Job{}).
+		Select("id AS job_id, company_id, title, experience_level, location, salary_min, salary_max, salary_currency, salary_period").
+		Where("published_at IS NOT NULL AND published_at >= ?", f.Since).
+		Where("salary_currency <> '' AND (salary_min > 0 OR salary_max > 0)")
+	if f.Title != "" {
+		db = db.Where("title ILIKE ?", contains(f.Title))
+	}
+	if f.ExperienceLevel != "" {
+		db = db.Where("experience_level ILIKE ?", contains(f.ExperienceLevel))
+	}
+	if f.Location != "" {
+		db = db.Where("location ILIKE ?", contains(f.Location))
+	}
+	err := db.Order("id").Scan(&postings).Error
+	if err != nil {
+		return nil, err
+	}
+	return postings, nil
+}
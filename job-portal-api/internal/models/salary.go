@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// SalaryFilter narrows the postings salary insights are built from. Title, ExperienceLevel and
+// Location match anywhere in the posting, ignoring case; only jobs published since Since are counted.
+type SalaryFilter struct {
+	Title           string
+	ExperienceLevel string
+	Location        string
+	Since           time.Time
+}
+
+// SalaryPosting is the salary of a job as posted, with what insights group it by.
+type SalaryPosting struct {
+	JobID           uint
+	CompanyID       uint
+	Title           string
+	ExperienceLevel string
+	Location        string
+	SalaryMin       int
+	SalaryMax       int
+	SalaryCurrency  string
+	SalaryPeriod    string
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DecisionRole is the least role of a company member reading every scorecard without submitting one,
+// to make the hiring decision.
+const DecisionRole = RoleAdmin
+
+// Recommendations an interviewer closes a scorecard with, from worst to best.
+const (
+	RecommendStrongNo  = "strong_no"
+	RecommendNo        = "no"
+	RecommendYes       = "yes"
+	RecommendStrongYes = "strong_yes"
+)
+
+// ScorecardTemplate is what the interviewers of a job rate candidates on: every competency on a scale from 1 to Scale,
+// and free text answering Prompt.
+type ScorecardTemplate struct {
+	gorm.Model
+	JobID        uint         `json:"job_id" gorm:"uniqueIndex;not null"`
+	Competencies []Competency `json:"competencies" gorm:"serializer:json"`
+	Scale        int          `json:"scale"`
+	Prompt       string       `json:"prompt"`
+}
+
+// Competency is one skill or trait of a scorecard template.
+type Competency struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	Description string `json:"description" validate:"max=500"`
+}
+
+// NewScorecardTemplate is the payload accepted when setting the scorecard template of a job.
+type NewScorecardTemplate struct {
+	Competencies []Competency `json:"competencies" validate:"required,min=1,max=20,unique=Name,dive"`
+	Scale        int          `json:"scale" validate:"required,min=2,max=10"`
+	Prompt       string       `json:"prompt" validate:"max=1000"`
+}
+
+// Scorecard is an interviewer's feedback on an application at one stage of its pipeline.
+// Ratings name their competencies, so scorecards outlive changes to the template; Scale is the one they were given on.
+type Scorecard struct {
+	gorm.Model
+	ApplicationID  uint      `json:"application_id" gorm:"uniqueIndex:idx_scorecard_application_stage_interviewer;not null"`
+	StageID        uint      `json:"stage_id" gorm:"uniqueIndex:idx_scorecard_application_stage_interviewer"`
+	InterviewerID  uint      `json:"interviewer_id" gorm:"uniqueIndex:idx_scorecard_application_stage_interviewer;index"`
+	InterviewID    *uint     `json:"interview_id,omitempty"`
+	Ratings        []Rating  `json:"ratings" gorm:"serializer:json"`
+	Scale          int       `json:"scale"`
+	Recommendation string    `json:"recommendation"`
+	Notes          string    `json:"notes"`
+	SubmittedAt    time.Time `json:"submitted_at"`
+}
+
+// Rating is the score given to one competency.
+type Rating struct {
+	Competency string `json:"competency" validate:"required,max=100"`
+	Score      int    `json:"score" validate:"required,min=1,max=10"`
+	Comment    string `json:"comment" validate:"max=2000"`
+}
+
+// NewScorecard is the payload accepted when submitting a scorecard. Every competency of the job's template is rated once.
+type NewScorecard struct {
+	StageID        uint     `json:"stage_id" validate:"required"`
+	InterviewID    *uint    `json:"interview_id"`
+	Ratings        []Rating `json:"ratings" validate:"required,min=1,max=20,unique=Competency,dive"`
+	Recommendation string   `json:"recommendation" validate:"required,oneof=strong_no no yes strong_yes"`
+	Notes          string   `json:"notes" validate:"max=10000"`
+}
+
+// CompetencyScore is how a candidate did on one competency across scorecards.
+type CompetencyScore struct {
+	Competency string  `json:"competency"`
+	Score      float64 `json:"score"`
+	Ratings    int     `json:"ratings"`
+}
+
+// ScorecardSummary aggregates the scorecards of an application. Scores are percentages of their scale,
+// 0 for the lowest and 100 for the highest rating, so scorecards given on different scales add up.
+type ScorecardSummary struct {
+	ApplicationID   uint              `json:"application_id"`
+	CandidateID     uint              `json:"candidate_id,omitempty"`
+	Scorecards      int               `json:"scorecards"`
+	Score           float64           `json:"score"`
+	Competencies    []CompetencyScore `json:"competencies"`
+	Recommendations map[string]int    `json:"recommendations"`
+}
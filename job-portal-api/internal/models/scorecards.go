@@ -0,0 +1,262 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrNoScorecardTemplate is returned when submitting a scorecard for a job without a template.
+	ErrNoScorecardTemplate = errors.New("job has no scorecard template")
+	// ErrInvalidScorecard is returned when a scorecard does not match the template of its job.
+	ErrInvalidScorecard = errors.New("invalid scorecard")
+)
+
+// SetScorecardTemplate creates or replaces the scorecard template of the job.
+// Scorecards already submitted keep the competencies and scale they were given on.
+func (s *Conn) SetScorecardTemplate(ctx context.Context, jobId uint, nt NewScorecardTemplate) (ScorecardTemplate, error) {
+	t := ScorecardTemplate{JobID: jobId, Competencies: nt.Competencies, Scale: nt.Scale, Prompt: nt.Prompt}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Select("id").First(&Job{}, jobId).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "job_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"competencies", "scale", "prompt", "updated_at"}),
+		}).Create(&t).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("job_id = ?", jobId).First(&t).Error
+	})
+	if err != nil {
+		return ScorecardTemplate{}, err
+	}
+	return t, nil
+}
+
+// ViewScorecardTemplate returns the scorecard template of the job.
+func (s *Conn) ViewScorecardTemplate(ctx context.Context, jobId uint) (ScorecardTemplate, error) {
+	var t ScorecardTemplate
+	err := s.db.WithContext(ctx).Where("job_id = ?", jobId).First(&t).Error
+	if err != nil {
+		return ScorecardTemplate{}, err
+	}
+	return t, nil
+}
+
+// CreateScorecard submits the interviewer's scorecard for the application at a stage of its job's pipeline.
+// Interviewers submit one scorecard per stage, and cannot change it afterwards.
+func (s *Conn) CreateScorecard(ctx context.Context, applicationId, interviewerId uint, ns NewScorecard) (Scorecard, error) {
+	var sc Scorecard
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var app Application
+		err := tx.Select("id", "job_id").First(&app, applicationId).Error
+		if err != nil {
+			return err
+		}
+		var job Job
+		err = tx.Select("id", "company_id").First(&job, app.JobID).Error
+		if err != nil {
+			return err
+		}
+		var t ScorecardTemplate
+		err = tx.Where("job_id = ?", job.ID).First(&t).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNoScorecardTemplate
+		}
+		if err != nil {
+			return err
+		}
+		err = t.check(ns.Ratings)
+		if err != nil {
+			return err
+		}
+
+		stages, _, err := jobStages(tx, job)
+		if err != nil {
+			return err
+		}
+		found := false
+		for _, st := range stages {
+			found = found || st.ID == ns.StageID
+		}
+		if !found {
+			return ErrUnknownStage
+		}
+		if ns.InterviewID != nil {
+			var in Interview
+			err = tx.Select("id").Where("id = ? AND application_id = ?", *ns.InterviewID, app.ID).First(&in).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: interview %d is not one of the application's", ErrInvalidScorecard, *ns.InterviewID)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		sc = Scorecard{
+			ApplicationID:  app.ID,
+			StageID:        ns.StageID,
+			InterviewerID:  interviewerId,
+			InterviewID:    ns.InterviewID,
+			Ratings:        ns.Ratings,
+			Scale:          t.Scale,
+			Recommendation: ns.Recommendation,
+			Notes:          ns.Notes,
+			SubmittedAt:    time.Now().UTC(),
+		}
+		return tx.Create(&sc).Error
+	})
+	if err != nil {
+		return Scorecard{}, err
+	}
+	return sc, nil
+}
+
+// check makes sure the ratings score every competency of the template once, within its scale.
+func (t ScorecardTemplate) check(ratings []Rating) error {
+	competencies := make(map[string]bool, len(t.Competencies))
+	for _, c := range t.Competencies {
+		competencies[c.Name] = true
+	}
+	rated := make(map[string]bool, len(ratings))
+	for _, r := range ratings {
+		if !competencies[r.Competency] {
+			return fmt.Errorf("%w: %s is not a competency of the template", ErrInvalidScorecard, r.Competency)
+		}
+		if r.Score < 1 || r.Score > t.Scale {
+			return fmt.Errorf("%w: %s is rated %d, ratings go from 1 to %d", ErrInvalidScorecard, r.Competency, r.Score, t.Scale)
+		}
+		rated[r.Competency] = true
+	}
+	for _, c := range t.Competencies {
+		if !rated[c.Name] {
+			return fmt.Errorf("%w: %s is not rated", ErrInvalidScorecard, c.Name)
+		}
+	}
+	return nil
+}
+
+// ViewApplicationScorecards lists the scorecards submitted for the application, oldest first.
+func (s *Conn) ViewApplicationScorecards(ctx context.Context, applicationId uint) ([]Scorecard, error) {
+	var cards = make([]Scorecard, 0, 10)
+	err := s.db.WithContext(ctx).Where("application_id = ?", applicationId).Order("submitted_at, id").Find(&cards).Error
+	if err != nil {
+		return nil, err
+	}
+	return cards, nil
+}
+
+// ViewJobScorecards lists the scorecards submitted for every application to the job, oldest first.
+func (s *Conn) ViewJobScorecards(ctx context.Context, jobId uint) ([]Scorecard, error) {
+	var cards = make([]Scorecard, 0, 10)
+	err := s.db.WithContext(ctx).
+		Where("application_id IN (?)", s.db.Model(&Application{}).Select("id").Where("job_id = ?", jobId)).
+		Order("submitted_at, id").Find(&cards).Error
+	if err != nil {
+		return nil, err
+	}
+	return cards, nil
+}
+
+// VisibleScorecards keeps the scorecards an interviewer may read: their own, and those of others
+// for the stages they have submitted their own at, so feedback is given before seeing anyone else's.
+func VisibleScorecards(cards []Scorecard, interviewerId uint) []Scorecard {
+	submitted := map[[2]uint]bool{}
+	for _, sc := range cards {
+		if sc.InterviewerID == interviewerId {
+			submitted[[2]uint{sc.ApplicationID, sc.StageID}] = true
+		}
+	}
+	visible := make([]Scorecard, 0, len(cards))
+	for _, sc := range cards {
+		if submitted[[2]uint{sc.ApplicationID, sc.StageID}] {
+			visible = append(visible, sc)
+		}
+	}
+	return visible
+}
+
+// SummarizeScorecards aggregates the scorecards of one application.
+func SummarizeScorecards(applicationId uint, cards []Scorecard) ScorecardSummary {
+	sum := ScorecardSummary{
+		ApplicationID:   applicationId,
+		Scorecards:      len(cards),
+		Competencies:    []CompetencyScore{},
+		Recommendations: map[string]int{},
+	}
+	if len(cards) == 0 {
+		return sum
+	}
+	type total struct {
+		sum float64
+		n   int
+	}
+	byCompetency := map[string]*total{}
+	var order []string
+	var overall total
+	for _, sc := range cards {
+		sum.Recommendations[sc.Recommendation]++
+		for _, r := range sc.Ratings {
+			p := percent(r.Score, sc.Scale)
+			t, ok := byCompetency[r.Competency]
+			if !ok {
+				t = &total{}
+				byCompetency[r.Competency] = t
+				order = append(order, r.Competency)
+			}
+			t.sum += p
+			t.n++
+			overall.sum += p
+			overall.n++
+		}
+	}
+	for _, name := range order {
+		t := byCompetency[name]
+		sum.Competencies = append(sum.Competencies, CompetencyScore{Competency: name, Score: round1(t.sum / float64(t.n)), Ratings: t.n})
+	}
+	if overall.n > 0 {
+		sum.Score = round1(overall.sum / float64(overall.n))
+	}
+	return sum
+}
+
+// SummarizeJobScorecards aggregates the scorecards of a job per application, best score first.
+func SummarizeJobScorecards(cards []Scorecard) []ScorecardSummary {
+	byApplication := map[uint][]Scorecard{}
+	for _, sc := range cards {
+		byApplication[sc.ApplicationID] = append(byApplication[sc.ApplicationID], sc)
+	}
+	sums := make([]ScorecardSummary, 0, len(byApplication))
+	for id, cards := range byApplication {
+		sums = append(sums, SummarizeScorecards(id, cards))
+	}
+	sort.Slice(sums, func(i, j int) bool {
+		if sums[i].Score != sums[j].Score {
+			return sums[i].Score > sums[j].Score
+		}
+		return sums[i].ApplicationID < sums[j].ApplicationID
+	})
+	return sums
+}
+
+// percent places a rating on its scale, 0 for 1 and 100 for scale.
+func percent(score, scale int) float64 {
+	if scale < 2 {
+		return 0
+	}
+	return float64(score-1) / float64(scale-1) * 100
+}
+
+func round1(f float64) float64 {
+	return math.Round(f*10) / 10
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// Types of screening questions.
+const (
+	QuestionYesNo  = "yes_no"
+	QuestionChoice = "choice"
+	QuestionNumber = "number"
+	QuestionText   = "text"
+)
+
+// DefaultKnockoutMessage is told to candidates rejected by a knockout question without a message of its own.
+const DefaultKnockoutMessage = "Thank you for your interest. Unfortunately your application does not meet the requirements of this role."
+
+// ScreeningQuestion is asked to everyone applying to a job. Knockout questions reject applications
+// whose answer is not accepted: a yes/no or choice answer outside Accepted, or a number outside Min and Max.
+type ScreeningQuestion struct {
+	gorm.Model
+	JobID           uint     `json:"job_id" gorm:"index;not null"`
+	Position        int      `json:"position"`
+	Prompt          string   `json:"prompt"`
+	Type            string   `json:"type"`
+	Choices         []string `json:"choices,omitempty" gorm:"serializer:json"`
+	Required        bool     `json:"required"`
+	Knockout        bool     `json:"knockout,omitempty"`
+	Accepted        []string `json:"accepted,omitempty" gorm:"serializer:json"`
+	Min             *float64 `json:"min,omitempty"`
+	Max             *float64 `json:"max,omitempty"`
+	KnockoutMessage string   `json:"knockout_message,omitempty"`
+}
+
+// NewScreeningQuestion is one question of the payload accepted when setting the questions of a job.
+type NewScreeningQuestion struct {
+	Prompt          string   `json:"prompt" validate:"required,max=500"`
+	Type            string   `json:"type" validate:"required,oneof=yes_no choice number text"`
+	Choices         []string `json:"choices" validate:"max=20,unique,dive,required,max=200"`
+	Required        bool     `json:"required"`
+	Knockout        bool     `json:"knockout"`
+	Accepted        []string `json:"accepted" validate:"max=20,dive,required,max=200"`
+	Min             *float64 `json:"min"`
+	Max             *float64 `json:"max"`
+	KnockoutMessage string   `json:"knockout_message" validate:"max=1000"`
+}
+
+// ScreeningUpdate is the payload accepted when setting the questions of a job, in the order they are asked.
+// An empty list removes them.
+type ScreeningUpdate struct {
+	Questions []NewScreeningQuestion `json:"questions" validate:"max=30,dive"`
+}
+
+// ApplicationAnswer is a candidate's answer to a screening question, normalized: yes or no, the choice
+// as the job spells it, or the number, which is also kept in Number for filtering.
+type ApplicationAnswer struct {
+	ID            uint     `json:"id" gorm:"primarykey"`
+	ApplicationID uint     `json:"application_id" gorm:"uniqueIndex:idx_answer_application_question;not null"`
+	QuestionID    uint     `json:"question_id" gorm:"uniqueIndex:idx_answer_application_question;index"`
+	Value         string   `json:"value"`
+	Number        *float64 `json:"number,omitempty"`
+}
+
+// NewAnswer is an answer in the payload accepted when applying.
+type NewAnswer struct {
+	QuestionID uint   `json:"question_id" validate:"required"`
+	Value      string `json:"value" validate:"max=5000"`
+}
+
+// ApplicationFilter narrows down the applications of a job. Answers match answers exactly, ignoring case;
+// AnswersMin and AnswersMax bound the answers to number questions.
+type ApplicationFilter struct {
+	Status     string
+	Answers    map[uint]string
+	AnswersMin map[uint]float64
+	AnswersMax map[uint]float64
+}
@@ -0,0 +1,235 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidQuestion is returned when a screening question being saved does not hold together.
+	ErrInvalidQuestion = errors.New("invalid screening question")
+	// ErrInvalidAnswers is returned when the answers of an application do not fit the job's screening questions.
+	ErrInvalidAnswers = errors.New("invalid answers")
+)
+
+// SetScreeningQuestions replaces the screening questions of the job.
+// Answers already given keep pointing at the questions they answered.
+func (s *Conn) SetScreeningQuestions(ctx context.Context, jobId uint, su ScreeningUpdate) ([]ScreeningQuestion, error) {
+	qs := make([]ScreeningQuestion, 0, len(su.Questions))
+	for i, nq := range su.Questions {
+		q, err := nq.question()
+		if err != nil {
+			return nil, fmt.Errorf("question %d: %w", i+1, err)
+		}
+		q.JobID, q.Position = jobId, i
+		qs = append(qs, q)
+	}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Select("id").First(&Job{}, jobId).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Where("job_id = ?", jobId).Delete(&ScreeningQuestion{}).Error
+		if err != nil || len(qs) == 0 {
+			return err
+		}
+		return tx.Create(&qs).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return qs, nil
+}
+
+// ViewScreeningQuestions lists the screening questions of the job in the order they are asked.
+func (s *Conn) ViewScreeningQuestions(ctx context.Context, jobId uint) ([]ScreeningQuestion, error) {
+	var qs = make([]ScreeningQuestion, 0, 10)
+	err := s.db.WithContext(ctx).Where("job_id = ?", jobId).Order("position").Find(&qs).Error
+	if err != nil {
+		return nil, err
+	}
+	return qs, nil
+}
+
+// ForCandidates strips the questions of what it takes to pass them.
+func ForCandidates(qs []ScreeningQuestion) []ScreeningQuestion {
+	out := make([]ScreeningQuestion, 0, len(qs))
+	for _, q := range qs {
+		q.Knockout, q.Accepted, q.Min, q.Max, q.KnockoutMessage = false, nil, nil, nil, ""
+		out = append(out, q)
+	}
+	return out
+}
+
+// question checks that the parts of the question fit its type.
+func (nq NewScreeningQuestion) question() (ScreeningQuestion, error) {
+	q := ScreeningQuestion{
+		Prompt:          strings.TrimSpace(nq.Prompt),
+		Type:            nq.Type,
+		Choices:         nq.Choices,
+		Required:        nq.Required || nq.Knockout,
+		Knockout:        nq.Knockout,
+		Accepted:        nq.Accepted,
+		Min:             nq.Min,
+		Max:             nq.Max,
+		KnockoutMessage: strings.TrimSpace(nq.KnockoutMessage),
+	}
+	if q.Type == QuestionChoice && len(q.Choices) < 2 {
+		return ScreeningQuestion{}, fmt.Errorf("%w: choice questions need at least 2 choices", ErrInvalidQuestion)
+	}
+	if q.Type != QuestionChoice && len(q.Choices) > 0 {
+		return ScreeningQuestion{}, fmt.Errorf("%w: only choice questions have choices", ErrInvalidQuestion)
+	}
+	if q.Min != nil && q.Max != nil && *q.Min > *q.Max {
+		return ScreeningQuestion{}, fmt.Errorf("%w: min is above max", ErrInvalidQuestion)
+	}
+	if !q.Knockout {
+		if len(q.Accepted) > 0 || q.Min != nil || q.Max != nil || q.KnockoutMessage != "" {
+			return ScreeningQuestion{}, fmt.Errorf("%w: only knockout questions have accepted answers or a knockout message", ErrInvalidQuestion)
+		}
+		return q, nil
+	}
+
+	switch q.Type {
+	case QuestionText:
+		return ScreeningQuestion{}, fmt.Errorf("%w: text questions cannot be knockout questions", ErrInvalidQuestion)
+	case QuestionNumber:
+		if q.Min == nil && q.Max == nil {
+			return ScreeningQuestion{}, fmt.Errorf("%w: knockout number questions need a min or max", ErrInvalidQuestion)
+		}
+		if len(q.Accepted) > 0 {
+			return ScreeningQuestion{}, fmt.Errorf("%w: number questions accept a range, not answers", ErrInvalidQuestion)
+		}
+		return q, nil
+	}
+	if q.Min != nil || q.Max != nil {
+		return ScreeningQuestion{}, fmt.Errorf("%w: only number questions have a min or max", ErrInvalidQuestion)
+	}
+	if len(q.Accepted) == 0 {
+		return ScreeningQuestion{}, fmt.Errorf("%w: knockout questions need accepted answers", ErrInvalidQuestion)
+	}
+	for i, a := range q.Accepted {
+		v, ok := q.normalize(a)
+		if !ok {
+			return ScreeningQuestion{}, fmt.Errorf("%w: %q is not an answer to the question", ErrInvalidQuestion, a)
+		}
+		q.Accepted[i] = v
+	}
+	return q, nil
+}
+
+// normalize spells a yes/no or choice answer the way the question does, reporting whether it is one.
+func (q ScreeningQuestion) normalize(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	switch q.Type {
+	case QuestionYesNo:
+		switch strings.ToLower(value) {
+		case "yes", "true":
+			return "yes", true
+		case "no", "false":
+			return "no", true
+		}
+		return "", false
+	case QuestionChoice:
+		for _, c := range q.Choices {
+			if strings.EqualFold(c, value) {
+				return c, true
+			}
+		}
+		return "", false
+	}
+	return value, true
+}
+
+// answer checks the value answers the question, and whether a knockout question accepts it.
+func (q ScreeningQuestion) answer(value string) (ApplicationAnswer, bool, error) {
+	a := ApplicationAnswer{QuestionID: q.ID}
+	switch q.Type {
+	case QuestionNumber:
+		n, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return ApplicationAnswer{}, false, fmt.Errorf("%w: %q needs a number", ErrInvalidAnswers, q.Prompt)
+		}
+		a.Value, a.Number = strconv.FormatFloat(n, 'f', -1, 64), &n
+		passed := (q.Min == nil || n >= *q.Min) && (q.Max == nil || n <= *q.Max)
+		return a, !q.Knockout || passed, nil
+	case QuestionText:
+		a.Value = strings.TrimSpace(value)
+		return a, true, nil
+	}
+	v, ok := q.normalize(value)
+	if !ok {
+		want := "yes or no"
+		if q.Type == QuestionChoice {
+			want = "one of " + strings.Join(q.Choices, ", ")
+		}
+		return ApplicationAnswer{}, false, fmt.Errorf("%w: %q needs %s", ErrInvalidAnswers, q.Prompt, want)
+	}
+	a.Value = v
+	return a, !q.Knockout || slices.Contains(q.Accepted, v), nil
+}
+
+// screen checks the answers of an application against the screening questions of its job.
+// It returns the answers to keep and the message of the first knockout question the application failed, if any.
+func screen(qs []ScreeningQuestion, answers []NewAnswer) ([]ApplicationAnswer, string, error) {
+	asked := make(map[uint]bool, len(qs))
+	for _, q := range qs {
+		asked[q.ID] = true
+	}
+	given := make(map[uint]string, len(answers))
+	for _, a := range answers {
+		if !asked[a.QuestionID] {
+			return nil, "", fmt.Errorf("%w: question %d is not asked for this job", ErrInvalidAnswers, a.QuestionID)
+		}
+		given[a.QuestionID] = a.Value
+	}
+	kept := make([]ApplicationAnswer, 0, len(qs))
+	knockout := ""
+	for _, q := range qs {
+		value, ok := given[q.ID]
+		if !ok || strings.TrimSpace(value) == "" {
+			if q.Required {
+				return nil, "", fmt.Errorf("%w: %q needs an answer", ErrInvalidAnswers, q.Prompt)
+			}
+			continue
+		}
+		a, passed, err := q.answer(value)
+		if err != nil {
+			return nil, "", err
+		}
+		if !passed && knockout == "" {
+			knockout = q.KnockoutMessage
+			if knockout == "" {
+				knockout = DefaultKnockoutMessage
+			}
+		}
+		kept = append(kept, a)
+	}
+	return kept, knockout, nil
+}
+
+// filterApplications narrows a query on applications down to those matching the filter.
+func filterApplications(f ApplicationFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if f.Status != "" {
+			db = db.Where("applications.status = ?", f.Status)
+		}
+		const answered = "EXISTS (SELECT 1 FROM application_answers a WHERE a.application_id = applications.id AND a.question_id = ? AND "
+		for id, v := range f.Answers {
+			db = db.Where(answered+"lower(a.value) = lower(?))", id, strings.TrimSpace(v))
+		}
+		for id, n := range f.AnswersMin {
+			db = db.Where(answered+"a.number >= ?)", id, n)
+		}
+		for id, n := range f.AnswersMax {
+			db = db.Where(answered+"a.number <= ?)", id, n)
+		}
+		return db
+	}
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Digest frequencies of a saved search.
+const (
+	FrequencyDaily  = "daily"
+	FrequencyWeekly = "weekly"
+)
+
+// SavedSearch is a job search a candidate wants to be alerted about.
+// Jobs created after LastRunAt that match the filters are mailed in a digest once NextRunAt is reached.
+// SalaryMin is yearly; jobs paid by the hour or month are compared on a yearly basis.
+type SavedSearch struct {
+	gorm.Model
+	UserID           uint      `json:"user_id" gorm:"index;not null"`
+	Name             string    `json:"name"`
+	Keywords         string    `json:"keywords"`
+	Location         string    `json:"location"`
+	Skills           []string  `json:"skills" gorm:"serializer:json"`
+	ExperienceLevel  string    `json:"experience_level"`
+	SalaryMin        int       `json:"salary_min"`
+	Frequency        string    `json:"frequency"`
+	Active           bool      `json:"active"`
+	LastRunAt        time.Time `json:"last_run_at"`
+	NextRunAt        time.Time `json:"next_run_at" gorm:"index"`
+	UnsubscribeToken string    `json:"-" gorm:"uniqueIndex;not null"`
+}
+
+// NewSavedSearch is the payload accepted when a candidate saves or edits a search.
+type NewSavedSearch struct {
+	Name            string   `json:"name" validate:"required,max=100"`
+	Keywords        string   `json:"keywords" validate:"max=200"`
+	Location        string   `json:"location" validate:"max=100"`
+	Skills          []string `json:"skills" validate:"max=20,dive,required,max=50"`
+	ExperienceLevel string   `json:"experience_level" validate:"max=50"`
+	SalaryMin       int      `json:"salary_min" validate:"gte=0"`
+	Frequency       string   `json:"frequency" validate:"required,oneof=daily weekly"`
+}
+
+// SentAlert remembers which jobs a user was already mailed about,
+// so a job matching several of their searches, or several runs, is only sent once.
+type SentAlert struct {
+	ID     uint `gorm:"primarykey"`
+	UserID uint `gorm:"uniqueIndex:idx_sent_alert_user_job"`
+	JobID  uint `gorm:"uniqueIndex:idx_sent_alert_user_job"`
+	SentAt time.Time
+}
+
+// Period is how long a digest of the given frequency waits between runs.
+func Period(frequency string) time.Duration {
+	if frequency == FrequencyWeekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
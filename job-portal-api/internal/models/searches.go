@@ -0,0 +1,169 @@
+package models
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateSavedSearch saves a search for the user. Only jobs created from now on are alerted.
+func (s *Conn) CreateSavedSearch(ctx context.Context, ns NewSavedSearch, userId uint) (SavedSearch, error) {
+	token, err := secretToken()
+	if err != nil {
+		return SavedSearch{}, err
+	}
+	now := time.Now().UTC()
+	ss := SavedSearch{
+		UserID:           userId,
+		Active:           true,
+		LastRunAt:        now,
+		NextRunAt:        now.Add(Period(ns.Frequency)),
+		UnsubscribeToken: token,
+	}
+	applySearch(&ss, ns)
+	err = s.db.WithContext(ctx).Create(&ss).Error
+	if err != nil {
+		return SavedSearch{}, err
+	}
+	return ss, nil
+}
+
+// ViewSavedSearches lists the searches saved by the user.
+func (s *Conn) ViewSavedSearches(ctx context.Context, userId uint) ([]SavedSearch, error) {
+	var searches = make([]SavedSearch, 0, 10)
+	err := s.db.WithContext(ctx).Where("user_id = ?", userId).Order("id").Find(&searches).Error
+	if err != nil {
+		return nil, err
+	}
+	return searches, nil
+}
+
+// UpdateSavedSearch replaces the filters of a search owned by the user and turns its alerts back on.
+func (s *Conn) UpdateSavedSearch(ctx context.Context, ns NewSavedSearch, searchId uint, userId uint) (SavedSearch, error) {
+	var ss SavedSearch
+	err := s.db.WithContext(ctx).Where("id = ? AND user_id = ?", searchId, userId).First(&ss).Error
+	if err != nil {
+		return SavedSearch{}, err
+	}
+	applySearch(&ss, ns)
+	ss.Active = true
+	ss.NextRunAt = ss.LastRunAt.Add(Period(ss.Frequency))
+	err = s.db.WithContext(ctx).Save(&ss).Error
+	if err != nil {
+		return SavedSearch{}, err
+	}
+	return ss, nil
+}
+
+// DeleteSavedSearch removes a search owned by the user.
+func (s *Conn) DeleteSavedSearch(ctx context.Context, searchId uint, userId uint) error {
+	tx := s.db.WithContext(ctx).Where("id = ? AND user_id = ?", searchId, userId).Delete(&SavedSearch{})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// UnsubscribeSavedSearch stops the alerts of the search the token was issued for.
+func (s *Conn) UnsubscribeSavedSearch(ctx context.Context, token string) (SavedSearch, error) {
+	var ss SavedSearch
+	err := s.db.WithContext(ctx).Where("unsubscribe_token = ?", token).First(&ss).Error
+	if err != nil {
+		return SavedSearch{}, err
+	}
+	ss.Active = false
+	err = s.db.WithContext(ctx).Model(&ss).Update("active", false).Error
+	if err != nil {
+		return SavedSearch{}, err
+	}
+	return ss, nil
+}
+
+// DueSavedSearches returns active searches whose next digest is due, grouped by user.
+func (s *Conn) DueSavedSearches(ctx context.Context, now time.Time, limit int) ([]SavedSearch, error) {
+	var searches []SavedSearch
+	err := s.db.WithContext(ctx).Where("active AND next_run_at <= ?", now).
+		Order("user_id, id").Limit(limit).Find(&searches).Error
+	if err != nil {
+		return nil, err
+	}
+	return searches, nil
+}
+
+// JobsPublishedBetween returns the listed jobs first published after from and no later than to.
+func (s *Conn) JobsPublishedBetween(ctx context.Context, from, to time.Time) ([]Job, error) {
+	var jobs []Job
+	err := s.db.WithContext(ctx).Scopes(listed).Where("published_at > ? AND published_at <= ?", from, to).
+		Order("published_at").Find(&jobs).Error
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// SentJobIDs returns which of the given jobs the user was already alerted about.
+func (s *Conn) SentJobIDs(ctx context.Context, userId uint, jobIds []uint) ([]uint, error) {
+	var ids []uint
+	if len(jobIds) == 0 {
+		return ids, nil
+	}
+	err := s.db.WithContext(ctx).Model(&SentAlert{}).
+		Where("user_id = ? AND job_id IN ?", userId, jobIds).Pluck("job_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// RecordDigest marks the jobs as sent to the user and moves the searches on to their next run.
+func (s *Conn) RecordDigest(ctx context.Context, userId uint, jobIds []uint, searches []SavedSearch, runAt time.Time) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(jobIds) > 0 {
+			sent := make([]SentAlert, 0, len(jobIds))
+			for _, id := range jobIds {
+				sent = append(sent, SentAlert{UserID: userId, JobID: id, SentAt: runAt})
+			}
+			err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&sent).Error
+			if err != nil {
+				return err
+			}
+		}
+		for _, ss := range searches {
+			err := tx.Model(&SavedSearch{}).Where("id = ?", ss.ID).Updates(map[string]any{
+				"last_run_at": runAt,
+				"next_run_at": runAt.Add(Period(ss.Frequency)),
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func applySearch(ss *SavedSearch, ns NewSavedSearch) {
+	ss.Name = ns.Name
+	ss.Keywords = ns.Keywords
+	ss.Location = ns.Location
+	ss.Skills = NormalizeSkills(ns.Skills)
+	ss.ExperienceLevel = ns.ExperienceLevel
+	ss.SalaryMin = ns.SalaryMin
+	ss.Frequency = ns.Frequency
+}
+
+// secretToken is the secret put in links that work without logging in, like unsubscribe links and calendar feeds.
+func secretToken() (string, error) {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
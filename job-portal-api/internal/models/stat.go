@@ -0,0 +1,75 @@
+package models
+
+import "time"
+
+// Kinds of job activity counted by the analytics.
+const (
+	ActivityView       = "view"
+	ActivityApplyStart = "apply_start"
+	ActivityApply      = "apply"
+	// ActivityStage is an application leaving a stage of its pipeline
+	ActivityStage = "stage"
+	ActivityHire  = "hire"
+)
+
+// Intervals analytics series are bucketed by.
+const (
+	StatIntervalDay  = "day"
+	StatIntervalWeek = "week"
+)
+
+// JobActivity is something that happened to a job its analytics count, recorded as it happens.
+// Activities are rolled up per job and day into JobDailyStat; durations are kept to compute medians.
+type JobActivity struct {
+	ID            uint      `json:"id" gorm:"primarykey"`
+	JobID         uint      `json:"job_id" gorm:"index:idx_job_activity_job,priority:1;not null"`
+	CompanyID     uint      `json:"company_id" gorm:"index:idx_job_activity_company,priority:1;not null"`
+	Kind          string    `json:"kind" gorm:"not null"`
+	Day           time.Time `json:"day" gorm:"type:date;index:idx_job_activity_job,priority:2;index:idx_job_activity_company,priority:2;index"`
+	OccurredAt    time.Time `json:"occurred_at"`
+	ApplicationID *uint     `json:"application_id,omitempty"`
+	// StageID is the stage left by stage activities
+	StageID *uint `json:"stage_id,omitempty"`
+	// Seconds is how long the application spent in the stage it left, or took from applying to being hired
+	Seconds int64 `json:"seconds,omitempty"`
+	// DedupKey makes views and application starts count once per visitor and day, other kinds leave it empty
+	DedupKey *string `json:"-" gorm:"uniqueIndex"`
+}
+
+// StatCounts are the activities counted over a period.
+type StatCounts struct {
+	Views        int64 `json:"views"`
+	ApplyStarts  int64 `json:"apply_starts"`
+	Applications int64 `json:"applications"`
+	StageMoves   int64 `json:"stage_moves"`
+	Hires        int64 `json:"hires"`
+}
+
+// JobDailyStat is the rollup of the activities of a job over a day.
+type JobDailyStat struct {
+	JobID      uint      `json:"job_id" gorm:"primaryKey;autoIncrement:false"`
+	Day        time.Time `json:"day" gorm:"primaryKey;type:date"`
+	CompanyID  uint      `json:"company_id" gorm:"index;not null"`
+	StatCounts `gorm:"embedded"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// StatPoint is the activity of one bucket of a series, starting on Period.
+type StatPoint struct {
+	Period time.Time `json:"period"`
+	StatCounts
+}
+
+// StatScope picks the jobs analytics cover: every job of the company, or only JobID when set.
+type StatScope struct {
+	CompanyID uint
+	JobID     uint
+}
+
+// StageTime is how long applications stayed in a stage before moving on.
+type StageTime struct {
+	StageID     uint    `json:"stage_id"`
+	Name        string  `json:"name"`
+	MedianHours float64 `json:"median_hours"`
+	Samples     int64   `json:"samples"`
+}
@@ -0,0 +1,172 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// statDay is the UTC day an activity at t is counted on.
+func statDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+// recordActivity adds an activity, inside the caller's transaction when tx is one.
+// Activities with a dedup key already recorded are dropped.
+func recordActivity(tx *gorm.DB, a JobActivity) error {
+	if a.OccurredAt.IsZero() {
+		a.OccurredAt = time.Now().UTC()
+	}
+	a.Day = statDay(a.OccurredAt)
+	return tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "dedup_key"}}, DoNothing: true}).Create(&a).Error
+}
+
+// recordVisit counts a visitor doing something on a job once per day, however often they do it.
+func (s *Conn) recordVisit(ctx context.Context, jobId, companyId uint, kind, visitor string) error {
+	now := time.Now().UTC()
+	key := fmt.Sprintf("%s:%d:%s:%s", kind, jobId, statDay(now).Format(time.DateOnly), visitor)
+	return recordActivity(s.db.WithContext(ctx), JobActivity{
+		JobID: jobId, CompanyID: companyId, Kind: kind, OccurredAt: now, DedupKey: &key,
+	})
+}
+
+// RecordJobView counts the visitor seeing the job's details. Visitors are told apart by the caller,
+// by user or session, and count once per job and day.
+func (s *Conn) RecordJobView(ctx context.Context, jobId, companyId uint, visitor string) error {
+	return s.recordVisit(ctx, jobId, companyId, ActivityView, visitor)
+}
+
+// RecordApplyStart counts the visitor starting an application to the job, once per job and day.
+func (s *Conn) RecordApplyStart(ctx context.Context, jobId, companyId uint, visitor string) error {
+	return s.recordVisit(ctx, jobId, companyId, ActivityApplyStart, visitor)
+}
+
+// RollupJobStats recomputes the daily stats of every job from the activities of the days since the given one,
+// and returns how many job days it wrote. Rolling up a day again replaces its counts.
+func (s *Conn) RollupJobStats(ctx context.Context, since time.Time) (int64, error) {
+	return rollupJobStats(s.db.WithContext(ctx), since)
+}
+
+func rollupJobStats(db *gorm.DB, since time.Time) (int64, error) {
+	res := db.Exec(`INSERT INTO job_daily_stats
+			(job_id, day, company_id, views, apply_starts, applications, stage_moves, hires, updated_at)
+		SELECT job_id, day, company_id,
+			COUNT(*) FILTER (WHERE kind = ?), COUNT(*) FILTER (WHERE kind = ?), COUNT(*) FILTER (WHERE kind = ?),
+			COUNT(*) FILTER (WHERE kind = ?), COUNT(*) FILTER (WHERE kind = ?), ?
+		FROM job_activities WHERE day >= ? GROUP BY job_id, day, company_id
+		ON CONFLICT (job_id, day) DO UPDATE SET company_id = EXCLUDED.company_id, views = EXCLUDED.views,
+			apply_starts = EXCLUDED.apply_starts, applications = EXCLUDED.applications,
+			stage_moves = EXCLUDED.stage_moves, hires = EXCLUDED.hires, updated_at = EXCLUDED.updated_at`,
+		ActivityView, ActivityApplyStart, ActivityApply, ActivityStage, ActivityHire, time.Now().UTC(), statDay(since))
+	return res.RowsAffected, res.Error
+}
+
+// statScope restricts a query to the jobs of the scope, on the given table.
+func statScope(table string, scope StatScope) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where(table+".company_id = ?", scope.CompanyID)
+		if scope.JobID != 0 {
+			db = db.Where(table+".job_id = ?", scope.JobID)
+		}
+		return db
+	}
+}
+
+// JobStatSeries sums the daily stats of the scope from the day of from to the day before to, by day or by week
+// (weeks start on Monday). Buckets without any activity are left out.
+func (s *Conn) JobStatSeries(ctx context.Context, scope StatScope, from, to time.Time, interval string) ([]StatPoint, error) {
+	if interval != StatIntervalDay && interval != StatIntervalWeek {
+		return nil, fmt.Errorf("unknown interval %q", interval)
+	}
+	var points = make([]StatPoint, 0, 31)
+	err := s.db.WithContext(ctx).Table("job_daily_stats").Scopes(statScope("job_daily_stats", scope)).
+		Select(`date_trunc(?, day)::date AS period, SUM(views) AS views, SUM(apply_starts) AS apply_starts,
+			SUM(applications) AS applications, SUM(stage_moves) AS stage_moves, SUM(hires) AS hires`, interval).
+		Where("day >= ? AND day < ?", statDay(from), statDay(to)).
+		Group("period").Order("period").Scan(&points).Error
+	if err != nil {
+		return nil, err
+	}
+	for i := range points {
+		points[i].Period = points[i].Period.UTC()
+	}
+	return points, nil
+}
+
+// MedianTimeToHire returns the median hours from applying to being hired of the hires of the scope
+// in the period. It is nil without any hire.
+func (s *Conn) MedianTimeToHire(ctx context.Context, scope StatScope, from, to time.Time) (*float64, error) {
+	var row struct {
+		Median *float64
+	}
+	err := s.db.WithContext(ctx).Model(&JobActivity{}).Scopes(statScope("job_activities", scope)).
+		Select("percentile_cont(0.5) WITHIN GROUP (ORDER BY seconds) / 3600 AS median").
+		Where("kind = ? AND occurred_at >= ? AND occurred_at < ?", ActivityHire, from, to).
+		Scan(&row).Error
+	if err != nil {
+		return nil, err
+	}
+	return row.Median, nil
+}
+
+// MedianTimeInStage returns, for every stage applications of the scope left in the period,
+// the median hours they spent in it, in pipeline order.
+func (s *Conn) MedianTimeInStage(ctx context.Context, scope StatScope, from, to time.Time) ([]StageTime, error) {
+	var times = make([]StageTime, 0, len(DefaultPipeline))
+	err := s.db.WithContext(ctx).Model(&JobActivity{}).Scopes(statScope("job_activities", scope)).
+		Select(`job_activities.stage_id, COALESCE(pipeline_stages.name, '') AS name,
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY job_activities.seconds) / 3600 AS median_hours,
+			COUNT(*) AS samples`).
+		Joins("LEFT JOIN pipeline_stages ON pipeline_stages.id = job_activities.stage_id").
+		Where("job_activities.kind = ? AND job_activities.stage_id IS NOT NULL", ActivityStage).
+		Where("job_activities.occurred_at >= ? AND job_activities.occurred_at < ?", from, to).
+		Group("job_activities.stage_id, pipeline_stages.name, pipeline_stages.position").
+		Order("pipeline_stages.position, job_activities.stage_id").Scan(&times).Error
+	if err != nil {
+		return nil, err
+	}
+	return times, nil
+}
+
+// backfillJobActivity records the activity of applications submitted before analytics existed: applying,
+// moving between stages and being hired. Views and application starts were never tracked and stay at zero.
+// It only runs while no activity was recorded.
+func backfillJobActivity(db *gorm.DB) error {
+	var n int64
+	err := db.Model(&JobActivity{}).Limit(1).Count(&n).Error
+	if err != nil || n > 0 {
+		return err
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`INSERT INTO job_activities (job_id, company_id, kind, day, occurred_at, application_id)
+			SELECT a.job_id, j.company_id, ?, (a.created_at AT TIME ZONE 'UTC')::date, a.created_at, a.id
+			FROM applications a JOIN jobs j ON j.id = a.job_id WHERE a.deleted_at IS NULL`, ActivityApply).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Exec(`INSERT INTO job_activities (job_id, company_id, kind, day, occurred_at, application_id, stage_id, seconds)
+			SELECT a.job_id, j.company_id, ?, (c.created_at AT TIME ZONE 'UTC')::date, c.created_at, a.id, c.from_stage_id,
+				EXTRACT(EPOCH FROM c.created_at - c.entered_at)::bigint
+			FROM (SELECT *, LAG(created_at) OVER (PARTITION BY application_id ORDER BY id) AS entered_at
+				FROM application_stage_changes) c
+			JOIN applications a ON a.id = c.application_id JOIN jobs j ON j.id = a.job_id
+			WHERE c.from_stage_id IS NOT NULL AND c.entered_at IS NOT NULL AND a.deleted_at IS NULL`, ActivityStage).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Exec(`INSERT INTO job_activities (job_id, company_id, kind, day, occurred_at, application_id, seconds)
+			SELECT a.job_id, j.company_id, ?, (o.responded_at AT TIME ZONE 'UTC')::date, o.responded_at, a.id,
+				EXTRACT(EPOCH FROM o.responded_at - a.created_at)::bigint
+			FROM offers o JOIN applications a ON a.id = o.application_id JOIN jobs j ON j.id = a.job_id
+			WHERE o.status = ? AND o.responded_at IS NOT NULL AND o.deleted_at IS NULL AND a.deleted_at IS NULL`,
+			ActivityHire, OfferAccepted).Error
+		if err != nil {
+			return err
+		}
+		_, err = rollupJobStats(tx, time.Time{})
+		return err
+	})
+}
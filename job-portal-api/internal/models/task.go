@@ -0,0 +1,34 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Task states in the background queue.
+const (
+	TaskPending = "pending"
+	TaskRunning = "running"
+	TaskDone    = "done"
+	// TaskDead tasks ran out of attempts; they stay around until requeued by hand.
+	TaskDead = "dead"
+)
+
+// Task is a unit of background work kept in Postgres until a worker has finished it.
+type Task struct {
+	ID          uint            `json:"id" gorm:"primarykey"`
+	Kind        string          `json:"kind" gorm:"not null;index"`
+	Payload     json.RawMessage `json:"payload"`
+	Status      string          `json:"status" gorm:"not null;index:idx_task_claim,priority:1"`
+	RunAt       time.Time       `json:"run_at" gorm:"index:idx_task_claim,priority:2"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	// UniqueKey makes enqueueing idempotent: a second task with the same key is dropped.
+	UniqueKey  *string    `json:"unique_key,omitempty" gorm:"uniqueIndex"`
+	LockedBy   string     `json:"locked_by,omitempty"`
+	LockedAt   *time.Time `json:"locked_at,omitempty" gorm:"index"`
+	LastError  string     `json:"last_error,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
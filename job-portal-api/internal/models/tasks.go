@@ -0,0 +1,140 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EnqueueTask stores a new task. A task whose unique key is already taken is silently dropped.
+func (s *Conn) EnqueueTask(ctx context.Context, t Task) (Task, error) {
+	err := s.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&t).Error
+	if err != nil {
+		return Task{}, err
+	}
+	return t, nil
+}
+
+// ClaimTasks locks up to limit due tasks of the given kinds for a worker.
+// SKIP LOCKED lets several processes claim concurrently without handing out a task twice.
+func (s *Conn) ClaimTasks(ctx context.Context, kinds []string, worker string, limit int, now time.Time) ([]Task, error) {
+	var tasks []Task
+	if len(kinds) == 0 || limit <= 0 {
+		return tasks, nil
+	}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND run_at <= ? AND kind IN ?", TaskPending, now, kinds).
+			Order("run_at").Limit(limit).Find(&tasks).Error
+		if err != nil || len(tasks) == 0 {
+			return err
+		}
+		ids := make([]uint, 0, len(tasks))
+		for _, t := range tasks {
+			ids = append(ids, t.ID)
+		}
+		err = tx.Model(&Task{}).Where("id IN ?", ids).Updates(map[string]any{
+			"status":    TaskRunning,
+			"locked_by": worker,
+			"locked_at": now,
+			"attempts":  gorm.Expr("attempts + 1"),
+		}).Error
+		if err != nil {
+			return err
+		}
+		for i := range tasks {
+			tasks[i].Status = TaskRunning
+			tasks[i].LockedBy = worker
+			tasks[i].LockedAt = &now
+			tasks[i].Attempts++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// FinishTask marks a task as done.
+func (s *Conn) FinishTask(ctx context.Context, taskId uint) error {
+	return s.db.WithContext(ctx).Model(&Task{}).Where("id = ?", taskId).Updates(map[string]any{
+		"status":      TaskDone,
+		"finished_at": time.Now().UTC(),
+		"locked_by":   "",
+		"locked_at":   nil,
+	}).Error
+}
+
+// RetryTask puts a failed task back in the queue, to be run again at runAt.
+func (s *Conn) RetryTask(ctx context.Context, taskId uint, runAt time.Time, lastErr string) error {
+	return s.db.WithContext(ctx).Model(&Task{}).Where("id = ?", taskId).Updates(map[string]any{
+		"status":     TaskPending,
+		"run_at":     runAt,
+		"last_error": lastErr,
+		"locked_by":  "",
+		"locked_at":  nil,
+	}).Error
+}
+
+// BuryTask moves a task that keeps failing to the dead letter state.
+func (s *Conn) BuryTask(ctx context.Context, taskId uint, lastErr string) error {
+	return s.db.WithContext(ctx).Model(&Task{}).Where("id = ?", taskId).Updates(map[string]any{
+		"status":      TaskDead,
+		"last_error":  lastErr,
+		"finished_at": time.Now().UTC(),
+		"locked_by":   "",
+		"locked_at":   nil,
+	}).Error
+}
+
+// RequeueStaleTasks releases tasks locked before the given time, left behind by a worker that died.
+func (s *Conn) RequeueStaleTasks(ctx context.Context, lockedBefore time.Time) (int64, error) {
+	tx := s.db.WithContext(ctx).Model(&Task{}).
+		Where("status = ? AND locked_at < ?", TaskRunning, lockedBefore).
+		Updates(map[string]any{
+			"status":     TaskPending,
+			"run_at":     time.Now().UTC(),
+			"last_error": "worker lost",
+			"locked_by":  "",
+			"locked_at":  nil,
+		})
+	return tx.RowsAffected, tx.Error
+}
+
+// PurgeFinishedTasks deletes the done tasks finished before the given time. Dead tasks are kept.
+func (s *Conn) PurgeFinishedTasks(ctx context.Context, before time.Time) (int64, error) {
+	tx := s.db.WithContext(ctx).Where("status = ? AND finished_at < ?", TaskDone, before).Delete(&Task{})
+	return tx.RowsAffected, tx.Error
+}
+
+// ViewDeadTasks lists the tasks that ran out of attempts, most recent first.
+func (s *Conn) ViewDeadTasks(ctx context.Context, limit int) ([]Task, error) {
+	var tasks []Task
+	err := s.db.WithContext(ctx).Where("status = ?", TaskDead).
+		Order("finished_at desc").Limit(limit).Find(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// RequeueDeadTask gives a dead task a fresh set of attempts.
+func (s *Conn) RequeueDeadTask(ctx context.Context, taskId uint) error {
+	tx := s.db.WithContext(ctx).Model(&Task{}).Where("id = ? AND status = ?", taskId, TaskDead).
+		Updates(map[string]any{
+			"status":      TaskPending,
+			"attempts":    0,
+			"run_at":      time.Now().UTC(),
+			"finished_at": nil,
+		})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
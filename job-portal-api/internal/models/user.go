@@ -9,6 +9,8 @@ type User struct {
 	Name         string `json:"name"`
 	Email        string `json:"email"`
 	PasswordHash string `json:"-"`
+	// Admin users can read the audit log; the flag is only set in the database
+	Admin bool `json:"admin,omitempty"`
 }
 
 type NewUser struct {
@@ -16,4 +18,3 @@ type NewUser struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required"`
 }
-
@@ -26,6 +26,12 @@ func NewService(db *gorm.DB) (*Conn, error) {
 		return nil, errors.New("please provide a valid connection")
 	}
 
+	// Every write through db ends up in the audit log
+	err := registerAudit(db)
+	if err != nil {
+		return nil, err
+	}
+
 	// We initialize our service with the passed database instance.
 	s := &Conn{db: db}
 	return s, nil
@@ -47,8 +53,14 @@ func (s *Conn) CreateUser(ctx context.Context, nu NewUser) (User, error) {
 		PasswordHash: string(hashedPass),
 	}
 
-	// We attempt to create the new User record in the database.
-	err = s.db.Create(&u).Error
+	// We attempt to create the new User record in the database, announcing it in the same transaction.
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(&u).Error
+		if err != nil {
+			return err
+		}
+		return recordEvent(tx, AggregateUser, u.ID, EventUserRegistered, u)
+	})
 	if err != nil {
 		return User{}, err
 	}
@@ -87,3 +99,13 @@ func (s *Conn) Authenticate(ctx context.Context, email, password string) (jwt.Re
 	// And return those claims.
 	return c, nil
 }
+
+// ViewUser fetches a user by id.
+func (s *Conn) ViewUser(ctx context.Context, userId uint) (User, error) {
+	var u User
+	err := s.db.WithContext(ctx).First(&u, userId).Error
+	if err != nil {
+		return User{}, err
+	}
+	return u, nil
+}
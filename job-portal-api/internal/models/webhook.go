@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// WebhookEvents are the event types a company can subscribe its webhooks to.
+var WebhookEvents = []string{EventJobPosted, EventJobClosed, EventApplicationSubmitted, EventApplicationMoved,
+	EventApplicationOffered, EventApplicationHired, EventApplicationOfferDeclined}
+
+// EventWebhookTest is the type of the event sent by the "send test event" endpoint.
+const EventWebhookTest = "webhook.test"
+
+// Webhook is a company's subscription to domain events, delivered to URL as signed POST requests.
+// It is disabled automatically after too many failed deliveries in a row.
+type Webhook struct {
+	gorm.Model
+	CompanyID           uint       `json:"company_id" gorm:"index;not null"`
+	URL                 string     `json:"url"`
+	Secret              string     `json:"-"`
+	EventTypes          []string   `json:"event_types" gorm:"serializer:json"`
+	Active              bool       `json:"active"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	DisabledAt          *time.Time `json:"disabled_at,omitempty"`
+}
+
+// Subscribes reports whether the webhook wants events of the given type.
+func (w Webhook) Subscribes(eventType string) bool {
+	for _, t := range w.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// NewWebhook is the payload accepted when a company registers a webhook.
+type NewWebhook struct {
+	URL        string   `json:"url" validate:"required,url,startswith=http,max=2000"`
+	EventTypes []string `json:"event_types" validate:"required,min=1,dive,required"`
+}
+
+// WebhookDelivery logs one attempt to deliver an event to a webhook.
+type WebhookDelivery struct {
+	ID           uint      `json:"id" gorm:"primarykey"`
+	WebhookID    uint      `json:"webhook_id" gorm:"index:idx_delivery_webhook_event,priority:1"`
+	EventID      uint      `json:"event_id" gorm:"index:idx_delivery_webhook_event,priority:2"`
+	EventType    string    `json:"event_type"`
+	Attempt      int       `json:"attempt"`
+	Success      bool      `json:"success"`
+	StatusCode   int       `json:"status_code,omitempty"`
+	Error        string    `json:"error,omitempty"`
+	ResponseBody string    `json:"response_body,omitempty"`
+	DurationMs   int64     `json:"duration_ms"`
+	CreatedAt    time.Time `json:"created_at"`
+}
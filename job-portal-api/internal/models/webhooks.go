@@ -0,0 +1,144 @@
+package models
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CreateWebhook registers a webhook for the company with a freshly generated signing secret.
+// The secret is returned here and never shown again.
+func (s *Conn) CreateWebhook(ctx context.Context, nw NewWebhook, companyId uint) (Webhook, string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return Webhook{}, "", err
+	}
+	w := Webhook{
+		CompanyID:  companyId,
+		URL:        nw.URL,
+		Secret:     "whsec_" + hex.EncodeToString(b),
+		EventTypes: nw.EventTypes,
+		Active:     true,
+	}
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Select("id").First(&Company{}, companyId).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(&w).Error
+	})
+	if err != nil {
+		return Webhook{}, "", err
+	}
+	return w, w.Secret, nil
+}
+
+// ViewWebhooks lists the webhooks of a company.
+func (s *Conn) ViewWebhooks(ctx context.Context, companyId uint) ([]Webhook, error) {
+	var hooks = make([]Webhook, 0, 10)
+	err := s.db.WithContext(ctx).Where("company_id = ?", companyId).Order("id").Find(&hooks).Error
+	if err != nil {
+		return nil, err
+	}
+	return hooks, nil
+}
+
+func (s *Conn) ViewWebhook(ctx context.Context, webhookId uint) (Webhook, error) {
+	var w Webhook
+	err := s.db.WithContext(ctx).First(&w, webhookId).Error
+	if err != nil {
+		return Webhook{}, err
+	}
+	return w, nil
+}
+
+// DeleteWebhook removes a webhook; deliveries already queued for it are dropped.
+func (s *Conn) DeleteWebhook(ctx context.Context, webhookId uint) error {
+	return s.db.WithContext(ctx).Delete(&Webhook{}, webhookId).Error
+}
+
+// EnableWebhook turns a disabled webhook back on with a clean failure count.
+func (s *Conn) EnableWebhook(ctx context.Context, webhookId uint) (Webhook, error) {
+	var w Webhook
+	err := s.db.WithContext(ctx).First(&w, webhookId).Error
+	if err != nil {
+		return Webhook{}, err
+	}
+	w.Active = true
+	w.ConsecutiveFailures = 0
+	w.DisabledAt = nil
+	err = s.db.WithContext(ctx).Model(&w).Select("active", "consecutive_failures", "disabled_at").Updates(&w).Error
+	if err != nil {
+		return Webhook{}, err
+	}
+	return w, nil
+}
+
+// ActiveWebhooks returns the enabled webhooks of a company subscribed to the event type.
+func (s *Conn) ActiveWebhooks(ctx context.Context, companyId uint, eventType string) ([]Webhook, error) {
+	var hooks []Webhook
+	err := s.db.WithContext(ctx).Where("company_id = ? AND active", companyId).Find(&hooks).Error
+	if err != nil {
+		return nil, err
+	}
+	out := hooks[:0]
+	for _, w := range hooks {
+		if w.Subscribes(eventType) {
+			out = append(out, w)
+		}
+	}
+	return out, nil
+}
+
+// RecordWebhookDelivery logs a delivery attempt and keeps the failure streak of the webhook,
+// disabling it once disableAfter deliveries failed in a row.
+func (s *Conn) RecordWebhookDelivery(ctx context.Context, d WebhookDelivery, disableAfter int) (Webhook, error) {
+	var w Webhook
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.First(&w, d.WebhookID).Error
+		if err != nil {
+			return err
+		}
+		var previous int64
+		err = tx.Model(&WebhookDelivery{}).Where("webhook_id = ? AND event_id = ?", d.WebhookID, d.EventID).
+			Count(&previous).Error
+		if err != nil {
+			return err
+		}
+		d.Attempt = int(previous) + 1
+		err = tx.Create(&d).Error
+		if err != nil {
+			return err
+		}
+
+		if d.Success {
+			w.ConsecutiveFailures = 0
+		} else {
+			w.ConsecutiveFailures++
+			if w.Active && w.ConsecutiveFailures >= disableAfter {
+				now := time.Now().UTC()
+				w.Active = false
+				w.DisabledAt = &now
+			}
+		}
+		return tx.Model(&w).Select("active", "consecutive_failures", "disabled_at").Updates(&w).Error
+	})
+	if err != nil {
+		return Webhook{}, err
+	}
+	return w, nil
+}
+
+// ViewWebhookDeliveries lists the latest delivery attempts of a webhook.
+func (s *Conn) ViewWebhookDeliveries(ctx context.Context, webhookId uint, limit int) ([]WebhookDelivery, error) {
+	var ds = make([]WebhookDelivery, 0, limit)
+	err := s.db.WithContext(ctx).Where("webhook_id = ?", webhookId).Order("id desc").Limit(limit).Find(&ds).Error
+	if err != nil {
+		return nil, err
+	}
+	return ds, nil
+}
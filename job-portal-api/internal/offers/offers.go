@@ -0,0 +1,148 @@
+// Package offers writes offer letters: it fills in one of the company's offer templates for an application,
+// renders the letter as a PDF and keeps it in the blob store with the offer it belongs to.
+package offers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/pdf"
+	"job-portal-api/internal/storage"
+)
+
+// ErrUnknownTemplate is returned when the template of an offer is not one of the company's.
+var ErrUnknownTemplate = errors.New("offer template not found")
+
+// Store is the part of the data layer the issuer needs.
+type Store interface {
+	ViewOfferTemplate(ctx context.Context, templateId uint) (models.OfferTemplate, error)
+	ViewJobByJobId(ctx context.Context, jobById uint, userId string) ([]models.Job, error)
+	ViewCompany(ctx context.Context, companyID uint, userId string) (models.Company, error)
+	ViewUser(ctx context.Context, userId uint) (models.User, error)
+	CreateOffer(ctx context.Context, offer models.Offer, doc models.Document) (models.Offer, error)
+}
+
+// Issuer sends offers, generating their letters.
+type Issuer struct {
+	store Store
+	bs    storage.BlobStore
+	now   func() time.Time
+}
+
+// NewIssuer returns an issuer keeping the letters it writes in bs.
+func NewIssuer(store Store, bs storage.BlobStore) (*Issuer, error) {
+	if store == nil || bs == nil {
+		return nil, errors.New("store and blob store cannot be nil")
+	}
+	return &Issuer{store: store, bs: bs, now: time.Now}, nil
+}
+
+// Issue writes the offer letter for the application from the template named in no and sends the offer.
+func (is *Issuer) Issue(ctx context.Context, app models.Application, issuedBy uint, no models.NewOffer) (models.Offer, error) {
+	start, err := time.Parse(time.DateOnly, no.StartDate)
+	if err != nil {
+		return models.Offer{}, err
+	}
+	jobs, err := is.store.ViewJobByJobId(ctx, app.JobID, "")
+	if err != nil {
+		return models.Offer{}, err
+	}
+	if len(jobs) == 0 {
+		return models.Offer{}, gorm.ErrRecordNotFound
+	}
+	job := jobs[0]
+	t, err := is.store.ViewOfferTemplate(ctx, no.TemplateID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && t.CompanyID != job.CompanyID) {
+		return models.Offer{}, ErrUnknownTemplate
+	}
+	if err != nil {
+		return models.Offer{}, err
+	}
+	company, err := is.store.ViewCompany(ctx, job.CompanyID, "")
+	if err != nil {
+		return models.Offer{}, err
+	}
+	candidate, err := is.store.ViewUser(ctx, app.UserID)
+	if err != nil {
+		return models.Offer{}, err
+	}
+
+	title, body := t.Fill(map[string]string{
+		"candidate_name": candidate.Name,
+		"job_title":      job.Title,
+		"company_name":   company.CompanyName,
+		"salary":         Salary(no.Salary, no.Currency),
+		"start_date":     start.Format("2 January 2006"),
+	})
+	data := Letter(title, body, company.CompanyName, is.now()).Bytes()
+
+	key := fmt.Sprintf("offers/%d/%s.pdf", app.ID, uuid.NewString())
+	err = is.bs.Put(ctx, key, bytes.NewReader(data), int64(len(data)), pdf.ContentType)
+	if err != nil {
+		return models.Offer{}, fmt.Errorf("storing offer letter %w", err)
+	}
+	sum := sha256.Sum256(data)
+	doc := models.Document{
+		OwnerID:     issuedBy,
+		Kind:        models.DocumentKindOffer,
+		FileName:    fmt.Sprintf("offer-%d.pdf", app.ID),
+		ContentType: pdf.ContentType,
+		Size:        int64(len(data)),
+		Checksum:    hex.EncodeToString(sum[:]),
+		StorageKey:  key,
+	}
+	offer, err := is.store.CreateOffer(ctx, models.Offer{
+		ApplicationID: app.ID,
+		TemplateID:    t.ID,
+		Salary:        no.Salary,
+		Currency:      strings.ToUpper(no.Currency),
+		StartDate:     start,
+		IssuedBy:      issuedBy,
+	}, doc)
+	if err != nil {
+		// Do not leave an orphan letter behind
+		derr := is.bs.Delete(ctx, key)
+		if derr != nil {
+			log.Error().Err(derr).Str("key", key).Msg("deleting offer letter")
+		}
+		return models.Offer{}, err
+	}
+	return offer, nil
+}
+
+// Letter lays a filled in template out as a document: the body's paragraphs are separated by blank lines.
+func Letter(title, body, company string, at time.Time) pdf.Document {
+	var paragraphs []string
+	for _, p := range strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n\n") {
+		p = strings.Trim(p, "\n")
+		if strings.TrimSpace(p) != "" {
+			paragraphs = append(paragraphs, p)
+		}
+	}
+	return pdf.Document{Title: title, Author: company, Paragraphs: paragraphs, Created: at}
+}
+
+// Salary writes an amount the way letters show it, e.g. "EUR 85,000".
+func Salary(amount int, currency string) string {
+	digits := strconv.Itoa(amount)
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+	return strings.ToUpper(currency) + " " + b.String()
+}
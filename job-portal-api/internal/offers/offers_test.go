@@ -0,0 +1,131 @@
+package offers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/storage"
+)
+
+// fakeStore holds one job of company 2 and records the offer it is asked to create.
+type fakeStore struct {
+	createErr error
+	offer     models.Offer
+	doc       models.Document
+}
+
+func (f *fakeStore) ViewOfferTemplate(ctx context.Context, templateId uint) (models.OfferTemplate, error) {
+	templates := map[uint]models.OfferTemplate{
+		1: {Model: gorm.Model{ID: 1}, CompanyID: 2, Title: "Offer of employment at {{company_name}}",
+			Body: "Dear {{candidate_name}},\n\nWe are happy to offer you the role of {{ job_title }}, starting {{start_date}}.\n\nYour salary: {{salary}}."},
+		3: {Model: gorm.Model{ID: 3}, CompanyID: 8, Body: "Someone else's letter"},
+	}
+	t, ok := templates[templateId]
+	if !ok {
+		return models.OfferTemplate{}, gorm.ErrRecordNotFound
+	}
+	return t, nil
+}
+
+func (f *fakeStore) ViewJobByJobId(ctx context.Context, jobId uint, userId string) ([]models.Job, error) {
+	return []models.Job{{Model: gorm.Model{ID: jobId}, Title: "Go developer", CompanyID: 2}}, nil
+}
+
+func (f *fakeStore) ViewCompany(ctx context.Context, companyID uint, userId string) (models.Company, error) {
+	return models.Company{Model: gorm.Model{ID: companyID}, CompanyName: "Acme"}, nil
+}
+
+func (f *fakeStore) ViewUser(ctx context.Context, userId uint) (models.User, error) {
+	return models.User{Model: gorm.Model{ID: userId}, Name: "Asha"}, nil
+}
+
+func (f *fakeStore) CreateOffer(ctx context.Context, offer models.Offer, doc models.Document) (models.Offer, error) {
+	if f.createErr != nil {
+		return models.Offer{}, f.createErr
+	}
+	offer.Status = models.OfferPending
+	f.offer, f.doc = offer, doc
+	return offer, nil
+}
+
+func newIssuer(t *testing.T, store Store) (*Issuer, string) {
+	dir := t.TempDir()
+	bs, err := storage.NewLocalStore(dir, "http://localhost:8080/files", []byte("secret"))
+	require.NoError(t, err)
+	is, err := NewIssuer(store, bs)
+	require.NoError(t, err)
+	is.now = func() time.Time { return time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC) }
+	return is, dir
+}
+
+func TestIssuer_Issue(t *testing.T) {
+	store := &fakeStore{}
+	is, _ := newIssuer(t, store)
+	app := models.Application{Model: gorm.Model{ID: 5}, JobID: 4, UserID: 9}
+
+	offer, err := is.Issue(context.Background(), app, 1, models.NewOffer{TemplateID: 1, Salary: 85000, Currency: "eur", StartDate: "2026-04-01"})
+	require.NoError(t, err)
+	require.Equal(t, uint(5), offer.ApplicationID)
+	require.Equal(t, "EUR", offer.Currency)
+	require.Equal(t, time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), offer.StartDate)
+	require.Equal(t, models.DocumentKindOffer, store.doc.Kind)
+	require.Equal(t, uint(1), store.doc.OwnerID)
+	require.True(t, strings.HasPrefix(store.doc.StorageKey, "offers/5/"))
+
+	rc, err := is.bs.Get(context.Background(), store.doc.StorageKey)
+	require.NoError(t, err)
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	require.Equal(t, store.doc.Size, int64(len(data)))
+	letter := string(data)
+	require.True(t, strings.HasPrefix(letter, "%PDF-"))
+	require.Contains(t, letter, "(Offer of employment at Acme) Tj")
+	require.Contains(t, letter, "(Dear Asha,) Tj")
+	require.Contains(t, letter, "(We are happy to offer you the role of Go developer, starting 1 April 2026.) Tj")
+	require.Contains(t, letter, "(Your salary: EUR 85,000.) Tj")
+}
+
+func TestIssuer_IssueFails(t *testing.T) {
+	app := models.Application{Model: gorm.Model{ID: 5}, JobID: 4, UserID: 9}
+	valid := models.NewOffer{TemplateID: 1, Salary: 85000, Currency: "EUR", StartDate: "2026-04-01"}
+
+	// Templates of other companies are not found
+	for _, id := range []uint{3, 42} {
+		is, _ := newIssuer(t, &fakeStore{})
+		no := valid
+		no.TemplateID = id
+		_, err := is.Issue(context.Background(), app, 1, no)
+		require.ErrorIs(t, err, ErrUnknownTemplate)
+	}
+
+	// The letter is removed when the offer cannot be recorded
+	is, dir := newIssuer(t, &fakeStore{createErr: models.ErrOfferNotAllowed})
+	_, err := is.Issue(context.Background(), app, 1, valid)
+	require.True(t, errors.Is(err, models.ErrOfferNotAllowed))
+	letters, err := filepath.Glob(filepath.Join(dir, "offers", "5", "*.pdf"))
+	require.NoError(t, err)
+	require.Empty(t, letters)
+}
+
+func TestLetter(t *testing.T) {
+	doc := Letter("Offer", "Dear Asha,\r\n\r\nLine one\nLine two\n\n\n\nKind regards,\nAcme", "Acme", time.Time{})
+	require.Equal(t, []string{"Dear Asha,", "Line one\nLine two", "Kind regards,\nAcme"}, doc.Paragraphs)
+	require.Equal(t, "Acme", doc.Author)
+}
+
+func TestSalary(t *testing.T) {
+	tt := map[int]string{7: "USD 7", 999: "USD 999", 1000: "USD 1,000", 85000: "USD 85,000", 1234567: "USD 1,234,567"}
+	for amount, want := range tt {
+		require.Equal(t, want, Salary(amount, "usd"))
+	}
+}
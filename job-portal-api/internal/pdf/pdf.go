@@ -0,0 +1,281 @@
+// Package pdf writes plain text documents, such as offer letters, as PDF files (ISO 32000).
+// Text is set in the Helvetica fonts every PDF reader has built in, so no font files or external services are needed.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ContentType is the media type of PDF files.
+const ContentType = "application/pdf"
+
+// Page geometry in points: A4 with one inch margins.
+const (
+	pageWidth  = 595
+	pageHeight = 842
+	margin     = 72
+	textWidth  = pageWidth - 2*margin
+)
+
+// Font sizes and line heights in points.
+const (
+	titleSize    = 16
+	titleLeading = 22
+	bodySize     = 11
+	bodyLeading  = 15
+	footerSize   = 9
+)
+
+// Document is a titled text in paragraphs. Lines within a paragraph are kept, long ones are wrapped,
+// and pages are added as the text needs them.
+type Document struct {
+	// Title is set in bold above the text and shown by readers in the document properties.
+	Title      string
+	Author     string
+	Paragraphs []string
+	Created    time.Time
+}
+
+// line is a line of text placed on a page, its baseline gap from the previous line.
+type line struct {
+	text string
+	bold bool
+	size int
+	gap  int
+}
+
+// Bytes renders the document.
+func (d Document) Bytes() []byte {
+	pages := paginate(d.lines())
+
+	var w writer
+	w.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	// Objects 1 to 5 are fixed, each page then takes two: the page and its content stream
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 6+2*i)
+	}
+	w.object("<< /Type /Catalog /Pages 2 0 R >>")
+	w.object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	w.object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	w.object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	info := "<< /Producer (Job Portal)"
+	if d.Title != "" {
+		info += " /Title (" + encode(d.Title) + ")"
+	}
+	if d.Author != "" {
+		info += " /Author (" + encode(d.Author) + ")"
+	}
+	if !d.Created.IsZero() {
+		info += " /CreationDate (D:" + d.Created.UTC().Format("20060102150405") + "Z)"
+	}
+	w.object(info + " >>")
+
+	for i, p := range pages {
+		content := p.content()
+		if len(pages) > 1 {
+			content += footer(fmt.Sprintf("Page %d of %d", i+1, len(pages)))
+		}
+		w.object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>", pageWidth, pageHeight, 7+2*i))
+		w.object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(content), content))
+	}
+	w.trailer()
+	return w.Bytes()
+}
+
+// lines lays the title and paragraphs out in lines no wider than the text width.
+func (d Document) lines() []line {
+	var out []line
+	if d.Title != "" {
+		for _, l := range wrap(d.Title, textWidth, boldWidth(titleSize)) {
+			out = append(out, line{text: l, bold: true, size: titleSize, gap: titleLeading})
+		}
+	}
+	for i, p := range d.Paragraphs {
+		// A blank line between paragraphs, and below the title
+		gap := bodyLeading
+		if i > 0 || d.Title != "" {
+			gap += bodyLeading
+		}
+		for _, raw := range strings.Split(strings.ReplaceAll(p, "\r\n", "\n"), "\n") {
+			for _, l := range wrap(raw, textWidth, width(bodySize)) {
+				out = append(out, line{text: l, size: bodySize, gap: gap})
+				gap = bodyLeading
+			}
+		}
+	}
+	return out
+}
+
+type page []line
+
+// paginate fills pages from the top, the first line of each page set at the top margin.
+func paginate(lines []line) []page {
+	pages := []page{nil}
+	used := 0
+	for _, l := range lines {
+		cur := &pages[len(pages)-1]
+		if len(*cur) > 0 && used+l.gap > pageHeight-2*margin {
+			pages = append(pages, nil)
+			cur, used = &pages[len(pages)-1], 0
+		}
+		if len(*cur) == 0 {
+			l.gap = l.size
+		}
+		used += l.gap
+		*cur = append(*cur, l)
+	}
+	return pages
+}
+
+// content is the page's content stream: one text object moving down line by line.
+func (p page) content() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("BT\n%d %d Td\n", margin, pageHeight-margin))
+	for _, l := range p {
+		font := "F1"
+		if l.bold {
+			font = "F2"
+		}
+		b.WriteString(fmt.Sprintf("/%s %d Tf\n0 %d Td\n(%s) Tj\n", font, l.size, -l.gap, encode(l.text)))
+	}
+	b.WriteString("ET\n")
+	return b.String()
+}
+
+// footer centres text at the bottom margin.
+func footer(text string) string {
+	x := (pageWidth - width(footerSize)(text)) / 2
+	return fmt.Sprintf("BT\n/F1 %d Tf\n%.1f %d Td\n(%s) Tj\nET\n", footerSize, x, margin/2, encode(text))
+}
+
+// wrap breaks text into lines no wider than max as measured, at spaces where it can.
+// An empty text is one empty line.
+func wrap(text string, max float64, measure func(string) float64) []string {
+	text = strings.ReplaceAll(text, "\t", "    ")
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return []string{""}
+	}
+	var lines []string
+	cur := ""
+	for _, word := range words {
+		next := word
+		if cur != "" {
+			next = cur + " " + word
+		}
+		if measure(next) <= max {
+			cur = next
+			continue
+		}
+		if cur != "" {
+			lines = append(lines, cur)
+		}
+		// Words wider than a line are broken wherever they have to be
+		for measure(word) > max {
+			n := 1
+			for n < len(word) && measure(word[:n+1]) <= max {
+				n++
+			}
+			for n > 0 && !utf8.RuneStart(word[n]) {
+				n--
+			}
+			if n == 0 {
+				_, n = utf8.DecodeRuneInString(word)
+			}
+			lines = append(lines, word[:n])
+			word = word[n:]
+		}
+		cur = word
+	}
+	return append(lines, cur)
+}
+
+// width returns the measure of text set in Helvetica at size points.
+func width(size int) func(string) float64 {
+	return func(s string) float64 {
+		total := 0
+		for _, r := range s {
+			w := 556
+			if r >= ' ' && r <= '~' {
+				w = helvetica[r-' ']
+			}
+			total += w
+		}
+		return float64(total) * float64(size) / 1000
+	}
+}
+
+// boldWidth overestimates the measure of Helvetica-Bold, a little wider than the regular face.
+func boldWidth(size int) func(string) float64 {
+	regular := width(size)
+	return func(s string) float64 {
+		return regular(s) * 1.1
+	}
+}
+
+// helvetica holds the advance widths of the printable ASCII characters in Helvetica, per 1000 units of font size.
+var helvetica = [...]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278, // space to /
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556, // 0 to ?
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778, // @ to O
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556, // P to _
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556, // ` to o
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584, // p to ~
+}
+
+// winAnsi maps the characters of WinAnsiEncoding outside Latin-1 to their codes.
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87, '‰': 0x89, 'Š': 0x8a,
+	'‹': 0x8b, 'Œ': 0x8c, 'Ž': 0x8e, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95,
+	'–': 0x96, '—': 0x97, '™': 0x99, 'š': 0x9a, '›': 0x9b, 'œ': 0x9c, 'ž': 0x9e, 'Ÿ': 0x9f,
+}
+
+// encode turns text into the body of a PDF string in WinAnsiEncoding.
+// Characters the encoding lacks are replaced by a question mark.
+func encode(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= ' ' && r <= '~':
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			b.WriteString(fmt.Sprintf("\\%03o", r))
+		case winAnsi[r] != 0:
+			b.WriteString(fmt.Sprintf("\\%03o", winAnsi[r]))
+		case r < ' ':
+			b.WriteByte(' ')
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// writer numbers the objects it writes and remembers where they start, for the cross-reference table.
+type writer struct {
+	bytes.Buffer
+	offsets []int
+}
+
+func (w *writer) object(body string) {
+	w.offsets = append(w.offsets, w.Len())
+	fmt.Fprintf(w, "%d 0 obj\n%s\nendobj\n", len(w.offsets), body)
+}
+
+func (w *writer) trailer() {
+	start := w.Len()
+	fmt.Fprintf(w, "xref\n0 %d\n0000000000 65535 f \n", len(w.offsets)+1)
+	for _, off := range w.offsets {
+		fmt.Fprintf(w, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(w, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(w.offsets)+1, start)
+}
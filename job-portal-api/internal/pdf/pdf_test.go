@@ -0,0 +1,96 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocument_Bytes(t *testing.T) {
+	doc := Document{
+		Title:      "Offer of Employment",
+		Author:     "Acme (Europe) Ltd",
+		Paragraphs: []string{"Dear Ada,", "We are pleased to offer you the role of Backend Engineer.\nYour salary will be € 85,000."},
+		Created:    time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC),
+	}
+	out := doc.Bytes()
+
+	require.True(t, bytes.HasPrefix(out, []byte("%PDF-1.4\n")))
+	require.True(t, bytes.HasSuffix(out, []byte("%%EOF\n")))
+	require.Contains(t, string(out), "/Title (Offer of Employment)")
+	require.Contains(t, string(out), `/Author (Acme \(Europe\) Ltd)`)
+	require.Contains(t, string(out), "/CreationDate (D:20260301093000Z)")
+	require.Contains(t, string(out), "(Dear Ada,) Tj")
+	require.Contains(t, string(out), `(Your salary will be \200 85,000.) Tj`)
+	require.Equal(t, 1, strings.Count(string(out), "/Type /Page "))
+	require.NotContains(t, string(out), "Page 1 of")
+	checkXref(t, out)
+}
+
+func TestDocument_BytesPaginates(t *testing.T) {
+	para := strings.Repeat("All work and no play makes a dull letter. ", 40)
+	doc := Document{Title: "Long", Paragraphs: []string{para, para, para, para, para}}
+	out := doc.Bytes()
+
+	pages := strings.Count(string(out), "/Type /Page ")
+	require.Greater(t, pages, 1)
+	require.Contains(t, string(out), fmt.Sprintf("/Count %d", pages))
+	require.Contains(t, string(out), fmt.Sprintf("(Page %d of %d) Tj", pages, pages))
+	checkXref(t, out)
+}
+
+// checkXref makes sure every entry of the cross-reference table points at the object it names.
+func checkXref(t *testing.T, out []byte) {
+	t.Helper()
+	m := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(out)
+	require.NotNil(t, m)
+	start, err := strconv.Atoi(string(m[1]))
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix(out[start:], []byte("xref\n")))
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n \n`).FindAllSubmatch(out[start:], -1)
+	require.NotEmpty(t, entries)
+	for i, e := range entries {
+		off, err := strconv.Atoi(string(e[1]))
+		require.NoError(t, err)
+		require.True(t, bytes.HasPrefix(out[off:], []byte(fmt.Sprintf("%d 0 obj\n", i+1))), "object %d", i+1)
+	}
+	require.Contains(t, string(out[start:]), fmt.Sprintf("/Size %d", len(entries)+1))
+}
+
+func TestWrap(t *testing.T) {
+	measure := width(bodySize)
+	tt := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "Empty", text: "", want: []string{""}},
+		{name: "Short", text: "Dear  Ada,", want: []string{"Dear Ada,"}},
+		{name: "Long", text: strings.Repeat("word ", 30), want: []string{strings.TrimSpace(strings.Repeat("word ", 16)), strings.TrimSpace(strings.Repeat("word ", 14))}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, wrap(tc.text, textWidth, measure))
+		})
+	}
+
+	// Words wider than a line are broken, without splitting characters
+	lines := wrap(strings.Repeat("é", 200), textWidth, measure)
+	require.Greater(t, len(lines), 1)
+	for _, l := range lines {
+		require.LessOrEqual(t, measure(l), float64(textWidth))
+		require.Equal(t, strings.Repeat("é", len([]rune(l))), l)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	require.Equal(t, `a\(b\)c\\`, encode(`a(b)c\`))
+	require.Equal(t, `caf\351 \223ok\224 ?`, encode("café “ok” ✓"))
+}
@@ -0,0 +1,44 @@
+package queue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCron(t *testing.T) {
+	// 2024-03-04 is a Monday
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 3, day, hour, minute, 0, 0, time.UTC)
+	}
+	tt := []struct {
+		spec string
+		t    time.Time
+		want bool
+	}{
+		{spec: "* * * * *", t: at(4, 13, 7), want: true},
+		{spec: "*/15 * * * *", t: at(4, 13, 45), want: true},
+		{spec: "*/15 * * * *", t: at(4, 13, 46), want: false},
+		{spec: "0 9-17/4 * * *", t: at(4, 13, 0), want: true},
+		{spec: "0 9-17/4 * * *", t: at(4, 15, 0), want: false},
+		{spec: "30 8 * * 1-5", t: at(4, 8, 30), want: true},
+		{spec: "30 8 * * 1-5", t: at(3, 8, 30), want: false},
+		{spec: "0 0 * * 7", t: at(3, 0, 0), want: true},
+		{spec: "@daily", t: at(5, 0, 0), want: true},
+		{spec: "@weekly", t: at(5, 0, 0), want: false},
+		// Both day fields restricted: either one matching is enough
+		{spec: "0 0 1 * 1", t: at(4, 0, 0), want: true},
+		{spec: "0 0 1,15 * *", t: at(15, 0, 0), want: true},
+	}
+	for _, tc := range tt {
+		c, err := ParseCron(tc.spec)
+		require.NoError(t, err, tc.spec)
+		require.Equal(t, tc.want, c.Matches(tc.t), "%s at %s", tc.spec, tc.t)
+	}
+
+	for _, bad := range []string{"", "* * * *", "60 * * * *", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		_, err := ParseCron(bad)
+		require.Error(t, err, bad)
+	}
+}
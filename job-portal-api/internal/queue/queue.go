@@ -0,0 +1,434 @@
+package queue
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	mrand "math/rand"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"job-portal-api/internal/models"
+)
+
+// Store is the part of the data layer the queue needs.
+type Store interface {
+	EnqueueTask(ctx context.Context, t models.Task) (models.Task, error)
+	ClaimTasks(ctx context.Context, kinds []string, worker string, limit int, now time.Time) ([]models.Task, error)
+	FinishTask(ctx context.Context, taskId uint) error
+	RetryTask(ctx context.Context, taskId uint, runAt time.Time, lastErr string) error
+	BuryTask(ctx context.Context, taskId uint, lastErr string) error
+	RequeueStaleTasks(ctx context.Context, lockedBefore time.Time) (int64, error)
+	PurgeFinishedTasks(ctx context.Context, before time.Time) (int64, error)
+}
+
+// SystemActor is the audit log actor of changes made by background tasks.
+const SystemActor = models.AuditSystem
+
+// Handler runs one task. Returning an error retries the task later, unless it was wrapped with Permanent.
+type Handler func(ctx context.Context, payload json.RawMessage) error
+
+type permanentError struct{ err error }
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent marks an error that retrying cannot fix, so the task goes straight to the dead letters.
+func Permanent(err error) error {
+	return permanentError{err}
+}
+
+// Options tune the queue. Zero values fall back to the defaults noted on each field.
+type Options struct {
+	// Workers is how many tasks run at the same time in this process, 4 by default.
+	Workers int
+	// PollInterval is how often the database is checked when the queue ran dry, 1s by default.
+	PollInterval time.Duration
+	// MaxAttempts is how often a task is tried before it is declared dead, 5 by default.
+	MaxAttempts int
+	// BaseBackoff is the wait before the first retry; it doubles with each attempt. 10s by default.
+	BaseBackoff time.Duration
+	// MaxBackoff caps the wait between retries, 1h by default.
+	MaxBackoff time.Duration
+	// Timeout bounds a single run of a task, 5m by default.
+	// Tasks still locked after twice the timeout are assumed lost and run again.
+	Timeout time.Duration
+	// DrainTimeout is how long running tasks may finish once the queue is stopped, 30s by default.
+	DrainTimeout time.Duration
+	// Retention is how long finished tasks are kept, 7 days by default.
+	Retention time.Duration
+}
+
+func (o Options) withDefaults() Options {
+	if o.Workers <= 0 {
+		o.Workers = 4
+	}
+	if o.PollInterval <= 0 {
+		o.PollInterval = time.Second
+	}
+	if o.MaxAttempts <= 0 {
+		o.MaxAttempts = 5
+	}
+	if o.BaseBackoff <= 0 {
+		o.BaseBackoff = 10 * time.Second
+	}
+	if o.MaxBackoff <= 0 {
+		o.MaxBackoff = time.Hour
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = 5 * time.Minute
+	}
+	if o.DrainTimeout <= 0 {
+		o.DrainTimeout = 30 * time.Second
+	}
+	if o.Retention <= 0 {
+		o.Retention = 7 * 24 * time.Hour
+	}
+	return o
+}
+
+type cronEntry struct {
+	name    string
+	cron    Cron
+	kind    string
+	payload json.RawMessage
+}
+
+// Queue runs background tasks stored in Postgres with a pool of workers.
+// Tasks survive restarts, are retried with exponential backoff and end up as dead letters
+// once they run out of attempts. Several processes can share one queue.
+type Queue struct {
+	store  Store
+	opts   Options
+	worker string
+
+	mu       sync.RWMutex
+	handlers map[string]Handler
+	crons    []cronEntry
+
+	wake chan struct{}
+	wg   sync.WaitGroup
+	now  func() time.Time
+}
+
+// New returns a queue. Register handlers and schedules, then call Run.
+func New(store Store, opts Options) (*Queue, error) {
+	if store == nil {
+		return nil, errors.New("store cannot be nil")
+	}
+	return &Queue{
+		store:    store,
+		opts:     opts.withDefaults(),
+		worker:   workerName(),
+		handlers: map[string]Handler{},
+		wake:     make(chan struct{}, 1),
+		now:      time.Now,
+	}, nil
+}
+
+// Handle registers the handler running tasks of the given kind.
+// This process only claims tasks of kinds it has a handler for.
+func (q *Queue) Handle(kind string, h Handler) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.handlers[kind] = h
+}
+
+// Schedule enqueues a task of the given kind every time the cron expression fires.
+// Each firing is enqueued once no matter how many processes run the schedule.
+func (q *Queue) Schedule(name, spec, kind string, payload any) error {
+	c, err := ParseCron(spec)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.crons = append(q.crons, cronEntry{name: name, cron: c, kind: kind, payload: data})
+	return nil
+}
+
+// Option changes how a task is enqueued.
+type Option func(t *models.Task)
+
+// At delays the task until the given time.
+func At(t time.Time) Option {
+	return func(task *models.Task) { task.RunAt = t.UTC() }
+}
+
+// Unique drops the task if one with the same key was ever enqueued.
+func Unique(key string) Option {
+	return func(task *models.Task) { task.UniqueKey = &key }
+}
+
+// MaxAttempts overrides how often the task is tried.
+func MaxAttempts(n int) Option {
+	return func(task *models.Task) { task.MaxAttempts = n }
+}
+
+// Enqueue stores a task; payload is encoded as JSON for the handler.
+func (q *Queue) Enqueue(ctx context.Context, kind string, payload any, opts ...Option) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encoding %s payload %w", kind, err)
+	}
+	t := models.Task{
+		Kind:        kind,
+		Payload:     data,
+		Status:      models.TaskPending,
+		RunAt:       q.now().UTC(),
+		MaxAttempts: q.opts.MaxAttempts,
+	}
+	for _, o := range opts {
+		o(&t)
+	}
+	_, err = q.store.EnqueueTask(ctx, t)
+	if err != nil {
+		return fmt.Errorf("enqueueing %s %w", kind, err)
+	}
+	// Let a local worker pick it up without waiting for the next poll
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Run starts the workers, the scheduler and the housekeeping.
+// Once ctx is cancelled no new task is claimed and running tasks get DrainTimeout to finish;
+// Wait blocks until they did. Tasks cut short are picked up again after the lock expires.
+func (q *Queue) Run(ctx context.Context) {
+	taskCtx, cancelTasks := context.WithCancel(context.Background())
+	drained := make(chan struct{})
+	q.wg.Add(3)
+	go func() {
+		defer q.wg.Done()
+		defer close(drained)
+		q.poll(ctx, taskCtx)
+	}()
+	go func() {
+		defer q.wg.Done()
+		q.tick(ctx)
+	}()
+	go func() {
+		defer q.wg.Done()
+		defer cancelTasks()
+		<-ctx.Done()
+		t := time.NewTimer(q.opts.DrainTimeout)
+		defer t.Stop()
+		select {
+		case <-drained:
+		case <-t.C:
+			log.Warn().Msg("queue : drain timed out, cancelling running tasks")
+		}
+	}()
+}
+
+// Wait blocks until Run has stopped and running tasks are drained.
+func (q *Queue) Wait() {
+	q.wg.Wait()
+}
+
+func (q *Queue) kinds() []string {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	kinds := make([]string, 0, len(q.handlers))
+	for k := range q.handlers {
+		kinds = append(kinds, k)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+func (q *Queue) handler(kind string) Handler {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	return q.handlers[kind]
+}
+
+// poll claims tasks whenever a worker slot is free and runs each in its own goroutine.
+func (q *Queue) poll(ctx, taskCtx context.Context) {
+	var running sync.WaitGroup
+	defer running.Wait()
+	slots := make(chan struct{}, q.opts.Workers)
+	for {
+		// Wait for at least one free slot, then take every other free one
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+		free := 1
+	more:
+		for free < q.opts.Workers {
+			select {
+			case slots <- struct{}{}:
+				free++
+			default:
+				break more
+			}
+		}
+
+		tasks, err := q.store.ClaimTasks(ctx, q.kinds(), q.worker, free, q.now().UTC())
+		if err != nil && ctx.Err() == nil {
+			log.Error().Err(err).Msg("queue : claiming tasks")
+		}
+		for i := len(tasks); i < free; i++ {
+			<-slots
+		}
+		for _, t := range tasks {
+			running.Add(1)
+			go func(t models.Task) {
+				defer running.Done()
+				defer func() { <-slots }()
+				q.process(taskCtx, t)
+			}(t)
+		}
+		if len(tasks) == free {
+			continue
+		}
+
+		// The queue ran dry, wait for new work
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.wake:
+		case <-time.After(q.opts.PollInterval):
+		}
+	}
+}
+
+func (q *Queue) process(taskCtx context.Context, t models.Task) {
+	err := q.run(taskCtx, t)
+
+	// Record the outcome even when the queue is shutting down
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	logger := log.With().Uint("task", t.ID).Str("kind", t.Kind).Int("attempt", t.Attempts).Logger()
+
+	if err == nil {
+		err = q.store.FinishTask(ctx, t.ID)
+		if err != nil {
+			logger.Error().Err(err).Msg("queue : marking task done")
+		}
+		return
+	}
+
+	var perm permanentError
+	if errors.As(err, &perm) || t.Attempts >= t.MaxAttempts {
+		logger.Error().Err(err).Msg("queue : task failed for good, moved to dead letters")
+		err = q.store.BuryTask(ctx, t.ID, err.Error())
+		if err != nil {
+			logger.Error().Err(err).Msg("queue : burying task")
+		}
+		return
+	}
+
+	delay := Backoff(t.Attempts, q.opts.BaseBackoff, q.opts.MaxBackoff)
+	logger.Warn().Err(err).Dur("retry_in", delay).Msg("queue : task failed")
+	err = q.store.RetryTask(ctx, t.ID, q.now().UTC().Add(delay), err.Error())
+	if err != nil {
+		logger.Error().Err(err).Msg("queue : rescheduling task")
+	}
+}
+
+// run calls the task's handler, turning a panic into an error.
+func (q *Queue) run(taskCtx context.Context, t models.Task) (err error) {
+	h := q.handler(t.Kind)
+	if h == nil {
+		return fmt.Errorf("no handler for %q", t.Kind)
+	}
+	ctx, cancel := context.WithTimeout(taskCtx, q.opts.Timeout)
+	defer cancel()
+	// Background writes show up in the audit log as done by the system, traced to the task
+	ctx = models.WithAuditActor(ctx, models.AuditActor{UserID: SystemActor, TraceID: fmt.Sprintf("task-%d", t.ID)})
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return h(ctx, t.Payload)
+}
+
+// Backoff is the wait before retrying after the given attempt: base doubled per attempt,
+// capped at max, with up to 20% jitter so failed tasks don't all come back at once.
+func Backoff(attempt int, base, max time.Duration) time.Duration {
+	d := time.Duration(float64(base) * math.Pow(2, float64(attempt-1)))
+	if d <= 0 || d > max {
+		d = max
+	}
+	return d + time.Duration(mrand.Int63n(int64(d)/5+1))
+}
+
+// tick fires the cron schedules and does the housekeeping once a minute.
+func (q *Queue) tick(ctx context.Context) {
+	last := q.now().UTC().Truncate(time.Minute).Add(-time.Minute)
+	for {
+		now := q.now().UTC()
+		minute := now.Truncate(time.Minute)
+		// Catch up on minutes missed while busy, but not on a long outage
+		if minute.Sub(last) > time.Hour {
+			last = minute.Add(-time.Minute)
+		}
+		for m := last.Add(time.Minute); !m.After(minute); m = m.Add(time.Minute) {
+			q.fire(ctx, m)
+		}
+		last = minute
+		q.housekeep(ctx, now)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(minute.Add(time.Minute).Sub(now)):
+		}
+	}
+}
+
+func (q *Queue) fire(ctx context.Context, minute time.Time) {
+	q.mu.RLock()
+	crons := append([]cronEntry(nil), q.crons...)
+	q.mu.RUnlock()
+	for _, c := range crons {
+		if !c.cron.Matches(minute) {
+			continue
+		}
+		key := fmt.Sprintf("cron:%s:%s", c.name, minute.Format(time.RFC3339))
+		err := q.Enqueue(ctx, c.kind, c.payload, At(minute), Unique(key))
+		if err != nil && ctx.Err() == nil {
+			log.Error().Err(err).Str("schedule", c.name).Msg("queue : enqueueing scheduled task")
+		}
+	}
+}
+
+func (q *Queue) housekeep(ctx context.Context, now time.Time) {
+	n, err := q.store.RequeueStaleTasks(ctx, now.Add(-2*q.opts.Timeout))
+	if err != nil && ctx.Err() == nil {
+		log.Error().Err(err).Msg("queue : requeueing stale tasks")
+	}
+	if n > 0 {
+		log.Warn().Int64("tasks", n).Msg("queue : requeued tasks of lost workers")
+	}
+	_, err = q.store.PurgeFinishedTasks(ctx, now.Add(-q.opts.Retention))
+	if err != nil && ctx.Err() == nil {
+		log.Error().Err(err).Msg("queue : purging finished tasks")
+	}
+}
+
+// workerName identifies this process in task locks.
+func workerName() string {
+	host, _ := os.Hostname()
+	b := make([]byte, 4)
+	_, _ = rand.Read(b)
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), hex.EncodeToString(b))
+}
@@ -0,0 +1,199 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"job-portal-api/internal/models"
+)
+
+// memStore is an in-memory Store behaving like the Postgres one.
+type memStore struct {
+	mu     sync.Mutex
+	tasks  []models.Task
+	unique map[string]bool
+}
+
+func newMemStore() *memStore {
+	return &memStore{unique: map[string]bool{}}
+}
+
+func (m *memStore) EnqueueTask(ctx context.Context, t models.Task) (models.Task, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if t.UniqueKey != nil {
+		if m.unique[*t.UniqueKey] {
+			return t, nil
+		}
+		m.unique[*t.UniqueKey] = true
+	}
+	t.ID = uint(len(m.tasks) + 1)
+	m.tasks = append(m.tasks, t)
+	return t, nil
+}
+
+func (m *memStore) ClaimTasks(ctx context.Context, kinds []string, worker string, limit int, now time.Time) ([]models.Task, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []models.Task
+	for i := range m.tasks {
+		t := &m.tasks[i]
+		if len(out) == limit || t.Status != models.TaskPending || t.RunAt.After(now) {
+			continue
+		}
+		t.Status = models.TaskRunning
+		t.LockedBy = worker
+		t.LockedAt = &now
+		t.Attempts++
+		out = append(out, *t)
+	}
+	return out, nil
+}
+
+func (m *memStore) update(id uint, f func(t *models.Task)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f(&m.tasks[id-1])
+	return nil
+}
+
+func (m *memStore) FinishTask(ctx context.Context, taskId uint) error {
+	return m.update(taskId, func(t *models.Task) { t.Status = models.TaskDone })
+}
+
+func (m *memStore) RetryTask(ctx context.Context, taskId uint, runAt time.Time, lastErr string) error {
+	return m.update(taskId, func(t *models.Task) {
+		t.Status = models.TaskPending
+		// Retry right away so the test doesn't wait for the backoff
+		t.RunAt = time.Time{}
+		t.LastError = lastErr
+	})
+}
+
+func (m *memStore) BuryTask(ctx context.Context, taskId uint, lastErr string) error {
+	return m.update(taskId, func(t *models.Task) {
+		t.Status = models.TaskDead
+		t.LastError = lastErr
+	})
+}
+
+func (m *memStore) RequeueStaleTasks(ctx context.Context, lockedBefore time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (m *memStore) PurgeFinishedTasks(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (m *memStore) task(id uint) models.Task {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.tasks[id-1]
+}
+
+func TestQueue(t *testing.T) {
+	store := newMemStore()
+	q, err := New(store, Options{Workers: 2, PollInterval: 10 * time.Millisecond, MaxAttempts: 3})
+	require.NoError(t, err)
+
+	type payload struct {
+		N int `json:"n"`
+	}
+	var mu sync.Mutex
+	calls := map[string]int{}
+	count := func(kind string) int {
+		mu.Lock()
+		defer mu.Unlock()
+		calls[kind]++
+		return calls[kind]
+	}
+	q.Handle("ok", func(ctx context.Context, raw json.RawMessage) error {
+		var p payload
+		require.NoError(t, json.Unmarshal(raw, &p))
+		require.Equal(t, 42, p.N)
+		count("ok")
+		return nil
+	})
+	q.Handle("flaky", func(ctx context.Context, raw json.RawMessage) error {
+		if count("flaky") < 2 {
+			return errors.New("try again")
+		}
+		return nil
+	})
+	q.Handle("broken", func(ctx context.Context, raw json.RawMessage) error {
+		count("broken")
+		return errors.New("always fails")
+	})
+	q.Handle("invalid", func(ctx context.Context, raw json.RawMessage) error {
+		count("invalid")
+		return Permanent(errors.New("bad payload"))
+	})
+	q.Handle("panics", func(ctx context.Context, raw json.RawMessage) error {
+		count("panics")
+		panic("boom")
+	})
+
+	ctx := context.Background()
+	for _, kind := range []string{"ok", "flaky", "broken", "invalid", "panics"} {
+		require.NoError(t, q.Enqueue(ctx, kind, payload{N: 42}, MaxAttempts(3)))
+	}
+	// A task scheduled in the future is left alone
+	require.NoError(t, q.Enqueue(ctx, "ok", payload{N: 42}, At(time.Now().Add(time.Hour))))
+
+	runCtx, stop := context.WithCancel(ctx)
+	q.Run(runCtx)
+	require.Eventually(t, func() bool {
+		for id := uint(1); id <= 5; id++ {
+			s := store.task(id).Status
+			if s != models.TaskDone && s != models.TaskDead {
+				return false
+			}
+		}
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+	stop()
+	q.Wait()
+
+	require.Equal(t, models.TaskDone, store.task(1).Status)
+	require.Equal(t, models.TaskDone, store.task(2).Status)
+	require.Equal(t, 2, store.task(2).Attempts)
+	require.Equal(t, models.TaskDead, store.task(3).Status)
+	require.Equal(t, 3, store.task(3).Attempts)
+	require.Equal(t, "always fails", store.task(3).LastError)
+	require.Equal(t, models.TaskDead, store.task(4).Status)
+	require.Equal(t, 1, store.task(4).Attempts)
+	require.Equal(t, models.TaskDead, store.task(5).Status)
+	require.Equal(t, "panic: boom", store.task(5).LastError)
+	require.Equal(t, models.TaskPending, store.task(6).Status)
+	require.Equal(t, 1, calls["ok"])
+}
+
+func TestQueue_ScheduleOncePerFiring(t *testing.T) {
+	store := newMemStore()
+	q, err := New(store, Options{})
+	require.NoError(t, err)
+	require.NoError(t, q.Schedule("digest", "*/15 * * * *", "digest", nil))
+
+	minute := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	q.fire(context.Background(), minute)
+	// A second process firing the same minute enqueues nothing new
+	q.fire(context.Background(), minute)
+	q.fire(context.Background(), minute.Add(time.Minute))
+	require.Len(t, store.tasks, 1)
+	require.Equal(t, minute, store.tasks[0].RunAt)
+}
+
+func TestBackoff(t *testing.T) {
+	base, max := 10*time.Second, time.Hour
+	for attempt, want := range map[int]time.Duration{1: 10 * time.Second, 2: 20 * time.Second, 4: 80 * time.Second, 30: time.Hour} {
+		d := Backoff(attempt, base, max)
+		require.GreaterOrEqual(t, d, want)
+		require.LessOrEqual(t, d, want+want/5)
+	}
+}
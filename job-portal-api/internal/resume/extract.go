@@ -0,0 +1,418 @@
+package resume
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"job-portal-api/internal/storage"
+)
+
+// ErrUnsupported is returned for documents whose format cannot be turned into text.
+var ErrUnsupported = errors.New("unsupported document type")
+
+// maxStreamSize caps how much a single compressed PDF stream may expand to.
+const maxStreamSize = 10 << 20
+
+// ExtractText returns the plain text of a PDF, DOCX or text document.
+func ExtractText(data []byte, contentType string) (string, error) {
+	switch contentType {
+	case storage.MimeText:
+		if !utf8.Valid(data) {
+			return strings.ToValidUTF8(string(data), ""), nil
+		}
+		return string(data), nil
+	case storage.MimeDOCX:
+		return docxText(data)
+	case storage.MimePDF:
+		return pdfText(data)
+	}
+	return "", fmt.Errorf("%w: %s", ErrUnsupported, contentType)
+}
+
+// docxText reads word/document.xml and keeps the text runs, turning paragraphs and breaks into newlines.
+func docxText(data []byte) (string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("opening docx %w", err)
+	}
+	var doc *zip.File
+	for _, f := range zr.File {
+		if f.Name == "word/document.xml" {
+			doc = f
+			break
+		}
+	}
+	if doc == nil {
+		return "", errors.New("docx without word/document.xml")
+	}
+	rc, err := doc.Open()
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	var b strings.Builder
+	dec := xml.NewDecoder(io.LimitReader(rc, maxStreamSize))
+	inText := false
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("reading docx %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				b.WriteByte('\t')
+			case "br", "cr":
+				b.WriteByte('\n')
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				b.WriteByte('\n')
+			}
+		case xml.CharData:
+			if inText {
+				b.Write(t)
+			}
+		}
+	}
+	return b.String(), nil
+}
+
+var (
+	pdfStream = regexp.MustCompile(`(?s)<<(.*?)>>\s*stream\r?\n`)
+	pdfFlate  = regexp.MustCompile(`/Filter\s*(?:\[\s*)?/FlateDecode`)
+)
+
+// pdfText pulls the text shown by the content streams of a PDF.
+// It understands uncompressed and FlateDecode streams and the standard text operators,
+// which covers resumes exported by word processors. Fonts using custom encodings without
+// a plain byte mapping come out garbled, in which case the candidate fills the profile by hand.
+func pdfText(data []byte) (string, error) {
+	if !bytes.HasPrefix(data, []byte("%PDF-")) {
+		return "", errors.New("not a pdf file")
+	}
+	var b strings.Builder
+	for _, m := range pdfStream.FindAllSubmatchIndex(data, -1) {
+		dict := data[m[2]:m[3]]
+		start := m[1]
+		end := bytes.Index(data[start:], []byte("endstream"))
+		if end < 0 {
+			break
+		}
+		raw := data[start : start+end]
+
+		content := raw
+		if pdfFlate.Match(dict) {
+			zr, err := zlib.NewReader(bytes.NewReader(raw))
+			if err != nil {
+				continue
+			}
+			content, err = io.ReadAll(io.LimitReader(zr, maxStreamSize))
+			zr.Close()
+			if err != nil && len(content) == 0 {
+				continue
+			}
+		} else if bytes.Contains(dict, []byte("/Filter")) {
+			// Images and other encodings carry no text
+			continue
+		}
+		if !bytes.Contains(content, []byte("BT")) {
+			continue
+		}
+		showText(&b, content)
+	}
+	text := b.String()
+	if strings.TrimSpace(text) == "" {
+		return "", errors.New("no text found in pdf")
+	}
+	return text, nil
+}
+
+// showText interprets the text operators of one content stream.
+func showText(b *strings.Builder, content []byte) {
+	var operands []any
+	var array []any
+	inArray := false
+	lastY := 0.0
+
+	newline := func() {
+		s := b.String()
+		if len(s) > 0 && s[len(s)-1] != '\n' {
+			b.WriteByte('\n')
+		}
+	}
+
+	for i := 0; i < len(content); {
+		c := content[i]
+		switch {
+		case isPDFSpace(c):
+			i++
+		case c == '%':
+			for i < len(content) && content[i] != '\n' && content[i] != '\r' {
+				i++
+			}
+		case c == '(':
+			s, n := pdfLiteral(content[i:])
+			i += n
+			if inArray {
+				array = append(array, s)
+			} else {
+				operands = append(operands, s)
+			}
+		case c == '<' && i+1 < len(content) && content[i+1] == '<':
+			// Inline dictionaries (marked content properties) carry no text
+			depth := 0
+			for i < len(content)-1 {
+				if content[i] == '<' && content[i+1] == '<' {
+					depth++
+					i += 2
+					continue
+				}
+				if content[i] == '>' && content[i+1] == '>' {
+					depth--
+					i += 2
+					if depth == 0 {
+						break
+					}
+					continue
+				}
+				i++
+			}
+		case c == '<':
+			end := bytes.IndexByte(content[i:], '>')
+			if end < 0 {
+				return
+			}
+			s := pdfHex(content[i+1 : i+end])
+			i += end + 1
+			if inArray {
+				array = append(array, s)
+			} else {
+				operands = append(operands, s)
+			}
+		case c == '[':
+			inArray = true
+			array = array[:0]
+			i++
+		case c == ']':
+			inArray = false
+			operands = append(operands, append([]any(nil), array...))
+			i++
+		default:
+			j := i
+			for j < len(content) && !isPDFSpace(content[j]) && !bytes.ContainsRune([]byte("()<>[]/%"), rune(content[j])) {
+				j++
+			}
+			if c == '/' {
+				j = i + 1
+				for j < len(content) && !isPDFSpace(content[j]) && !bytes.ContainsRune([]byte("()<>[]/%"), rune(content[j])) {
+					j++
+				}
+				operands = append(operands, string(content[i:j]))
+				i = j
+				continue
+			}
+			if j == i {
+				i++
+				continue
+			}
+			word := string(content[i:j])
+			i = j
+			if f, err := strconv.ParseFloat(word, 64); err == nil {
+				if inArray {
+					array = append(array, f)
+				} else {
+					operands = append(operands, f)
+				}
+				continue
+			}
+
+			switch word {
+			case "Tj":
+				if s, ok := lastString(operands); ok {
+					b.WriteString(s)
+				}
+			case "'", "\"":
+				newline()
+				if s, ok := lastString(operands); ok {
+					b.WriteString(s)
+				}
+			case "TJ":
+				if len(operands) > 0 {
+					if arr, ok := operands[len(operands)-1].([]any); ok {
+						for _, el := range arr {
+							switch v := el.(type) {
+							case string:
+								b.WriteString(v)
+							case float64:
+								// Large negative kerning is how generators render a word gap
+								if v < -200 {
+									b.WriteByte(' ')
+								}
+							}
+						}
+					}
+				}
+			case "Td", "TD":
+				if len(operands) >= 2 {
+					if y, ok := operands[len(operands)-1].(float64); ok && y != 0 {
+						newline()
+					} else if x, ok := operands[len(operands)-2].(float64); ok && x > 0 {
+						b.WriteByte(' ')
+					}
+				}
+			case "Tm":
+				if len(operands) >= 6 {
+					if y, ok := operands[5].(float64); ok {
+						if y != lastY {
+							newline()
+						} else {
+							b.WriteByte(' ')
+						}
+						lastY = y
+					}
+				}
+			case "T*", "ET":
+				newline()
+			}
+			operands = operands[:0]
+		}
+	}
+	newline()
+}
+
+func lastString(operands []any) (string, bool) {
+	if len(operands) == 0 {
+		return "", false
+	}
+	s, ok := operands[len(operands)-1].(string)
+	return s, ok
+}
+
+func isPDFSpace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\f' || c == 0
+}
+
+// pdfLiteral decodes the literal string starting at data[0] == '(' and returns it with the number of bytes consumed.
+func pdfLiteral(data []byte) (string, int) {
+	var b []byte
+	depth := 0
+	i := 0
+	for i < len(data) {
+		c := data[i]
+		switch c {
+		case '(':
+			depth++
+			if depth > 1 {
+				b = append(b, c)
+			}
+		case ')':
+			depth--
+			if depth == 0 {
+				return pdfDecode(b), i + 1
+			}
+			b = append(b, c)
+		case '\\':
+			i++
+			if i >= len(data) {
+				break
+			}
+			e := data[i]
+			switch e {
+			case 'n':
+				b = append(b, '\n')
+			case 'r':
+				b = append(b, '\r')
+			case 't':
+				b = append(b, '\t')
+			case 'b', 'f':
+			case '\r', '\n':
+				// Line continuation
+			default:
+				if e >= '0' && e <= '7' {
+					v := 0
+					n := 0
+					for n < 3 && i < len(data) && data[i] >= '0' && data[i] <= '7' {
+						v = v*8 + int(data[i]-'0')
+						i++
+						n++
+					}
+					i--
+					b = append(b, byte(v))
+				} else {
+					b = append(b, e)
+				}
+			}
+		default:
+			b = append(b, c)
+		}
+		i++
+	}
+	return pdfDecode(b), len(data)
+}
+
+func pdfHex(h []byte) string {
+	var clean []byte
+	for _, c := range h {
+		if !isPDFSpace(c) {
+			clean = append(clean, c)
+		}
+	}
+	if len(clean)%2 == 1 {
+		clean = append(clean, '0')
+	}
+	out := make([]byte, 0, len(clean)/2)
+	for i := 0; i+1 < len(clean); i += 2 {
+		v, err := strconv.ParseUint(string(clean[i:i+2]), 16, 8)
+		if err != nil {
+			return ""
+		}
+		out = append(out, byte(v))
+	}
+	return pdfDecode(out)
+}
+
+// pdfDecode turns string bytes into text, handling UTF-16BE strings (with BOM) and
+// two-byte glyph strings as produced for Identity-H fonts whose codes match Unicode.
+func pdfDecode(b []byte) string {
+	if len(b) >= 2 && b[0] == 0xFE && b[1] == 0xFF {
+		return utf16be(b[2:])
+	}
+	if len(b) >= 2 && len(b)%2 == 0 && b[0] == 0 {
+		return utf16be(b)
+	}
+	// Treat single byte strings as Latin-1, a fair approximation of WinAnsiEncoding
+	r := make([]rune, len(b))
+	for i, c := range b {
+		r[i] = rune(c)
+	}
+	return string(r)
+}
+
+func utf16be(b []byte) string {
+	u := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		u = append(u, uint16(b[i])<<8|uint16(b[i+1]))
+	}
+	return string(utf16.Decode(u))
+}
@@ -0,0 +1,396 @@
+package resume
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"job-portal-api/internal/models"
+)
+
+type section int
+
+const (
+	sectionNone section = iota
+	sectionSkills
+	sectionExperience
+	sectionEducation
+	sectionOther
+)
+
+// headings maps the usual resume section titles to the section they open.
+var headings = map[string]section{
+	"skills":                  sectionSkills,
+	"technical skills":        sectionSkills,
+	"key skills":              sectionSkills,
+	"core skills":             sectionSkills,
+	"core competencies":       sectionSkills,
+	"technologies":            sectionSkills,
+	"tech stack":              sectionSkills,
+	"experience":              sectionExperience,
+	"work experience":         sectionExperience,
+	"professional experience": sectionExperience,
+	"employment":              sectionExperience,
+	"employment history":      sectionExperience,
+	"work history":            sectionExperience,
+	"career history":          sectionExperience,
+	"education":               sectionEducation,
+	"academic background":     sectionEducation,
+	"qualifications":          sectionEducation,
+	"education and training":  sectionEducation,
+	"summary":                 sectionOther,
+	"profile":                 sectionOther,
+	"objective":               sectionOther,
+	"about me":                sectionOther,
+	"projects":                sectionOther,
+	"certifications":          sectionOther,
+	"languages":               sectionOther,
+	"interests":               sectionOther,
+	"hobbies":                 sectionOther,
+	"references":              sectionOther,
+	"awards":                  sectionOther,
+	"publications":            sectionOther,
+	"contact":                 sectionOther,
+}
+
+// knownSkills are picked up anywhere in the text, even without a skills section.
+var knownSkills = []string{
+	"go", "golang", "python", "java", "javascript", "typescript", "kotlin", "swift", "scala", "rust",
+	"ruby", "php", "c++", "c#", "sql", "postgresql", "mysql", "mongodb", "redis", "elasticsearch",
+	"kafka", "rabbitmq", "docker", "kubernetes", "terraform", "ansible", "aws", "gcp", "azure",
+	"linux", "git", "graphql", "grpc", "rest", "react", "angular", "vue", "node.js", "django",
+	"flask", "spring", "html", "css", "machine learning", "pandas", "spark", "hadoop", "jenkins",
+	"ci/cd", "microservices", "figma", "excel", "tableau", "power bi", "salesforce", "sap",
+}
+
+var (
+	emailRe = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	phoneRe = regexp.MustCompile(`\+?\d[\d ().-]{7,}\d`)
+	linkRe  = regexp.MustCompile(`(?i)\b(?:https?://|www\.|(?:linkedin|github|gitlab)\.com/)[^\s,;|)]+`)
+	yearRe  = regexp.MustCompile(`\b(19|20)\d{2}\b`)
+
+	month     = `(?:jan|feb|mar|apr|may|jun|jul|aug|sep|sept|oct|nov|dec)[a-z]*\.?`
+	dateToken = `(?:` + month + `\s+\d{4}|\d{1,2}/\d{4}|\d{4}-\d{2}|\d{4})`
+	rangeRe   = regexp.MustCompile(`(?i)(` + dateToken + `)\s*(?:-|–|—|to|until)\s*(` + dateToken + `|present|current|now|today|date)`)
+
+	degreeRe      = regexp.MustCompile(`(?i)\b(bachelor[a-z']*|master[a-z']*|b\.?\s?sc|m\.?\s?sc|b\.?\s?tech|m\.?\s?tech|b\.?\s?e\.?|m\.?\s?e\.?|b\.?\s?a\.?|m\.?\s?a\.?|mba|ph\.?\s?d|doctorate|diploma|associate[a-z']*)\b`)
+	institutionRe = regexp.MustCompile(`(?i)\b(university|college|institute|school|academy|polytechnic|iit|nit)\b`)
+	splitRe       = regexp.MustCompile(`\s+(?:at|@)\s+|\s*[|–—]\s*|\s+-\s+|,\s+`)
+	bulletRe      = regexp.MustCompile(`^[•*·▪‣◦-]\s*`)
+)
+
+var months = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+// Parse applies heuristics to the plain text of a resume and returns what it recognised.
+// Anything it is unsure about is left out rather than guessed.
+func Parse(text string) models.ResumeDraft {
+	var d models.ResumeDraft
+	lines := splitLines(text)
+
+	d.Email = emailRe.FindString(text)
+	for _, l := range lines {
+		if p := phoneRe.FindString(l); p != "" && !rangeRe.MatchString(l) && countDigits(p) >= 8 {
+			d.Phone = strings.TrimSpace(p)
+			break
+		}
+	}
+	d.Links = uniq(linkRe.FindAllString(text, -1))
+	d.Name = guessName(lines)
+
+	// Group the lines by the section they appear in
+	sections := map[section][]string{}
+	current := sectionNone
+	for _, l := range lines {
+		if s, ok := heading(l); ok {
+			current = s
+			continue
+		}
+		sections[current] = append(sections[current], l)
+	}
+
+	d.Skills = parseSkills(sections[sectionSkills], text)
+	d.Experiences = parseExperiences(sections[sectionExperience])
+	d.Educations = parseEducations(sections[sectionEducation])
+	return d
+}
+
+func splitLines(text string) []string {
+	raw := strings.FieldsFunc(text, func(r rune) bool { return r == '\n' || r == '\r' })
+	lines := make([]string, 0, len(raw))
+	for _, l := range raw {
+		l = strings.Join(strings.Fields(l), " ")
+		if l != "" {
+			lines = append(lines, l)
+		}
+	}
+	return lines
+}
+
+func heading(line string) (section, bool) {
+	h := strings.ToLower(strings.TrimRight(strings.TrimSpace(line), ":"))
+	s, ok := headings[h]
+	return s, ok
+}
+
+// guessName takes the first short line near the top made only of capitalised words.
+func guessName(lines []string) string {
+	for i, l := range lines {
+		if i >= 5 {
+			break
+		}
+		if _, ok := heading(l); ok {
+			break
+		}
+		words := strings.Fields(l)
+		if len(words) < 2 || len(words) > 4 {
+			continue
+		}
+		ok := true
+		for _, w := range words {
+			r := []rune(w)
+			if !(r[0] >= 'A' && r[0] <= 'Z') || strings.ContainsAny(w, "@/:0123456789|") {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return l
+		}
+	}
+	return ""
+}
+
+func parseSkills(lines []string, text string) []string {
+	var skills []string
+	for _, l := range lines {
+		l = bulletRe.ReplaceAllString(l, "")
+		// "Languages: Go, Python" lists the skills after the label
+		if i := strings.Index(l, ":"); i >= 0 {
+			l = l[i+1:]
+		}
+		for _, s := range strings.FieldsFunc(l, func(r rune) bool { return strings.ContainsRune(",;|•·/", r) }) {
+			s = strings.TrimSpace(s)
+			if s != "" && len(s) <= 50 && len(strings.Fields(s)) <= 4 {
+				skills = append(skills, s)
+			}
+		}
+	}
+
+	lower := " " + strings.ToLower(text) + " "
+	for _, k := range knownSkills {
+		if containsWord(lower, k) {
+			skills = append(skills, k)
+		}
+	}
+	return models.NormalizeSkills(skills)
+}
+
+// containsWord reports whether w appears in s without letters or digits glued to either side.
+func containsWord(s, w string) bool {
+	for i := 0; ; {
+		j := strings.Index(s[i:], w)
+		if j < 0 {
+			return false
+		}
+		start := i + j
+		end := start + len(w)
+		if !isWordChar(s[start-1]) && (end >= len(s) || !isWordChar(s[end])) {
+			return true
+		}
+		i = start + 1
+	}
+}
+
+func isWordChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '+' || c == '#'
+}
+
+// parseExperiences starts a new entry at every line holding a date range.
+// The title and company come from that line, or from the line just above it when the
+// date range stands on its own. Following lines become the description.
+func parseExperiences(lines []string) []models.NewExperience {
+	var out []models.NewExperience
+	var desc []string
+	var cur *models.NewExperience
+
+	flush := func() {
+		if cur == nil {
+			return
+		}
+		cur.Description = strings.Join(desc, "\n")
+		if cur.Title != "" && cur.Company != "" {
+			out = append(out, *cur)
+		}
+		cur, desc = nil, nil
+	}
+
+	for i, l := range lines {
+		m := rangeRe.FindStringSubmatchIndex(l)
+		if m == nil {
+			if cur != nil {
+				desc = append(desc, bulletRe.ReplaceAllString(l, ""))
+			}
+			continue
+		}
+
+		header := strings.TrimSpace(strings.Trim(l[:m[0]]+" "+l[m[1]:], " ,|()–—-"))
+		if header == "" && i > 0 && cur != nil && len(desc) > 0 {
+			// The title line was taken as description of the previous entry
+			header = desc[len(desc)-1]
+			desc = desc[:len(desc)-1]
+		} else if header == "" && i > 0 && cur == nil {
+			header = lines[i-1]
+		}
+		flush()
+
+		e := models.NewExperience{}
+		e.StartDate = normalizeDate(l[m[2]:m[3]])
+		end := strings.ToLower(l[m[4]:m[5]])
+		switch end {
+		case "present", "current", "now", "today", "date":
+			e.Current = true
+		default:
+			e.EndDate = normalizeDate(end)
+		}
+		if e.StartDate == "" {
+			continue
+		}
+
+		parts := splitRe.Split(header, -1)
+		if len(parts) >= 2 {
+			e.Title, e.Company = strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+			if len(parts) >= 3 {
+				e.Location = strings.TrimSpace(parts[2])
+			}
+		}
+		cur = &e
+	}
+	flush()
+	return out
+}
+
+// normalizeDate turns "Jan 2019", "01/2019", "2019-01" or "2019" into "2019-01".
+func normalizeDate(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if len(s) >= 3 {
+		if mo, ok := months[s[:3]]; ok {
+			y := yearRe.FindString(s)
+			if y == "" {
+				return ""
+			}
+			return y + "-" + twoDigits(mo)
+		}
+	}
+	if i := strings.IndexByte(s, '/'); i > 0 {
+		mo, err := strconv.Atoi(s[:i])
+		if err != nil || mo < 1 || mo > 12 {
+			return ""
+		}
+		return s[i+1:] + "-" + twoDigits(mo)
+	}
+	if len(s) == 7 && s[4] == '-' {
+		return s
+	}
+	if len(s) == 4 {
+		return s + "-01"
+	}
+	return ""
+}
+
+func twoDigits(n int) string {
+	if n < 10 {
+		return "0" + strconv.Itoa(n)
+	}
+	return strconv.Itoa(n)
+}
+
+// parseEducations makes an entry of every line naming a degree or an institution.
+// A degree line directly followed by its institution line (or the reverse) is merged into one entry.
+func parseEducations(lines []string) []models.NewEducation {
+	var out []models.NewEducation
+	for _, l := range lines {
+		degree := degreeRe.MatchString(l)
+		inst := institutionRe.MatchString(l)
+		if !degree && !inst {
+			continue
+		}
+
+		var e models.NewEducation
+		for _, p := range splitRe.Split(yearRe.ReplaceAllString(l, ""), -1) {
+			p = strings.Trim(strings.TrimSpace(p), "()–—-")
+			switch {
+			case p == "":
+			case e.Institution == "" && institutionRe.MatchString(p):
+				e.Institution = p
+			case e.Degree == "" && degreeRe.MatchString(p):
+				e.Degree = p
+				if i := strings.Index(strings.ToLower(p), " in "); i > 0 {
+					e.Degree, e.FieldOfStudy = p[:i], p[i+4:]
+				}
+			}
+		}
+		years := yearRe.FindAllString(l, -1)
+		if len(years) > 0 {
+			sort.Strings(years)
+			e.StartYear, _ = strconv.Atoi(years[0])
+			e.EndYear, _ = strconv.Atoi(years[len(years)-1])
+			if len(years) == 1 {
+				e.StartYear = 0
+			}
+		}
+
+		// Merge with the previous entry when it only has the other half
+		if n := len(out); n > 0 {
+			prev := &out[n-1]
+			if prev.Institution == "" && e.Institution != "" && e.Degree == "" ||
+				prev.Degree == "" && e.Degree != "" && e.Institution == "" {
+				if prev.Institution == "" {
+					prev.Institution = e.Institution
+				} else {
+					prev.Degree, prev.FieldOfStudy = e.Degree, e.FieldOfStudy
+				}
+				if prev.EndYear == 0 {
+					prev.StartYear, prev.EndYear = e.StartYear, e.EndYear
+				}
+				continue
+			}
+		}
+		out = append(out, e)
+	}
+
+	// Entries without an institution cannot be saved to a profile
+	valid := out[:0]
+	for _, e := range out {
+		if e.Institution != "" {
+			valid = append(valid, e)
+		}
+	}
+	return valid
+}
+
+func countDigits(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			n++
+		}
+	}
+	return n
+}
+
+func uniq(in []string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, s := range in {
+		s = strings.TrimRight(s, ".")
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
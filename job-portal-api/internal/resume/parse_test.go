@@ -0,0 +1,67 @@
+package resume
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/storage"
+)
+
+func TestParseFixtures(t *testing.T) {
+	expected := models.ResumeDraft{
+		Name:   "Priya Sharma",
+		Email:  "priya.sharma@example.com",
+		Phone:  "+91 98765 43210",
+		Links:  []string{"github.com/priyasharma"},
+		Skills: []string{"go", "python", "sql", "docker", "kubernetes", "postgresql"},
+		Experiences: []models.NewExperience{
+			{
+				Title:       "Senior Software Engineer",
+				Company:     "Acme Corp",
+				Location:    "Pune",
+				StartDate:   "2021-01",
+				Current:     true,
+				Description: "Built the payments API in Go\nMoved services to Kubernetes",
+			},
+			{
+				Title:       "Software Engineer",
+				Company:     "Globex",
+				StartDate:   "2017-06",
+				EndDate:     "2020-12",
+				Description: "Maintained Python data pipelines",
+			},
+		},
+		Educations: []models.NewEducation{
+			{
+				Institution:  "University of Pune",
+				Degree:       "Bachelor of Engineering",
+				FieldOfStudy: "Computer Science",
+				StartYear:    2013,
+				EndYear:      2017,
+			},
+		},
+	}
+
+	tt := []struct {
+		file        string
+		contentType string
+	}{
+		{file: "testdata/resume.txt", contentType: storage.MimeText},
+		{file: "testdata/resume.docx", contentType: storage.MimeDOCX},
+		{file: "testdata/resume.pdf", contentType: storage.MimePDF},
+	}
+	for _, tc := range tt {
+		t.Run(tc.file, func(t *testing.T) {
+			data, err := os.ReadFile(tc.file)
+			require.NoError(t, err)
+			require.Equal(t, tc.contentType, storage.DetectContentType(data, tc.file))
+
+			text, err := ExtractText(data, tc.contentType)
+			require.NoError(t, err)
+			require.Equal(t, expected, Parse(text))
+		})
+	}
+}
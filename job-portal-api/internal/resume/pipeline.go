@@ -0,0 +1,95 @@
+package resume
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/rs/zerolog/log"
+
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/queue"
+	"job-portal-api/internal/storage"
+)
+
+// KindParse is the queue task parsing one resume.
+const KindParse = "resume.parse"
+
+// Store is the part of the data layer the pipeline needs.
+type Store interface {
+	ViewDocument(ctx context.Context, documentId uint) (models.Document, error)
+	FinishResumeParse(ctx context.Context, documentId uint, draft models.ResumeDraft, parseErr string) error
+}
+
+// Pipeline parses uploaded resumes in the background.
+// Callers record a pending models.ResumeParse and Enqueue the document;
+// a queue worker later fills in the draft or the failure reason.
+type Pipeline struct {
+	store Store
+	bs    storage.BlobStore
+	q     *queue.Queue
+}
+
+type parseTask struct {
+	DocumentID uint `json:"document_id"`
+}
+
+// NewPipeline returns a pipeline running its work on q.
+func NewPipeline(store Store, bs storage.BlobStore, q *queue.Queue) (*Pipeline, error) {
+	if store == nil || bs == nil || q == nil {
+		return nil, errors.New("store, blob store and queue cannot be nil")
+	}
+	p := &Pipeline{store: store, bs: bs, q: q}
+	q.Handle(KindParse, p.handle)
+	return p, nil
+}
+
+// Enqueue schedules a document for parsing without waiting for it.
+func (p *Pipeline) Enqueue(ctx context.Context, documentId uint) error {
+	return p.q.Enqueue(ctx, KindParse, parseTask{DocumentID: documentId})
+}
+
+func (p *Pipeline) handle(ctx context.Context, payload json.RawMessage) error {
+	var t parseTask
+	err := json.Unmarshal(payload, &t)
+	if err != nil {
+		return queue.Permanent(err)
+	}
+	return p.process(ctx, t.DocumentID)
+}
+
+// process records the draft, or why none could be made. A resume that cannot be read is not retried,
+// the candidate sees the reason and can upload another file.
+func (p *Pipeline) process(ctx context.Context, documentId uint) error {
+	draft, err := p.Parse(ctx, documentId)
+	msg := ""
+	if err != nil {
+		log.Error().Err(err).Uint("document", documentId).Msg("resume parsing failed")
+		msg = err.Error()
+	}
+	return p.store.FinishResumeParse(ctx, documentId, draft, msg)
+}
+
+// Parse loads the document from the blob store and turns it into a draft.
+func (p *Pipeline) Parse(ctx context.Context, documentId uint) (models.ResumeDraft, error) {
+	doc, err := p.store.ViewDocument(ctx, documentId)
+	if err != nil {
+		return models.ResumeDraft{}, fmt.Errorf("loading document %w", err)
+	}
+	rc, err := p.bs.Get(ctx, doc.StorageKey)
+	if err != nil {
+		return models.ResumeDraft{}, fmt.Errorf("reading blob %w", err)
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(io.LimitReader(rc, storage.ResumePolicy.MaxSize))
+	if err != nil {
+		return models.ResumeDraft{}, fmt.Errorf("reading blob %w", err)
+	}
+	text, err := ExtractText(data, doc.ContentType)
+	if err != nil {
+		return models.ResumeDraft{}, err
+	}
+	return Parse(text), nil
+}
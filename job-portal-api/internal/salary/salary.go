@@ -0,0 +1,292 @@
+// Package salary reports what jobs pay, aggregated from the salaries posted with them. Salaries are
+// brought to a common basis first: converted to one currency with a configured table of exchange rates,
+// and to one pay period assuming full-time work. Groups with too few postings, or postings of a single
+// company, are left out of reports so that no posting can be told from the figures.
+package salary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"job-portal-api/internal/models"
+)
+
+// DefaultMinSamples is the fewest postings a group needs to be reported.
+const DefaultMinSamples = 5
+
+// minCompanies is the fewest companies whose postings a group needs to be reported.
+const minCompanies = 2
+
+// lookback is how far back postings are counted.
+const lookback = 365 * 24 * time.Hour
+
+// Dimensions postings are grouped by.
+const (
+	GroupTitle      = "title"
+	GroupExperience = "experience"
+	GroupLocation   = "location"
+)
+
+// Percentiles reported for each group.
+var Percentiles = []float64{10, 25, 50, 75, 90}
+
+// ErrInvalidQuery is returned for reports that cannot be built as asked.
+var ErrInvalidQuery = errors.New("group_by must list title, experience or location, and period must be hourly, monthly or annual")
+
+// ErrUnknownCurrency is returned when a report is asked for in a currency without an exchange rate.
+var ErrUnknownCurrency = errors.New("no exchange rate for the currency")
+
+// Store is the part of the data layer salary insights need.
+type Store interface {
+	SalaryPostings(ctx context.Context, f models.SalaryFilter) ([]models.SalaryPosting, error)
+}
+
+// Rates converts amounts between currencies through a base currency.
+type Rates struct {
+	Base string
+	// toBase is what one unit of each currency is worth in the base currency
+	toBase map[string]float64
+}
+
+// ParseRates reads exchange rates to the base currency in the "EUR=1.08,GBP=1.27" form, meaning one
+// euro is worth 1.08 of the base currency. The base currency, USD when empty, needs no rate.
+func ParseRates(s, base string) (Rates, error) {
+	base = strings.ToUpper(strings.TrimSpace(base))
+	if base == "" {
+		base = "USD"
+	}
+	r := Rates{Base: base, toBase: map[string]float64{base: 1}}
+	if strings.TrimSpace(s) == "" {
+		return r, nil
+	}
+	for _, part := range strings.Split(s, ",") {
+		code, val, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return Rates{}, fmt.Errorf("invalid exchange rate %q", part)
+		}
+		f, err := strconv.ParseFloat(val, 64)
+		if err != nil || f <= 0 {
+			return Rates{}, fmt.Errorf("invalid exchange rate %q", part)
+		}
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if len(code) != 3 {
+			return Rates{}, fmt.Errorf("invalid currency %q", code)
+		}
+		if code == base && f != 1 {
+			return Rates{}, fmt.Errorf("the base currency %s must have a rate of 1", base)
+		}
+		r.toBase[code] = f
+	}
+	return r, nil
+}
+
+// Convert turns an amount of one currency into another. It is false when either has no rate.
+func (r Rates) Convert(amount float64, from, to string) (float64, bool) {
+	f, ok := r.toBase[strings.ToUpper(from)]
+	if !ok {
+		return 0, false
+	}
+	t, ok := r.toBase[strings.ToUpper(to)]
+	if !ok {
+		return 0, false
+	}
+	return amount * f / t, true
+}
+
+// Query is how a report is asked for.
+type Query struct {
+	Filter  models.SalaryFilter
+	GroupBy []string
+	// Currency and Period are what the figures are given in; the base currency and annual by default
+	Currency string
+	Period   string
+}
+
+// ParseQuery reads the dimensions to group by, comma separated and title when empty, the currency and
+// the pay period. The filter is left for the caller to set.
+func ParseQuery(groupBy, currency, period string) (Query, error) {
+	q := Query{Currency: strings.ToUpper(strings.TrimSpace(currency)), Period: period}
+	if q.Period == "" {
+		q.Period = models.PayAnnual
+	}
+	if _, ok := models.PeriodsPerYear[q.Period]; !ok {
+		return Query{}, ErrInvalidQuery
+	}
+	if strings.TrimSpace(groupBy) == "" {
+		groupBy = GroupTitle
+	}
+	seen := map[string]bool{}
+	for _, g := range strings.Split(groupBy, ",") {
+		g = strings.TrimSpace(g)
+		if g != GroupTitle && g != GroupExperience && g != GroupLocation || seen[g] {
+			return Query{}, ErrInvalidQuery
+		}
+		seen[g] = true
+		q.GroupBy = append(q.GroupBy, g)
+	}
+	return q, nil
+}
+
+// Bucket is the salary spread of the postings sharing a title, experience level and location, as far
+// as the report groups by them. Percentiles are keyed by their rank, "p10" to "p90".
+type Bucket struct {
+	Title           string             `json:"title,omitempty"`
+	ExperienceLevel string             `json:"experience_level,omitempty"`
+	Location        string             `json:"location,omitempty"`
+	Samples         int                `json:"samples"`
+	Percentiles     map[string]float64 `json:"percentiles"`
+}
+
+// Report is what the postings matching a query pay.
+type Report struct {
+	Currency   string   `json:"currency"`
+	Period     string   `json:"period"`
+	GroupBy    []string `json:"group_by"`
+	MinSamples int      `json:"min_samples"`
+	Buckets    []Bucket `json:"buckets"`
+	// Suppressed counts the groups left out for having too few postings or companies
+	Suppressed int `json:"suppressed"`
+	// Unconverted counts the postings left out for being in a currency without an exchange rate
+	Unconverted int `json:"unconverted"`
+}
+
+// Reporter builds salary reports.
+type Reporter struct {
+	store      Store
+	rates      Rates
+	minSamples int
+}
+
+// NewReporter returns a reporter converting with the rates, which reports groups of at least
+// minSamples postings, DefaultMinSamples when not positive.
+func NewReporter(store Store, rates Rates, minSamples int) *Reporter {
+	if minSamples <= 0 {
+		minSamples = DefaultMinSamples
+	}
+	return &Reporter{store: store, rates: rates, minSamples: minSamples}
+}
+
+// group is the postings of a bucket as they are collected.
+type group struct {
+	bucket    Bucket
+	values    []float64
+	companies map[uint]bool
+}
+
+// Report builds the report asked for from the postings of the last year.
+func (r *Reporter) Report(ctx context.Context, q Query, now time.Time) (Report, error) {
+	if q.Currency == "" {
+		q.Currency = r.rates.Base
+	}
+	if _, ok := r.rates.Convert(1, q.Currency, q.Currency); !ok {
+		return Report{}, fmt.Errorf("%w %s", ErrUnknownCurrency, q.Currency)
+	}
+	q.Filter.Since = now.Add(-lookback)
+	postings, err := r.store.SalaryPostings(ctx, q.Filter)
+	if err != nil {
+		return Report{}, err
+	}
+
+	rep := Report{Currency: q.Currency, Period: q.Period, GroupBy: q.GroupBy, MinSamples: r.minSamples, Buckets: []Bucket{}}
+	groups := map[string]*group{}
+	var order []string
+	for _, p := range postings {
+		amount, ok := r.normalize(p, q.Currency, q.Period)
+		if !ok {
+			rep.Unconverted++
+			continue
+		}
+		key, b := bucketOf(p, q.GroupBy)
+		g, ok := groups[key]
+		if !ok {
+			g = &group{bucket: b, companies: map[uint]bool{}}
+			groups[key] = g
+			order = append(order, key)
+		}
+		g.values = append(g.values, amount)
+		g.companies[p.CompanyID] = true
+	}
+
+	for _, key := range order {
+		g := groups[key]
+		if len(g.values) < r.minSamples || len(g.companies) < minCompanies {
+			rep.Suppressed++
+			continue
+		}
+		sort.Float64s(g.values)
+		g.bucket.Samples = len(g.values)
+		g.bucket.Percentiles = make(map[string]float64, len(Percentiles))
+		for _, pct := range Percentiles {
+			g.bucket.Percentiles[fmt.Sprintf("p%g", pct)] = round(percentile(g.values, pct), q.Period)
+		}
+		rep.Buckets = append(rep.Buckets, g.bucket)
+	}
+	sort.SliceStable(rep.Buckets, func(i, j int) bool {
+		return rep.Buckets[i].Samples > rep.Buckets[j].Samples
+	})
+	return rep, nil
+}
+
+// normalize is the middle of the posted salary range in the currency and pay period asked for.
+func (r *Reporter) normalize(p models.SalaryPosting, currency, period string) (float64, bool) {
+	mid := float64(p.SalaryMin+p.SalaryMax) / 2
+	if p.SalaryMin == 0 || p.SalaryMax == 0 {
+		mid = float64(max(p.SalaryMin, p.SalaryMax))
+	}
+	perYear, ok := models.PeriodsPerYear[p.SalaryPeriod]
+	if !ok {
+		perYear = 1
+	}
+	amount, ok := r.rates.Convert(mid*perYear, p.SalaryCurrency, currency)
+	if !ok {
+		return 0, false
+	}
+	return amount / models.PeriodsPerYear[period], true
+}
+
+// bucketOf keys the posting by the dimensions grouped by, ignoring case and spacing. The bucket is
+// named after the first posting of the group.
+func bucketOf(p models.SalaryPosting, groupBy []string) (string, Bucket) {
+	var b Bucket
+	keys := make([]string, 0, len(groupBy))
+	for _, g := range groupBy {
+		var v string
+		switch g {
+		case GroupTitle:
+			v = strings.Join(strings.Fields(p.Title), " ")
+			b.Title = v
+		case GroupExperience:
+			v = strings.Join(strings.Fields(p.ExperienceLevel), " ")
+			b.ExperienceLevel = v
+		case GroupLocation:
+			v = strings.Join(strings.Fields(p.Location), " ")
+			b.Location = v
+		}
+		keys = append(keys, strings.ToLower(v))
+	}
+	return strings.Join(keys, "\x00"), b
+}
+
+// percentile interpolates linearly between the closest ranks of the sorted values.
+func percentile(sorted []float64, pct float64) float64 {
+	pos := pct / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(pos))
+	if lo+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[lo] + (pos-float64(lo))*(sorted[lo+1]-sorted[lo])
+}
+
+// round keeps cents of hourly figures and whole amounts otherwise.
+func round(f float64, period string) float64 {
+	if period == models.PayHourly {
+		return math.Round(f*100) / 100
+	}
+	return math.Round(f)
+}
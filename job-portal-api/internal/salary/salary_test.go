@@ -0,0 +1,131 @@
+package salary
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"job-portal-api/internal/models"
+)
+
+type fakeStore struct {
+	postings []models.SalaryPosting
+	err      error
+
+	filter models.SalaryFilter
+}
+
+func (f *fakeStore) SalaryPostings(ctx context.Context, filter models.SalaryFilter) ([]models.SalaryPosting, error) {
+	f.filter = filter
+	return f.postings, f.err
+}
+
+func TestParseRates(t *testing.T) {
+	r, err := ParseRates(" eur=1.25, GBP=1.5", "")
+	require.NoError(t, err)
+	require.Equal(t, "USD", r.Base)
+
+	got, ok := r.Convert(100, "EUR", "USD")
+	require.True(t, ok)
+	require.InDelta(t, 125, got, 1e-9)
+	got, ok = r.Convert(150, "usd", "GBP")
+	require.True(t, ok)
+	require.InDelta(t, 100, got, 1e-9)
+	_, ok = r.Convert(1, "JPY", "USD")
+	require.False(t, ok)
+
+	for _, in := range []string{"EUR", "EUR=abc", "EUR=0", "EURO=1.1", "USD=2"} {
+		_, err := ParseRates(in, "USD")
+		require.Error(t, err, in)
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	q, err := ParseQuery("", "eur", "")
+	require.NoError(t, err)
+	require.Equal(t, Query{GroupBy: []string{GroupTitle}, Currency: "EUR", Period: models.PayAnnual}, q)
+
+	q, err = ParseQuery("location, experience", "", models.PayHourly)
+	require.NoError(t, err)
+	require.Equal(t, []string{GroupLocation, GroupExperience}, q.GroupBy)
+
+	for _, in := range [][2]string{{"company", ""}, {"title,title", ""}, {"title", "weekly"}} {
+		_, err := ParseQuery(in[0], "", in[1])
+		require.ErrorIs(t, err, ErrInvalidQuery, in)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	values := []float64{10, 20, 30, 40, 50}
+	require.Equal(t, 10.0, percentile(values, 0))
+	require.Equal(t, 14.0, percentile(values, 10))
+	require.Equal(t, 30.0, percentile(values, 50))
+	require.Equal(t, 50.0, percentile(values, 100))
+	require.Equal(t, 7.0, percentile([]float64{7}, 90))
+}
+
+func TestReport(t *testing.T) {
+	now := time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC)
+	rates, err := ParseRates("EUR=1.5", "USD")
+	require.NoError(t, err)
+	store := &fakeStore{postings: []models.SalaryPosting{
+		{CompanyID: 1, Title: "Go Developer", SalaryMin: 90000, SalaryMax: 110000, SalaryCurrency: "USD"},
+		// 50 an hour is 104000 a year
+		{CompanyID: 2, Title: "go  developer", SalaryMin: 50, SalaryCurrency: "USD", SalaryPeriod: models.PayHourly},
+		// 10000 EUR a month is 180000 USD a year
+		{CompanyID: 2, Title: "Go developer", SalaryMax: 10000, SalaryCurrency: "EUR", SalaryPeriod: models.PayMonthly},
+		{CompanyID: 3, Title: "Go developer", SalaryMin: 120000, SalaryCurrency: "USD", SalaryPeriod: models.PayAnnual},
+		{CompanyID: 1, Title: "Go developer", SalaryMin: 5000000, SalaryCurrency: "JPY"},
+		// One company posting many jobs is not enough
+		{CompanyID: 4, Title: "Designer", SalaryMin: 80000, SalaryCurrency: "USD"},
+		{CompanyID: 4, Title: "Designer", SalaryMin: 81000, SalaryCurrency: "USD"},
+		{CompanyID: 4, Title: "Designer", SalaryMin: 82000, SalaryCurrency: "USD"},
+		{CompanyID: 4, Title: "Designer", SalaryMin: 83000, SalaryCurrency: "USD"},
+		{CompanyID: 5, Title: "Writer", SalaryMin: 50000, SalaryCurrency: "USD"},
+	}}
+	r := NewReporter(store, rates, 4)
+
+	q, err := ParseQuery("title", "", "")
+	require.NoError(t, err)
+	q.Filter.Location = "Berlin"
+	rep, err := r.Report(context.Background(), q, now)
+	require.NoError(t, err)
+
+	require.Equal(t, "Berlin", store.filter.Location)
+	require.Equal(t, now.Add(-lookback), store.filter.Since)
+	require.Equal(t, "USD", rep.Currency)
+	require.Equal(t, 4, rep.MinSamples)
+	require.Equal(t, 1, rep.Unconverted)
+	require.Equal(t, 2, rep.Suppressed)
+	require.Len(t, rep.Buckets, 1)
+	b := rep.Buckets[0]
+	require.Equal(t, "Go Developer", b.Title)
+	require.Equal(t, 4, b.Samples)
+	// 100000, 104000, 120000, 180000
+	require.Equal(t, 112000.0, b.Percentiles["p50"])
+	require.Equal(t, 101200.0, b.Percentiles["p10"])
+	require.Equal(t, 162000.0, b.Percentiles["p90"])
+
+	q.Currency, q.Period = "EUR", models.PayHourly
+	rep, err = r.Report(context.Background(), q, now)
+	require.NoError(t, err)
+	// 112000 USD a year is 74666.67 EUR, or 35.90 an hour
+	require.Equal(t, 35.9, rep.Buckets[0].Percentiles["p50"])
+}
+
+func TestReportErrors(t *testing.T) {
+	rates, err := ParseRates("", "USD")
+	require.NoError(t, err)
+	store := &fakeStore{err: errors.New("db down")}
+	r := NewReporter(store, rates, 0)
+	require.Equal(t, DefaultMinSamples, r.minSamples)
+
+	_, err = r.Report(context.Background(), Query{Currency: "EUR", Period: models.PayAnnual}, time.Now())
+	require.ErrorIs(t, err, ErrUnknownCurrency)
+
+	_, err = r.Report(context.Background(), Query{Period: models.PayAnnual}, time.Now())
+	require.ErrorContains(t, err, "db down")
+}
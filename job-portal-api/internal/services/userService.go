@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"job-portal-api/internal/models"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
@@ -21,6 +22,151 @@ type Service interface {
 	ViewCompany(ctx context.Context, companyID uint, userId string) (models.Company, error)
 	ViewJobByCompId(ctx context.Context, companyID uint, userId string) ([]models.Job, error)
 	ViewJobByJobId(ctx context.Context, jobById uint, userId string) ([]models.Job, error)
+	ViewProfile(ctx context.Context, userId uint) (models.Profile, error)
+	UpdateProfile(ctx context.Context, np models.NewProfile, userId uint) (models.Profile, error)
+	DeleteProfile(ctx context.Context, userId uint) error
+	CreateDocument(ctx context.Context, doc models.Document) (models.Document, error)
+	ViewDocuments(ctx context.Context, ownerId uint) ([]models.Document, error)
+	ViewDocument(ctx context.Context, documentId uint) (models.Document, error)
+	DeleteDocument(ctx context.Context, documentId uint) error
+	SetCompanyLogo(ctx context.Context, companyId uint, documentId uint) (models.Company, models.Document, error)
+	CreateApplication(ctx context.Context, na models.NewApplication, jobId uint, userId uint) (models.Application, error)
+	ViewApplication(ctx context.Context, applicationId uint) (models.Application, error)
+	ViewApplicationsByJob(ctx context.Context, jobId uint, f models.ApplicationFilter) ([]models.Application, error)
+	ViewApplicationsByUser(ctx context.Context, userId uint) ([]models.Application, error)
+	StartResumeParse(ctx context.Context, documentId uint, userId uint) (models.ResumeParse, error)
+	FinishResumeParse(ctx context.Context, documentId uint, draft models.ResumeDraft, parseErr string) error
+	ViewResumeParse(ctx context.Context, documentId uint) (models.ResumeParse, error)
+	ReplaceJobMatches(ctx context.Context, jobId uint, scores []models.MatchScore) error
+	ReplaceProfileMatches(ctx context.Context, userId uint, scores []models.MatchScore) error
+	CountStaleMatchScores(ctx context.Context, weights string) (int64, error)
+	ViewJobMatches(ctx context.Context, userId uint, limit int) ([]models.JobMatch, error)
+	ViewCandidateMatches(ctx context.Context, jobId uint, limit int) ([]models.CandidateMatch, error)
+	ProfilesAfter(ctx context.Context, afterId uint, limit int) ([]models.Profile, error)
+	JobsAfter(ctx context.Context, afterId uint, limit int) ([]models.Job, error)
+	ViewUser(ctx context.Context, userId uint) (models.User, error)
+	CreateSavedSearch(ctx context.Context, ns models.NewSavedSearch, userId uint) (models.SavedSearch, error)
+	ViewSavedSearches(ctx context.Context, userId uint) ([]models.SavedSearch, error)
+	UpdateSavedSearch(ctx context.Context, ns models.NewSavedSearch, searchId uint, userId uint) (models.SavedSearch, error)
+	DeleteSavedSearch(ctx context.Context, searchId uint, userId uint) error
+	UnsubscribeSavedSearch(ctx context.Context, token string) (models.SavedSearch, error)
+	DueSavedSearches(ctx context.Context, now time.Time, limit int) ([]models.SavedSearch, error)
+	JobsPublishedBetween(ctx context.Context, from, to time.Time) ([]models.Job, error)
+	SentJobIDs(ctx context.Context, userId uint, jobIds []uint) ([]uint, error)
+	RecordDigest(ctx context.Context, userId uint, jobIds []uint, searches []models.SavedSearch, runAt time.Time) error
+	EnqueueTask(ctx context.Context, t models.Task) (models.Task, error)
+	ClaimTasks(ctx context.Context, kinds []string, worker string, limit int, now time.Time) ([]models.Task, error)
+	FinishTask(ctx context.Context, taskId uint) error
+	RetryTask(ctx context.Context, taskId uint, runAt time.Time, lastErr string) error
+	BuryTask(ctx context.Context, taskId uint, lastErr string) error
+	RequeueStaleTasks(ctx context.Context, lockedBefore time.Time) (int64, error)
+	PurgeFinishedTasks(ctx context.Context, before time.Time) (int64, error)
+	ViewDeadTasks(ctx context.Context, limit int) ([]models.Task, error)
+	RequeueDeadTask(ctx context.Context, taskId uint) error
+	RelayEvents(ctx context.Context, limit int, retry models.EventRetry, publish func(e models.OutboxEvent) error) (int, error)
+	PurgePublishedEvents(ctx context.Context, before time.Time) (int64, error)
+	CreateWebhook(ctx context.Context, nw models.NewWebhook, companyId uint) (models.Webhook, string, error)
+	ViewWebhooks(ctx context.Context, companyId uint) ([]models.Webhook, error)
+	ViewWebhook(ctx context.Context, webhookId uint) (models.Webhook, error)
+	DeleteWebhook(ctx context.Context, webhookId uint) error
+	EnableWebhook(ctx context.Context, webhookId uint) (models.Webhook, error)
+	ActiveWebhooks(ctx context.Context, companyId uint, eventType string) ([]models.Webhook, error)
+	RecordWebhookDelivery(ctx context.Context, d models.WebhookDelivery, disableAfter int) (models.Webhook, error)
+	ViewWebhookDeliveries(ctx context.Context, webhookId uint, limit int) ([]models.WebhookDelivery, error)
+	ViewAuditLog(ctx context.Context, f models.AuditFilter) ([]models.AuditEntry, error)
+	CreateJobs(ctx context.Context, companyId uint, njs []models.NewJob) ([]models.Job, error)
+	EachCompanyJob(ctx context.Context, companyId uint, fn func(job models.Job) error) error
+	FeedJobs(ctx context.Context, since time.Time, afterId uint, limit int) ([]models.Job, error)
+	ViewCompanies(ctx context.Context, companyIds []uint) ([]models.Company, error)
+	TransitionJob(ctx context.Context, jobId uint, t models.JobTransition) (models.Job, error)
+	SweepJobs(ctx context.Context, now time.Time, limit int) (int, error)
+	ViewCompanyJobs(ctx context.Context, companyId uint, status string) ([]models.Job, error)
+	ViewMembership(ctx context.Context, companyId, userId uint) (models.Membership, error)
+	ViewMemberships(ctx context.Context, companyId uint) ([]models.Membership, error)
+	ViewUserMemberships(ctx context.Context, userId uint) ([]models.Membership, error)
+	UpdateMembership(ctx context.Context, companyId, userId uint, role string) (models.Membership, error)
+	DeleteMembership(ctx context.Context, companyId, userId uint) error
+	CreateInvitation(ctx context.Context, companyId, invitedBy uint, ni models.NewInvitation) (models.Invitation, string, error)
+	ViewInvitations(ctx context.Context, companyId uint) ([]models.Invitation, error)
+	DeleteInvitation(ctx context.Context, companyId, invitationId uint) error
+	AcceptInvitation(ctx context.Context, token string, userId uint) (models.Membership, error)
+	CreateInterview(ctx context.Context, applicationId, recruiterId uint, ni models.NewInterview) (models.Interview, error)
+	ViewInterview(ctx context.Context, interviewId uint) (models.Interview, error)
+	ViewApplicationInterviews(ctx context.Context, applicationId uint) ([]models.Interview, error)
+	ViewUserInterviews(ctx context.Context, userId uint, since time.Time) ([]models.Interview, error)
+	SelectInterviewSlot(ctx context.Context, interviewId, slotId, candidateId uint) (models.Interview, error)
+	CancelInterview(ctx context.Context, interviewId uint, reason string) (models.Interview, error)
+	CalendarFeedToken(ctx context.Context, userId uint, rotate bool) (string, error)
+	ViewCalendarFeedUser(ctx context.Context, token string) (uint, error)
+	OpenConversation(ctx context.Context, applicationId uint) (models.Conversation, error)
+	CreateMessage(ctx context.Context, conversationId, senderId uint, nm models.NewMessage) (models.Message, error)
+	ViewMessages(ctx context.Context, conversationId, before uint, limit int) ([]models.Message, error)
+	ViewMessage(ctx context.Context, messageId uint) (models.Message, error)
+	MarkConversationRead(ctx context.Context, conversationId, userId, messageId uint) (models.ConversationRead, error)
+	ViewUserConversations(ctx context.Context, userId uint) ([]models.Conversation, error)
+	ViewCompanyPipeline(ctx context.Context, companyId uint) (models.Pipeline, error)
+	ViewJobPipeline(ctx context.Context, jobId uint) (models.Pipeline, error)
+	UpdateCompanyPipeline(ctx context.Context, companyId uint, pu models.PipelineUpdate) (models.Pipeline, error)
+	UpdateJobPipeline(ctx context.Context, jobId uint, pu models.PipelineUpdate) (models.Pipeline, error)
+	MoveApplication(ctx context.Context, applicationId, stageId, movedBy uint) (models.Application, error)
+	ViewApplicationStageChanges(ctx context.Context, applicationId uint) ([]models.ApplicationStageChange, error)
+	ViewBoard(ctx context.Context, jobId uint) (models.Board, error)
+	SetScorecardTemplate(ctx context.Context, jobId uint, nt models.NewScorecardTemplate) (models.ScorecardTemplate, error)
+	ViewScorecardTemplate(ctx context.Context, jobId uint) (models.ScorecardTemplate, error)
+	CreateScorecard(ctx context.Context, applicationId, interviewerId uint, ns models.NewScorecard) (models.Scorecard, error)
+	ViewApplicationScorecards(ctx context.Context, applicationId uint) ([]models.Scorecard, error)
+	ViewJobScorecards(ctx context.Context, jobId uint) ([]models.Scorecard, error)
+	SetScreeningQuestions(ctx context.Context, jobId uint, su models.ScreeningUpdate) ([]models.ScreeningQuestion, error)
+	ViewScreeningQuestions(ctx context.Context, jobId uint) ([]models.ScreeningQuestion, error)
+	CreateOfferTemplate(ctx context.Context, companyId uint, nt models.NewOfferTemplate) (models.OfferTemplate, error)
+	ViewOfferTemplates(ctx context.Context, companyId uint) ([]models.OfferTemplate, error)
+	ViewOfferTemplate(ctx context.Context, templateId uint) (models.OfferTemplate, error)
+	UpdateOfferTemplate(ctx context.Context, templateId uint, nt models.NewOfferTemplate) (models.OfferTemplate, error)
+	DeleteOfferTemplate(ctx context.Context, templateId uint) error
+	CreateOffer(ctx context.Context, offer models.Offer, doc models.Document) (models.Offer, error)
+	ViewOffer(ctx context.Context, offerId uint) (models.Offer, error)
+	ViewApplicationOffers(ctx context.Context, applicationId uint) ([]models.Offer, error)
+	ViewOffersByCandidate(ctx context.Context, candidateId uint) ([]models.Offer, error)
+	RespondOffer(ctx context.Context, offerId, candidateId uint, accept bool, reason string) (models.Offer, error)
+	SearchCandidates(ctx context.Context, q models.CandidateSearch, limit int) ([]models.Candidate, error)
+	CreateTalentPool(ctx context.Context, companyId, createdBy uint, np models.NewTalentPool) (models.TalentPool, error)
+	ViewTalentPools(ctx context.Context, companyId uint) ([]models.TalentPool, error)
+	ViewTalentPool(ctx context.Context, poolId uint) (models.TalentPool, error)
+	UpdateTalentPool(ctx context.Context, poolId uint, np models.NewTalentPool) (models.TalentPool, error)
+	DeleteTalentPool(ctx context.Context, poolId uint) error
+	SavePoolMember(ctx context.Context, poolId, userId, addedBy uint, nm models.NewPoolMember) (models.PoolMember, error)
+	ViewPoolCandidates(ctx context.Context, poolId uint, tag string) ([]models.PoolCandidate, error)
+	DeletePoolMember(ctx context.Context, poolId, userId uint) error
+	CreateJobInvitation(ctx context.Context, companyId, userId, invitedBy uint, ni models.NewJobInvitation) (models.JobInvitation, error)
+	ViewJobInvitationsByUser(ctx context.Context, userId uint) ([]models.JobInvitation, error)
+	BookmarkJob(ctx context.Context, userId, jobId uint) (models.Bookmark, error)
+	DeleteBookmark(ctx context.Context, userId, jobId uint) error
+	ViewBookmarkedJobs(ctx context.Context, userId uint) ([]models.Job, error)
+	BookmarkedJobIDs(ctx context.Context, userId uint, jobIds []uint) ([]uint, error)
+	FollowCompany(ctx context.Context, userId, companyId uint) (models.CompanyFollow, error)
+	UnfollowCompany(ctx context.Context, userId, companyId uint) error
+	ViewFollowedCompanies(ctx context.Context, userId uint) ([]models.Company, error)
+	CompanyFollowers(ctx context.Context, companyId uint) ([]models.User, error)
+	RecordJobView(ctx context.Context, jobId, companyId uint, visitor string) error
+	RecordApplyStart(ctx context.Context, jobId, companyId uint, visitor string) error
+	RollupJobStats(ctx context.Context, since time.Time) (int64, error)
+	JobStatSeries(ctx context.Context, scope models.StatScope, from, to time.Time, interval string) ([]models.StatPoint, error)
+	MedianTimeToHire(ctx context.Context, scope models.StatScope, from, to time.Time) (*float64, error)
+	MedianTimeInStage(ctx context.Context, scope models.StatScope, from, to time.Time) ([]models.StageTime, error)
+	UpdateCompany(ctx context.Context, companyId uint, nc models.NewCompany) (models.Company, error)
+	CreateCompanyReview(ctx context.Context, companyId, userId uint, nr models.NewCompanyReview) (models.CompanyReview, error)
+	UpdateCompanyReview(ctx context.Context, reviewId, userId uint, nr models.NewCompanyReview) (models.CompanyReview, error)
+	DeleteCompanyReview(ctx context.Context, reviewId, userId uint) error
+	ViewCompanyReviews(ctx context.Context, companyId uint, limit int) ([]models.CompanyReview, error)
+	ViewReviewsByUser(ctx context.Context, userId uint) ([]models.CompanyReview, error)
+	ViewPendingReviews(ctx context.Context, limit int) ([]models.CompanyReview, error)
+	ModerateReview(ctx context.Context, reviewId, moderatorId uint, rm models.ReviewModeration) (models.CompanyReview, error)
+	ViewCompanyRating(ctx context.Context, companyId uint) (models.CompanyRating, error)
+	CompanyRatings(ctx context.Context, companyIds []uint) ([]models.RatingSummary, error)
+	SalaryPostings(ctx context.Context, f models.SalaryFilter) ([]models.SalaryPosting, error)
+	SearchJobs(ctx context.Context, q models.JobSearch, limit int) ([]models.Job, error)
+	ViewCandidateProfile(ctx context.Context, viewerId, userId uint) (models.Profile, error)
+	ApplicantProfiles(ctx context.Context, jobId uint) ([]models.Profile, error)
 	AutoMigrate() error
 }
 
@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStore_SignedURL(t *testing.T) {
+	l, err := NewLocalStore(t.TempDir(), "http://localhost:8080/files", []byte("secret"))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	body := []byte("plain text resume")
+	require.NoError(t, l.Put(ctx, "resumes/7/cv.txt", bytes.NewReader(body), int64(len(body)), MimeText))
+	require.Error(t, l.Put(ctx, "../escape.txt", bytes.NewReader(body), int64(len(body)), MimeText))
+
+	link, err := l.SignedURL(ctx, "resumes/7/cv.txt", time.Minute)
+	require.NoError(t, err)
+	u, err := url.Parse(link)
+	require.NoError(t, err)
+	path := strings.TrimPrefix(u.Path, "/files")
+
+	serve := func(rawQuery string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		l.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path+"?"+rawQuery, nil))
+		return rec
+	}
+
+	rec := serve(u.RawQuery)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, body, rec.Body.Bytes())
+
+	// A tampered signature is refused
+	q := u.Query()
+	q.Set("sig", strings.Repeat("0", 64))
+	require.Equal(t, http.StatusForbidden, serve(q.Encode()).Code)
+
+	// So is a link used after it expired
+	l.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	require.Equal(t, http.StatusForbidden, serve(u.RawQuery).Code)
+}
@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	MimePDF  = "application/pdf"
+	MimeDOCX = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	MimeText = "text/plain"
+	MimePNG  = "image/png"
+	MimeJPEG = "image/jpeg"
+	MimeWEBP = "image/webp"
+)
+
+var (
+	// ErrTooLarge is returned when an upload exceeds the size limit of its policy.
+	ErrTooLarge = errors.New("file too large")
+	// ErrTypeNotAllowed is returned when the sniffed content type is not accepted by the policy.
+	ErrTypeNotAllowed = errors.New("file type not allowed")
+	// ErrInfected is returned by a Scanner when the file must not be stored.
+	ErrInfected = errors.New("file rejected by virus scan")
+)
+
+// Policy describes which uploads are accepted for one kind of document.
+type Policy struct {
+	MaxSize      int64
+	AllowedTypes []string
+}
+
+var (
+	// ResumePolicy accepts PDF, Word and plain text resumes up to 5 MB.
+	ResumePolicy = Policy{MaxSize: 5 << 20, AllowedTypes: []string{MimePDF, MimeDOCX, MimeText}}
+	// LogoPolicy accepts PNG, JPEG and WebP images up to 1 MB.
+	LogoPolicy = Policy{MaxSize: 1 << 20, AllowedTypes: []string{MimePNG, MimeJPEG, MimeWEBP}}
+	// AttachmentPolicy accepts documents and images sent with messages, up to 10 MB.
+	AttachmentPolicy = Policy{MaxSize: 10 << 20, AllowedTypes: []string{MimePDF, MimeDOCX, MimeText, MimePNG, MimeJPEG, MimeWEBP}}
+)
+
+// Inspect checks data against the policy and returns the content type detected from the bytes themselves.
+// The client supplied Content-Type header is never trusted.
+func (p Policy) Inspect(data []byte, filename string) (string, error) {
+	if int64(len(data)) > p.MaxSize {
+		return "", ErrTooLarge
+	}
+	ct := DetectContentType(data, filename)
+	for _, allowed := range p.AllowedTypes {
+		if ct == allowed {
+			return ct, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrTypeNotAllowed, ct)
+}
+
+// DetectContentType sniffs the content type of data.
+// On top of http.DetectContentType it recognises DOCX files, which are zip archives
+// containing a word/document.xml part.
+func DetectContentType(data []byte, filename string) string {
+	ct := http.DetectContentType(data)
+	if i := strings.IndexByte(ct, ';'); i >= 0 {
+		ct = ct[:i]
+	}
+	if ct == "application/zip" && isDOCX(data) {
+		return MimeDOCX
+	}
+	// DetectContentType only reports text/plain for plain text, whatever the extension says
+	if ct == MimeText && !strings.EqualFold(filepath.Ext(filename), ".txt") && filepath.Ext(filename) != "" {
+		return "application/octet-stream"
+	}
+	return ct
+}
+
+func isDOCX(data []byte) bool {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return false
+	}
+	for _, f := range zr.File {
+		if f.Name == "word/document.xml" {
+			return true
+		}
+	}
+	return false
+}
+
+// Scanner is the hook used to check uploads for malware before they are stored.
+// Implementations return ErrInfected (possibly wrapped) to reject a file.
+type Scanner interface {
+	Scan(ctx context.Context, filename string, r io.Reader) error
+}
+
+// NopScanner accepts every file. It is used when no scanner is configured.
+type NopScanner struct{}
+
+func (NopScanner) Scan(ctx context.Context, filename string, r io.Reader) error {
+	return nil
+}
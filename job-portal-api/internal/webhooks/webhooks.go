@@ -0,0 +1,315 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"gorm.io/gorm"
+
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/queue"
+)
+
+// KindDeliver is the queue task delivering one event to one webhook.
+const KindDeliver = "webhook.deliver"
+
+// DisableAfter is how many deliveries in a row may fail before a webhook is disabled.
+const DisableAfter = 15
+
+// maxAttempts is how often a single delivery is tried; with the queue's backoff this spans about a day.
+const maxAttempts = 12
+
+// responseLimit is how much of a receiver's response is kept in the delivery log, enough for an error message.
+const responseLimit = 256
+
+// ErrPrivateAddress is returned when a webhook resolves to an address inside the network,
+// which company admins must not be able to probe through the webhooks.
+var ErrPrivateAddress = errors.New("webhook address is not public")
+
+// Signature header format: "t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<body>">".
+const (
+	HeaderSignature = "X-Webhook-Signature"
+	HeaderEventID   = "X-Webhook-Event-Id"
+	HeaderEventType = "X-Webhook-Event-Type"
+	HeaderWebhookID = "X-Webhook-Id"
+)
+
+// Store is the part of the data layer the dispatcher needs.
+type Store interface {
+	ActiveWebhooks(ctx context.Context, companyId uint, eventType string) ([]models.Webhook, error)
+	ViewWebhook(ctx context.Context, webhookId uint) (models.Webhook, error)
+	RecordWebhookDelivery(ctx context.Context, d models.WebhookDelivery, disableAfter int) (models.Webhook, error)
+	ViewJobByJobId(ctx context.Context, jobId uint, userId string) ([]models.Job, error)
+}
+
+// Payload is the JSON body POSTed to webhooks.
+type Payload struct {
+	ID         uint            `json:"id"`
+	Type       string          `json:"type"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+type deliverTask struct {
+	WebhookID uint    `json:"webhook_id"`
+	Event     Payload `json:"event"`
+}
+
+// Dispatcher is the event sink fanning domain events out to the webhooks of the company concerned.
+// Every delivery is a queue task, so each webhook is retried on its own.
+type Dispatcher struct {
+	store  Store
+	q      *queue.Queue
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewDispatcher returns a dispatcher delivering through client, or NewClient(false) when nil.
+func NewDispatcher(store Store, q *queue.Queue, client *http.Client) (*Dispatcher, error) {
+	if store == nil || q == nil {
+		return nil, errors.New("store and queue cannot be nil")
+	}
+	if client == nil {
+		client = NewClient(false)
+	}
+	d := &Dispatcher{store: store, q: q, client: client, now: time.Now}
+	q.Handle(KindDeliver, d.handle)
+	return d, nil
+}
+
+// NewClient returns the client webhooks are delivered with, with a 10s timeout. Unless allowPrivate is set,
+// which only tests delivering to a local receiver should do, it refuses to connect to loopback, private,
+// link-local and unspecified addresses. The check is made on the address dialed, after DNS resolution,
+// so a host name resolving to such an address is refused too.
+func NewClient(allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !public(ip) {
+				return fmt.Errorf("%w: %s", ErrPrivateAddress, host)
+			}
+			return nil
+		}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	// A proxy would be dialed instead of the receiver and defeat the check
+	transport.Proxy = nil
+	return &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: transport,
+		// A redirect is reported as a failed delivery rather than followed
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+}
+
+// public reports whether ip is an address on the internet.
+func public(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified())
+}
+
+// Publish queues a delivery of the event to every subscribed webhook. It implements events.Sink.
+func (d *Dispatcher) Publish(ctx context.Context, e models.OutboxEvent) error {
+	companyId, err := d.companyOf(ctx, e)
+	if err != nil || companyId == 0 {
+		return err
+	}
+	hooks, err := d.store.ActiveWebhooks(ctx, companyId, e.Type)
+	if err != nil {
+		return err
+	}
+	for _, w := range hooks {
+		t := deliverTask{
+			WebhookID: w.ID,
+			Event:     Payload{ID: e.ID, Type: e.Type, OccurredAt: e.OccurredAt, Data: e.Payload},
+		}
+		// The relay may publish an event twice, the webhook still gets it once
+		err = d.q.Enqueue(ctx, KindDeliver, t, queue.MaxAttempts(maxAttempts),
+			queue.Unique(fmt.Sprintf("webhook:%d:event:%d", w.ID, e.ID)))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// companyOf finds the company an event concerns, 0 for events no company subscribes to.
+func (d *Dispatcher) companyOf(ctx context.Context, e models.OutboxEvent) (uint, error) {
+	switch e.AggregateType {
+	case models.AggregateCompany:
+		return e.AggregateID, nil
+	case models.AggregateJob:
+		var job struct {
+			CompanyID uint `json:"company_id"`
+		}
+		err := json.Unmarshal(e.Payload, &job)
+		return job.CompanyID, err
+	case models.AggregateApplication:
+		var app struct {
+			JobID uint `json:"job_id"`
+		}
+		err := json.Unmarshal(e.Payload, &app)
+		if err != nil {
+			return 0, err
+		}
+		jobs, err := d.store.ViewJobByJobId(ctx, app.JobID, "")
+		if err != nil || len(jobs) == 0 {
+			return 0, err
+		}
+		return jobs[0].CompanyID, nil
+	}
+	return 0, nil
+}
+
+func (d *Dispatcher) handle(ctx context.Context, payload json.RawMessage) error {
+	var t deliverTask
+	err := json.Unmarshal(payload, &t)
+	if err != nil {
+		return queue.Permanent(err)
+	}
+	w, err := d.store.ViewWebhook(ctx, t.WebhookID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Deleted in the meantime
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !w.Active {
+		return nil
+	}
+	_, w, err = d.deliver(ctx, w, t.Event)
+	if err != nil && !w.Active {
+		return queue.Permanent(fmt.Errorf("webhook disabled after %d failures: %w", w.ConsecutiveFailures, err))
+	}
+	return err
+}
+
+// SendTest delivers a test event to the webhook right away, even when it is disabled.
+// The error is only set when the attempt could not be logged.
+func (d *Dispatcher) SendTest(ctx context.Context, w models.Webhook) (models.WebhookDelivery, error) {
+	data, err := json.Marshal(map[string]any{
+		"webhook_id": w.ID,
+		"message":    "This is a test event from the job portal.",
+	})
+	if err != nil {
+		return models.WebhookDelivery{}, err
+	}
+	p := Payload{Type: models.EventWebhookTest, OccurredAt: d.now().UTC(), Data: data}
+	delivery, _, err := d.deliver(ctx, w, p)
+	var rerr recordError
+	if errors.As(err, &rerr) {
+		return delivery, err
+	}
+	// A failed delivery is reported through the delivery itself
+	return delivery, nil
+}
+
+// recordError is returned by deliver when the attempt could not be logged.
+type recordError struct{ error }
+
+func (e recordError) Unwrap() error { return e.error }
+
+// deliver POSTs the payload and logs the attempt. A response outside 2xx is an error.
+func (d *Dispatcher) deliver(ctx context.Context, w models.Webhook, p Payload) (models.WebhookDelivery, models.Webhook, error) {
+	body, err := json.Marshal(p)
+	if err != nil {
+		return models.WebhookDelivery{}, w, err
+	}
+	start := d.now()
+	delivery := models.WebhookDelivery{WebhookID: w.ID, EventID: p.ID, EventType: p.Type}
+	status, respBody, sendErr := d.post(ctx, w, p, body, start)
+	delivery.DurationMs = d.now().Sub(start).Milliseconds()
+	delivery.StatusCode = status
+	delivery.ResponseBody = respBody
+	if sendErr == nil && (status < 200 || status > 299) {
+		sendErr = fmt.Errorf("webhook responded with status %d", status)
+	}
+	delivery.Success = sendErr == nil
+	if sendErr != nil {
+		delivery.Error = sendErr.Error()
+	}
+
+	updated, err := d.store.RecordWebhookDelivery(ctx, delivery, DisableAfter)
+	if err != nil {
+		return delivery, w, recordError{errors.Join(sendErr, err)}
+	}
+	return delivery, updated, sendErr
+}
+
+func (d *Dispatcher) post(ctx context.Context, w models.Webhook, p Payload, body []byte, now time.Time) (int, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", err
+	}
+	ts := now.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "job-portal-webhooks/1.0")
+	req.Header.Set(HeaderWebhookID, strconv.FormatUint(uint64(w.ID), 10))
+	req.Header.Set(HeaderEventID, strconv.FormatUint(uint64(p.ID), 10))
+	req.Header.Set(HeaderEventType, p.Type)
+	req.Header.Set(HeaderSignature, fmt.Sprintf("t=%d,v1=%s", ts, Sign(w.Secret, ts, body)))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, responseLimit))
+	return resp.StatusCode, string(data), nil
+}
+
+// Sign computes the signature of a body sent at the given unix time.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a signature header the way receivers should:
+// the signature must match and the timestamp be within tolerance of now, which stops replays.
+func Verify(secret, header string, body []byte, tolerance time.Duration, now time.Time) error {
+	var ts int64
+	var sigs []string
+	for _, part := range strings.Split(header, ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch k {
+		case "t":
+			ts, _ = strconv.ParseInt(v, 10, 64)
+		case "v1":
+			sigs = append(sigs, v)
+		}
+	}
+	if ts == 0 || len(sigs) == 0 {
+		return errors.New("malformed signature header")
+	}
+	if age := now.Sub(time.Unix(ts, 0)); age > tolerance || age < -tolerance {
+		return errors.New("signature timestamp outside tolerance")
+	}
+	want := Sign(secret, ts, body)
+	for _, s := range sigs {
+		if hmac.Equal([]byte(s), []byte(want)) {
+			return nil
+		}
+	}
+	return errors.New("signature mismatch")
+}
@@ -0,0 +1,248 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/queue"
+)
+
+// fakeStore keeps webhooks, deliveries and queued tasks in memory.
+type fakeStore struct {
+	mu         sync.Mutex
+	hooks      map[uint]models.Webhook
+	deliveries []models.WebhookDelivery
+	tasks      []models.Task
+}
+
+func newFakeStore(hooks ...models.Webhook) *fakeStore {
+	f := &fakeStore{hooks: map[uint]models.Webhook{}}
+	for _, w := range hooks {
+		f.hooks[w.ID] = w
+	}
+	return f
+}
+
+func (f *fakeStore) ActiveWebhooks(ctx context.Context, companyId uint, eventType string) ([]models.Webhook, error) {
+	var out []models.Webhook
+	for _, w := range f.hooks {
+		if w.CompanyID == companyId && w.Active && w.Subscribes(eventType) {
+			out = append(out, w)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeStore) ViewWebhook(ctx context.Context, webhookId uint) (models.Webhook, error) {
+	w, ok := f.hooks[webhookId]
+	if !ok {
+		return models.Webhook{}, gorm.ErrRecordNotFound
+	}
+	return w, nil
+}
+
+func (f *fakeStore) RecordWebhookDelivery(ctx context.Context, d models.WebhookDelivery, disableAfter int) (models.Webhook, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w := f.hooks[d.WebhookID]
+	d.ID = uint(len(f.deliveries) + 1)
+	f.deliveries = append(f.deliveries, d)
+	if d.Success {
+		w.ConsecutiveFailures = 0
+	} else {
+		w.ConsecutiveFailures++
+		if w.ConsecutiveFailures >= disableAfter {
+			w.Active = false
+		}
+	}
+	f.hooks[w.ID] = w
+	return w, nil
+}
+
+func (f *fakeStore) ViewJobByJobId(ctx context.Context, jobId uint, userId string) ([]models.Job, error) {
+	return []models.Job{{Model: gorm.Model{ID: jobId}, CompanyID: 7}}, nil
+}
+
+func (f *fakeStore) EnqueueTask(ctx context.Context, t models.Task) (models.Task, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, old := range f.tasks {
+		if t.UniqueKey != nil && old.UniqueKey != nil && *old.UniqueKey == *t.UniqueKey {
+			return t, nil
+		}
+	}
+	f.tasks = append(f.tasks, t)
+	return t, nil
+}
+
+func (f *fakeStore) ClaimTasks(ctx context.Context, kinds []string, worker string, limit int, now time.Time) ([]models.Task, error) {
+	return nil, nil
+}
+func (f *fakeStore) FinishTask(ctx context.Context, taskId uint) error { return nil }
+func (f *fakeStore) RetryTask(ctx context.Context, taskId uint, runAt time.Time, lastErr string) error {
+	return nil
+}
+func (f *fakeStore) BuryTask(ctx context.Context, taskId uint, lastErr string) error { return nil }
+func (f *fakeStore) RequeueStaleTasks(ctx context.Context, lockedBefore time.Time) (int64, error) {
+	return 0, nil
+}
+func (f *fakeStore) PurgeFinishedTasks(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+// newDispatcher returns a dispatcher allowed to deliver to the local test receivers.
+func newDispatcher(t *testing.T, f *fakeStore) *Dispatcher {
+	q, err := queue.New(f, queue.Options{})
+	require.NoError(t, err)
+	d, err := NewDispatcher(f, q, NewClient(true))
+	require.NoError(t, err)
+	return d
+}
+
+func TestPublishQueuesSubscribedWebhooks(t *testing.T) {
+	f := newFakeStore(
+		models.Webhook{Model: gorm.Model{ID: 1}, CompanyID: 7, Active: true, EventTypes: []string{models.EventApplicationSubmitted}},
+		models.Webhook{Model: gorm.Model{ID: 2}, CompanyID: 7, Active: true, EventTypes: []string{models.EventJobPosted}},
+		models.Webhook{Model: gorm.Model{ID: 3}, CompanyID: 8, Active: true, EventTypes: []string{models.EventApplicationSubmitted}},
+		models.Webhook{Model: gorm.Model{ID: 4}, CompanyID: 7, Active: false, EventTypes: []string{models.EventApplicationSubmitted}},
+	)
+	d := newDispatcher(t, f)
+	e := models.OutboxEvent{
+		ID: 42, Type: models.EventApplicationSubmitted,
+		AggregateType: models.AggregateApplication, AggregateID: 5,
+		Payload: json.RawMessage(`{"id":5,"job_id":9}`),
+	}
+
+	require.NoError(t, d.Publish(context.Background(), e))
+	// The relay publishing the event again must not deliver it twice
+	require.NoError(t, d.Publish(context.Background(), e))
+
+	require.Len(t, f.tasks, 1)
+	require.Equal(t, KindDeliver, f.tasks[0].Kind)
+	var task deliverTask
+	require.NoError(t, json.Unmarshal(f.tasks[0].Payload, &task))
+	require.Equal(t, uint(1), task.WebhookID)
+	require.Equal(t, uint(42), task.Event.ID)
+	require.JSONEq(t, `{"id":5,"job_id":9}`, string(task.Event.Data))
+}
+
+func TestDeliverySignedAndLogged(t *testing.T) {
+	const secret = "whsec_test"
+	var got struct {
+		body      []byte
+		signature string
+		eventType string
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got.body, _ = io.ReadAll(r.Body)
+		got.signature = r.Header.Get(HeaderSignature)
+		got.eventType = r.Header.Get(HeaderEventType)
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	f := newFakeStore(models.Webhook{Model: gorm.Model{ID: 1}, CompanyID: 7, URL: srv.URL, Secret: secret, Active: true,
+		EventTypes: []string{models.EventJobPosted}})
+	d := newDispatcher(t, f)
+	payload, err := json.Marshal(deliverTask{WebhookID: 1, Event: Payload{ID: 3, Type: models.EventJobPosted, Data: json.RawMessage(`{}`)}})
+	require.NoError(t, err)
+
+	require.NoError(t, d.handle(context.Background(), payload))
+
+	require.Equal(t, models.EventJobPosted, got.eventType)
+	require.NoError(t, Verify(secret, got.signature, got.body, 5*time.Minute, time.Now()))
+	require.Error(t, Verify("wrong", got.signature, got.body, 5*time.Minute, time.Now()))
+	require.Error(t, Verify(secret, got.signature, got.body, 5*time.Minute, time.Now().Add(time.Hour)))
+
+	require.Len(t, f.deliveries, 1)
+	require.True(t, f.deliveries[0].Success)
+	require.Equal(t, http.StatusOK, f.deliveries[0].StatusCode)
+	require.Equal(t, "ok", f.deliveries[0].ResponseBody)
+}
+
+func TestWebhookDisabledAfterFailures(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	f := newFakeStore(models.Webhook{Model: gorm.Model{ID: 1}, CompanyID: 7, URL: srv.URL, Active: true,
+		EventTypes: []string{models.EventJobPosted}})
+	d := newDispatcher(t, f)
+	payload, err := json.Marshal(deliverTask{WebhookID: 1, Event: Payload{ID: 3, Type: models.EventJobPosted}})
+	require.NoError(t, err)
+
+	for i := 1; i < DisableAfter; i++ {
+		err = d.handle(context.Background(), payload)
+		require.ErrorContains(t, err, "status 500")
+		require.True(t, f.hooks[1].Active)
+	}
+	err = d.handle(context.Background(), payload)
+	require.ErrorContains(t, err, "webhook disabled")
+	require.False(t, f.hooks[1].Active)
+
+	// Deliveries still queued for the disabled webhook are dropped
+	require.NoError(t, d.handle(context.Background(), payload))
+	require.Len(t, f.deliveries, DisableAfter)
+}
+
+func TestSendTest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer srv.Close()
+
+	w := models.Webhook{Model: gorm.Model{ID: 1}, CompanyID: 7, URL: srv.URL, Active: false}
+	f := newFakeStore(w)
+	d := newDispatcher(t, f)
+
+	delivery, err := d.SendTest(context.Background(), w)
+	require.NoError(t, err)
+	require.False(t, delivery.Success)
+	require.Equal(t, http.StatusGone, delivery.StatusCode)
+	require.Equal(t, models.EventWebhookTest, delivery.EventType)
+	require.Len(t, f.deliveries, 1)
+}
+
+func TestPrivateAddressRefused(t *testing.T) {
+	var called bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer srv.Close()
+
+	f := newFakeStore(models.Webhook{Model: gorm.Model{ID: 1}, CompanyID: 7, URL: srv.URL, Active: true,
+		EventTypes: []string{models.EventJobPosted}})
+	q, err := queue.New(f, queue.Options{})
+	require.NoError(t, err)
+	d, err := NewDispatcher(f, q, nil)
+	require.NoError(t, err)
+	payload, err := json.Marshal(deliverTask{WebhookID: 1, Event: Payload{ID: 3, Type: models.EventJobPosted}})
+	require.NoError(t, err)
+
+	err = d.handle(context.Background(), payload)
+	require.ErrorIs(t, err, ErrPrivateAddress)
+	require.False(t, called)
+	require.False(t, f.deliveries[0].Success)
+}
+
+func TestPublic(t *testing.T) {
+	for _, ip := range []string{"127.0.0.1", "::1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254",
+		"fe80::1", "fd00::1", "0.0.0.0", "::", "::ffff:127.0.0.1"} {
+		require.False(t, public(net.ParseIP(ip)), ip)
+	}
+	for _, ip := range []string{"93.184.216.34", "2606:2800:220:1:248:1893:25c8:1946"} {
+		require.True(t, public(net.ParseIP(ip)), ip)
+	}
+}